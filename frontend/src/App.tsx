@@ -1,7 +1,7 @@
 // 主應用程式組件
 // 文件路徑: frontend/src/App.tsx
 
-import { useState, useEffect } from 'react'
+import { useState, useEffect, useCallback } from 'react'
 import {
   Container,
   AppBar,
@@ -21,11 +21,10 @@ import FileDownloadIcon from '@mui/icons-material/FileDownload'
 import CloseIcon from '@mui/icons-material/Close'
 // 匯入 Wails 綁定的 API 和類型
 import * as AppAPI from '../wailsjs/wailsjs/go/app/App'
-import type { models } from '../wailsjs/wailsjs/go/models'
 import type { parser } from '../wailsjs/wailsjs/go/models'
+import { filter } from '../wailsjs/wailsjs/go/models'
 
 // 使用 Wails 生成的類型別名
-type LogEntry = models.LogEntry
 type ParseError = parser.ParseError
 import TabPanel from './components/TabPanel'
 import LogTable from './components/LogTable'
@@ -36,15 +35,15 @@ import ExportProgress from './components/ExportProgress'
 import SearchBar from './components/SearchBar'
 import FilterPanel from './components/FilterPanel'
 import RecentFiles from './components/RecentFiles'
-import { SearchService, SearchCriteria } from './services/searchService'
-import { FilterService, FilterCriteria } from './services/filterService'
+import { SearchCriteria } from './services/searchService'
+import { FilterCriteria, toBackendCriteria } from './services/filterService'
 
 // 檔案資訊介面
 interface FileInfo {
   path: string
   name: string
-  entries: LogEntry[]  // 原始日誌記錄
-  filteredEntries: LogEntry[]  // 篩選/搜尋後的日誌記錄
+  totalCount: number  // 成功解析的記錄總數（記錄本身由 LogTable 向後端分頁取得）
+  matchedCount: number  // 符合篩選/搜尋條件的記錄總數
   errorCount: number
   errorSamples: ParseError[]
   statistics: Statistics | null  // User Story 2: 統計資訊
@@ -54,6 +53,7 @@ interface FileInfo {
   // User Story 4: 搜尋和篩選狀態
   searchCriteria: SearchCriteria | null
   filterCriteria: FilterCriteria | null
+  criteria: filter.FilterCriteria  // 合併後傳給後端的條件
   showFilterPanel: boolean
 }
 
@@ -64,10 +64,6 @@ function App() {
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState<string | null>(null)
 
-  // User Story 4: 搜尋和篩選服務
-  const searchService = new SearchService()
-  const filterService = new FilterService()
-
   // 匯出狀態
   const [exporting, setExporting] = useState(false)
   const [exportProgress, setExportProgress] = useState(0)
@@ -134,8 +130,8 @@ function App() {
       const newFile: FileInfo = {
         path: filePath,
         name: fileName,
-        entries: [],
-        filteredEntries: [],  // User Story 4: 初始為空
+        totalCount: 0,
+        matchedCount: 0,
         errorCount: 0,
         errorSamples: [],
         statistics: null,  // 統計資訊初始為 null
@@ -145,6 +141,7 @@ function App() {
         // User Story 4: 搜尋篩選初始狀態
         searchCriteria: null,
         filterCriteria: null,
+        criteria: toBackendCriteria(null, null),
         showFilterPanel: false,
       }
       
@@ -154,7 +151,7 @@ function App() {
 
       // 呼叫 Wails API 解析檔案
       // 不需要傳遞 context 參數
-      const result = await AppAPI.ParseFile({ filePath })
+      const result = await AppAPI.ParseFile({ filePath, buildIndex: true })
       
       // 檢查解析是否成功
       if (!result.success || !result.logFile) {
@@ -166,11 +163,10 @@ function App() {
       // 更新檔案資訊
       const updatedFiles = newFiles.map((f, i) => {
         if (i === newFiles.length - 1) {
-          const entries = logFile.entries || []
           return {
             ...f,
-            entries: entries,
-            filteredEntries: entries,  // User Story 4: 初始顯示所有記錄
+            totalCount: logFile.parsedLines || 0,
+            matchedCount: logFile.parsedLines || 0,  // User Story 4: 初始顯示所有記錄
             errorCount: logFile.errorLines || 0,
             errorSamples: result.errorSamples || [],
             statistics: logFile.statistics || null,
@@ -217,8 +213,8 @@ function App() {
       const newFile: FileInfo = {
         path: filePath,
         name: fileName,
-        entries: [],
-        filteredEntries: [],
+        totalCount: 0,
+        matchedCount: 0,
         errorCount: 0,
         errorSamples: [],
         statistics: null,
@@ -227,6 +223,7 @@ function App() {
         error: null,
         searchCriteria: null,
         filterCriteria: null,
+        criteria: toBackendCriteria(null, null),
         showFilterPanel: false,
       }
       
@@ -235,7 +232,7 @@ function App() {
       setCurrentTab(newFiles.length - 1)
 
       // 呼叫 Wails API 解析檔案
-      const result = await AppAPI.ParseFile({ filePath })
+      const result = await AppAPI.ParseFile({ filePath, buildIndex: true })
       
       // 檢查解析是否成功
       if (!result.success || !result.logFile) {
@@ -247,11 +244,10 @@ function App() {
       // 更新檔案資訊
       const updatedFiles = newFiles.map((f, i) => {
         if (i === newFiles.length - 1) {
-          const entries = logFile.entries || []
           return {
             ...f,
-            entries: entries,
-            filteredEntries: entries,
+            totalCount: logFile.parsedLines || 0,
+            matchedCount: logFile.parsedLines || 0,
             errorCount: logFile.errorLines || 0,
             errorSamples: result.errorSamples || [],
             statistics: logFile.statistics || null,
@@ -379,38 +375,45 @@ function App() {
   }
 
   /**
-   * User Story 4: 處理搜尋
-   * 根據搜尋條件篩選日誌記錄
+   * User Story 4: 套用篩選與搜尋條件
+   * 更新後端條件，LogTable 會依新條件向後端重新分頁取得記錄
    */
-  const handleSearch = (criteria: SearchCriteria) => {
-    if (files.length === 0 || currentTab >= files.length) return
-    
-    const currentFile = files[currentTab]
-    
-    // 先套用篩選，再套用搜尋
-    let filtered = currentFile.entries
-    
-    // 如果有篩選條件，先篩選
-    if (currentFile.filterCriteria) {
-      filtered = filterService.filter(filtered, currentFile.filterCriteria)
-    }
-    
-    // 再套用搜尋
-    const searched = searchService.search(filtered, criteria)
-    
-    // 更新檔案資訊
-    const updatedFiles = files.map((f, i) => {
-      if (i === currentTab) {
+  const applyCriteria = (
+    filterCriteria: FilterCriteria | null,
+    searchCriteria: SearchCriteria | null
+  ) => {
+    const tabIndex = currentTab
+
+    setFiles(prev => prev.map((f, i) => {
+      if (i === tabIndex) {
         return {
           ...f,
-          filteredEntries: searched,
-          searchCriteria: criteria
+          filterCriteria,
+          searchCriteria,
+          criteria: toBackendCriteria(filterCriteria, searchCriteria)
         }
       }
       return f
+    }))
+  }
+
+  /**
+   * User Story 4: 更新符合條件的記錄數（由 LogTable 取得資料後回報）
+   */
+  const handleMatchedChange = useCallback((filePath: string, matched: number) => {
+    setFiles(prev => {
+      const target = prev.find(f => f.path === filePath)
+      if (!target || target.matchedCount === matched) return prev
+      return prev.map(f => (f.path === filePath ? { ...f, matchedCount: matched } : f))
     })
-    
-    setFiles(updatedFiles)
+  }, [])
+
+  /**
+   * User Story 4: 處理搜尋
+   */
+  const handleSearch = (criteria: SearchCriteria) => {
+    if (files.length === 0 || currentTab >= files.length) return
+    applyCriteria(files[currentTab].filterCriteria, criteria)
   }
 
   /**
@@ -418,27 +421,7 @@ function App() {
    */
   const handleClearSearch = () => {
     if (files.length === 0 || currentTab >= files.length) return
-    
-    const currentFile = files[currentTab]
-    
-    // 重新套用篩選（如果有），否則顯示所有記錄
-    let filtered = currentFile.entries
-    if (currentFile.filterCriteria) {
-      filtered = filterService.filter(filtered, currentFile.filterCriteria)
-    }
-    
-    const updatedFiles = files.map((f, i) => {
-      if (i === currentTab) {
-        return {
-          ...f,
-          filteredEntries: filtered,
-          searchCriteria: null
-        }
-      }
-      return f
-    })
-    
-    setFiles(updatedFiles)
+    applyCriteria(files[currentTab].filterCriteria, null)
   }
 
   /**
@@ -446,30 +429,7 @@ function App() {
    */
   const handleFilter = (criteria: FilterCriteria) => {
     if (files.length === 0 || currentTab >= files.length) return
-    
-    const currentFile = files[currentTab]
-    
-    // 先套用篩選，再套用搜尋（如果有）
-    let filtered = filterService.filter(currentFile.entries, criteria)
-    
-    // 如果有搜尋條件，再套用搜尋
-    if (currentFile.searchCriteria) {
-      filtered = searchService.search(filtered, currentFile.searchCriteria)
-    }
-    
-    // 更新檔案資訊
-    const updatedFiles = files.map((f, i) => {
-      if (i === currentTab) {
-        return {
-          ...f,
-          filteredEntries: filtered,
-          filterCriteria: criteria
-        }
-      }
-      return f
-    })
-    
-    setFiles(updatedFiles)
+    applyCriteria(criteria, files[currentTab].searchCriteria)
   }
 
   /**
@@ -477,27 +437,7 @@ function App() {
    */
   const handleClearFilter = () => {
     if (files.length === 0 || currentTab >= files.length) return
-    
-    const currentFile = files[currentTab]
-    
-    // 重新套用搜尋（如果有），否則顯示所有記錄
-    let filtered = currentFile.entries
-    if (currentFile.searchCriteria) {
-      filtered = searchService.search(filtered, currentFile.searchCriteria)
-    }
-    
-    const updatedFiles = files.map((f, i) => {
-      if (i === currentTab) {
-        return {
-          ...f,
-          filteredEntries: filtered,
-          filterCriteria: null
-        }
-      }
-      return f
-    })
-    
-    setFiles(updatedFiles)
+    applyCriteria(null, files[currentTab].searchCriteria)
   }
 
   /**
@@ -675,7 +615,7 @@ function App() {
                       {file.name}
                     </Typography>
                     <Typography variant="body2" color="text.secondary">
-                      總記錄數: {file.entries.length.toLocaleString()} | 錯誤數: {file.errorCount.toLocaleString()}
+                      總記錄數: {file.totalCount.toLocaleString()} | 錯誤數: {file.errorCount.toLocaleString()}
                     </Typography>
                   </Box>
                   
@@ -689,7 +629,7 @@ function App() {
                   
                   {/* 統計儀表板 */}
                   <TabPanel value={currentSubTab} index={0}>
-                    <Dashboard statistics={file.statistics} statTime={file.statTime} />
+                    <Dashboard statistics={file.statistics} statTime={file.statTime} filePath={file.path} />
                   </TabPanel>
                   
                   {/* 日誌明細 */}
@@ -701,10 +641,10 @@ function App() {
                         onClear={handleClearSearch}
                         onToggleFilter={handleToggleFilterPanel}
                         searchStats={{
-                          total: file.entries.length,
-                          results: file.filteredEntries.length,
-                          percentage: file.entries.length > 0 
-                            ? (file.filteredEntries.length / file.entries.length) * 100 
+                          total: file.totalCount,
+                          results: file.matchedCount,
+                          percentage: file.totalCount > 0 
+                            ? (file.matchedCount / file.totalCount) * 100 
                             : 0
                         }}
                       />
@@ -717,28 +657,31 @@ function App() {
                           onApplyFilter={handleFilter}
                           onClearFilter={handleClearFilter}
                           filterStats={{
-                            total: file.entries.length,
-                            filtered: file.filteredEntries.length,
-                            percentage: file.entries.length > 0 
-                              ? (file.filteredEntries.length / file.entries.length) * 100 
+                            total: file.totalCount,
+                            filtered: file.matchedCount,
+                            percentage: file.totalCount > 0 
+                              ? (file.matchedCount / file.totalCount) * 100 
                               : 0
                           }}
                         />
                       </Box>
                     )}
                     
-                    {/* 錯誤摘要 */}
-                    {file.errorCount > 0 && (
-                      <Box sx={{ p: 2 }}>
-                        <ErrorSummary
-                          errorCount={file.errorCount}
-                          errorSamples={file.errorSamples}
-                        />
-                      </Box>
-                    )}
+                    {/* 錯誤摘要（解析錯誤與 HTTP 錯誤分析） */}
+                    <Box sx={{ p: 2 }}>
+                      <ErrorSummary
+                        errorCount={file.errorCount}
+                        errorSamples={file.errorSamples}
+                        filePath={file.path}
+                      />
+                    </Box>
                     
                     {/* 日誌表格 */}
-                    <LogTable entries={file.filteredEntries} />
+                    <LogTable
+                      filePath={file.path}
+                      criteria={file.criteria}
+                      onMatchedChange={(matched) => handleMatchedChange(file.path, matched)}
+                    />
                   </TabPanel>
                 </Box>
               )}
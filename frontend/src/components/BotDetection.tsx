@@ -24,10 +24,89 @@ interface BotStat {
   percentage: number
 }
 
+// 機器人代理程式統計（對應 Go stats.BotAgentStat）
+export interface BotAgentStat {
+  name: string
+  vendor: string
+  category: string
+  versions: string[] | null
+  count: number
+  percentage: number
+  bytes: number
+  distinctIPs: number
+  firstSeen: string
+  lastSeen: string
+  verified?: number
+  spoofed?: number
+  unverifiable?: number
+}
+
+// 爬蟲驗證結果統計（對應 Go stats.BotVerificationStats）
+export interface BotVerificationStats {
+  verified: number
+  spoofed: number
+  unverifiable: number
+}
+
+// 冒充主要爬蟲的來源（對應 Go stats.SpoofedCrawlerStat）
+export interface SpoofedCrawlerStat {
+  ip: string
+  claimedName: string
+  category: string
+  count: number
+  firstSeen: string
+  lastSeen: string
+}
+
+// 行為評分的單一訊號（對應 Go stats.BotScoreReason）
+export interface BotScoreReason {
+  signal: string
+  points: number
+  detail: string
+}
+
+// 單一用戶端的行為評分（對應 Go stats.BotScore）
+export interface BotScore {
+  ip: string
+  userAgent: string
+  score: number
+  level: string
+  requests: number
+  reasons: BotScoreReason[] | null
+}
+
 interface BotDetectionProps {
   botRequests: number
   botPercentage: number
   topBots: BotStat[]
+  agents?: BotAgentStat[]
+  verification?: BotVerificationStats
+  spoofedCrawlers?: SpoofedCrawlerStat[] | null
+  suspectedBots?: BotScore[] | null
+}
+
+/**
+ * 格式化傳輸量為人類可讀的格式
+ */
+function formatBytes(bytes: number): string {
+  if (bytes < 1024) return `${bytes} B`
+  const units = ['KB', 'MB', 'GB', 'TB']
+  let value = bytes / 1024
+  let unit = 0
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024
+    unit++
+  }
+  return `${value.toFixed(1)} ${units[unit]}`
+}
+
+/**
+ * 格式化時間（零值時間顯示為 -）
+ */
+function formatTime(value: string): string {
+  const date = new Date(value)
+  if (isNaN(date.getTime()) || date.getFullYear() <= 1) return '-'
+  return date.toLocaleString()
 }
 
 /**
@@ -35,13 +114,20 @@ interface BotDetectionProps {
  * 
  * @param botRequests - 機器人請求總數
  * @param botPercentage - 機器人流量百分比
- * @param topBots - Top 10 機器人列表
+ * @param topBots - Top 10 機器人類型列表
+ * @param agents - 各機器人代理程式統計
+ * @param verification - 爬蟲驗證結果統計
+ * @param spoofedCrawlers - 冒充主要爬蟲的來源
+ * @param suspectedBots - User-Agent 未識別但行為像機器人的用戶端
  */
-function BotDetection({ botRequests, botPercentage, topBots }: BotDetectionProps) {
+function BotDetection({ botRequests, botPercentage, topBots, agents, verification, spoofedCrawlers, suspectedBots }: BotDetectionProps) {
   // 提供預設值以避免 undefined 錯誤
   const safeBotRequests = botRequests ?? 0
   const safeBotPercentage = botPercentage ?? 0
   const safeTopBots = topBots ?? []
+  const safeAgents = agents ?? []
+  const safeSpoofed = spoofedCrawlers ?? []
+  const safeSuspected = suspectedBots ?? []
   
   // 判斷機器人流量是否異常高
   const isHighBotTraffic = safeBotPercentage > 50
@@ -121,20 +207,29 @@ function BotDetection({ botRequests, botPercentage, topBots }: BotDetectionProps
             機器人流量異常偏高（&gt; 50%），建議檢查是否有爬蟲或攻擊行為
           </Alert>
         )}
+
+        {/* 爬蟲驗證摘要 */}
+        {verification && verification.verified + verification.spoofed > 0 && (
+          <Alert severity={verification.spoofed > 0 ? 'error' : 'success'} sx={{ mb: 2 }}>
+            已驗證爬蟲請求 {verification.verified.toLocaleString()} 次，
+            冒充爬蟲請求 {verification.spoofed.toLocaleString()} 次，
+            無法驗證 {verification.unverifiable.toLocaleString()} 次
+          </Alert>
+        )}
       </Box>
 
       {/* Top 10 機器人列表 */}
       {safeTopBots && safeTopBots.length > 0 && (
         <>
           <Typography variant="subtitle2" gutterBottom>
-            Top 10 機器人 User-Agent
+            Top 10 機器人類型
           </Typography>
           <TableContainer>
             <Table size="small">
               <TableHead>
                 <TableRow>
                   <TableCell>排名</TableCell>
-                  <TableCell>User-Agent</TableCell>
+                  <TableCell>類型</TableCell>
                   <TableCell align="right">請求次數</TableCell>
                   <TableCell align="right">百分比</TableCell>
                 </TableRow>
@@ -186,6 +281,165 @@ function BotDetection({ botRequests, botPercentage, topBots }: BotDetectionProps
         </>
       )}
 
+      {/* 已識別的機器人代理程式 */}
+      {safeAgents.length > 0 && (
+        <>
+          <Typography variant="subtitle2" gutterBottom sx={{ mt: 3 }}>
+            已識別的機器人
+          </Typography>
+          <TableContainer sx={{ maxHeight: 360 }}>
+            <Table size="small" stickyHeader>
+              <TableHead>
+                <TableRow>
+                  <TableCell>代理程式</TableCell>
+                  <TableCell>類型</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                  <TableCell align="right">傳輸量</TableCell>
+                  <TableCell align="right">IP 數</TableCell>
+                  <TableCell>出現期間</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {safeAgents.map((agent) => (
+                  <TableRow key={agent.name} hover>
+                    <TableCell>
+                      <Typography variant="body2" sx={{ fontWeight: 500 }}>
+                        {agent.name}
+                      </Typography>
+                      <Typography variant="caption" color="text.secondary">
+                        {[agent.vendor, (agent.versions ?? []).join(', ')].filter(Boolean).join(' · ') || '-'}
+                      </Typography>
+                    </TableCell>
+                    <TableCell>
+                      {agent.category}
+                      {(agent.spoofed ?? 0) > 0 && (
+                        <Typography variant="caption" color="error" display="block">
+                          冒充 {(agent.spoofed ?? 0).toLocaleString()} 次
+                        </Typography>
+                      )}
+                    </TableCell>
+                    <TableCell align="right">
+                      {agent.count.toLocaleString()}
+                      <Typography variant="caption" color="text.secondary" display="block">
+                        {agent.percentage.toFixed(2)}%
+                      </Typography>
+                    </TableCell>
+                    <TableCell align="right">{formatBytes(agent.bytes)}</TableCell>
+                    <TableCell align="right">{agent.distinctIPs.toLocaleString()}</TableCell>
+                    <TableCell>
+                      <Typography variant="caption" display="block">
+                        {formatTime(agent.firstSeen)}
+                      </Typography>
+                      <Typography variant="caption" color="text.secondary" display="block">
+                        {formatTime(agent.lastSeen)}
+                      </Typography>
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+
+      {/* 冒充主要爬蟲的來源 */}
+      {safeSpoofed.length > 0 && (
+        <>
+          <Typography variant="subtitle2" gutterBottom sx={{ mt: 3 }}>
+            冒充爬蟲的來源
+          </Typography>
+          <TableContainer sx={{ maxHeight: 360 }}>
+            <Table size="small" stickyHeader>
+              <TableHead>
+                <TableRow>
+                  <TableCell>來源 IP</TableCell>
+                  <TableCell>自稱爬蟲</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                  <TableCell>出現期間</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {safeSpoofed.map((item) => (
+                  <TableRow key={`${item.ip}-${item.claimedName}`} hover>
+                    <TableCell sx={{ fontFamily: 'monospace' }}>{item.ip}</TableCell>
+                    <TableCell>
+                      {item.claimedName}
+                      <Typography variant="caption" color="text.secondary" display="block">
+                        {item.category}
+                      </Typography>
+                    </TableCell>
+                    <TableCell align="right">{item.count.toLocaleString()}</TableCell>
+                    <TableCell>
+                      <Typography variant="caption" display="block">
+                        {formatTime(item.firstSeen)}
+                      </Typography>
+                      <Typography variant="caption" color="text.secondary" display="block">
+                        {formatTime(item.lastSeen)}
+                      </Typography>
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+
+      {/* 行為像機器人的用戶端 */}
+      {safeSuspected.length > 0 && (
+        <>
+          <Typography variant="subtitle2" gutterBottom sx={{ mt: 3 }}>
+            疑似機器人（依行為評分）
+          </Typography>
+          <TableContainer sx={{ maxHeight: 360 }}>
+            <Table size="small" stickyHeader>
+              <TableHead>
+                <TableRow>
+                  <TableCell>來源</TableCell>
+                  <TableCell align="right">評分</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                  <TableCell>原因</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {safeSuspected.map((item) => (
+                  <TableRow key={`${item.ip}-${item.userAgent}`} hover>
+                    <TableCell>
+                      <Typography variant="body2" sx={{ fontFamily: 'monospace' }}>
+                        {item.ip}
+                      </Typography>
+                      <Typography
+                        variant="caption"
+                        color="text.secondary"
+                        display="block"
+                        sx={{ maxWidth: 260, overflow: 'hidden', textOverflow: 'ellipsis', whiteSpace: 'nowrap' }}
+                        title={item.userAgent}
+                      >
+                        {item.userAgent || '-'}
+                      </Typography>
+                    </TableCell>
+                    <TableCell align="right">
+                      {item.score}
+                      <Typography variant="caption" color="text.secondary" display="block">
+                        {item.level}
+                      </Typography>
+                    </TableCell>
+                    <TableCell align="right">{item.requests.toLocaleString()}</TableCell>
+                    <TableCell>
+                      {(item.reasons ?? []).map((reason) => (
+                        <Typography key={reason.signal} variant="caption" display="block">
+                          {reason.detail}（+{reason.points}）
+                        </Typography>
+                      ))}
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+
       {(!safeTopBots || safeTopBots.length === 0) && (
         <Typography variant="body2" color="text.secondary" sx={{ textAlign: 'center', mt: 2 }}>
           未偵測到機器人流量
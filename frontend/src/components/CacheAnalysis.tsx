@@ -0,0 +1,201 @@
+// CacheAnalysis 元件 - 顯示快取與條件式請求的成效
+// 文件路徑: frontend/src/components/CacheAnalysis.tsx
+// 用途: 從 access log 評估 304 比例、節省流量、靜態資源重複下載與快取命中率，並列出需要檢查快取標頭的路徑
+
+import { useState } from 'react'
+import {
+  Alert,
+  Box,
+  Button,
+  Chip,
+  CircularProgress,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+  Typography,
+} from '@mui/material'
+import * as AppAPI from '../../wailsjs/wailsjs/go/app/App'
+import { app } from '../../wailsjs/wailsjs/go/models'
+import type { cacheanalysis } from '../../wailsjs/wailsjs/go/models'
+
+interface CacheAnalysisProps {
+  filePath?: string  // 已載入的檔案路徑
+}
+
+// 內容類型的顯示名稱（對應 Go internal/cacheanalysis 的 Type* 常數）
+const CONTENT_TYPE_LABELS: Record<string, string> = {
+  page: '頁面',
+  stylesheet: 'CSS',
+  script: 'JavaScript',
+  image: '圖片',
+  font: '字型',
+  media: '影音',
+  document: '文件',
+  data: '資料',
+  other: '其他',
+}
+
+// 建議原因的顯示名稱（對應 Go internal/cacheanalysis 的 Reason* 常數）
+const REASON_LABELS: Record<string, string> = {
+  'repeat-downloads': '重複下載',
+  'frequent-revalidation': '頻繁重新驗證',
+  'low-hit-ratio': '命中率偏低',
+}
+
+/**
+ * 將位元組格式化為 MB
+ */
+function formatMB(bytes: number): string {
+  return `${(bytes / (1024 * 1024)).toFixed(2)} MB`
+}
+
+/**
+ * CacheAnalysis 元件 - 按下按鈕後分析已載入檔案的快取成效
+ *
+ * @param filePath - 已載入的檔案路徑
+ */
+function CacheAnalysis({ filePath }: CacheAnalysisProps) {
+  const [report, setReport] = useState<cacheanalysis.Report | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  if (!filePath) {
+    return null
+  }
+
+  const analyze = async () => {
+    setLoading(true)
+    setError(null)
+    try {
+      const response = await AppAPI.AnalyzeCache(app.CacheAnalysisRequest.createFrom({ filePath, options: {} }))
+      if (!response.success || !response.report) {
+        throw new Error(response.errorMessage || '快取分析失敗')
+      }
+      setReport(response.report)
+    } catch (err) {
+      setReport(null)
+      setError(err instanceof Error ? err.message : String(err))
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const recommendations = report?.recommendations ?? []
+  const contentTypes = report?.contentTypes ?? []
+  const repeatedAssets = report?.repeatedAssets ?? []
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Box sx={{ display: 'flex', alignItems: 'center', gap: 2, mb: 1 }}>
+        <Typography variant="h6">快取成效</Typography>
+        <Button size="small" variant="outlined" onClick={analyze} disabled={loading}>
+          {report ? '重新分析' : '分析快取成效'}
+        </Button>
+        {loading && <CircularProgress size={20} />}
+      </Box>
+
+      {error && <Alert severity="error">{error}</Alert>}
+
+      {report && (
+        <>
+          <Typography variant="body2" color="text.secondary" gutterBottom>
+            可快取請求 {report.cacheableRequests.toLocaleString()} 筆，304 比例 {report.notModifiedRate.toFixed(1)}%，
+            以 304 節省約 {formatMB(report.bytesSaved)}，靜態資源重複下載 {report.repeatFetches.toLocaleString()} 次
+            {report.cacheStatus && `，快取命中率 ${report.cacheStatus.hitRatio.toFixed(1)}%`}
+          </Typography>
+
+          {recommendations.length > 0 && (
+            <Box sx={{ mt: 2 }}>
+              <Typography variant="subtitle2" gutterBottom>
+                建議檢查快取標頭的路徑
+              </Typography>
+              <Table size="small">
+                <TableHead>
+                  <TableRow>
+                    <TableCell>路徑</TableCell>
+                    <TableCell>原因</TableCell>
+                    <TableCell>說明</TableCell>
+                    <TableCell align="right">請求數</TableCell>
+                  </TableRow>
+                </TableHead>
+                <TableBody>
+                  {recommendations.map((r) => (
+                    <TableRow key={`${r.path}-${r.reason}`}>
+                      <TableCell sx={{ wordBreak: 'break-all' }}>{r.path}</TableCell>
+                      <TableCell>
+                        <Chip label={REASON_LABELS[r.reason] ?? r.reason} color="warning" size="small" />
+                      </TableCell>
+                      <TableCell>{r.message}</TableCell>
+                      <TableCell align="right">{r.requests.toLocaleString()}</TableCell>
+                    </TableRow>
+                  ))}
+                </TableBody>
+              </Table>
+            </Box>
+          )}
+
+          <Box sx={{ mt: 2 }}>
+            <Typography variant="subtitle2" gutterBottom>
+              各內容類型
+            </Typography>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>內容類型</TableCell>
+                  <TableCell align="right">請求數</TableCell>
+                  <TableCell align="right">304 比例</TableCell>
+                  <TableCell align="right">節省流量</TableCell>
+                  <TableCell align="right">重複下載</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {contentTypes.map((t) => (
+                  <TableRow key={t.contentType}>
+                    <TableCell>{CONTENT_TYPE_LABELS[t.contentType] ?? t.contentType}</TableCell>
+                    <TableCell align="right">{t.requests.toLocaleString()}</TableCell>
+                    <TableCell align="right">{t.notModifiedRate.toFixed(1)}%</TableCell>
+                    <TableCell align="right">{formatMB(t.bytesSaved)}</TableCell>
+                    <TableCell align="right">{t.repeatFetches.toLocaleString()}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </Box>
+
+          {repeatedAssets.length > 0 && (
+            <Box sx={{ mt: 2 }}>
+              <Typography variant="subtitle2" gutterBottom>
+                重複下載最多的靜態資源（{report.options.repeatWindow} 內）
+              </Typography>
+              <Table size="small">
+                <TableHead>
+                  <TableRow>
+                    <TableCell>路徑</TableCell>
+                    <TableCell align="right">重複下載</TableCell>
+                    <TableCell align="right">占完整下載</TableCell>
+                    <TableCell align="right">304 次數</TableCell>
+                  </TableRow>
+                </TableHead>
+                <TableBody>
+                  {repeatedAssets.map((p) => (
+                    <TableRow key={p.path}>
+                      <TableCell sx={{ wordBreak: 'break-all' }}>{p.path}</TableCell>
+                      <TableCell align="right">{p.repeatFetches.toLocaleString()}</TableCell>
+                      <TableCell align="right">{p.repeatRate.toFixed(1)}%</TableCell>
+                      <TableCell align="right">{p.notModified.toLocaleString()}</TableCell>
+                    </TableRow>
+                  ))}
+                </TableBody>
+              </Table>
+            </Box>
+          )}
+        </>
+      )}
+    </Paper>
+  )
+}
+
+export default CacheAnalysis
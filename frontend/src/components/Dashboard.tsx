@@ -2,11 +2,21 @@
 // 文件路徑: frontend/src/components/Dashboard.tsx
 // 用途: User Story 2 - 統計資訊儀表板（T072）
 
-import { Box, Grid, Paper, Typography } from '@mui/material'
+import { useEffect, useState } from 'react'
+import { Alert, Box, CircularProgress, FormControl, Grid, InputLabel, MenuItem, Paper, Select, Typography } from '@mui/material'
+import * as AppAPI from '../../wailsjs/wailsjs/go/app/App'
+import { app } from '../../wailsjs/wailsjs/go/models'
 import TopIPsList from './TopIPsList'
 import TopPathsList from './TopPathsList'
 import StatusCodeDistribution from './StatusCodeDistribution'
-import BotDetection from './BotDetection'
+import BotDetection, { BotAgentStat, BotScore, BotVerificationStats, SpoofedCrawlerStat } from './BotDetection'
+import ThreatDetection, { ThreatStats } from './ThreatDetection'
+import UserAgentDistribution, { UserAgentStatistics } from './UserAgentDistribution'
+import RefererAnalysis, { RefererStatistics } from './RefererAnalysis'
+import NotFoundReport, { NotFoundReportData } from './NotFoundReport'
+import VirtualHostSummary, { VirtualHostStatistics } from './VirtualHostSummary'
+import UserActivity, { UserStatistics } from './UserActivity'
+import CacheAnalysis from './CacheAnalysis'
 
 // 統計資料介面（對應 Go internal/stats/statistics.go）
 // 注意：欄位名稱必須與 Go JSON 標籤匹配（小寫開頭）
@@ -23,6 +33,11 @@ export interface Statistics {
     ip: string
     requestCount: number
     totalBytes: number
+    country?: string
+    city?: string
+    asn?: number
+    organization?: string
+    group?: string
   }>
   
   // Top 路徑統計
@@ -54,12 +69,38 @@ export interface Statistics {
       count: number
       percentage: number
     }>
+    agents?: BotAgentStat[]   // 各機器人代理程式統計
+    verification?: BotVerificationStats      // 爬蟲驗證結果統計
+    spoofedCrawlers?: SpoofedCrawlerStat[] | null  // 冒充主要爬蟲的來源
+    suspectedBots?: BotScore[] | null  // User-Agent 未識別但行為像機器人的用戶端
   }
+
+  // 攻擊偵測
+  threats?: ThreatStats
+
+  // 瀏覽器、作業系統、裝置與排版引擎分布
+  userAgents?: UserAgentStatistics
+
+  // 流量來源、行銷活動與盜連
+  referers?: RefererStatistics
+
+  // 失效連結（404/410）報表
+  notFound?: NotFoundReportData
+
+  // 各虛擬主機摘要（vhost_combined 格式）
+  virtualHosts?: VirtualHostStatistics[] | null
+
+  // 各來源伺服器摘要（載入時標記的伺服器名稱）
+  servers?: VirtualHostStatistics[] | null
+
+  // 已認證使用者（%u）活動
+  users?: UserStatistics
 }
 
 interface DashboardProps {
   statistics: Statistics | null
   statTime: number  // 統計計算耗時（毫秒）
+  filePath?: string  // 已載入的檔案路徑（切換虛擬主機或來源伺服器時使用）
 }
 
 /**
@@ -67,8 +108,51 @@ interface DashboardProps {
  * 
  * @param statistics - 統計資料物件
  * @param statTime - 統計計算耗時（毫秒）
+ * @param filePath - 已載入的檔案路徑
  */
-function Dashboard({ statistics, statTime }: DashboardProps) {
+function Dashboard({ statistics, statTime, filePath }: DashboardProps) {
+  const [selectedHost, setSelectedHost] = useState('')  // 空字串表示全部主機
+  const [selectedServer, setSelectedServer] = useState('')  // 空字串表示全部伺服器
+  const [hostStatistics, setHostStatistics] = useState<Statistics | null>(null)
+  const [hostLoading, setHostLoading] = useState(false)
+  const [hostError, setHostError] = useState<string | null>(null)
+
+  // 重新載入檔案時回到全部主機與伺服器
+  useEffect(() => {
+    setSelectedHost('')
+    setSelectedServer('')
+    setHostStatistics(null)
+    setHostError(null)
+  }, [statistics])
+
+  // 切換虛擬主機或來源伺服器：選擇其一時由後端重新計算統計
+  const selectScope = async (host: string, server: string) => {
+    setSelectedHost(host)
+    setSelectedServer(server)
+    setHostError(null)
+    if ((!host && !server) || !filePath) {
+      setHostStatistics(null)
+      return
+    }
+    setHostLoading(true)
+    try {
+      const response = await AppAPI.GetHostStatistics(app.HostStatisticsRequest.createFrom({ filePath, host, server }))
+      if (response.success) {
+        setHostStatistics(response.statistics as unknown as Statistics)
+      } else {
+        setHostError(response.errorMessage)
+        setHostStatistics(null)
+      }
+    } catch (err) {
+      setHostError(String(err))
+      setHostStatistics(null)
+    } finally {
+      setHostLoading(false)
+    }
+  }
+  const handleSelectHost = (host: string) => selectScope(host, selectedServer)
+  const handleSelectServer = (server: string) => selectScope(selectedHost, server)
+
   if (!statistics) {
     return (
       <Box sx={{ p: 3, textAlign: 'center' }}>
@@ -79,20 +163,76 @@ function Dashboard({ statistics, statTime }: DashboardProps) {
     )
   }
 
+  const virtualHosts = statistics.virtualHosts ?? []
+  // 只有一台伺服器時不需要切換
+  const servers = (statistics.servers ?? []).length > 1 ? statistics.servers ?? [] : []
+  // 選擇單一主機或伺服器時顯示該範圍的統計
+  const shown = (selectedHost || selectedServer) && hostStatistics ? hostStatistics : statistics
+
   return (
     <Box sx={{ p: 3 }}>
       {/* 基本統計摘要 */}
       <Paper sx={{ p: 2, mb: 3 }}>
-        <Typography variant="h6" gutterBottom>
-          統計摘要
-        </Typography>
+        <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', mb: 1 }}>
+          <Typography variant="h6">
+            統計摘要
+          </Typography>
+          {(virtualHosts.length > 0 || servers.length > 0) && filePath && (
+            <Box sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+              {hostLoading && <CircularProgress size={20} />}
+              {servers.length > 0 && (
+                <FormControl size="small" sx={{ minWidth: 200 }}>
+                  <InputLabel id="dashboard-server-label">來源伺服器</InputLabel>
+                  <Select
+                    labelId="dashboard-server-label"
+                    label="來源伺服器"
+                    value={selectedServer}
+                    disabled={hostLoading}
+                    onChange={(e) => handleSelectServer(e.target.value as string)}
+                  >
+                    <MenuItem value="">全部伺服器</MenuItem>
+                    {servers.map((s) => (
+                      <MenuItem key={s.host} value={s.host}>
+                        {s.host}（{s.requestCount.toLocaleString()}）
+                      </MenuItem>
+                    ))}
+                  </Select>
+                </FormControl>
+              )}
+              {virtualHosts.length > 0 && (
+                <FormControl size="small" sx={{ minWidth: 240 }}>
+                  <InputLabel id="dashboard-host-label">虛擬主機</InputLabel>
+                  <Select
+                    labelId="dashboard-host-label"
+                    label="虛擬主機"
+                    value={selectedHost}
+                    disabled={hostLoading}
+                    onChange={(e) => handleSelectHost(e.target.value as string)}
+                  >
+                    <MenuItem value="">全部主機</MenuItem>
+                    {virtualHosts.map((h) => (
+                      <MenuItem key={h.host} value={h.host}>
+                        {h.host}（{h.requestCount.toLocaleString()}）
+                      </MenuItem>
+                    ))}
+                  </Select>
+                </FormControl>
+              )}
+            </Box>
+          )}
+        </Box>
+        {hostError && (
+          <Alert severity="error" sx={{ mb: 2 }}>
+            {hostError}
+          </Alert>
+        )}
         <Grid container spacing={2}>
           <Grid item xs={12} sm={6} md={3}>
             <Typography variant="body2" color="text.secondary">
               總請求數
             </Typography>
             <Typography variant="h5">
-              {statistics.totalRequests.toLocaleString()}
+              {shown.totalRequests.toLocaleString()}
             </Typography>
           </Grid>
           <Grid item xs={12} sm={6} md={3}>
@@ -100,7 +240,7 @@ function Dashboard({ statistics, statTime }: DashboardProps) {
               唯一 IP 數
             </Typography>
             <Typography variant="h5">
-              {statistics.uniqueIPs.toLocaleString()}
+              {shown.uniqueIPs.toLocaleString()}
             </Typography>
           </Grid>
           <Grid item xs={12} sm={6} md={3}>
@@ -108,7 +248,7 @@ function Dashboard({ statistics, statTime }: DashboardProps) {
               總流量
             </Typography>
             <Typography variant="h5">
-              {(statistics.totalBytes / (1024 * 1024)).toFixed(2)} MB
+              {(shown.totalBytes / (1024 * 1024)).toFixed(2)} MB
             </Typography>
           </Grid>
           <Grid item xs={12} sm={6} md={3}>
@@ -124,29 +264,84 @@ function Dashboard({ statistics, statTime }: DashboardProps) {
 
       {/* 統計圖表區域 */}
       <Grid container spacing={3}>
+        {/* 虛擬主機摘要（只在全部主機時顯示，點選可切換） */}
+        {!selectedHost && (
+          <Grid item xs={12}>
+            <VirtualHostSummary
+              virtualHosts={shown.virtualHosts}
+              onSelectHost={filePath ? handleSelectHost : undefined}
+            />
+          </Grid>
+        )}
+
+        {/* 來源伺服器摘要（多台伺服器且未選擇伺服器時顯示） */}
+        {!selectedServer && servers.length > 0 && (
+          <Grid item xs={12}>
+            <VirtualHostSummary
+              title="來源伺服器"
+              virtualHosts={shown.servers}
+              onSelectHost={filePath ? handleSelectServer : undefined}
+            />
+          </Grid>
+        )}
+
         {/* Top 10 IP */}
         <Grid item xs={12} md={6}>
-          <TopIPsList topIPs={statistics.topIPs} />
+          <TopIPsList topIPs={shown.topIPs} />
         </Grid>
 
         {/* Top 10 路徑 */}
         <Grid item xs={12} md={6}>
-          <TopPathsList topPaths={statistics.topPaths} />
+          <TopPathsList topPaths={shown.topPaths} />
         </Grid>
 
         {/* 狀態碼分布 */}
         <Grid item xs={12} md={6}>
-          <StatusCodeDistribution distribution={statistics.statusCodeDistribution} />
+          <StatusCodeDistribution distribution={shown.statusCodeDistribution} />
         </Grid>
 
         {/* 機器人偵測 */}
         <Grid item xs={12} md={6}>
           <BotDetection
-            botRequests={statistics.botStats.botRequests}
-            botPercentage={statistics.botStats.botPercentage}
-            topBots={statistics.botStats.topBots}
+            botRequests={shown.botStats.botRequests}
+            botPercentage={shown.botStats.botPercentage}
+            topBots={shown.botStats.topBots}
+            agents={shown.botStats.agents}
+            verification={shown.botStats.verification}
+            spoofedCrawlers={shown.botStats.spoofedCrawlers}
+            suspectedBots={shown.botStats.suspectedBots}
           />
         </Grid>
+
+        {/* 用戶端分布 */}
+        <Grid item xs={12}>
+          <UserAgentDistribution userAgents={shown.userAgents} />
+        </Grid>
+
+        {/* 流量來源 */}
+        <Grid item xs={12}>
+          <RefererAnalysis referers={shown.referers} />
+        </Grid>
+
+        {/* 失效連結 */}
+        <Grid item xs={12}>
+          <NotFoundReport notFound={shown.notFound} />
+        </Grid>
+
+        {/* 已認證使用者 */}
+        <Grid item xs={12}>
+          <UserActivity users={shown.users} filePath={filePath} />
+        </Grid>
+
+        {/* 快取成效 */}
+        <Grid item xs={12}>
+          <CacheAnalysis filePath={filePath} />
+        </Grid>
+
+        {/* 安全威脅偵測 */}
+        <Grid item xs={12}>
+          <ThreatDetection threats={shown.threats} />
+        </Grid>
       </Grid>
     </Box>
   )
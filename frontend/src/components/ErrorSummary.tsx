@@ -1,11 +1,15 @@
 // ErrorSummary 錯誤摘要組件
-// 顯示解析過程中的錯誤數量和樣本
+// 顯示解析過程中的錯誤數量和樣本，並可深入分析 HTTP 4xx/5xx 錯誤
 // 文件路徑: frontend/src/components/ErrorSummary.tsx
 
+import { useState } from 'react'
 import {
   Alert,
   AlertTitle,
   Box,
+  Button,
+  Chip,
+  CircularProgress,
   Typography,
   Accordion,
   AccordionSummary,
@@ -13,15 +17,248 @@ import {
   List,
   ListItem,
   ListItemText,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
 } from '@mui/material'
 import ExpandMoreIcon from '@mui/icons-material/ExpandMore'
 import ErrorOutlineIcon from '@mui/icons-material/ErrorOutline'
-import type { parser } from '../../wailsjs/wailsjs/go/models'
+import * as AppAPI from '../../wailsjs/wailsjs/go/app/App'
+import { app } from '../../wailsjs/wailsjs/go/models'
+import type { erroranalysis, parser } from '../../wailsjs/wailsjs/go/models'
 
 interface ErrorSummaryProps {
   errorCount: number
   errorSamples: parser.ParseError[]
   maxSamples?: number
+  filePath?: string  // 已載入的檔案路徑，提供時可深入分析 HTTP 錯誤
+}
+
+/**
+ * 呼叫錯誤分析 API，path 為空字串時分析全部路徑
+ */
+async function analyzeErrors(filePath: string, path: string): Promise<erroranalysis.Report> {
+  const response = await AppAPI.AnalyzeErrors(app.ErrorAnalysisRequest.createFrom({
+    filePath,
+    options: { path },
+  }))
+  if (!response.success || !response.report) {
+    throw new Error(response.errorMessage || '錯誤分析失敗')
+  }
+  return response.report
+}
+
+/**
+ * PathRanking - 路徑錯誤排名表，點選路徑可深入分析
+ */
+function PathRanking({ title, paths, onSelect }: {
+  title: string
+  paths: erroranalysis.PathErrors[] | null
+  onSelect: (path: string) => void
+}) {
+  return (
+    <Box sx={{ mb: 2 }}>
+      <Typography variant="subtitle2" gutterBottom>
+        {title}
+      </Typography>
+      {(!paths || paths.length === 0) ? (
+        <Typography variant="body2" color="text.secondary">
+          無資料
+        </Typography>
+      ) : (
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell>路徑</TableCell>
+              <TableCell align="right">請求數</TableCell>
+              <TableCell align="right">4xx</TableCell>
+              <TableCell align="right">5xx</TableCell>
+              <TableCell align="right">錯誤率</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {paths.map((p) => (
+              <TableRow key={p.path} hover sx={{ cursor: 'pointer' }} onClick={() => onSelect(p.path)}>
+                <TableCell sx={{ wordBreak: 'break-all' }}>{p.path}</TableCell>
+                <TableCell align="right">{p.requests.toLocaleString()}</TableCell>
+                <TableCell align="right">{p.clientErrors.toLocaleString()}</TableCell>
+                <TableCell align="right">{p.serverErrors.toLocaleString()}</TableCell>
+                <TableCell align="right">{p.errorRate.toFixed(1)}%</TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      )}
+    </Box>
+  )
+}
+
+/**
+ * ErrorDetails - 錯誤爆發區段、時間軸與各狀態碼的記錄樣本
+ */
+function ErrorDetails({ report }: { report: erroranalysis.Report }) {
+  const timeline = report.byCount?.[0]?.timeline ?? []
+  const peak = Math.max(1, ...timeline.map((point) => point.clientErrors + point.serverErrors))
+
+  return (
+    <Box>
+      {report.options.path && timeline.length > 0 && (
+        <Box sx={{ mb: 2 }}>
+          <Typography variant="subtitle2" gutterBottom>
+            錯誤時間軸（每 {report.options.bucket}）
+          </Typography>
+          <Box sx={{ display: 'flex', alignItems: 'flex-end', gap: '1px', height: 60 }}>
+            {timeline.map((point) => (
+              <Box
+                key={String(point.start)}
+                title={`${new Date(point.start).toLocaleString()}：4xx ${point.clientErrors}、5xx ${point.serverErrors}／${point.requests} 次請求`}
+                sx={{ flex: 1, display: 'flex', flexDirection: 'column', justifyContent: 'flex-end', height: '100%' }}
+              >
+                <Box sx={{ bgcolor: 'error.main', height: `${(point.serverErrors / peak) * 100}%` }} />
+                <Box sx={{ bgcolor: 'warning.main', height: `${(point.clientErrors / peak) * 100}%` }} />
+              </Box>
+            ))}
+          </Box>
+        </Box>
+      )}
+
+      {(report.bursts?.length ?? 0) > 0 && (
+        <Box sx={{ mb: 2 }}>
+          <Typography variant="subtitle2" gutterBottom>
+            連續錯誤爆發
+          </Typography>
+          <Table size="small">
+            <TableHead>
+              <TableRow>
+                <TableCell>開始</TableCell>
+                <TableCell>結束</TableCell>
+                <TableCell align="right">錯誤數</TableCell>
+                <TableCell>行號</TableCell>
+                <TableCell>主要路徑</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {report.bursts.map((burst) => (
+                <TableRow key={`${burst.firstLine}-${burst.lastLine}`}>
+                  <TableCell>{new Date(burst.start).toLocaleString()}</TableCell>
+                  <TableCell>{new Date(burst.end).toLocaleString()}</TableCell>
+                  <TableCell align="right">{burst.count.toLocaleString()}</TableCell>
+                  <TableCell>{burst.firstLine}–{burst.lastLine}</TableCell>
+                  <TableCell sx={{ wordBreak: 'break-all' }}>
+                    {(burst.topPaths ?? []).map((p) => `${p.path} (${p.count})`).join('、')}
+                  </TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </Box>
+      )}
+
+      <Typography variant="subtitle2" gutterBottom>
+        記錄樣本
+      </Typography>
+      {(report.samples ?? []).map((sample) => (
+        <Box key={sample.statusCode} sx={{ mb: 1 }}>
+          <Chip
+            size="small"
+            color={sample.statusCode >= 500 ? 'error' : 'warning'}
+            label={`${sample.statusCode}（${sample.count.toLocaleString()} 次）`}
+            sx={{ mb: 0.5 }}
+          />
+          {(sample.entries ?? []).map((entry) => (
+            <Typography
+              key={entry.lineNumber}
+              variant="body2"
+              component="pre"
+              sx={{ fontFamily: 'monospace', fontSize: '0.8rem', whiteSpace: 'pre-wrap', wordBreak: 'break-all', m: 0 }}
+            >
+              第 {entry.lineNumber} 行：{entry.rawLine || `${entry.method} ${entry.url}`}
+            </Typography>
+          ))}
+        </Box>
+      ))}
+    </Box>
+  )
+}
+
+/**
+ * HttpErrorAnalysis - HTTP 4xx/5xx 錯誤分析，可從路徑排名深入單一路徑
+ */
+function HttpErrorAnalysis({ filePath }: { filePath: string }) {
+  const [report, setReport] = useState<erroranalysis.Report | null>(null)
+  const [detail, setDetail] = useState<erroranalysis.Report | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = async (path: string) => {
+    setLoading(true)
+    setError(null)
+    try {
+      const result = await analyzeErrors(filePath, path)
+      if (path) {
+        setDetail(result)
+      } else {
+        setReport(result)
+        setDetail(null)
+      }
+    } catch (err) {
+      setError(err instanceof Error ? err.message : String(err))
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <Accordion>
+      <AccordionSummary expandIcon={<ExpandMoreIcon />} aria-controls="http-error-content" id="http-error-header">
+        <Box sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+          <ErrorOutlineIcon color="error" />
+          <Typography variant="h6">HTTP 錯誤分析</Typography>
+        </Box>
+      </AccordionSummary>
+      <AccordionDetails>
+        {!report && (
+          <Button variant="outlined" onClick={() => load('')} disabled={loading}>
+            分析 4xx/5xx 錯誤
+          </Button>
+        )}
+        {loading && <CircularProgress size={24} sx={{ ml: 2 }} />}
+        {error && <Alert severity="error" sx={{ mt: 1 }}>{error}</Alert>}
+
+        {report && !detail && (
+          <Box>
+            <Typography variant="body2" color="text.secondary" gutterBottom>
+              {report.totalRequests.toLocaleString()} 次請求中有 4xx {report.clientErrors.toLocaleString()} 次、
+              5xx {report.serverErrors.toLocaleString()} 次；點選路徑查看時間軸與樣本
+            </Typography>
+            <PathRanking title="錯誤次數最多" paths={report.byCount} onSelect={load} />
+            <PathRanking
+              title={`錯誤率最高（至少 ${report.options.minRequests} 次請求）`}
+              paths={report.byRate}
+              onSelect={load}
+            />
+            <ErrorDetails report={report} />
+          </Box>
+        )}
+
+        {detail && (
+          <Box>
+            <Box sx={{ display: 'flex', alignItems: 'center', gap: 1, mb: 2 }}>
+              <Button size="small" onClick={() => setDetail(null)}>
+                返回排名
+              </Button>
+              <Typography variant="subtitle1" sx={{ wordBreak: 'break-all' }}>
+                {detail.options.path}
+              </Typography>
+            </Box>
+            <ErrorDetails report={detail} />
+          </Box>
+        )}
+      </AccordionDetails>
+    </Accordion>
+  )
 }
 
 /**
@@ -31,15 +268,21 @@ interface ErrorSummaryProps {
  * @param errorCount - 錯誤總數
  * @param errorSamples - 錯誤樣本陣列（原始錯誤行）
  * @param maxSamples - 最多顯示的樣本數量（預設：10）
+ * @param filePath - 已載入的檔案路徑，提供時顯示 HTTP 錯誤分析
  */
 export default function ErrorSummary({
   errorCount,
   errorSamples,
   maxSamples = 10,
+  filePath,
 }: ErrorSummaryProps) {
-  // 如果沒有錯誤，不顯示組件
+  // 如果沒有解析錯誤，只顯示 HTTP 錯誤分析
   if (errorCount === 0) {
-    return null
+    return filePath ? (
+      <Box sx={{ mb: 2 }}>
+        <HttpErrorAnalysis filePath={filePath} />
+      </Box>
+    ) : null
   }
 
   // 取得要顯示的錯誤樣本
@@ -127,6 +370,7 @@ export default function ErrorSummary({
           </Alert>
         </AccordionDetails>
       </Accordion>
+      {filePath && <HttpErrorAnalysis filePath={filePath} />}
     </Box>
   )
 }
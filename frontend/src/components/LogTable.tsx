@@ -1,11 +1,13 @@
 // LogTable 虛擬化表格組件
-// 使用 ag-Grid 顯示日誌記錄，支援虛擬化滾動
+// 使用 ag-Grid 無限捲動模式顯示日誌記錄，資料由後端 GetEntries 分頁提供
 // 文件路徑: frontend/src/components/LogTable.tsx
 
-import { useMemo } from 'react'
+import { useMemo, useRef } from 'react'
 import { AgGridReact } from 'ag-grid-react'
-import { ColDef } from 'ag-grid-community'
+import { ColDef, IDatasource, IGetRowsParams } from 'ag-grid-community'
 import { Box } from '@mui/material'
+import * as AppAPI from '../../wailsjs/wailsjs/go/app/App'
+import { filter } from '../../wailsjs/wailsjs/go/models'
 import type { models } from '../../wailsjs/wailsjs/go/models'
 import 'ag-grid-community/styles/ag-grid.css'
 import 'ag-grid-community/styles/ag-theme-material.css'
@@ -13,19 +15,32 @@ import 'ag-grid-community/styles/ag-theme-material.css'
 // 類型別名便於使用
 type LogEntry = models.LogEntry
 
+// 每次向後端請求的記錄筆數
+const BLOCK_SIZE = 200
+
 interface LogTableProps {
-  entries: models.LogEntry[]
+  filePath: string
+  criteria: filter.FilterCriteria
+  onMatchedChange?: (matched: number) => void
   height?: string | number
 }
 
 /**
  * LogTable 組件
- * 使用 ag-Grid 顯示日誌記錄，支援虛擬化滾動以處理大量數據
+ * 使用 ag-Grid 無限捲動模式顯示日誌記錄，捲動時才向後端請求當頁資料，
+ * 排序由後端處理以支援大量數據
  * 
- * @param entries - 日誌記錄陣列
+ * @param filePath - 已載入的 log 檔案路徑
+ * @param criteria - 篩選與搜尋條件
+ * @param onMatchedChange - 符合條件的記錄數更新時的回調
  * @param height - 表格高度（預設：'calc(100vh - 250px)'）
  */
-export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: LogTableProps) {
+export default function LogTable({
+  filePath,
+  criteria,
+  onMatchedChange,
+  height = 'calc(100vh - 250px)'
+}: LogTableProps) {
   // 定義表格欄位
   const columnDefs = useMemo<ColDef<LogEntry>[]>(() => [
     {
@@ -33,7 +48,6 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: '行號',
       width: 100,
       sortable: true,
-      filter: 'agNumberColumnFilter',
       pinned: 'left',
     },
     {
@@ -41,14 +55,12 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: 'IP 位址',
       width: 150,
       sortable: true,
-      filter: 'agTextColumnFilter',
     },
     {
       field: 'timestamp',
       headerName: '時間戳',
       width: 200,
       sortable: true,
-      filter: 'agDateColumnFilter',
       valueFormatter: (params) => {
         if (!params.value) return ''
         const date = new Date(params.value)
@@ -67,7 +79,6 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: 'HTTP 方法',
       width: 120,
       sortable: true,
-      filter: 'agTextColumnFilter',
       cellStyle: (params) => {
         // 根據 HTTP 方法著色
         const colors: { [key: string]: string } = {
@@ -86,7 +97,6 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: 'URL 路徑',
       width: 300,
       sortable: true,
-      filter: 'agTextColumnFilter',
       flex: 1, // 自動調整寬度
     },
     {
@@ -94,14 +104,12 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: '協定',
       width: 120,
       sortable: true,
-      filter: 'agTextColumnFilter',
     },
     {
       field: 'statusCode',
       headerName: '狀態碼',
       width: 120,
       sortable: true,
-      filter: 'agNumberColumnFilter',
       cellStyle: (params) => {
         // 根據狀態碼著色
         const code = params.value as number
@@ -129,7 +137,6 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: '回應大小',
       width: 130,
       sortable: true,
-      filter: 'agNumberColumnFilter',
       valueFormatter: (params) => {
         if (params.value === undefined || params.value === null) return ''
         const bytes = params.value as number
@@ -149,24 +156,57 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       headerName: 'Referer',
       width: 200,
       sortable: true,
-      filter: 'agTextColumnFilter',
     },
     {
       field: 'userAgent',
       headerName: 'User Agent',
       width: 300,
       sortable: true,
-      filter: 'agTextColumnFilter',
     },
   ], [])
 
-  // 預設欄位設定
+  // 預設欄位設定（篩選由 FilterPanel 與 SearchBar 透過後端處理）
   const defaultColDef = useMemo<ColDef>(() => ({
     resizable: true,
     sortable: true,
-    filter: true,
+    filter: false,
   }), [])
 
+  // 以 ref 保存回調，避免父元件重新渲染時重建資料來源導致表格重新載入
+  const onMatchedChangeRef = useRef(onMatchedChange)
+  onMatchedChangeRef.current = onMatchedChange
+
+  // 後端資料來源：條件變更時重建，表格會自動清除快取並重新載入
+  const datasource = useMemo<IDatasource>(() => ({
+    getRows: async (params: IGetRowsParams) => {
+      const sortModel = params.sortModel[0]
+      const sortSpec = filter.SortSpec.createFrom({
+        field: sortModel?.colId || '',
+        descending: sortModel?.sort === 'desc'
+      })
+
+      try {
+        const response = await AppAPI.GetEntries(
+          filePath,
+          params.startRow,
+          params.endRow - params.startRow,
+          sortSpec,
+          criteria
+        )
+
+        if (!response.success) {
+          throw new Error(response.errorMessage || '取得記錄失敗')
+        }
+
+        onMatchedChangeRef.current?.(response.matched)
+        params.successCallback(response.entries || [], response.matched)
+      } catch (err) {
+        console.error('取得記錄失敗:', err)
+        params.failCallback()
+      }
+    }
+  }), [filePath, criteria])
+
   return (
     <Box
       className="ag-theme-material"
@@ -176,18 +216,18 @@ export default function LogTable({ entries, height = 'calc(100vh - 250px)' }: Lo
       }}
     >
       <AgGridReact<LogEntry>
-        rowData={entries}
+        rowModelType="infinite"
+        datasource={datasource}
+        cacheBlockSize={BLOCK_SIZE}
+        maxBlocksInCache={50}
         columnDefs={columnDefs}
         defaultColDef={defaultColDef}
         rowSelection="multiple"
-        animateRows={true}
         enableCellTextSelection={true}
         ensureDomOrder={true}
         // 啟用虛擬化以處理大量數據
         rowBuffer={10}
         suppressColumnVirtualisation={false}
-        // 分頁設定（可選）
-        pagination={false}
         // 效能優化
         suppressRowHoverHighlight={false}
         suppressCellFocus={false}
@@ -0,0 +1,128 @@
+// NotFoundReport 元件 - 顯示失效連結（404/410）報表
+// 文件路徑: frontend/src/components/NotFoundReport.tsx
+// 用途: 找出被連結但已不存在的路徑與其來源
+
+import {
+  Chip,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+  Tooltip,
+  Typography,
+} from '@mui/material'
+import { DistributionItem } from './UserAgentDistribution'
+
+// 匹配 Go internal/stats/not_found.go 的 NotFoundPath 結構
+export interface NotFoundPath {
+  path: string
+  count: number
+  goneCount: number
+  firstSeen: string
+  lastSeen: string
+  botHits: number
+  humanHits: number
+  internalReferers: DistributionItem[] | null
+  externalReferers: DistributionItem[] | null
+  redirectCount: number
+  lastRedirect: string
+  redirectedFrom: DistributionItem[] | null
+}
+
+// 匹配 Go internal/stats/not_found.go 的 NotFoundReport 結構
+export interface NotFoundReportData {
+  totalRequests: number
+  botRequests: number
+  uniquePaths: number
+  paths: NotFoundPath[] | null
+}
+
+interface NotFoundReportProps {
+  notFound?: NotFoundReportData
+}
+
+// 顯示的路徑數上限（完整清單請匯出 Excel）
+const MAX_ROWS = 20
+
+/**
+ * 將來源清單合併為提示文字
+ */
+function formatSources(items: DistributionItem[] | null): string {
+  return (items ?? []).map((item) => `${item.name} (${item.count})`).join('\n')
+}
+
+/**
+ * NotFoundReport 元件 - 顯示 404/410 次數最多的路徑
+ *
+ * @param notFound - 失效連結報表
+ */
+function NotFoundReport({ notFound }: NotFoundReportProps) {
+  if (!notFound || notFound.totalRequests === 0) {
+    return null
+  }
+
+  const paths = (notFound.paths ?? []).slice(0, MAX_ROWS)
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Typography variant="h6" gutterBottom>
+        失效連結
+      </Typography>
+      <Typography variant="body2" color="text.secondary" gutterBottom>
+        共 {notFound.uniquePaths.toLocaleString()} 個路徑、{notFound.totalRequests.toLocaleString()} 次請求
+        （機器人 {notFound.botRequests.toLocaleString()} 次）
+      </Typography>
+      <Table size="small">
+        <TableHead>
+          <TableRow>
+            <TableCell>路徑</TableCell>
+            <TableCell align="right">次數</TableCell>
+            <TableCell align="right">機器人 / 一般用戶</TableCell>
+            <TableCell>來源</TableCell>
+            <TableCell>最後出現</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {paths.map((p) => {
+            const internal = p.internalReferers ?? []
+            const external = p.externalReferers ?? []
+            return (
+              <TableRow key={p.path}>
+                <TableCell sx={{ wordBreak: 'break-all' }}>
+                  {p.path}
+                  {p.goneCount > 0 && <Chip size="small" label="410" sx={{ ml: 1 }} />}
+                  {p.redirectCount > 0 && (
+                    <Tooltip title={`曾轉址 ${p.redirectCount} 次`}>
+                      <Chip size="small" color="warning" label="曾轉址" sx={{ ml: 1 }} />
+                    </Tooltip>
+                  )}
+                </TableCell>
+                <TableCell align="right">{p.count.toLocaleString()}</TableCell>
+                <TableCell align="right">
+                  {p.botHits.toLocaleString()} / {p.humanHits.toLocaleString()}
+                </TableCell>
+                <TableCell>
+                  {internal.length > 0 && (
+                    <Tooltip title={<span style={{ whiteSpace: 'pre-line' }}>{formatSources(internal)}</span>}>
+                      <Chip size="small" color="error" label={`站內 ${internal.length}`} sx={{ mr: 1 }} />
+                    </Tooltip>
+                  )}
+                  {external.length > 0 && (
+                    <Tooltip title={<span style={{ whiteSpace: 'pre-line' }}>{formatSources(external)}</span>}>
+                      <Chip size="small" label={`外部 ${external.length}`} />
+                    </Tooltip>
+                  )}
+                </TableCell>
+                <TableCell>{new Date(p.lastSeen).toLocaleString()}</TableCell>
+              </TableRow>
+            )
+          })}
+        </TableBody>
+      </Table>
+    </Paper>
+  )
+}
+
+export default NotFoundReport
@@ -0,0 +1,219 @@
+// RefererAnalysis 元件 - 顯示流量來源、外部網域、行銷活動與盜連
+// 文件路徑: frontend/src/components/RefererAnalysis.tsx
+// 用途: Referer 與 utm 參數的分析結果
+
+import {
+  Box,
+  Chip,
+  Grid,
+  LinearProgress,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+  Typography,
+} from '@mui/material'
+import { DistributionItem } from './UserAgentDistribution'
+
+// 匹配 Go internal/stats/referers.go 的 RefererDomain 結構
+export interface RefererDomain {
+  domain: string     // 來源網域
+  channel: string    // 管道：search、social、email 或 referral
+  count: number      // 請求次數
+  percentage: number // 佔外部來源請求的百分比
+}
+
+// 匹配 Go internal/stats/referers.go 的 CampaignStatistics 結構
+export interface CampaignStatistics {
+  source: string
+  medium: string
+  campaign: string
+  count: number
+  uniqueIPs: number
+}
+
+// 匹配 Go internal/stats/referers.go 的 HotlinkStatistics 結構
+export interface HotlinkStatistics {
+  domain: string
+  path: string
+  count: number
+  bytes: number
+}
+
+// 匹配 Go internal/stats/referers.go 的 RefererStatistics 結構
+export interface RefererStatistics {
+  direct: number
+  internal: number
+  external: number
+  channels: DistributionItem[] | null
+  domains: RefererDomain[] | null
+  searchEngines: DistributionItem[] | null
+  campaigns: CampaignStatistics[] | null
+  hotlinks: HotlinkStatistics[] | null
+  hotlinkRequests: number
+  hotlinkBytes: number
+  siteHosts: string[] | null
+  siteHostsSource: string
+}
+
+interface RefererAnalysisProps {
+  referers?: RefererStatistics
+}
+
+// 管道的顯示名稱
+const channelLabels: Record<string, string> = {
+  direct: '直接造訪',
+  internal: '站內',
+  search: '搜尋引擎',
+  social: '社群網站',
+  email: '電子郵件',
+  referral: '其他網站',
+}
+
+// 本站主機來源的說明
+const siteHostsSourceLabels: Record<string, string> = {
+  configured: '使用者設定',
+  vhost: '由虛擬主機推斷',
+  referer: '由最常見的來源網域推斷',
+  none: '無法判斷本站主機，不區分站內外、不偵測盜連',
+}
+
+/**
+ * RefererAnalysis 元件 - 顯示流量來源分析
+ *
+ * @param referers - 流量來源分析結果
+ */
+function RefererAnalysis({ referers }: RefererAnalysisProps) {
+  if (!referers) {
+    return null
+  }
+
+  const domains = referers.domains ?? []
+  const campaigns = referers.campaigns ?? []
+  const hotlinks = referers.hotlinks ?? []
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Typography variant="h6" gutterBottom>
+        流量來源
+      </Typography>
+      {referers.siteHostsSource && (
+        <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+          本站主機：{(referers.siteHosts ?? []).join(', ') || '—'}（
+          {siteHostsSourceLabels[referers.siteHostsSource] ?? referers.siteHostsSource}）
+        </Typography>
+      )}
+      <Grid container spacing={3}>
+        <Grid item xs={12} md={4}>
+          <Typography variant="subtitle2" gutterBottom>
+            管道
+          </Typography>
+          {(referers.channels ?? []).map((item) => (
+            <Box key={item.name} sx={{ mb: 1 }}>
+              <Box sx={{ display: 'flex', justifyContent: 'space-between' }}>
+                <Typography variant="body2">{channelLabels[item.name] ?? item.name}</Typography>
+                <Typography variant="body2" color="text.secondary">
+                  {item.count.toLocaleString()}（{item.percentage.toFixed(1)}%）
+                </Typography>
+              </Box>
+              <LinearProgress variant="determinate" value={item.percentage} />
+            </Box>
+          ))}
+        </Grid>
+
+        <Grid item xs={12} md={8}>
+          <Typography variant="subtitle2" gutterBottom>
+            外部來源網域
+          </Typography>
+          {domains.length === 0 ? (
+            <Typography variant="body2" color="text.secondary">
+              無外部來源
+            </Typography>
+          ) : (
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>網域</TableCell>
+                  <TableCell>管道</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                  <TableCell align="right">佔外部來源</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {domains.map((domain) => (
+                  <TableRow key={domain.domain}>
+                    <TableCell>{domain.domain}</TableCell>
+                    <TableCell>
+                      <Chip size="small" label={channelLabels[domain.channel] ?? domain.channel} />
+                    </TableCell>
+                    <TableCell align="right">{domain.count.toLocaleString()}</TableCell>
+                    <TableCell align="right">{domain.percentage.toFixed(1)}%</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          )}
+        </Grid>
+
+        {campaigns.length > 0 && (
+          <Grid item xs={12} md={6}>
+            <Typography variant="subtitle2" gutterBottom>
+              行銷活動（utm）
+            </Typography>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>來源 / 媒介</TableCell>
+                  <TableCell>活動</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                  <TableCell align="right">IP 數</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {campaigns.map((campaign) => (
+                  <TableRow key={`${campaign.source}/${campaign.medium}/${campaign.campaign}`}>
+                    <TableCell>{campaign.source} / {campaign.medium}</TableCell>
+                    <TableCell>{campaign.campaign}</TableCell>
+                    <TableCell align="right">{campaign.count.toLocaleString()}</TableCell>
+                    <TableCell align="right">{campaign.uniqueIPs.toLocaleString()}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </Grid>
+        )}
+
+        {hotlinks.length > 0 && (
+          <Grid item xs={12} md={6}>
+            <Typography variant="subtitle2" gutterBottom>
+              盜連（共 {referers.hotlinkRequests.toLocaleString()} 次、
+              {(referers.hotlinkBytes / 1024 / 1024).toFixed(2)} MB）
+            </Typography>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>來源網域</TableCell>
+                  <TableCell>資源路徑</TableCell>
+                  <TableCell align="right">請求次數</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {hotlinks.map((hotlink) => (
+                  <TableRow key={`${hotlink.domain}${hotlink.path}`}>
+                    <TableCell>{hotlink.domain}</TableCell>
+                    <TableCell sx={{ wordBreak: 'break-all' }}>{hotlink.path}</TableCell>
+                    <TableCell align="right">{hotlink.count.toLocaleString()}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </Grid>
+        )}
+      </Grid>
+    </Paper>
+  )
+}
+
+export default RefererAnalysis
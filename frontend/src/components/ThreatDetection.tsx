@@ -0,0 +1,229 @@
+// ThreatDetection 元件 - 顯示請求內容中的攻擊特徵偵測結果
+// 文件路徑: frontend/src/components/ThreatDetection.tsx
+// 用途: 安全威脅偵測（SQL 注入、XSS、路徑穿越、Log4Shell、敏感檔案探測等）
+
+import { useState } from 'react'
+import {
+  Paper,
+  Typography,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Box,
+  Chip,
+  Alert,
+  Collapse,
+  IconButton,
+} from '@mui/material'
+import SecurityIcon from '@mui/icons-material/Security'
+import KeyboardArrowDownIcon from '@mui/icons-material/KeyboardArrowDown'
+import KeyboardArrowUpIcon from '@mui/icons-material/KeyboardArrowUp'
+
+// 命中規則的範例請求（對應 Go security.ThreatExample）
+export interface ThreatExample {
+  lineNumber: number
+  ip: string
+  timestamp: string
+  method: string
+  url: string
+  statusCode: number
+  successful: boolean
+  rawLine: string
+}
+
+// 單一規則的命中統計（對應 Go security.ThreatRuleStat）
+export interface ThreatRuleStat {
+  id: string
+  name: string
+  category: string
+  severity: string
+  pathOnly: boolean
+  hits: number
+  successful: number
+  distinctIPs: number
+  examples: ThreatExample[] | null
+}
+
+// 單一攻擊來源 IP（對應 Go security.AttackerStat）
+export interface AttackerStat {
+  ip: string
+  hits: number
+  successful: number
+  categories: string[] | null
+  firstSeen: string
+  lastSeen: string
+}
+
+// 攻擊偵測統計（對應 Go security.ThreatStats）
+export interface ThreatStats {
+  attackRequests: number
+  successfulRequests: number
+  attackerCount: number
+  categories: Record<string, number> | null
+  rules: ThreatRuleStat[] | null
+  attackers: AttackerStat[] | null
+}
+
+interface ThreatDetectionProps {
+  threats?: ThreatStats
+}
+
+// 嚴重程度的顯示名稱與顏色
+const severityLabels: Record<string, { label: string; color: 'error' | 'warning' | 'info' }> = {
+  critical: { label: '嚴重', color: 'error' },
+  high: { label: '高', color: 'warning' },
+  medium: { label: '中', color: 'info' },
+}
+
+/**
+ * 單一規則的列，可展開顯示範例請求
+ */
+function RuleRow({ rule }: { rule: ThreatRuleStat }) {
+  const [open, setOpen] = useState(false)
+  const severity = severityLabels[rule.severity] ?? { label: rule.severity, color: 'info' as const }
+  const examples = rule.examples ?? []
+
+  return (
+    <>
+      <TableRow hover>
+        <TableCell padding="checkbox">
+          <IconButton size="small" onClick={() => setOpen(!open)} disabled={examples.length === 0}>
+            {open ? <KeyboardArrowUpIcon /> : <KeyboardArrowDownIcon />}
+          </IconButton>
+        </TableCell>
+        <TableCell>
+          <Typography variant="body2">{rule.name}</Typography>
+          <Typography variant="caption" color="text.secondary">
+            {rule.category}
+          </Typography>
+        </TableCell>
+        <TableCell>
+          <Chip size="small" label={severity.label} color={severity.color} />
+        </TableCell>
+        <TableCell align="right">{rule.hits.toLocaleString()}</TableCell>
+        <TableCell align="right">
+          <Typography variant="body2" color={rule.successful > 0 ? 'error' : 'text.primary'}>
+            {rule.successful.toLocaleString()}
+          </Typography>
+        </TableCell>
+        <TableCell align="right">{rule.distinctIPs.toLocaleString()}</TableCell>
+      </TableRow>
+      <TableRow>
+        <TableCell colSpan={6} sx={{ py: 0, borderBottom: open ? undefined : 'none' }}>
+          <Collapse in={open} timeout="auto" unmountOnExit>
+            <Box sx={{ my: 1 }}>
+              {examples.map((example) => (
+                <Typography
+                  key={`${example.lineNumber}-${example.ip}`}
+                  variant="caption"
+                  display="block"
+                  sx={{ fontFamily: 'monospace', wordBreak: 'break-all', color: example.successful ? 'error.main' : 'text.secondary' }}
+                >
+                  #{example.lineNumber} {example.ip} {example.method} {example.url} → {example.statusCode}
+                </Typography>
+              ))}
+            </Box>
+          </Collapse>
+        </TableCell>
+      </TableRow>
+    </>
+  )
+}
+
+/**
+ * ThreatDetection 元件 - 顯示攻擊特徵的命中規則與攻擊來源
+ *
+ * @param threats - 攻擊偵測統計
+ */
+function ThreatDetection({ threats }: ThreatDetectionProps) {
+  const rules = threats?.rules ?? []
+  const attackers = threats?.attackers ?? []
+  const attackRequests = threats?.attackRequests ?? 0
+  const successfulRequests = threats?.successfulRequests ?? 0
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Box sx={{ display: 'flex', alignItems: 'center', gap: 1, mb: 1 }}>
+        <SecurityIcon color={attackRequests > 0 ? 'error' : 'action'} />
+        <Typography variant="h6">安全威脅偵測</Typography>
+      </Box>
+
+      {attackRequests === 0 ? (
+        <Typography variant="body2" color="text.secondary" sx={{ textAlign: 'center', mt: 2 }}>
+          未偵測到攻擊特徵
+        </Typography>
+      ) : (
+        <>
+          <Alert severity={successfulRequests > 0 ? 'error' : 'warning'} sx={{ mb: 2 }}>
+            偵測到 {attackRequests.toLocaleString()} 個攻擊請求，來自 {(threats?.attackerCount ?? 0).toLocaleString()} 個 IP
+            {successfulRequests > 0 && `，其中 ${successfulRequests.toLocaleString()} 個請求伺服器回應 2xx，請確認是否成功`}
+          </Alert>
+
+          <Typography variant="subtitle2" gutterBottom>
+            命中規則
+          </Typography>
+          <TableContainer sx={{ maxHeight: 400 }}>
+            <Table size="small" stickyHeader>
+              <TableHead>
+                <TableRow>
+                  <TableCell padding="checkbox" />
+                  <TableCell>規則</TableCell>
+                  <TableCell>嚴重程度</TableCell>
+                  <TableCell align="right">命中次數</TableCell>
+                  <TableCell align="right">2xx 回應</TableCell>
+                  <TableCell align="right">IP 數</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {rules.map((rule) => (
+                  <RuleRow key={rule.id} rule={rule} />
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+
+          {attackers.length > 0 && (
+            <>
+              <Typography variant="subtitle2" gutterBottom sx={{ mt: 3 }}>
+                攻擊來源
+              </Typography>
+              <TableContainer sx={{ maxHeight: 360 }}>
+                <Table size="small" stickyHeader>
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>IP</TableCell>
+                      <TableCell>攻擊類別</TableCell>
+                      <TableCell align="right">攻擊請求</TableCell>
+                      <TableCell align="right">2xx 回應</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {attackers.map((attacker) => (
+                      <TableRow key={attacker.ip} hover>
+                        <TableCell sx={{ fontFamily: 'monospace' }}>{attacker.ip}</TableCell>
+                        <TableCell>
+                          <Box sx={{ display: 'flex', flexWrap: 'wrap', gap: 0.5 }}>
+                            {(attacker.categories ?? []).map((category) => (
+                              <Chip key={category} size="small" variant="outlined" label={category} />
+                            ))}
+                          </Box>
+                        </TableCell>
+                        <TableCell align="right">{attacker.hits.toLocaleString()}</TableCell>
+                        <TableCell align="right">{attacker.successful.toLocaleString()}</TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </TableContainer>
+            </>
+          )}
+        </>
+      )}
+    </Paper>
+  )
+}
+
+export default ThreatDetection
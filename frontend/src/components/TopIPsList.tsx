@@ -3,6 +3,7 @@
 // 用途: User Story 2 - Top 10 IP 列表（T073）
 
 import {
+  Chip,
   Paper,
   Typography,
   Table,
@@ -18,6 +19,11 @@ interface IPStatistics {
   ip: string           // IP 位址
   requestCount: number // 請求次數
   totalBytes: number   // 總傳輸量（位元組）
+  country?: string      // 國家代碼（需載入 GeoIP 資料庫）
+  city?: string         // 城市
+  asn?: number          // 自治系統編號
+  organization?: string // ASN 所屬組織
+  group?: string        // 所屬的具名 CIDR 群組
 }
 
 interface TopIPsListProps {
@@ -40,6 +46,9 @@ function TopIPsList({ topIPs }: TopIPsListProps) {
     )
   }
 
+  // 載入 GeoIP 資料庫時才顯示地理欄位
+  const hasGeo = topIPs.some((ip) => ip.country || ip.asn)
+
   return (
     <Paper sx={{ p: 2 }}>
       <Typography variant="h6" gutterBottom>
@@ -51,6 +60,8 @@ function TopIPsList({ topIPs }: TopIPsListProps) {
             <TableRow>
               <TableCell>排名</TableCell>
               <TableCell>IP 位址</TableCell>
+              {hasGeo && <TableCell>位置</TableCell>}
+              {hasGeo && <TableCell>ASN</TableCell>}
               <TableCell align="right">請求次數</TableCell>
               <TableCell align="right">流量 (MB)</TableCell>
             </TableRow>
@@ -62,8 +73,21 @@ function TopIPsList({ topIPs }: TopIPsListProps) {
                 <TableCell>
                   <Typography variant="body2" sx={{ fontFamily: 'monospace' }}>
                     {ip.ip}
+                    {ip.group && (
+                      <Chip label={ip.group} size="small" sx={{ ml: 1 }} />
+                    )}
                   </Typography>
                 </TableCell>
+                {hasGeo && (
+                  <TableCell>
+                    {[ip.country, ip.city].filter(Boolean).join(' / ') || '-'}
+                  </TableCell>
+                )}
+                {hasGeo && (
+                  <TableCell>
+                    {ip.asn ? `AS${ip.asn} ${ip.organization ?? ''}` : '-'}
+                  </TableCell>
+                )}
                 <TableCell align="right">
                   {ip.requestCount.toLocaleString()}
                 </TableCell>
@@ -0,0 +1,239 @@
+// UserActivity 元件 - 顯示已認證使用者（%u）的活動
+// 文件路徑: frontend/src/components/UserActivity.tsx
+// 用途: 內網日誌依使用者檢視請求、錯誤、使用時段與連線來源，並可展開單一使用者的活動時間軸
+
+import { useState } from 'react'
+import {
+  Alert,
+  Box,
+  Chip,
+  CircularProgress,
+  MenuItem,
+  Paper,
+  Select,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+  Tooltip,
+  Typography,
+} from '@mui/material'
+import * as AppAPI from '../../wailsjs/wailsjs/go/app/App'
+import { app } from '../../wailsjs/wailsjs/go/models'
+import type { stats } from '../../wailsjs/wailsjs/go/models'
+import { DistributionItem } from './UserAgentDistribution'
+
+// 匹配 Go internal/stats/users.go 的 UserActivity 結構
+export interface UserActivityStat {
+  user: string
+  requestCount: number
+  uniquePaths: number
+  errorCount: number
+  errorRate: number
+  activeHours: number[]
+  firstSeen: string
+  lastSeen: string
+  uniqueIPs: number
+  ips: DistributionItem[] | null
+  countries?: string[] | null
+  manyIPs: boolean
+  manyCountries: boolean
+}
+
+// 匹配 Go internal/stats/users.go 的 UserStatistics 結構
+export interface UserStatistics {
+  authenticatedRequests: number
+  uniqueUsers: number
+  topUsers: UserActivityStat[] | null
+}
+
+interface UserActivityProps {
+  users?: UserStatistics
+  filePath?: string  // 已載入的檔案路徑，提供時可展開單一使用者的時間軸
+}
+
+// 時間軸可選的區間長度
+const BUCKETS = ['1h', '1d']
+
+/**
+ * ActiveHours - 以 24 格長條顯示一天中各小時的請求數
+ */
+function ActiveHours({ hours }: { hours: number[] }) {
+  const peak = Math.max(1, ...hours)
+  return (
+    <Box sx={{ display: 'flex', alignItems: 'flex-end', height: 24, gap: '1px' }}>
+      {hours.map((count, hour) => (
+        <Tooltip key={hour} title={`${hour}:00 - ${count} 次`}>
+          <Box
+            sx={{
+              width: 4,
+              height: `${Math.max(count > 0 ? 10 : 2, (count / peak) * 100)}%`,
+              bgcolor: count > 0 ? 'primary.main' : 'grey.300',
+            }}
+          />
+        </Tooltip>
+      ))}
+    </Box>
+  )
+}
+
+/**
+ * UserActivity 元件 - 顯示 Top 使用者與單一使用者的活動時間軸
+ *
+ * @param users - 已認證使用者統計
+ * @param filePath - 已載入的檔案路徑
+ */
+function UserActivity({ users, filePath }: UserActivityProps) {
+  const [selectedUser, setSelectedUser] = useState<string | null>(null)
+  const [bucket, setBucket] = useState('1h')
+  const [timeline, setTimeline] = useState<stats.UserTimeline | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  if (!users || users.uniqueUsers === 0) {
+    return null
+  }
+
+  const topUsers = users.topUsers ?? []
+
+  const loadTimeline = async (user: string, size: string) => {
+    if (!filePath) {
+      return
+    }
+    setSelectedUser(user)
+    setBucket(size)
+    setLoading(true)
+    setError(null)
+    try {
+      const response = await AppAPI.GetUserActivity(app.UserActivityRequest.createFrom({ filePath, user, bucket: size }))
+      if (!response.success || !response.timeline) {
+        throw new Error(response.errorMessage || '載入使用者時間軸失敗')
+      }
+      setTimeline(response.timeline)
+    } catch (err) {
+      setTimeline(null)
+      setError(err instanceof Error ? err.message : String(err))
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const points = timeline?.points ?? []
+  const peak = Math.max(1, ...points.map((p) => p.requests))
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Typography variant="h6" gutterBottom>
+        已認證使用者
+      </Typography>
+      <Typography variant="body2" color="text.secondary" gutterBottom>
+        {users.uniqueUsers.toLocaleString()} 位使用者，共 {users.authenticatedRequests.toLocaleString()} 筆請求
+      </Typography>
+
+      <Table size="small">
+        <TableHead>
+          <TableRow>
+            <TableCell>使用者</TableCell>
+            <TableCell align="right">請求數</TableCell>
+            <TableCell align="right">路徑數</TableCell>
+            <TableCell align="right">錯誤（錯誤率）</TableCell>
+            <TableCell>使用時段</TableCell>
+            <TableCell>首次 / 最後出現</TableCell>
+            <TableCell>來源 IP</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {topUsers.map((u) => {
+            const ips = u.ips ?? []
+            const countries = u.countries ?? []
+            return (
+              <TableRow
+                key={u.user}
+                hover
+                selected={u.user === selectedUser}
+                sx={{ cursor: filePath ? 'pointer' : 'default' }}
+                onClick={() => loadTimeline(u.user, bucket)}
+              >
+                <TableCell sx={{ wordBreak: 'break-all' }}>{u.user}</TableCell>
+                <TableCell align="right">{u.requestCount.toLocaleString()}</TableCell>
+                <TableCell align="right">{u.uniquePaths.toLocaleString()}</TableCell>
+                <TableCell align="right">
+                  {u.errorCount.toLocaleString()}（{u.errorRate.toFixed(1)}%）
+                </TableCell>
+                <TableCell>
+                  <ActiveHours hours={u.activeHours} />
+                </TableCell>
+                <TableCell>
+                  <Typography variant="caption" display="block">
+                    {new Date(u.firstSeen).toLocaleString()}
+                  </Typography>
+                  <Typography variant="caption" display="block">
+                    {new Date(u.lastSeen).toLocaleString()}
+                  </Typography>
+                </TableCell>
+                <TableCell>
+                  <Tooltip
+                    title={
+                      <span style={{ whiteSpace: 'pre-line' }}>
+                        {ips.map((ip) => `${ip.name} (${ip.count})`).join('\n')}
+                      </span>
+                    }
+                  >
+                    <span>
+                      {u.uniqueIPs.toLocaleString()} 個 IP
+                      {countries.length > 0 && `（${countries.join(', ')}）`}
+                    </span>
+                  </Tooltip>
+                  {u.manyIPs && <Chip label="多 IP" color="warning" size="small" sx={{ ml: 1 }} />}
+                  {u.manyCountries && <Chip label="多國家" color="error" size="small" sx={{ ml: 1 }} />}
+                </TableCell>
+              </TableRow>
+            )
+          })}
+        </TableBody>
+      </Table>
+
+      {selectedUser && (
+        <Box sx={{ mt: 2 }}>
+          <Box sx={{ display: 'flex', alignItems: 'center', gap: 2, mb: 1 }}>
+            <Typography variant="subtitle2">{selectedUser} 的活動時間軸</Typography>
+            <Select
+              size="small"
+              value={bucket}
+              onChange={(e) => loadTimeline(selectedUser, e.target.value)}
+            >
+              {BUCKETS.map((b) => (
+                <MenuItem key={b} value={b}>
+                  {b === '1h' ? '每小時' : '每日'}
+                </MenuItem>
+              ))}
+            </Select>
+            {loading && <CircularProgress size={20} />}
+          </Box>
+          {error && <Alert severity="error">{error}</Alert>}
+          {!error && points.length > 0 && (
+            <Box sx={{ display: 'flex', alignItems: 'flex-end', height: 80, gap: '1px', overflowX: 'auto' }}>
+              {points.map((p) => (
+                <Tooltip
+                  key={String(p.start)}
+                  title={`${new Date(p.start).toLocaleString()}：${p.requests} 次請求、${p.errors} 個錯誤、${p.uniquePaths} 個路徑、${p.uniqueIPs} 個 IP`}
+                >
+                  <Box
+                    sx={{
+                      flex: '0 0 6px',
+                      height: `${Math.max(p.requests > 0 ? 5 : 1, (p.requests / peak) * 100)}%`,
+                      bgcolor: p.errors > 0 ? 'error.main' : p.requests > 0 ? 'primary.main' : 'grey.300',
+                    }}
+                  />
+                </Tooltip>
+              ))}
+            </Box>
+          )}
+        </Box>
+      )}
+    </Paper>
+  )
+}
+
+export default UserActivity
@@ -0,0 +1,108 @@
+// UserAgentDistribution 元件 - 顯示瀏覽器、作業系統、裝置與排版引擎分布
+// 文件路徑: frontend/src/components/UserAgentDistribution.tsx
+// 用途: User-Agent 解析結果的分布
+
+import {
+  Box,
+  Grid,
+  LinearProgress,
+  Paper,
+  Typography,
+} from '@mui/material'
+
+// 匹配 Go internal/stats/user_agents.go 的 DistributionItem 結構
+export interface DistributionItem {
+  name: string       // 名稱
+  count: number      // 請求次數
+  percentage: number // 佔總請求的百分比
+}
+
+// 匹配 Go internal/stats/user_agents.go 的 UserAgentStatistics 結構
+export interface UserAgentStatistics {
+  browsers: DistributionItem[] | null
+  browserVersions: DistributionItem[] | null
+  operatingSystems: DistributionItem[] | null
+  devices: DistributionItem[] | null
+  engines: DistributionItem[] | null
+}
+
+interface UserAgentDistributionProps {
+  userAgents?: UserAgentStatistics
+}
+
+// 裝置類型的顯示名稱
+const deviceLabels: Record<string, string> = {
+  desktop: '桌機',
+  mobile: '手機',
+  tablet: '平板',
+  bot: '機器人',
+  tv: '電視/遊戲機',
+  other: '其他',
+}
+
+/**
+ * DistributionList - 以進度條顯示單一分布的前幾項
+ */
+function DistributionList({ title, items, labels }: {
+  title: string
+  items: DistributionItem[] | null
+  labels?: Record<string, string>
+}) {
+  return (
+    <Box>
+      <Typography variant="subtitle2" gutterBottom>
+        {title}
+      </Typography>
+      {(!items || items.length === 0) ? (
+        <Typography variant="body2" color="text.secondary">
+          無資料
+        </Typography>
+      ) : items.slice(0, 6).map((item) => (
+        <Box key={item.name} sx={{ mb: 1 }}>
+          <Box sx={{ display: 'flex', justifyContent: 'space-between' }}>
+            <Typography variant="body2">{labels?.[item.name] ?? item.name}</Typography>
+            <Typography variant="body2" color="text.secondary">
+              {item.count.toLocaleString()}（{item.percentage.toFixed(1)}%）
+            </Typography>
+          </Box>
+          <LinearProgress variant="determinate" value={item.percentage} />
+        </Box>
+      ))}
+    </Box>
+  )
+}
+
+/**
+ * UserAgentDistribution 元件 - 顯示 User-Agent 解析後的各項分布
+ *
+ * @param userAgents - User-Agent 分布統計
+ */
+function UserAgentDistribution({ userAgents }: UserAgentDistributionProps) {
+  if (!userAgents) {
+    return null
+  }
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Typography variant="h6" gutterBottom>
+        用戶端分布
+      </Typography>
+      <Grid container spacing={3}>
+        <Grid item xs={12} sm={6} md={3}>
+          <DistributionList title="瀏覽器" items={userAgents.browsers} />
+        </Grid>
+        <Grid item xs={12} sm={6} md={3}>
+          <DistributionList title="作業系統" items={userAgents.operatingSystems} />
+        </Grid>
+        <Grid item xs={12} sm={6} md={3}>
+          <DistributionList title="裝置類型" items={userAgents.devices} labels={deviceLabels} />
+        </Grid>
+        <Grid item xs={12} sm={6} md={3}>
+          <DistributionList title="排版引擎" items={userAgents.engines} />
+        </Grid>
+      </Grid>
+    </Paper>
+  )
+}
+
+export default UserAgentDistribution
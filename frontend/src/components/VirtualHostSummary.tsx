@@ -0,0 +1,111 @@
+// VirtualHostSummary 元件 - 顯示各虛擬主機的摘要
+// 文件路徑: frontend/src/components/VirtualHostSummary.tsx
+// 用途: 共用主機的 vhost_combined 日誌依虛擬主機比較流量與錯誤
+
+import {
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+  Tooltip,
+  Typography,
+} from '@mui/material'
+import { DistributionItem } from './UserAgentDistribution'
+
+// 匹配 Go internal/stats/virtual_hosts.go 的 VirtualHostStatistics 結構
+export interface VirtualHostStatistics {
+  host: string
+  ports: number[] | null
+  requestCount: number
+  percentage: number
+  totalBytes: number
+  errorCount: number
+  errorRate: number
+  uniqueIPs: number
+  topPaths: DistributionItem[] | null
+}
+
+interface VirtualHostSummaryProps {
+  virtualHosts?: VirtualHostStatistics[] | null
+  title?: string  // 標題（來源伺服器摘要時為「來源伺服器」）
+  onSelectHost?: (host: string) => void  // 點選主機時切換為該主機的統計
+}
+
+/**
+ * VirtualHostSummary 元件 - 顯示各虛擬主機的請求數、流量、錯誤與熱門路徑
+ *
+ * @param virtualHosts - 各虛擬主機（或來源伺服器）摘要
+ * @param title - 標題
+ * @param onSelectHost - 點選主機時的回呼
+ */
+function VirtualHostSummary({ virtualHosts, title = '虛擬主機', onSelectHost }: VirtualHostSummaryProps) {
+  if (!virtualHosts || virtualHosts.length === 0) {
+    return null
+  }
+
+  return (
+    <Paper sx={{ p: 2 }}>
+      <Typography variant="h6" gutterBottom>
+        {title}
+      </Typography>
+      <Table size="small">
+        <TableHead>
+          <TableRow>
+            <TableCell>主機</TableCell>
+            <TableCell align="right">請求數</TableCell>
+            <TableCell align="right">流量</TableCell>
+            <TableCell align="right">錯誤（錯誤率）</TableCell>
+            <TableCell align="right">唯一 IP</TableCell>
+            <TableCell>熱門路徑</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {virtualHosts.map((h) => {
+            const ports = h.ports ?? []
+            const topPaths = h.topPaths ?? []
+            return (
+              <TableRow
+                key={h.host}
+                hover
+                sx={{ cursor: onSelectHost ? 'pointer' : 'default' }}
+                onClick={() => onSelectHost?.(h.host)}
+              >
+                <TableCell sx={{ wordBreak: 'break-all' }}>
+                  {h.host}
+                  {ports.length > 0 && (
+                    <Typography component="span" variant="caption" color="text.secondary" sx={{ ml: 1 }}>
+                      :{ports.join(', :')}
+                    </Typography>
+                  )}
+                </TableCell>
+                <TableCell align="right">
+                  {h.requestCount.toLocaleString()}（{h.percentage.toFixed(1)}%）
+                </TableCell>
+                <TableCell align="right">{(h.totalBytes / (1024 * 1024)).toFixed(2)} MB</TableCell>
+                <TableCell align="right">
+                  {h.errorCount.toLocaleString()}（{h.errorRate.toFixed(1)}%）
+                </TableCell>
+                <TableCell align="right">{h.uniqueIPs.toLocaleString()}</TableCell>
+                <TableCell sx={{ wordBreak: 'break-all' }}>
+                  <Tooltip
+                    title={
+                      <span style={{ whiteSpace: 'pre-line' }}>
+                        {topPaths.map((p) => `${p.name} (${p.count})`).join('\n')}
+                      </span>
+                    }
+                  >
+                    <span>{topPaths[0]?.name ?? '-'}</span>
+                  </Tooltip>
+                </TableCell>
+              </TableRow>
+            )
+          })}
+        </TableBody>
+      </Table>
+    </Paper>
+  )
+}
+
+export default VirtualHostSummary
@@ -1,13 +1,11 @@
 /**
  * 篩選器服務
  * 文件路徑: frontend/src/services/filterService.ts
- * 目的: 提供高效的日誌記錄篩選功能，支援狀態碼、時間範圍、HTTP 方法等篩選
+ * 目的: 定義篩選條件、預設快捷選項與驗證，實際篩選由後端 Filter API 執行
  */
 
-import { models } from '../../wailsjs/wailsjs/go/models'
-
-// 使用 Wails 生成的類型
-type LogEntry = models.LogEntry
+import { filter } from '../../wailsjs/wailsjs/go/models'
+import type { SearchCriteria } from './searchService'
 
 /**
  * 狀態碼範圍介面
@@ -55,135 +53,9 @@ export interface FilterStats {
 
 /**
  * 篩選器服務類別
- * 提供高效的日誌記錄篩選功能
+ * 提供篩選條件的預設快捷選項與驗證
  */
 export class FilterService {
-  /**
-   * 篩選日誌記錄
-   * @param entries 日誌記錄陣列
-   * @param criteria 篩選條件
-   * @returns 符合條件的日誌記錄陣列
-   * 
-   * 效能要求: 100 萬筆記錄篩選 ≤100ms
-   * 複雜度: O(n) - 線性掃描，所有條件在單次遍歷中完成
-   */
-  filter(entries: LogEntry[], criteria: FilterCriteria): LogEntry[] {
-    // 如果沒有篩選條件，返回所有資料
-    if (this.isEmptyCriteria(criteria)) {
-      return entries
-    }
-
-    return entries.filter(entry => {
-      // 檢查特定狀態碼列表
-      if (criteria.statusCodes && criteria.statusCodes.length > 0) {
-        if (!criteria.statusCodes.includes(entry.statusCode)) {
-          return false
-        }
-      }
-
-      // 檢查狀態碼範圍
-      if (criteria.statusCodeRange) {
-        const { min, max } = criteria.statusCodeRange
-        
-        // 驗證範圍有效性
-        if (min > max) {
-          return false
-        }
-        
-        if (entry.statusCode < min || entry.statusCode > max) {
-          return false
-        }
-      }
-
-      // 檢查時間範圍
-      if (criteria.timeRange) {
-        const { start, end } = criteria.timeRange
-        
-        // 驗證時間範圍有效性
-        if (start && end && start > end) {
-          return false
-        }
-        
-        if (start && entry.timestamp < start) {
-          return false
-        }
-        
-        if (end && entry.timestamp > end) {
-          return false
-        }
-      }
-
-      // 檢查 HTTP 方法列表
-      if (criteria.methods && criteria.methods.length > 0) {
-        if (!criteria.methods.includes(entry.method)) {
-          return false
-        }
-      }
-
-      // 檢查回應大小範圍
-      if (criteria.responseSizeRange) {
-        const { min, max } = criteria.responseSizeRange
-        
-        // 驗證範圍有效性
-        if (min !== undefined && max !== undefined && min > max) {
-          return false
-        }
-        
-        if (min !== undefined && entry.responseBytes < min) {
-          return false
-        }
-        
-        if (max !== undefined && entry.responseBytes > max) {
-          return false
-        }
-      }
-
-      // 所有條件都符合
-      return true
-    })
-  }
-
-  /**
-   * 檢查篩選條件是否為空
-   * @param criteria 篩選條件
-   * @returns 如果所有條件都未設定，返回 true
-   */
-  private isEmptyCriteria(criteria: FilterCriteria): boolean {
-    const {
-      statusCodes,
-      statusCodeRange,
-      timeRange,
-      methods,
-      responseSizeRange
-    } = criteria
-
-    return (
-      (!statusCodes || statusCodes.length === 0) &&
-      !statusCodeRange &&
-      !timeRange &&
-      (!methods || methods.length === 0) &&
-      !responseSizeRange
-    )
-  }
-
-  /**
-   * 取得篩選結果統計
-   * @param originalEntries 原始日誌記錄陣列
-   * @param filteredEntries 篩選後的日誌記錄陣列
-   * @returns 篩選統計資訊
-   */
-  getFilterStats(originalEntries: LogEntry[], filteredEntries: LogEntry[]): FilterStats {
-    const total = originalEntries.length
-    const filtered = filteredEntries.length
-    const percentage = total > 0 ? (filtered / total) * 100 : 0
-
-    return {
-      total,
-      filtered,
-      percentage: Math.round(percentage * 100) / 100 // 保留兩位小數
-    }
-  }
-
   /**
    * 建立預定義的狀態碼範圍篩選
    * 提供常用的 HTTP 狀態碼類別快捷方式
@@ -247,27 +119,6 @@ export class FilterService {
     }
   }
 
-  /**
-   * 組合搜尋和篩選條件
-   * 允許同時使用搜尋服務和篩選器服務
-   * 
-   * @param entries 日誌記錄陣列
-   * @param filterCriteria 篩選條件
-   * @param searchCallback 搜尋回調函式（先執行篩選，再執行搜尋）
-   * @returns 符合所有條件的日誌記錄陣列
-   */
-  filterAndSearch(
-    entries: LogEntry[],
-    filterCriteria: FilterCriteria,
-    searchCallback: (entries: LogEntry[]) => LogEntry[]
-  ): LogEntry[] {
-    // 先執行篩選
-    const filtered = this.filter(entries, filterCriteria)
-    
-    // 再執行搜尋
-    return searchCallback(filtered)
-  }
-
   /**
    * 驗證篩選條件的有效性
    * @param criteria 篩選條件
@@ -329,6 +180,51 @@ export class FilterService {
   }
 }
 
+/**
+ * 取篩選面板的方法列表與搜尋列的方法的交集（不區分大小寫）
+ * @returns 交集；兩者皆未設定時返回 undefined（不限方法），沒有交集時返回空陣列
+ */
+function intersectMethods(methods: string[] | undefined, method: string | undefined): string[] | undefined {
+  const listed = methods && methods.length > 0 ? methods : undefined
+  if (!method) {
+    return listed
+  }
+  if (!listed) {
+    return [method]
+  }
+  return listed.filter((m) => m.toUpperCase() === method.toUpperCase())
+}
+
+/**
+ * 將前端的篩選條件與搜尋條件合併為後端 Filter API 的條件
+ * @param filterCriteria 篩選條件（可為 null）
+ * @param searchCriteria 搜尋條件（可為 null）
+ * @returns 後端篩選條件，所有條件之間為 AND 關係
+ */
+export function toBackendCriteria(
+  filterCriteria: FilterCriteria | null,
+  searchCriteria: SearchCriteria | null
+): filter.FilterCriteria {
+  const methods = intersectMethods(filterCriteria?.methods, searchCriteria?.method)
+  // 篩選與搜尋的方法沒有交集時，明確要求後端不返回任何記錄
+  const matchNone = methods !== undefined && methods.length === 0
+
+  return filter.FilterCriteria.createFrom({
+    statusCodes: filterCriteria?.statusCodes,
+    statusCodeRange: filterCriteria?.statusCodeRange,
+    timeRange: filterCriteria?.timeRange,
+    methods: matchNone ? undefined : methods,
+    matchNone: matchNone || undefined,
+    sizeRange: filterCriteria?.responseSizeRange,
+    ip: searchCriteria?.ip || undefined,
+    url: searchCriteria?.url || undefined,
+    userAgent: searchCriteria?.userAgent || undefined,
+    user: searchCriteria?.user || undefined,
+    keyword: searchCriteria?.keyword || undefined,
+    caseSensitive: searchCriteria?.caseSensitive || undefined
+  })
+}
+
 /**
  * 匯出單例實例（方便直接使用）
  */
@@ -1,28 +1,106 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {app} from '../models';
+import {filter} from '../models';
 import {models} from '../models';
+import {subnet} from '../models';
+
+export function Aggregate(arg1:app.AggregateRequest):Promise<app.AggregateResponse>;
+
+export function AnalyzeCache(arg1:app.CacheAnalysisRequest):Promise<app.CacheAnalysisResponse>;
+
+export function AnalyzeErrors(arg1:app.ErrorAnalysisRequest):Promise<app.ErrorAnalysisResponse>;
+
+export function AnalyzeFunnel(arg1:app.FunnelRequest):Promise<app.FunnelResponse>;
+
+export function AnalyzeSessions(arg1:app.SessionRequest):Promise<app.SessionResponse>;
 
 export function ClearRecentFiles():Promise<app.ClearRecentFilesResponse>;
 
 export function CloseFile(arg1:string):Promise<boolean>;
 
+export function CompareStatistics(arg1:app.CompareRequest):Promise<app.CompareResponse>;
+
+export function DetectAnomalies(arg1:app.AnomalyRequest):Promise<app.AnomalyResponse>;
+
+export function DetectBruteForce(arg1:app.BruteForceRequest):Promise<app.BruteForceResponse>;
+
+export function ExportAggregateToExcel(arg1:app.ExportAggregateRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportAnomaliesToExcel(arg1:app.ExportAnomaliesRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportCacheReportToExcel(arg1:app.ExportCacheReportRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportComparisonToExcel(arg1:app.ExportComparisonRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportFunnelToExcel(arg1:app.ExportFunnelRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportNotFoundToExcel(arg1:app.ExportToExcelRequest):Promise<app.ExportToExcelResponse>;
+
+export function ExportReferersToExcel(arg1:app.ExportToExcelRequest):Promise<app.ExportToExcelResponse>;
+
 export function ExportToExcel(arg1:app.ExportToExcelRequest):Promise<app.ExportToExcelResponse>;
 
+export function Filter(arg1:app.FilterRequest):Promise<app.FilterResponse>;
+
 export function GetActiveFile():Promise<string>;
 
-export function GetFileData(arg1:string):Promise<models.LogFile>;
+export function GetBotRules():Promise<app.BotRulesResponse>;
+
+export function GetCrawlerVerification():Promise<app.CrawlerVerificationResponse>;
+
+export function GetEntries(arg1:string,arg2:number,arg3:number,arg4:filter.SortSpec,arg5:filter.FilterCriteria):Promise<app.GetEntriesResponse>;
+
+export function GetFileData(arg1:string):Promise<models.LogFileSummary>;
+
+export function GetGeoIPStatus():Promise<app.GeoIPStatusResponse>;
+
+export function GetHostStatistics(arg1:app.HostStatisticsRequest):Promise<app.HostStatisticsResponse>;
 
 export function GetOpenFiles():Promise<Array<string>>;
 
 export function GetRecentFiles():Promise<app.GetRecentFilesResponse>;
 
+export function GetSiteHosts():Promise<app.SiteHostsResponse>;
+
+export function GetSubnetSettings():Promise<app.SubnetSettingsResponse>;
+
+export function GetUserActivity(arg1:app.UserActivityRequest):Promise<app.UserActivityResponse>;
+
+export function GetUserAgentRules():Promise<app.UserAgentRulesResponse>;
+
+export function LoadBotRules(arg1:string):Promise<app.BotRulesResponse>;
+
+export function LoadCIDRGroups(arg1:string):Promise<app.SubnetSettingsResponse>;
+
+export function LoadCrawlerRanges(arg1:string):Promise<app.CrawlerVerificationResponse>;
+
+export function LoadGeoIPDatabases(arg1:string):Promise<app.GeoIPStatusResponse>;
+
+export function LoadUserAgentRegexes(arg1:string):Promise<app.UserAgentRulesResponse>;
+
+export function LookupIP(arg1:string):Promise<app.LookupIPResponse>;
+
 export function ParseFile(arg1:app.ParseFileRequest):Promise<app.ParseFileResponse>;
 
+export function ParseUserAgent(arg1:string):Promise<app.ParseUserAgentResponse>;
+
+export function Query(arg1:app.QueryRequest):Promise<app.QueryResponse>;
+
 export function SelectFile():Promise<app.SelectFileResponse>;
 
 export function SelectSaveLocation(arg1:string):Promise<app.SelectSaveLocationResponse>;
 
 export function SetActiveFile(arg1:string):Promise<boolean>;
 
+export function SetCIDRGroups(arg1:Array<subnet.Group>):Promise<app.SubnetSettingsResponse>;
+
+export function SetCrawlerDNSVerification(arg1:boolean):Promise<app.CrawlerVerificationResponse>;
+
+export function SetSiteHosts(arg1:Array<string>):Promise<app.SiteHostsResponse>;
+
+export function SetSubnetOptions(arg1:subnet.Options):Promise<app.SubnetSettingsResponse>;
+
 export function ValidateLogFormat(arg1:app.ValidateFormatRequest):Promise<app.ValidateFormatResponse>;
+
+export function ValidateQuery(arg1:string):Promise<app.ValidateQueryResponse>;
@@ -2,6 +2,26 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function Aggregate(arg1) {
+  return window['go']['app']['App']['Aggregate'](arg1);
+}
+
+export function AnalyzeCache(arg1) {
+  return window['go']['app']['App']['AnalyzeCache'](arg1);
+}
+
+export function AnalyzeErrors(arg1) {
+  return window['go']['app']['App']['AnalyzeErrors'](arg1);
+}
+
+export function AnalyzeFunnel(arg1) {
+  return window['go']['app']['App']['AnalyzeFunnel'](arg1);
+}
+
+export function AnalyzeSessions(arg1) {
+  return window['go']['app']['App']['AnalyzeSessions'](arg1);
+}
+
 export function ClearRecentFiles() {
   return window['go']['app']['App']['ClearRecentFiles']();
 }
@@ -10,18 +30,82 @@ export function CloseFile(arg1) {
   return window['go']['app']['App']['CloseFile'](arg1);
 }
 
+export function CompareStatistics(arg1) {
+  return window['go']['app']['App']['CompareStatistics'](arg1);
+}
+
+export function DetectAnomalies(arg1) {
+  return window['go']['app']['App']['DetectAnomalies'](arg1);
+}
+
+export function DetectBruteForce(arg1) {
+  return window['go']['app']['App']['DetectBruteForce'](arg1);
+}
+
+export function ExportAggregateToExcel(arg1) {
+  return window['go']['app']['App']['ExportAggregateToExcel'](arg1);
+}
+
+export function ExportAnomaliesToExcel(arg1) {
+  return window['go']['app']['App']['ExportAnomaliesToExcel'](arg1);
+}
+
+export function ExportCacheReportToExcel(arg1) {
+  return window['go']['app']['App']['ExportCacheReportToExcel'](arg1);
+}
+
+export function ExportComparisonToExcel(arg1) {
+  return window['go']['app']['App']['ExportComparisonToExcel'](arg1);
+}
+
+export function ExportFunnelToExcel(arg1) {
+  return window['go']['app']['App']['ExportFunnelToExcel'](arg1);
+}
+
+export function ExportNotFoundToExcel(arg1) {
+  return window['go']['app']['App']['ExportNotFoundToExcel'](arg1);
+}
+
+export function ExportReferersToExcel(arg1) {
+  return window['go']['app']['App']['ExportReferersToExcel'](arg1);
+}
+
 export function ExportToExcel(arg1) {
   return window['go']['app']['App']['ExportToExcel'](arg1);
 }
 
+export function Filter(arg1) {
+  return window['go']['app']['App']['Filter'](arg1);
+}
+
 export function GetActiveFile() {
   return window['go']['app']['App']['GetActiveFile']();
 }
 
+export function GetBotRules() {
+  return window['go']['app']['App']['GetBotRules']();
+}
+
+export function GetCrawlerVerification() {
+  return window['go']['app']['App']['GetCrawlerVerification']();
+}
+
+export function GetEntries(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['GetEntries'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function GetFileData(arg1) {
   return window['go']['app']['App']['GetFileData'](arg1);
 }
 
+export function GetGeoIPStatus() {
+  return window['go']['app']['App']['GetGeoIPStatus']();
+}
+
+export function GetHostStatistics(arg1) {
+  return window['go']['app']['App']['GetHostStatistics'](arg1);
+}
+
 export function GetOpenFiles() {
   return window['go']['app']['App']['GetOpenFiles']();
 }
@@ -30,10 +114,58 @@ export function GetRecentFiles() {
   return window['go']['app']['App']['GetRecentFiles']();
 }
 
+export function GetSiteHosts() {
+  return window['go']['app']['App']['GetSiteHosts']();
+}
+
+export function GetSubnetSettings() {
+  return window['go']['app']['App']['GetSubnetSettings']();
+}
+
+export function GetUserActivity(arg1) {
+  return window['go']['app']['App']['GetUserActivity'](arg1);
+}
+
+export function GetUserAgentRules() {
+  return window['go']['app']['App']['GetUserAgentRules']();
+}
+
+export function LoadBotRules(arg1) {
+  return window['go']['app']['App']['LoadBotRules'](arg1);
+}
+
+export function LoadCIDRGroups(arg1) {
+  return window['go']['app']['App']['LoadCIDRGroups'](arg1);
+}
+
+export function LoadCrawlerRanges(arg1) {
+  return window['go']['app']['App']['LoadCrawlerRanges'](arg1);
+}
+
+export function LoadGeoIPDatabases(arg1) {
+  return window['go']['app']['App']['LoadGeoIPDatabases'](arg1);
+}
+
+export function LoadUserAgentRegexes(arg1) {
+  return window['go']['app']['App']['LoadUserAgentRegexes'](arg1);
+}
+
+export function LookupIP(arg1) {
+  return window['go']['app']['App']['LookupIP'](arg1);
+}
+
 export function ParseFile(arg1) {
   return window['go']['app']['App']['ParseFile'](arg1);
 }
 
+export function ParseUserAgent(arg1) {
+  return window['go']['app']['App']['ParseUserAgent'](arg1);
+}
+
+export function Query(arg1) {
+  return window['go']['app']['App']['Query'](arg1);
+}
+
 export function SelectFile() {
   return window['go']['app']['App']['SelectFile']();
 }
@@ -46,6 +178,26 @@ export function SetActiveFile(arg1) {
   return window['go']['app']['App']['SetActiveFile'](arg1);
 }
 
+export function SetCIDRGroups(arg1) {
+  return window['go']['app']['App']['SetCIDRGroups'](arg1);
+}
+
+export function SetCrawlerDNSVerification(arg1) {
+  return window['go']['app']['App']['SetCrawlerDNSVerification'](arg1);
+}
+
+export function SetSiteHosts(arg1) {
+  return window['go']['app']['App']['SetSiteHosts'](arg1);
+}
+
+export function SetSubnetOptions(arg1) {
+  return window['go']['app']['App']['SetSubnetOptions'](arg1);
+}
+
 export function ValidateLogFormat(arg1) {
   return window['go']['app']['App']['ValidateLogFormat'](arg1);
 }
+
+export function ValidateQuery(arg1) {
+  return window['go']['app']['App']['ValidateQuery'](arg1);
+}
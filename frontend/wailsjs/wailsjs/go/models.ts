@@ -1,91 +1,4186 @@
+export namespace aggregate {
+	
+	export class Aggregate {
+	    func: string;
+	    field?: string;
+	    percentile?: number;
+	    alias?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Aggregate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.func = source["func"];
+	        this.field = source["field"];
+	        this.percentile = source["percentile"];
+	        this.alias = source["alias"];
+	    }
+	}
+	export class GroupKey {
+	    field: string;
+	    bucket?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.bucket = source["bucket"];
+	    }
+	}
+	export class Query {
+	    filter?: filter.FilterCriteria;
+	    groupBy: GroupKey[];
+	    aggregates: Aggregate[];
+	    orderBy?: string;
+	    descending?: boolean;
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Query(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filter = this.convertValues(source["filter"], filter.FilterCriteria);
+	        this.groupBy = this.convertValues(source["groupBy"], GroupKey);
+	        this.aggregates = this.convertValues(source["aggregates"], Aggregate);
+	        this.orderBy = source["orderBy"];
+	        this.descending = source["descending"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ResultRow {
+	    keys: string[];
+	    values: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keys = source["keys"];
+	        this.values = source["values"];
+	    }
+	}
+	export class Result {
+	    columns: string[];
+	    keyColumns: number;
+	    rows: ResultRow[];
+	    matchedEntries: number;
+	    totalGroups: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.columns = source["columns"];
+	        this.keyColumns = source["keyColumns"];
+	        this.rows = this.convertValues(source["rows"], ResultRow);
+	        this.matchedEntries = source["matchedEntries"];
+	        this.totalGroups = source["totalGroups"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TimeRange {
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace anomaly {
+	
+	export class Contributor {
+	    key: string;
+	    count: number;
+	    errors: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Contributor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.count = source["count"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class Options {
+	    bucket: string;
+	    baseline: number;
+	    zThreshold: number;
+	    errorRatioJump: number;
+	    minErrors: number;
+	    minNewIPs: number;
+	    minOutageBase: number;
+	    topN: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.baseline = source["baseline"];
+	        this.zThreshold = source["zThreshold"];
+	        this.errorRatioJump = source["errorRatioJump"];
+	        this.minErrors = source["minErrors"];
+	        this.minNewIPs = source["minNewIPs"];
+	        this.minOutageBase = source["minOutageBase"];
+	        this.topN = source["topN"];
+	    }
+	}
+	export class Window {
+	    kind: string;
+	    severity: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    buckets: number;
+	    value: number;
+	    baseline: number;
+	    score: number;
+	    requests: number;
+	    errors: number;
+	    description: string;
+	    topPaths: Contributor[];
+	    topIPs: Contributor[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Window(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.severity = source["severity"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.buckets = source["buckets"];
+	        this.value = source["value"];
+	        this.baseline = source["baseline"];
+	        this.score = source["score"];
+	        this.requests = source["requests"];
+	        this.errors = source["errors"];
+	        this.description = source["description"];
+	        this.topPaths = this.convertValues(source["topPaths"], Contributor);
+	        this.topIPs = this.convertValues(source["topIPs"], Contributor);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    options: Options;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    buckets: number;
+	    windows: Window[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.options = this.convertValues(source["options"], Options);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.buckets = source["buckets"];
+	        this.windows = this.convertValues(source["windows"], Window);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace app {
 	
-	export class ClearRecentFilesResponse {
+	export class AggregateRequest {
+	    filePath: string;
+	    query: aggregate.Query;
+	
+	    static createFrom(source: any = {}) {
+	        return new AggregateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.query = this.convertValues(source["query"], aggregate.Query);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AggregateResponse {
 	    success: boolean;
+	    result?: aggregate.Result;
 	    errorMessage: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ClearRecentFilesResponse(source);
+	        return new AggregateResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.success = source["success"];
+	        this.result = this.convertValues(source["result"], aggregate.Result);
 	        this.errorMessage = source["errorMessage"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnomalyRequest {
+	    filePath: string;
+	    options: anomaly.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnomalyRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.options = this.convertValues(source["options"], anomaly.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnomalyResponse {
+	    success: boolean;
+	    report?: anomaly.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnomalyResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], anomaly.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BotRulesResponse {
+	    success: boolean;
+	    rules: stats.BotRule[];
+	    rulesFile: string;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotRulesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.rules = this.convertValues(source["rules"], stats.BotRule);
+	        this.rulesFile = source["rulesFile"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BruteForceRequest {
+	    filePath: string;
+	    config: security.BruteForceConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new BruteForceRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.config = this.convertValues(source["config"], security.BruteForceConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BruteForceResponse {
+	    success: boolean;
+	    config: security.BruteForceConfig;
+	    report?: security.BruteForceReport;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BruteForceResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.config = this.convertValues(source["config"], security.BruteForceConfig);
+	        this.report = this.convertValues(source["report"], security.BruteForceReport);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheAnalysisRequest {
+	    filePath: string;
+	    options: cacheanalysis.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheAnalysisRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.options = this.convertValues(source["options"], cacheanalysis.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheAnalysisResponse {
+	    success: boolean;
+	    report?: cacheanalysis.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheAnalysisResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], cacheanalysis.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ClearRecentFilesResponse {
+	    success: boolean;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClearRecentFilesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class ComparisonSource {
+	    filePath: string;
+	    range: aggregate.TimeRange;
+	
+	    static createFrom(source: any = {}) {
+	        return new ComparisonSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.range = this.convertValues(source["range"], aggregate.TimeRange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompareRequest {
+	    before: ComparisonSource;
+	    after: ComparisonSource;
+	    options: compare.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.before = this.convertValues(source["before"], ComparisonSource);
+	        this.after = this.convertValues(source["after"], ComparisonSource);
+	        this.options = this.convertValues(source["options"], compare.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompareResponse {
+	    success: boolean;
+	    report?: compare.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], compare.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CrawlerVerificationResponse {
+	    success: boolean;
+	    profiles: stats.CrawlerProfile[];
+	    dnsEnabled: boolean;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CrawlerVerificationResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.profiles = this.convertValues(source["profiles"], stats.CrawlerProfile);
+	        this.dnsEnabled = source["dnsEnabled"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ErrorAnalysisRequest {
+	    filePath: string;
+	    options: erroranalysis.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new ErrorAnalysisRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.options = this.convertValues(source["options"], erroranalysis.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ErrorAnalysisResponse {
+	    success: boolean;
+	    report?: erroranalysis.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ErrorAnalysisResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], erroranalysis.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportAggregateRequest {
+	    filePath: string;
+	    savePath: string;
+	    query: aggregate.Query;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportAggregateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.savePath = source["savePath"];
+	        this.query = this.convertValues(source["query"], aggregate.Query);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportAnomaliesRequest {
+	    filePath: string;
+	    savePath: string;
+	    options: anomaly.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportAnomaliesRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.savePath = source["savePath"];
+	        this.options = this.convertValues(source["options"], anomaly.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportCacheReportRequest {
+	    filePath: string;
+	    options: cacheanalysis.Options;
+	    savePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportCacheReportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.options = this.convertValues(source["options"], cacheanalysis.Options);
+	        this.savePath = source["savePath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportComparisonRequest {
+	    before: ComparisonSource;
+	    after: ComparisonSource;
+	    options: compare.Options;
+	    savePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportComparisonRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.before = this.convertValues(source["before"], ComparisonSource);
+	        this.after = this.convertValues(source["after"], ComparisonSource);
+	        this.options = this.convertValues(source["options"], compare.Options);
+	        this.savePath = source["savePath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportFunnelRequest {
+	    filePath: string;
+	    savePath: string;
+	    definition: funnel.Definition;
+	    range: aggregate.TimeRange;
+	    compareRange?: aggregate.TimeRange;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportFunnelRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.savePath = source["savePath"];
+	        this.definition = this.convertValues(source["definition"], funnel.Definition);
+	        this.range = this.convertValues(source["range"], aggregate.TimeRange);
+	        this.compareRange = this.convertValues(source["compareRange"], aggregate.TimeRange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportToExcelRequest {
+	    filePath: string;
+	    savePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportToExcelRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.savePath = source["savePath"];
+	    }
+	}
+	export class ExportToExcelResponse {
+	    success: boolean;
+	    exportPath: string;
+	    fileSize: number;
+	    totalRecords: number;
+	    truncatedRows: number;
+	    duration: string;
+	    warnings: string[];
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportToExcelResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.exportPath = source["exportPath"];
+	        this.fileSize = source["fileSize"];
+	        this.totalRecords = source["totalRecords"];
+	        this.truncatedRows = source["truncatedRows"];
+	        this.duration = source["duration"];
+	        this.warnings = source["warnings"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class FilterRequest {
+	    filePath: string;
+	    criteria?: filter.FilterCriteria;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilterRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.criteria = this.convertValues(source["criteria"], filter.FilterCriteria);
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FilterResponse {
+	    success: boolean;
+	    entries: models.LogEntry[];
+	    total: number;
+	    matched: number;
+	    percentage: number;
+	    offset: number;
+	    limit: number;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilterResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.entries = this.convertValues(source["entries"], models.LogEntry);
+	        this.total = source["total"];
+	        this.matched = source["matched"];
+	        this.percentage = source["percentage"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FunnelRequest {
+	    filePath: string;
+	    definition: funnel.Definition;
+	    range: aggregate.TimeRange;
+	    compareRange?: aggregate.TimeRange;
+	
+	    static createFrom(source: any = {}) {
+	        return new FunnelRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.definition = this.convertValues(source["definition"], funnel.Definition);
+	        this.range = this.convertValues(source["range"], aggregate.TimeRange);
+	        this.compareRange = this.convertValues(source["compareRange"], aggregate.TimeRange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FunnelResponse {
+	    success: boolean;
+	    report?: funnel.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FunnelResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], funnel.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GeoIPStatusResponse {
+	    success: boolean;
+	    enabled: boolean;
+	    databases: geoip.DatabaseInfo[];
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GeoIPStatusResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.enabled = source["enabled"];
+	        this.databases = this.convertValues(source["databases"], geoip.DatabaseInfo);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GetEntriesResponse {
+	    success: boolean;
+	    entries: models.LogEntry[];
+	    total: number;
+	    matched: number;
+	    offset: number;
+	    limit: number;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GetEntriesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.entries = this.convertValues(source["entries"], models.LogEntry);
+	        this.total = source["total"];
+	        this.matched = source["matched"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RecentFile {
+	    path: string;
+	    name: string;
+	    size: number;
+	    openedAt: string;
+	    totalLines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecentFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.openedAt = source["openedAt"];
+	        this.totalLines = source["totalLines"];
+	    }
+	}
+	export class GetRecentFilesResponse {
+	    success: boolean;
+	    files: RecentFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GetRecentFilesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.files = this.convertValues(source["files"], RecentFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HostStatisticsRequest {
+	    filePath: string;
+	    host: string;
+	    server?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HostStatisticsRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.host = source["host"];
+	        this.server = source["server"];
+	    }
+	}
+	export class HostStatisticsResponse {
+	    success: boolean;
+	    host: string;
+	    server: string;
+	    statistics?: stats.Statistics;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HostStatisticsResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.host = source["host"];
+	        this.server = source["server"];
+	        this.statistics = this.convertValues(source["statistics"], stats.Statistics);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LookupIPResponse {
+	    success: boolean;
+	    found: boolean;
+	    location: geoip.Location;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LookupIPResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.found = source["found"];
+	        this.location = this.convertValues(source["location"], geoip.Location);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ParseFileRequest {
+	    filePath: string;
+	    buildIndex: boolean;
+	    server?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParseFileRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.buildIndex = source["buildIndex"];
+	        this.server = source["server"];
+	    }
+	}
+	export class ParseFileResponse {
+	    success: boolean;
+	    logFile?: models.LogFileSummary;
+	    errorMessage: string;
+	    errorSamples: parser.ParseError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ParseFileResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.logFile = this.convertValues(source["logFile"], models.LogFileSummary);
+	        this.errorMessage = source["errorMessage"];
+	        this.errorSamples = this.convertValues(source["errorSamples"], parser.ParseError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ParseUserAgentResponse {
+	    success: boolean;
+	    userAgent: useragent.UserAgent;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParseUserAgentResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.userAgent = this.convertValues(source["userAgent"], useragent.UserAgent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueryRequest {
+	    filePath: string;
+	    query: string;
+	    sort?: filter.SortSpec;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.query = source["query"];
+	        this.sort = this.convertValues(source["sort"], filter.SortSpec);
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueryResponse {
+	    success: boolean;
+	    entries: models.LogEntry[];
+	    total: number;
+	    matched: number;
+	    offset: number;
+	    limit: number;
+	    syntaxError?: query.SyntaxError;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.entries = this.convertValues(source["entries"], models.LogEntry);
+	        this.total = source["total"];
+	        this.matched = source["matched"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	        this.syntaxError = this.convertValues(source["syntaxError"], query.SyntaxError);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SelectFileResponse {
+	    success: boolean;
+	    filePath: string;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectFileResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.filePath = source["filePath"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class SelectSaveLocationResponse {
+	    success: boolean;
+	    savePath: string;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelectSaveLocationResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.savePath = source["savePath"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class SessionRequest {
+	    filePath: string;
+	    options: session.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.options = this.convertValues(source["options"], session.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionResponse {
+	    success: boolean;
+	    report?: session.Report;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.report = this.convertValues(source["report"], session.Report);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SiteHostsResponse {
+	    success: boolean;
+	    hosts: string[];
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SiteHostsResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.hosts = source["hosts"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class SubnetSettingsResponse {
+	    success: boolean;
+	    options: subnet.Options;
+	    groups: subnet.Group[];
+	    groupsFile: string;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubnetSettingsResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.options = this.convertValues(source["options"], subnet.Options);
+	        this.groups = this.convertValues(source["groups"], subnet.Group);
+	        this.groupsFile = source["groupsFile"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UserActivityRequest {
+	    filePath: string;
+	    user: string;
+	    bucket?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserActivityRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.user = source["user"];
+	        this.bucket = source["bucket"];
+	    }
+	}
+	export class UserActivityResponse {
+	    success: boolean;
+	    timeline?: stats.UserTimeline;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserActivityResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.timeline = this.convertValues(source["timeline"], stats.UserTimeline);
+	        this.errorMessage = source["errorMessage"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UserAgentRulesResponse {
+	    success: boolean;
+	    source: string;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserAgentRulesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.source = source["source"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class ValidateFormatRequest {
+	    filePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidateFormatRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	    }
+	}
+	export class ValidateFormatResponse {
+	    success: boolean;
+	    valid: boolean;
+	    errorMessage: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidateFormatResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.valid = source["valid"];
+	        this.errorMessage = source["errorMessage"];
+	    }
+	}
+	export class ValidateQueryResponse {
+	    valid: boolean;
+	    syntaxError?: query.SyntaxError;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidateQueryResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.valid = source["valid"];
+	        this.syntaxError = this.convertValues(source["syntaxError"], query.SyntaxError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace cacheanalysis {
+	
+	export class StatusCount {
+	    status: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.count = source["count"];
+	    }
+	}
+	export class CacheStatusStats {
+	    requests: number;
+	    hits: number;
+	    misses: number;
+	    hitRatio: number;
+	    statuses: StatusCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheStatusStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requests = source["requests"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.hitRatio = source["hitRatio"];
+	        this.statuses = this.convertValues(source["statuses"], StatusCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Options {
+	    repeatWindow: string;
+	    minRequests: number;
+	    topN: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repeatWindow = source["repeatWindow"];
+	        this.minRequests = source["minRequests"];
+	        this.topN = source["topN"];
+	    }
+	}
+	export class PathCache {
+	    path: string;
+	    contentType: string;
+	    requests: number;
+	    fullResponses: number;
+	    notModified: number;
+	    notModifiedRate: number;
+	    averageSize: number;
+	    bytesSaved: number;
+	    repeatFetches: number;
+	    repeatRate: number;
+	    cacheHits: number;
+	    cacheMisses: number;
+	    hitRatio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PathCache(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.contentType = source["contentType"];
+	        this.requests = source["requests"];
+	        this.fullResponses = source["fullResponses"];
+	        this.notModified = source["notModified"];
+	        this.notModifiedRate = source["notModifiedRate"];
+	        this.averageSize = source["averageSize"];
+	        this.bytesSaved = source["bytesSaved"];
+	        this.repeatFetches = source["repeatFetches"];
+	        this.repeatRate = source["repeatRate"];
+	        this.cacheHits = source["cacheHits"];
+	        this.cacheMisses = source["cacheMisses"];
+	        this.hitRatio = source["hitRatio"];
+	    }
+	}
+	export class Recommendation {
+	    path: string;
+	    contentType: string;
+	    reason: string;
+	    message: string;
+	    requests: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Recommendation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.contentType = source["contentType"];
+	        this.reason = source["reason"];
+	        this.message = source["message"];
+	        this.requests = source["requests"];
+	    }
+	}
+	export class TypeCache {
+	    contentType: string;
+	    requests: number;
+	    fullResponses: number;
+	    notModified: number;
+	    notModifiedRate: number;
+	    bytesTransferred: number;
+	    bytesSaved: number;
+	    repeatFetches: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TypeCache(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.contentType = source["contentType"];
+	        this.requests = source["requests"];
+	        this.fullResponses = source["fullResponses"];
+	        this.notModified = source["notModified"];
+	        this.notModifiedRate = source["notModifiedRate"];
+	        this.bytesTransferred = source["bytesTransferred"];
+	        this.bytesSaved = source["bytesSaved"];
+	        this.repeatFetches = source["repeatFetches"];
+	    }
+	}
+	export class Report {
+	    options: Options;
+	    totalRequests: number;
+	    cacheableRequests: number;
+	    fullResponses: number;
+	    notModified: number;
+	    notModifiedRate: number;
+	    bytesTransferred: number;
+	    bytesSaved: number;
+	    repeatFetches: number;
+	    paths: PathCache[];
+	    repeatedAssets: PathCache[];
+	    contentTypes: TypeCache[];
+	    cacheStatus?: CacheStatusStats;
+	    recommendations: Recommendation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.options = this.convertValues(source["options"], Options);
+	        this.totalRequests = source["totalRequests"];
+	        this.cacheableRequests = source["cacheableRequests"];
+	        this.fullResponses = source["fullResponses"];
+	        this.notModified = source["notModified"];
+	        this.notModifiedRate = source["notModifiedRate"];
+	        this.bytesTransferred = source["bytesTransferred"];
+	        this.bytesSaved = source["bytesSaved"];
+	        this.repeatFetches = source["repeatFetches"];
+	        this.paths = this.convertValues(source["paths"], PathCache);
+	        this.repeatedAssets = this.convertValues(source["repeatedAssets"], PathCache);
+	        this.contentTypes = this.convertValues(source["contentTypes"], TypeCache);
+	        this.cacheStatus = this.convertValues(source["cacheStatus"], CacheStatusStats);
+	        this.recommendations = this.convertValues(source["recommendations"], Recommendation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace compare {
+	
+	export class Change {
+	    name: string;
+	    before: number;
+	    after: number;
+	    delta: number;
+	    relativeChange: number;
+	    significant: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Change(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.delta = source["delta"];
+	        this.relativeChange = source["relativeChange"];
+	        this.significant = source["significant"];
+	    }
+	}
+	export class Options {
+	    minRelativeChange: number;
+	    minRateChange: number;
+	    minCount: number;
+	    topN: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minRelativeChange = source["minRelativeChange"];
+	        this.minRateChange = source["minRateChange"];
+	        this.minCount = source["minCount"];
+	        this.topN = source["topN"];
+	    }
+	}
+	export class PathChange {
+	    path: string;
+	    requests: Change;
+	    errorRate: Change;
+	    requestTime: Change;
+	
+	    static createFrom(source: any = {}) {
+	        return new PathChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.requests = this.convertValues(source["requests"], Change);
+	        this.errorRate = this.convertValues(source["errorRate"], Change);
+	        this.requestTime = this.convertValues(source["requestTime"], Change);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StatusChange {
+	    statusCode: number;
+	    count: Change;
+	    share: Change;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statusCode = source["statusCode"];
+	        this.count = this.convertValues(source["count"], Change);
+	        this.share = this.convertValues(source["share"], Change);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    options: Options;
+	    beforeLabel: string;
+	    afterLabel: string;
+	    summary: Change[];
+	    statusCodes: StatusChange[];
+	    newPaths: PathChange[];
+	    vanishedPaths: PathChange[];
+	    changedPaths: PathChange[];
+	    latencyRegressions: PathChange[];
+	    newTopIPs: Change[];
+	    newBots: Change[];
+	    incomplete: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.options = this.convertValues(source["options"], Options);
+	        this.beforeLabel = source["beforeLabel"];
+	        this.afterLabel = source["afterLabel"];
+	        this.summary = this.convertValues(source["summary"], Change);
+	        this.statusCodes = this.convertValues(source["statusCodes"], StatusChange);
+	        this.newPaths = this.convertValues(source["newPaths"], PathChange);
+	        this.vanishedPaths = this.convertValues(source["vanishedPaths"], PathChange);
+	        this.changedPaths = this.convertValues(source["changedPaths"], PathChange);
+	        this.latencyRegressions = this.convertValues(source["latencyRegressions"], PathChange);
+	        this.newTopIPs = this.convertValues(source["newTopIPs"], Change);
+	        this.newBots = this.convertValues(source["newBots"], Change);
+	        this.incomplete = source["incomplete"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace erroranalysis {
+	
+	export class PathCount {
+	    path: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PathCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.count = source["count"];
+	    }
+	}
+	export class StatusCount {
+	    statusCode: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statusCode = source["statusCode"];
+	        this.count = source["count"];
+	    }
+	}
+	export class Burst {
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    count: number;
+	    clientErrors: number;
+	    serverErrors: number;
+	    firstLine: number;
+	    lastLine: number;
+	    statusCodes: StatusCount[];
+	    topPaths: PathCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Burst(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.count = source["count"];
+	        this.clientErrors = source["clientErrors"];
+	        this.serverErrors = source["serverErrors"];
+	        this.firstLine = source["firstLine"];
+	        this.lastLine = source["lastLine"];
+	        this.statusCodes = this.convertValues(source["statusCodes"], StatusCount);
+	        this.topPaths = this.convertValues(source["topPaths"], PathCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Options {
+	    path: string;
+	    bucket: string;
+	    minRequests: number;
+	    topN: number;
+	    sampleSize: number;
+	    burstMinLength: number;
+	    burstGap: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.bucket = source["bucket"];
+	        this.minRequests = source["minRequests"];
+	        this.topN = source["topN"];
+	        this.sampleSize = source["sampleSize"];
+	        this.burstMinLength = source["burstMinLength"];
+	        this.burstGap = source["burstGap"];
+	    }
+	}
+	
+	export class TimelinePoint {
+	    // Go type: time
+	    start: any;
+	    requests: number;
+	    clientErrors: number;
+	    serverErrors: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimelinePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], null);
+	        this.requests = source["requests"];
+	        this.clientErrors = source["clientErrors"];
+	        this.serverErrors = source["serverErrors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PathErrors {
+	    path: string;
+	    requests: number;
+	    clientErrors: number;
+	    serverErrors: number;
+	    errorRate: number;
+	    statusCodes: StatusCount[];
+	    timeline: TimelinePoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PathErrors(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.requests = source["requests"];
+	        this.clientErrors = source["clientErrors"];
+	        this.serverErrors = source["serverErrors"];
+	        this.errorRate = source["errorRate"];
+	        this.statusCodes = this.convertValues(source["statusCodes"], StatusCount);
+	        this.timeline = this.convertValues(source["timeline"], TimelinePoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StatusSample {
+	    statusCode: number;
+	    count: number;
+	    entries: models.LogEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusSample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statusCode = source["statusCode"];
+	        this.count = source["count"];
+	        this.entries = this.convertValues(source["entries"], models.LogEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    options: Options;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    buckets: number;
+	    totalRequests: number;
+	    clientErrors: number;
+	    serverErrors: number;
+	    byCount: PathErrors[];
+	    byRate: PathErrors[];
+	    bursts: Burst[];
+	    samples: StatusSample[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.options = this.convertValues(source["options"], Options);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.buckets = source["buckets"];
+	        this.totalRequests = source["totalRequests"];
+	        this.clientErrors = source["clientErrors"];
+	        this.serverErrors = source["serverErrors"];
+	        this.byCount = this.convertValues(source["byCount"], PathErrors);
+	        this.byRate = this.convertValues(source["byRate"], PathErrors);
+	        this.bursts = this.convertValues(source["bursts"], Burst);
+	        this.samples = this.convertValues(source["samples"], StatusSample);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+
+}
+
+export namespace filter {
+	
+	export class SizeRange {
+	    min?: number;
+	    max?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.min = source["min"];
+	        this.max = source["max"];
+	    }
+	}
+	export class TimeRange {
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StatusCodeRange {
+	    min: number;
+	    max: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StatusCodeRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.min = source["min"];
+	        this.max = source["max"];
+	    }
+	}
+	export class FilterCriteria {
+	    statusCodes?: number[];
+	    statusCodeRange?: StatusCodeRange;
+	    timeRange?: TimeRange;
+	    methods?: string[];
+	    sizeRange?: SizeRange;
+	    virtualHost?: string;
+	    server?: string;
+	    matchNone?: boolean;
+	    ip?: string;
+	    url?: string;
+	    userAgent?: string;
+	    user?: string;
+	    keyword?: string;
+	    caseSensitive?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilterCriteria(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statusCodes = source["statusCodes"];
+	        this.statusCodeRange = this.convertValues(source["statusCodeRange"], StatusCodeRange);
+	        this.timeRange = this.convertValues(source["timeRange"], TimeRange);
+	        this.methods = source["methods"];
+	        this.sizeRange = this.convertValues(source["sizeRange"], SizeRange);
+	        this.virtualHost = source["virtualHost"];
+	        this.server = source["server"];
+	        this.matchNone = source["matchNone"];
+	        this.ip = source["ip"];
+	        this.url = source["url"];
+	        this.userAgent = source["userAgent"];
+	        this.user = source["user"];
+	        this.keyword = source["keyword"];
+	        this.caseSensitive = source["caseSensitive"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SortSpec {
+	    field: string;
+	    descending: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SortSpec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.descending = source["descending"];
+	    }
+	}
+	
+
+}
+
+export namespace funnel {
+	
+	export class Step {
+	    name: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Step(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class Definition {
+	    steps: Step[];
+	    maxStepGap: string;
+	    keyBy: string;
+	    session: session.Options;
+	
+	    static createFrom(source: any = {}) {
+	        return new Definition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.steps = this.convertValues(source["steps"], Step);
+	        this.maxStepGap = source["maxStepGap"];
+	        this.keyBy = source["keyBy"];
+	        this.session = this.convertValues(source["session"], session.Options);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StepChange {
+	    name: string;
+	    countDelta: number;
+	    conversionDelta: number;
+	    dropOffRateDelta: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StepChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.countDelta = source["countDelta"];
+	        this.conversionDelta = source["conversionDelta"];
+	        this.dropOffRateDelta = source["dropOffRateDelta"];
+	    }
+	}
+	export class StepResult {
+	    name: string;
+	    pattern: string;
+	    count: number;
+	    conversionRate: number;
+	    stepConversion: number;
+	    dropOff: number;
+	    dropOffRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StepResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pattern = source["pattern"];
+	        this.count = source["count"];
+	        this.conversionRate = source["conversionRate"];
+	        this.stepConversion = source["stepConversion"];
+	        this.dropOff = source["dropOff"];
+	        this.dropOffRate = source["dropOffRate"];
+	    }
+	}
+	export class Result {
+	    range: aggregate.TimeRange;
+	    units: number;
+	    steps: StepResult[];
+	    overallConversion: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.range = this.convertValues(source["range"], aggregate.TimeRange);
+	        this.units = source["units"];
+	        this.steps = this.convertValues(source["steps"], StepResult);
+	        this.overallConversion = source["overallConversion"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    definition: Definition;
+	    current?: Result;
+	    previous?: Result;
+	    changes: StepChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.definition = this.convertValues(source["definition"], Definition);
+	        this.current = this.convertValues(source["current"], Result);
+	        this.previous = this.convertValues(source["previous"], Result);
+	        this.changes = this.convertValues(source["changes"], StepChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+
+}
+
+export namespace geoip {
+	
+	export class DatabaseInfo {
+	    path: string;
+	    databaseType: string;
+	    kind: string;
+	    ipVersion: number;
+	    // Go type: time
+	    buildTime: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DatabaseInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.databaseType = source["databaseType"];
+	        this.kind = source["kind"];
+	        this.ipVersion = source["ipVersion"];
+	        this.buildTime = this.convertValues(source["buildTime"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Location {
+	    country?: string;
+	    countryName?: string;
+	    city?: string;
+	    asn?: number;
+	    organization?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Location(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.country = source["country"];
+	        this.countryName = source["countryName"];
+	        this.city = source["city"];
+	        this.asn = source["asn"];
+	        this.organization = source["organization"];
+	    }
+	}
+
+}
+
+export namespace models {
+	
+	export class LogEntry {
+	    ip: string;
+	    // Go type: time
+	    timestamp: any;
+	    method: string;
+	    url: string;
+	    protocol: string;
+	    statusCode: number;
+	    responseBytes: number;
+	    referer: string;
+	    userAgent: string;
+	    user?: string;
+	    requestTime?: number;
+	    virtualHost?: string;
+	    serverPort?: number;
+	    server?: string;
+	    cacheStatus?: string;
+	    lineNumber: number;
+	    rawLine: string;
+	    parseError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.protocol = source["protocol"];
+	        this.statusCode = source["statusCode"];
+	        this.responseBytes = source["responseBytes"];
+	        this.referer = source["referer"];
+	        this.userAgent = source["userAgent"];
+	        this.user = source["user"];
+	        this.requestTime = source["requestTime"];
+	        this.virtualHost = source["virtualHost"];
+	        this.serverPort = source["serverPort"];
+	        this.server = source["server"];
+	        this.cacheStatus = source["cacheStatus"];
+	        this.lineNumber = source["lineNumber"];
+	        this.rawLine = source["rawLine"];
+	        this.parseError = source["parseError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LogFileSummary {
+	    path: string;
+	    name: string;
+	    size: number;
+	    // Go type: time
+	    loadedAt: any;
+	    totalLines: number;
+	    parsedLines: number;
+	    errorLines: number;
+	    statistics: any;
+	    parseTime: number;
+	    statTime: number;
+	    memoryUsed: number;
+	    indexTime: number;
+	    indexMemory: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogFileSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.loadedAt = this.convertValues(source["loadedAt"], null);
+	        this.totalLines = source["totalLines"];
+	        this.parsedLines = source["parsedLines"];
+	        this.errorLines = source["errorLines"];
+	        this.statistics = source["statistics"];
+	        this.parseTime = source["parseTime"];
+	        this.statTime = source["statTime"];
+	        this.memoryUsed = source["memoryUsed"];
+	        this.indexTime = source["indexTime"];
+	        this.indexMemory = source["indexMemory"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace parser {
+	
+	export class ParseError {
+	    lineNumber: number;
+	    line: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParseError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lineNumber = source["lineNumber"];
+	        this.line = source["line"];
+	        this.error = source["error"];
+	    }
+	}
+
+}
+
+export namespace query {
+	
+	export class SyntaxError {
+	    position: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyntaxError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.position = source["position"];
+	        this.message = source["message"];
+	    }
+	}
+
+}
+
+export namespace security {
+	
+	export class AttackerStat {
+	    ip: string;
+	    hits: number;
+	    successful: number;
+	    categories: string[];
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AttackerStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.hits = source["hits"];
+	        this.successful = source["successful"];
+	        this.categories = source["categories"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BruteForceConfig {
+	    endpoints: string[];
+	    window: string;
+	    ipThreshold: number;
+	    userThreshold: number;
+	    distributedIPThreshold: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BruteForceConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoints = source["endpoints"];
+	        this.window = source["window"];
+	        this.ipThreshold = source["ipThreshold"];
+	        this.userThreshold = source["userThreshold"];
+	        this.distributedIPThreshold = source["distributedIPThreshold"];
+	    }
+	}
+	export class BruteForceIncident {
+	    kind: string;
+	    key: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    failures: number;
+	    distinctIPs: number;
+	    distinctUsers: number;
+	    endpoints: string[];
+	    succeeded: boolean;
+	    // Go type: time
+	    successTime: any;
+	    successIP: string;
+	    successUser: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BruteForceIncident(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.key = source["key"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.failures = source["failures"];
+	        this.distinctIPs = source["distinctIPs"];
+	        this.distinctUsers = source["distinctUsers"];
+	        this.endpoints = source["endpoints"];
+	        this.succeeded = source["succeeded"];
+	        this.successTime = this.convertValues(source["successTime"], null);
+	        this.successIP = source["successIP"];
+	        this.successUser = source["successUser"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BruteForceReport {
+	    authAttempts: number;
+	    failedAttempts: number;
+	    incidents: BruteForceIncident[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BruteForceReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.authAttempts = source["authAttempts"];
+	        this.failedAttempts = source["failedAttempts"];
+	        this.incidents = this.convertValues(source["incidents"], BruteForceIncident);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ThreatExample {
+	    lineNumber: number;
+	    ip: string;
+	    // Go type: time
+	    timestamp: any;
+	    method: string;
+	    url: string;
+	    statusCode: number;
+	    successful: boolean;
+	    rawLine: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThreatExample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lineNumber = source["lineNumber"];
+	        this.ip = source["ip"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.statusCode = source["statusCode"];
+	        this.successful = source["successful"];
+	        this.rawLine = source["rawLine"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ThreatRuleStat {
+	    id: string;
+	    name: string;
+	    category: string;
+	    severity: string;
+	    pathOnly: boolean;
+	    hits: number;
+	    successful: number;
+	    distinctIPs: number;
+	    examples: ThreatExample[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ThreatRuleStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.category = source["category"];
+	        this.severity = source["severity"];
+	        this.pathOnly = source["pathOnly"];
+	        this.hits = source["hits"];
+	        this.successful = source["successful"];
+	        this.distinctIPs = source["distinctIPs"];
+	        this.examples = this.convertValues(source["examples"], ThreatExample);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ThreatStats {
+	    attackRequests: number;
+	    successfulRequests: number;
+	    attackerCount: number;
+	    categories: Record<string, number>;
+	    rules: ThreatRuleStat[];
+	    attackers: AttackerStat[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ThreatStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.attackRequests = source["attackRequests"];
+	        this.successfulRequests = source["successfulRequests"];
+	        this.attackerCount = source["attackerCount"];
+	        this.categories = source["categories"];
+	        this.rules = this.convertValues(source["rules"], ThreatRuleStat);
+	        this.attackers = this.convertValues(source["attackers"], AttackerStat);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace session {
+	
+	export class DistributionBin {
+	    label: string;
+	    count: number;
+	    percentage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DistributionBin(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
+	    }
+	}
+	export class Options {
+	    timeout: string;
+	    includeBots: boolean;
+	    includeStatic: boolean;
+	    topN: number;
+	    sankeySteps: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timeout = source["timeout"];
+	        this.includeBots = source["includeBots"];
+	        this.includeStatic = source["includeStatic"];
+	        this.topN = source["topN"];
+	        this.sankeySteps = source["sankeySteps"];
+	    }
+	}
+	export class PageStat {
+	    path: string;
+	    count: number;
+	    percentage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PageStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
+	    }
+	}
+	export class SankeyLink {
+	    source: number;
+	    target: number;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SankeyLink(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.value = source["value"];
+	    }
+	}
+	export class SankeyNode {
+	    name: string;
+	    step: number;
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SankeyNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.step = source["step"];
+	        this.path = source["path"];
+	    }
+	}
+	export class SankeyData {
+	    nodes: SankeyNode[];
+	    links: SankeyLink[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SankeyData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.nodes = this.convertValues(source["nodes"], SankeyNode);
+	        this.links = this.convertValues(source["links"], SankeyLink);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Transition {
+	    from: string;
+	    to: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Transition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.count = source["count"];
+	    }
+	}
+	export class Report {
+	    options: Options;
+	    sessions: number;
+	    visitors: number;
+	    pageViews: number;
+	    excludedBotRequests: number;
+	    bounceRate: number;
+	    avgDuration: number;
+	    medianDuration: number;
+	    avgDepth: number;
+	    durationDistribution: DistributionBin[];
+	    depthDistribution: DistributionBin[];
+	    entryPages: PageStat[];
+	    exitPages: PageStat[];
+	    transitions: Transition[];
+	    sankey: SankeyData;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.options = this.convertValues(source["options"], Options);
+	        this.sessions = source["sessions"];
+	        this.visitors = source["visitors"];
+	        this.pageViews = source["pageViews"];
+	        this.excludedBotRequests = source["excludedBotRequests"];
+	        this.bounceRate = source["bounceRate"];
+	        this.avgDuration = source["avgDuration"];
+	        this.medianDuration = source["medianDuration"];
+	        this.avgDepth = source["avgDepth"];
+	        this.durationDistribution = this.convertValues(source["durationDistribution"], DistributionBin);
+	        this.depthDistribution = this.convertValues(source["depthDistribution"], DistributionBin);
+	        this.entryPages = this.convertValues(source["entryPages"], PageStat);
+	        this.exitPages = this.convertValues(source["exitPages"], PageStat);
+	        this.transitions = this.convertValues(source["transitions"], Transition);
+	        this.sankey = this.convertValues(source["sankey"], SankeyData);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+
+}
+
+export namespace stats {
+	
+	export class BotAgentStat {
+	    name: string;
+	    vendor: string;
+	    category: string;
+	    versions: string[];
+	    count: number;
+	    percentage: number;
+	    bytes: number;
+	    distinctIPs: number;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    verified: number;
+	    spoofed: number;
+	    unverifiable: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotAgentStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.vendor = source["vendor"];
+	        this.category = source["category"];
+	        this.versions = source["versions"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
+	        this.bytes = source["bytes"];
+	        this.distinctIPs = source["distinctIPs"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.verified = source["verified"];
+	        this.spoofed = source["spoofed"];
+	        this.unverifiable = source["unverifiable"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BotIPStat {
+	    ip: string;
+	    botType: string;
+	    confidence: string;
+	    score: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotIPStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.botType = source["botType"];
+	        this.confidence = source["confidence"];
+	        this.score = source["score"];
+	        this.count = source["count"];
+	    }
+	}
+	export class BotRule {
+	    name: string;
+	    vendor?: string;
+	    category: string;
+	    substring?: string;
+	    regex?: string;
+	    priority: number;
+	    generic?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.vendor = source["vendor"];
+	        this.category = source["category"];
+	        this.substring = source["substring"];
+	        this.regex = source["regex"];
+	        this.priority = source["priority"];
+	        this.generic = source["generic"];
+	    }
+	}
+	export class BotScoreReason {
+	    signal: string;
+	    points: number;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotScoreReason(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.signal = source["signal"];
+	        this.points = source["points"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class BotScore {
+	    ip: string;
+	    userAgent: string;
+	    score: number;
+	    level: string;
+	    requests: number;
+	    reasons: BotScoreReason[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BotScore(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.userAgent = source["userAgent"];
+	        this.score = source["score"];
+	        this.level = source["level"];
+	        this.requests = source["requests"];
+	        this.reasons = this.convertValues(source["reasons"], BotScoreReason);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BotStat {
+	    name: string;
+	    count: number;
+	    percentage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
+	    }
+	}
+	export class SpoofedCrawlerStat {
+	    ip: string;
+	    claimedName: string;
+	    category: string;
+	    count: number;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpoofedCrawlerStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.claimedName = source["claimedName"];
+	        this.category = source["category"];
+	        this.count = source["count"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BotVerificationStats {
+	    verified: number;
+	    spoofed: number;
+	    unverifiable: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BotVerificationStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.verified = source["verified"];
+	        this.spoofed = source["spoofed"];
+	        this.unverifiable = source["unverifiable"];
+	    }
+	}
+	export class BotStats {
+	    total: number;
+	    botRequests: number;
+	    humanRequests: number;
+	    botPercentage: number;
+	    botTypes: Record<string, number>;
+	    topBots: BotStat[];
+	    botIPs: BotIPStat[];
+	    agents: BotAgentStat[];
+	    verification: BotVerificationStats;
+	    spoofedCrawlers: SpoofedCrawlerStat[];
+	    suspectedBots: BotScore[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BotStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.botRequests = source["botRequests"];
+	        this.humanRequests = source["humanRequests"];
+	        this.botPercentage = source["botPercentage"];
+	        this.botTypes = source["botTypes"];
+	        this.topBots = this.convertValues(source["topBots"], BotStat);
+	        this.botIPs = this.convertValues(source["botIPs"], BotIPStat);
+	        this.agents = this.convertValues(source["agents"], BotAgentStat);
+	        this.verification = this.convertValues(source["verification"], BotVerificationStats);
+	        this.spoofedCrawlers = this.convertValues(source["spoofedCrawlers"], SpoofedCrawlerStat);
+	        this.suspectedBots = this.convertValues(source["suspectedBots"], BotScore);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CampaignStatistics {
+	    source: string;
+	    medium: string;
+	    campaign: string;
+	    count: number;
+	    uniqueIPs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CampaignStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.medium = source["medium"];
+	        this.campaign = source["campaign"];
+	        this.count = source["count"];
+	        this.uniqueIPs = source["uniqueIPs"];
+	    }
+	}
+	export class CrawlerProfile {
+	    name: string;
+	    domains?: string[];
+	    cidrs?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CrawlerProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.domains = source["domains"];
+	        this.cidrs = source["cidrs"];
+	    }
+	}
+	export class DistributionItem {
+	    name: string;
+	    count: number;
+	    percentage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DistributionItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
+	    }
+	}
+	export class GeoStatistics {
+	    key: string;
+	    name: string;
+	    requestCount: number;
+	    totalBytes: number;
+	    uniqueIPs: number;
+	    errorRate: number;
+	    botShare: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GeoStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.name = source["name"];
+	        this.requestCount = source["requestCount"];
+	        this.totalBytes = source["totalBytes"];
+	        this.uniqueIPs = source["uniqueIPs"];
+	        this.errorRate = source["errorRate"];
+	        this.botShare = source["botShare"];
+	    }
+	}
+	export class HotlinkStatistics {
+	    domain: string;
+	    path: string;
+	    count: number;
+	    bytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HotlinkStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.path = source["path"];
+	        this.count = source["count"];
+	        this.bytes = source["bytes"];
+	    }
+	}
+	export class IPStatistics {
+	    ip: string;
+	    requestCount: number;
+	    totalBytes: number;
+	    country?: string;
+	    city?: string;
+	    asn?: number;
+	    organization?: string;
+	    group?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IPStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.requestCount = source["requestCount"];
+	        this.totalBytes = source["totalBytes"];
+	        this.country = source["country"];
+	        this.city = source["city"];
+	        this.asn = source["asn"];
+	        this.organization = source["organization"];
+	        this.group = source["group"];
+	    }
+	}
+	export class LatencyStatistics {
+	    samples: number;
+	    average: number;
+	    p50: number;
+	    p95: number;
+	    p99: number;
+	    max: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LatencyStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.samples = source["samples"];
+	        this.average = source["average"];
+	        this.p50 = source["p50"];
+	        this.p95 = source["p95"];
+	        this.p99 = source["p99"];
+	        this.max = source["max"];
+	    }
+	}
+	export class NotFoundPath {
+	    path: string;
+	    count: number;
+	    goneCount: number;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    botHits: number;
+	    humanHits: number;
+	    internalReferers: DistributionItem[];
+	    externalReferers: DistributionItem[];
+	    redirectCount: number;
+	    // Go type: time
+	    lastRedirect: any;
+	    redirectedFrom: DistributionItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotFoundPath(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.count = source["count"];
+	        this.goneCount = source["goneCount"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.botHits = source["botHits"];
+	        this.humanHits = source["humanHits"];
+	        this.internalReferers = this.convertValues(source["internalReferers"], DistributionItem);
+	        this.externalReferers = this.convertValues(source["externalReferers"], DistributionItem);
+	        this.redirectCount = source["redirectCount"];
+	        this.lastRedirect = this.convertValues(source["lastRedirect"], null);
+	        this.redirectedFrom = this.convertValues(source["redirectedFrom"], DistributionItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotFoundReport {
+	    totalRequests: number;
+	    botRequests: number;
+	    uniquePaths: number;
+	    paths: NotFoundPath[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotFoundReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.totalRequests = source["totalRequests"];
+	        this.botRequests = source["botRequests"];
+	        this.uniquePaths = source["uniquePaths"];
+	        this.paths = this.convertValues(source["paths"], NotFoundPath);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ExportToExcelRequest {
-	    filePath: string;
-	    savePath: string;
+	export class PathStatistics {
+	    path: string;
+	    requestCount: number;
+	    averageSize: number;
+	    errorRate: number;
+	    averageRequestTime?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExportToExcelRequest(source);
+	        return new PathStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.filePath = source["filePath"];
-	        this.savePath = source["savePath"];
+	        this.path = source["path"];
+	        this.requestCount = source["requestCount"];
+	        this.averageSize = source["averageSize"];
+	        this.errorRate = source["errorRate"];
+	        this.averageRequestTime = source["averageRequestTime"];
 	    }
 	}
-	export class ExportToExcelResponse {
-	    success: boolean;
-	    exportPath: string;
-	    fileSize: number;
-	    totalRecords: number;
-	    truncatedRows: number;
-	    duration: string;
-	    warnings: string[];
-	    errorMessage: string;
+	export class RefererDomain {
+	    domain: string;
+	    channel: string;
+	    count: number;
+	    percentage: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExportToExcelResponse(source);
+	        return new RefererDomain(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.exportPath = source["exportPath"];
-	        this.fileSize = source["fileSize"];
-	        this.totalRecords = source["totalRecords"];
-	        this.truncatedRows = source["truncatedRows"];
-	        this.duration = source["duration"];
-	        this.warnings = source["warnings"];
-	        this.errorMessage = source["errorMessage"];
+	        this.domain = source["domain"];
+	        this.channel = source["channel"];
+	        this.count = source["count"];
+	        this.percentage = source["percentage"];
 	    }
 	}
-	export class RecentFile {
-	    path: string;
-	    name: string;
-	    size: number;
-	    openedAt: string;
-	    totalLines: number;
+	export class RefererStatistics {
+	    direct: number;
+	    internal: number;
+	    external: number;
+	    channels: DistributionItem[];
+	    domains: RefererDomain[];
+	    searchEngines: DistributionItem[];
+	    campaigns: CampaignStatistics[];
+	    hotlinks: HotlinkStatistics[];
+	    hotlinkRequests: number;
+	    hotlinkBytes: number;
+	    siteHosts: string[];
+	    siteHostsSource: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new RecentFile(source);
+	        return new RefererStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.openedAt = source["openedAt"];
-	        this.totalLines = source["totalLines"];
+	        this.direct = source["direct"];
+	        this.internal = source["internal"];
+	        this.external = source["external"];
+	        this.channels = this.convertValues(source["channels"], DistributionItem);
+	        this.domains = this.convertValues(source["domains"], RefererDomain);
+	        this.searchEngines = this.convertValues(source["searchEngines"], DistributionItem);
+	        this.campaigns = this.convertValues(source["campaigns"], CampaignStatistics);
+	        this.hotlinks = this.convertValues(source["hotlinks"], HotlinkStatistics);
+	        this.hotlinkRequests = source["hotlinkRequests"];
+	        this.hotlinkBytes = source["hotlinkBytes"];
+	        this.siteHosts = source["siteHosts"];
+	        this.siteHostsSource = source["siteHostsSource"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class GetRecentFilesResponse {
-	    success: boolean;
-	    files: RecentFile[];
+	
+	export class UserActivity {
+	    user: string;
+	    requestCount: number;
+	    uniquePaths: number;
+	    errorCount: number;
+	    errorRate: number;
+	    activeHours: number[];
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    uniqueIPs: number;
+	    ips: DistributionItem[];
+	    countries: string[];
+	    manyIPs: boolean;
+	    manyCountries: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new GetRecentFilesResponse(source);
+	        return new UserActivity(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.files = this.convertValues(source["files"], RecentFile);
+	        this.user = source["user"];
+	        this.requestCount = source["requestCount"];
+	        this.uniquePaths = source["uniquePaths"];
+	        this.errorCount = source["errorCount"];
+	        this.errorRate = source["errorRate"];
+	        this.activeHours = source["activeHours"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.uniqueIPs = source["uniqueIPs"];
+	        this.ips = this.convertValues(source["ips"], DistributionItem);
+	        this.countries = source["countries"];
+	        this.manyIPs = source["manyIPs"];
+	        this.manyCountries = source["manyCountries"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -106,34 +4201,66 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class ParseFileRequest {
-	    filePath: string;
+	export class UserStatistics {
+	    authenticatedRequests: number;
+	    uniqueUsers: number;
+	    topUsers: UserActivity[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ParseFileRequest(source);
+	        return new UserStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.filePath = source["filePath"];
+	        this.authenticatedRequests = source["authenticatedRequests"];
+	        this.uniqueUsers = source["uniqueUsers"];
+	        this.topUsers = this.convertValues(source["topUsers"], UserActivity);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ParseFileResponse {
-	    success: boolean;
-	    logFile?: models.LogFile;
-	    errorMessage: string;
-	    errorSamples: parser.ParseError[];
+	export class VirtualHostStatistics {
+	    host: string;
+	    ports: number[];
+	    requestCount: number;
+	    percentage: number;
+	    totalBytes: number;
+	    errorCount: number;
+	    errorRate: number;
+	    uniqueIPs: number;
+	    topPaths: DistributionItem[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ParseFileResponse(source);
+	        return new VirtualHostStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.logFile = this.convertValues(source["logFile"], models.LogFile);
-	        this.errorMessage = source["errorMessage"];
-	        this.errorSamples = this.convertValues(source["errorSamples"], parser.ParseError);
+	        this.host = source["host"];
+	        this.ports = source["ports"];
+	        this.requestCount = source["requestCount"];
+	        this.percentage = source["percentage"];
+	        this.totalBytes = source["totalBytes"];
+	        this.errorCount = source["errorCount"];
+	        this.errorRate = source["errorRate"];
+	        this.uniqueIPs = source["uniqueIPs"];
+	        this.topPaths = this.convertValues(source["topPaths"], DistributionItem);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -154,109 +4281,182 @@ export namespace app {
 		    return a;
 		}
 	}
-	
-	export class SelectFileResponse {
-	    success: boolean;
-	    filePath: string;
-	    errorMessage: string;
+	export class UserAgentStatistics {
+	    browsers: DistributionItem[];
+	    browserVersions: DistributionItem[];
+	    operatingSystems: DistributionItem[];
+	    devices: DistributionItem[];
+	    engines: DistributionItem[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SelectFileResponse(source);
+	        return new UserAgentStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.filePath = source["filePath"];
-	        this.errorMessage = source["errorMessage"];
+	        this.browsers = this.convertValues(source["browsers"], DistributionItem);
+	        this.browserVersions = this.convertValues(source["browserVersions"], DistributionItem);
+	        this.operatingSystems = this.convertValues(source["operatingSystems"], DistributionItem);
+	        this.devices = this.convertValues(source["devices"], DistributionItem);
+	        this.engines = this.convertValues(source["engines"], DistributionItem);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class SelectSaveLocationResponse {
-	    success: boolean;
-	    savePath: string;
-	    errorMessage: string;
+	export class SubnetStatistics {
+	    subnet: string;
+	    group?: string;
+	    requestCount: number;
+	    totalBytes: number;
+	    uniqueIPs: number;
+	    errorRate: number;
+	    botShare: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new SelectSaveLocationResponse(source);
+	        return new SubnetStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.savePath = source["savePath"];
-	        this.errorMessage = source["errorMessage"];
+	        this.subnet = source["subnet"];
+	        this.group = source["group"];
+	        this.requestCount = source["requestCount"];
+	        this.totalBytes = source["totalBytes"];
+	        this.uniqueIPs = source["uniqueIPs"];
+	        this.errorRate = source["errorRate"];
+	        this.botShare = source["botShare"];
 	    }
 	}
-	export class ValidateFormatRequest {
-	    filePath: string;
+	export class StatusCodeStatistics {
+	    success: number;
+	    redirection: number;
+	    clientError: number;
+	    serverError: number;
+	    details: Record<number, number>;
 	
 	    static createFrom(source: any = {}) {
-	        return new ValidateFormatRequest(source);
+	        return new StatusCodeStatistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.filePath = source["filePath"];
+	        this.success = source["success"];
+	        this.redirection = source["redirection"];
+	        this.clientError = source["clientError"];
+	        this.serverError = source["serverError"];
+	        this.details = source["details"];
 	    }
 	}
-	export class ValidateFormatResponse {
-	    success: boolean;
-	    valid: boolean;
-	    errorMessage: string;
+	export class Statistics {
+	    totalRequests: number;
+	    uniqueIPs: number;
+	    uniquePaths: number;
+	    totalBytes: number;
+	    averageResponseSize: number;
+	    topIPs: IPStatistics[];
+	    topPaths: PathStatistics[];
+	    statusCodeDistribution: StatusCodeStatistics;
+	    botStats: BotStats;
+	    threats: security.ThreatStats;
+	    countries: GeoStatistics[];
+	    asns: GeoStatistics[];
+	    topSubnets: SubnetStatistics[];
+	    cidrGroups: GeoStatistics[];
+	    userAgents: UserAgentStatistics;
+	    referers: RefererStatistics;
+	    notFound: NotFoundReport;
+	    latency: LatencyStatistics;
+	    virtualHosts: VirtualHostStatistics[];
+	    servers: VirtualHostStatistics[];
+	    users: UserStatistics;
 	
 	    static createFrom(source: any = {}) {
-	        return new ValidateFormatResponse(source);
+	        return new Statistics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.valid = source["valid"];
-	        this.errorMessage = source["errorMessage"];
+	        this.totalRequests = source["totalRequests"];
+	        this.uniqueIPs = source["uniqueIPs"];
+	        this.uniquePaths = source["uniquePaths"];
+	        this.totalBytes = source["totalBytes"];
+	        this.averageResponseSize = source["averageResponseSize"];
+	        this.topIPs = this.convertValues(source["topIPs"], IPStatistics);
+	        this.topPaths = this.convertValues(source["topPaths"], PathStatistics);
+	        this.statusCodeDistribution = this.convertValues(source["statusCodeDistribution"], StatusCodeStatistics);
+	        this.botStats = this.convertValues(source["botStats"], BotStats);
+	        this.threats = this.convertValues(source["threats"], security.ThreatStats);
+	        this.countries = this.convertValues(source["countries"], GeoStatistics);
+	        this.asns = this.convertValues(source["asns"], GeoStatistics);
+	        this.topSubnets = this.convertValues(source["topSubnets"], SubnetStatistics);
+	        this.cidrGroups = this.convertValues(source["cidrGroups"], GeoStatistics);
+	        this.userAgents = this.convertValues(source["userAgents"], UserAgentStatistics);
+	        this.referers = this.convertValues(source["referers"], RefererStatistics);
+	        this.notFound = this.convertValues(source["notFound"], NotFoundReport);
+	        this.latency = this.convertValues(source["latency"], LatencyStatistics);
+	        this.virtualHosts = this.convertValues(source["virtualHosts"], VirtualHostStatistics);
+	        this.servers = this.convertValues(source["servers"], VirtualHostStatistics);
+	        this.users = this.convertValues(source["users"], UserStatistics);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-
-}
-
-export namespace models {
 	
-	export class LogEntry {
-	    ip: string;
+	
+	
+	
+	
+	export class UserTimelinePoint {
 	    // Go type: time
-	    timestamp: any;
-	    method: string;
-	    url: string;
-	    protocol: string;
-	    statusCode: number;
-	    responseBytes: number;
-	    referer: string;
-	    userAgent: string;
-	    user?: string;
-	    requestTime?: number;
-	    lineNumber: number;
-	    rawLine: string;
-	    parseError?: string;
+	    start: any;
+	    requests: number;
+	    errors: number;
+	    uniquePaths: number;
+	    uniqueIPs: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new LogEntry(source);
+	        return new UserTimelinePoint(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ip = source["ip"];
-	        this.timestamp = this.convertValues(source["timestamp"], null);
-	        this.method = source["method"];
-	        this.url = source["url"];
-	        this.protocol = source["protocol"];
-	        this.statusCode = source["statusCode"];
-	        this.responseBytes = source["responseBytes"];
-	        this.referer = source["referer"];
-	        this.userAgent = source["userAgent"];
-	        this.user = source["user"];
-	        this.requestTime = source["requestTime"];
-	        this.lineNumber = source["lineNumber"];
-	        this.rawLine = source["rawLine"];
-	        this.parseError = source["parseError"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.requests = source["requests"];
+	        this.errors = source["errors"];
+	        this.uniquePaths = source["uniquePaths"];
+	        this.uniqueIPs = source["uniqueIPs"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -277,39 +4477,26 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class LogFile {
-	    path: string;
-	    name: string;
-	    size: number;
+	export class UserTimeline {
+	    user: string;
+	    bucket: string;
 	    // Go type: time
-	    loadedAt: any;
-	    totalLines: number;
-	    parsedLines: number;
-	    errorLines: number;
-	    entries: LogEntry[];
-	    statistics: any;
-	    parseTime: number;
-	    statTime: number;
-	    memoryUsed: number;
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    points: UserTimelinePoint[];
 	
 	    static createFrom(source: any = {}) {
-	        return new LogFile(source);
+	        return new UserTimeline(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.loadedAt = this.convertValues(source["loadedAt"], null);
-	        this.totalLines = source["totalLines"];
-	        this.parsedLines = source["parsedLines"];
-	        this.errorLines = source["errorLines"];
-	        this.entries = this.convertValues(source["entries"], LogEntry);
-	        this.statistics = source["statistics"];
-	        this.parseTime = source["parseTime"];
-	        this.statTime = source["statTime"];
-	        this.memoryUsed = source["memoryUsed"];
+	        this.user = source["user"];
+	        this.bucket = source["bucket"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.points = this.convertValues(source["points"], UserTimelinePoint);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -330,25 +4517,65 @@ export namespace models {
 		    return a;
 		}
 	}
+	
 
 }
 
-export namespace parser {
+export namespace subnet {
 	
-	export class ParseError {
-	    lineNumber: number;
-	    line: string;
-	    error: string;
+	export class Group {
+	    name: string;
+	    cidrs: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ParseError(source);
+	        return new Group(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.lineNumber = source["lineNumber"];
-	        this.line = source["line"];
-	        this.error = source["error"];
+	        this.name = source["name"];
+	        this.cidrs = source["cidrs"];
+	    }
+	}
+	export class Options {
+	    ipv4Prefix: number;
+	    ipv6Prefix: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ipv4Prefix = source["ipv4Prefix"];
+	        this.ipv6Prefix = source["ipv6Prefix"];
+	    }
+	}
+
+}
+
+export namespace useragent {
+	
+	export class UserAgent {
+	    browser: string;
+	    browserVersion: string;
+	    os: string;
+	    osVersion: string;
+	    device: string;
+	    engine: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserAgent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.browser = source["browser"];
+	        this.browserVersion = source["browserVersion"];
+	        this.os = source["os"];
+	        this.osVersion = source["osVersion"];
+	        this.device = source["device"];
+	        this.engine = source["engine"];
 	    }
 	}
 
@@ -0,0 +1,113 @@
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// TimeRange 時間範圍，Start 含、End 不含；零值表示不限制
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains 判斷時間是否在範圍內
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Start.IsZero() && t.Before(r.Start) {
+		return false
+	}
+	if !r.End.IsZero() && !t.Before(r.End) {
+		return false
+	}
+	return true
+}
+
+// IsZero 判斷是否未限制時間範圍
+func (r TimeRange) IsZero() bool {
+	return r.Start.IsZero() && r.End.IsZero()
+}
+
+// Chronological 返回有時間戳記且符合 match 的記錄索引，依時間排序（時間相同時保持原順序）
+// match 為 nil 時不篩選；記錄已依時間排序時不重新排序
+func Chronological(entries []models.LogEntry, match func(*models.LogEntry) bool) []int {
+	indexes := make([]int, 0, len(entries))
+	sorted := true
+	for i := range entries {
+		if entries[i].Timestamp.IsZero() {
+			continue
+		}
+		if match != nil && !match(&entries[i]) {
+			continue
+		}
+		if n := len(indexes); n > 0 && entries[i].Timestamp.Before(entries[indexes[n-1]].Timestamp) {
+			sorted = false
+		}
+		indexes = append(indexes, i)
+	}
+	if !sorted {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return entries[indexes[a]].Timestamp.Before(entries[indexes[b]].Timestamp)
+		})
+	}
+	return indexes
+}
+
+// Percentage 計算百分比，total 為 0 時返回 0
+func Percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// Default 在 *value 為零值時設為 fallback，供各分析參數的 withDefaults 使用
+func Default[T comparable](value *T, fallback T) {
+	var zero T
+	if *value == zero {
+		*value = fallback
+	}
+}
+
+// ParseWindow 解析必須為正數的時間長度參數（例如 "5m"）
+// 無效時返回 ValidationError，message 為錯誤說明
+func ParseWindow(field, value, message string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, &models.ValidationError{Field: field, Value: value, Message: message}
+	}
+	return d, nil
+}
+
+// NonNegative 檢查參數不可為負數，否則以 message 返回 ValidationError
+func NonNegative[T int | float64](field string, value T, message string) error {
+	return AtLeast(field, value, 0, message)
+}
+
+// AtLeast 檢查參數不小於 min，否則以 message 返回 ValidationError
+func AtLeast[T int | float64](field string, value, min T, message string) error {
+	if value < min {
+		return &models.ValidationError{Field: field, Value: fmt.Sprint(value), Message: message}
+	}
+	return nil
+}
+
+// InRange 檢查參數介於 min 與 max 之間（含），否則以 message 返回 ValidationError
+func InRange[T int | float64](field string, value, min, max T, message string) error {
+	if value < min || value > max {
+		return &models.ValidationError{Field: field, Value: fmt.Sprint(value), Message: message}
+	}
+	return nil
+}
+
+// FirstError 返回第一個非 nil 的錯誤，依序列出參數檢查時使用
+func FirstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChronological 測試依時間排序記錄索引並略過沒有時間戳記的記錄
+func TestChronological(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []models.LogEntry{
+		{URL: "/b", Timestamp: base.Add(time.Minute)},
+		{URL: "/a", Timestamp: base},
+		{URL: "/a", Timestamp: base.Add(time.Minute)},
+		{ParseError: "無法匹配 log 格式"},
+	}
+
+	assert.Equal(t, []int{1, 0, 2}, Chronological(entries, nil), "時間相同時保持原順序")
+	assert.Equal(t, []int{1, 2}, Chronological(entries, func(e *models.LogEntry) bool { return e.URL == "/a" }))
+	assert.Equal(t, []int{0, 1}, Chronological(entries[1:3], nil), "已排序時不重新排序")
+}
+
+// TestPercentage 測試百分比計算
+func TestPercentage(t *testing.T) {
+	assert.InDelta(t, 25, Percentage(1, 4), 0.001)
+	assert.Zero(t, Percentage(1, 0))
+}
+
+// TestOptionHelpers 測試分析參數的預設值與驗證輔助函式
+func TestOptionHelpers(t *testing.T) {
+	window, topN := "", 5
+	Default(&window, "5m")
+	Default(&topN, 10)
+	assert.Equal(t, "5m", window)
+	assert.Equal(t, 5, topN, "已設定的值不覆蓋")
+
+	d, err := ParseWindow("Window", window, "無效的時間範圍")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, d)
+
+	var validationErr *models.ValidationError
+	for _, err := range []error{
+		func() error { _, err := ParseWindow("Window", "-1m", "無效的時間範圍"); return err }(),
+		NonNegative("TopN", -1, "TopN 不可為負數"),
+		AtLeast("Steps", 1, 2, "至少需要 2 步"),
+		InRange("Ratio", 1.5, 0, 1, "比例必須介於 0 到 1"),
+	} {
+		assert.ErrorAs(t, err, &validationErr)
+	}
+
+	assert.NoError(t, FirstError(nil, NonNegative("TopN", 0, "TopN 不可為負數")))
+	assert.EqualError(t, FirstError(nil, AtLeast("Steps", 1, 2, "第一個"), AtLeast("Steps", 1, 3, "第二個")),
+		AtLeast("Steps", 1, 2, "第一個").Error())
+}
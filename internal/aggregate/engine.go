@@ -0,0 +1,306 @@
+package aggregate
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+)
+
+// Result 聚合查詢的表格結果
+// 前端直接以 Columns/Rows 呈現，匯出器可透過 Table 取得二維字串陣列
+type Result struct {
+	Columns        []string    `json:"columns"`        // 欄位名稱（分組鍵在前，聚合值在後）
+	KeyColumns     int         `json:"keyColumns"`     // 分組鍵欄位數量
+	Rows           []ResultRow `json:"rows"`           // 結果列
+	MatchedEntries int         `json:"matchedEntries"` // 符合篩選條件的記錄數
+	TotalGroups    int         `json:"totalGroups"`    // 套用 Limit 前的分組數量
+}
+
+// ResultRow 聚合結果的一列
+type ResultRow struct {
+	Keys   []string  `json:"keys"`   // 分組鍵值
+	Values []float64 `json:"values"` // 聚合值（順序對應 Query.Aggregates）
+}
+
+// Engine 聚合查詢引擎
+// 對已載入的日誌記錄進行單次遍歷的分組聚合
+type Engine struct {
+	log *logger.Logger
+}
+
+// NewEngine 建立新的聚合查詢引擎
+func NewEngine() *Engine {
+	return &Engine{
+		log: logger.Get().WithModule("aggregate"),
+	}
+}
+
+// aggState 單一分組中單一聚合運算的累積狀態
+type aggState struct {
+	sum      float64
+	min      float64
+	max      float64
+	values   []float64           // 僅 percentile 使用
+	distinct map[string]struct{} // 僅 countDistinct 使用
+}
+
+// groupState 單一分組的累積狀態
+type groupState struct {
+	keys  []string
+	count int
+	aggs  []aggState
+}
+
+// compiledAggregate 預先解析欄位取值函式的聚合運算
+type compiledAggregate struct {
+	Aggregate
+	value valueExtractor
+	key   keyExtractor
+}
+
+// Run 執行聚合查詢
+// 解析失敗的記錄會被略過
+func (e *Engine) Run(entries []models.LogEntry, q Query) (*Result, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 預先建立取值函式，避免在迴圈中重複判斷欄位
+	keyFuncs := make([]keyExtractor, len(q.GroupBy))
+	for i, key := range q.GroupBy {
+		keyFuncs[i], _ = newKeyExtractor(key)
+	}
+
+	aggs := make([]compiledAggregate, len(q.Aggregates))
+	for i, agg := range q.Aggregates {
+		aggs[i] = compiledAggregate{Aggregate: agg}
+		switch agg.Func {
+		case FuncCount:
+		case FuncCountDistinct:
+			aggs[i].key, _ = newKeyExtractor(GroupKey{Field: agg.Field})
+		default:
+			aggs[i].value, _ = newValueExtractor(agg.Field)
+		}
+	}
+
+	groups := make(map[string]*groupState)
+	order := make([]*groupState, 0)
+	matched := 0
+	keys := make([]string, len(keyFuncs))
+
+	for i := range entries {
+		entry := &entries[i]
+		if !q.Filter.Match(entry) {
+			continue
+		}
+		matched++
+
+		for k, fn := range keyFuncs {
+			keys[k] = fn(entry)
+		}
+		groupKey := strings.Join(keys, "\x00")
+
+		group, exists := groups[groupKey]
+		if !exists {
+			group = &groupState{
+				keys: append([]string(nil), keys...),
+				aggs: make([]aggState, len(aggs)),
+			}
+			for a, agg := range aggs {
+				if agg.Func == FuncCountDistinct {
+					group.aggs[a].distinct = make(map[string]struct{})
+				}
+			}
+			groups[groupKey] = group
+			order = append(order, group)
+		}
+
+		group.count++
+		for a, agg := range aggs {
+			state := &group.aggs[a]
+			switch agg.Func {
+			case FuncCount:
+			case FuncCountDistinct:
+				state.distinct[agg.key(entry)] = struct{}{}
+			default:
+				v := agg.value(entry)
+				if group.count == 1 || v < state.min {
+					state.min = v
+				}
+				if group.count == 1 || v > state.max {
+					state.max = v
+				}
+				state.sum += v
+				if agg.Func == FuncPercentile {
+					state.values = append(state.values, v)
+				}
+			}
+		}
+	}
+
+	result := &Result{
+		Columns:        make([]string, 0, len(q.GroupBy)+len(q.Aggregates)),
+		KeyColumns:     len(q.GroupBy),
+		Rows:           make([]ResultRow, 0, len(order)),
+		MatchedEntries: matched,
+		TotalGroups:    len(order),
+	}
+	for _, key := range q.GroupBy {
+		result.Columns = append(result.Columns, key.ColumnName())
+	}
+	for _, agg := range q.Aggregates {
+		result.Columns = append(result.Columns, agg.ColumnName())
+	}
+
+	for _, group := range order {
+		row := ResultRow{
+			Keys:   group.keys,
+			Values: make([]float64, len(aggs)),
+		}
+		for a, agg := range aggs {
+			row.Values[a] = finalize(agg.Aggregate, group.count, &group.aggs[a])
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	sortRows(result, q)
+
+	if q.Limit > 0 && len(result.Rows) > q.Limit {
+		result.Rows = result.Rows[:q.Limit]
+	}
+
+	e.log.Info().
+		Int("entries", len(entries)).
+		Int("matched", matched).
+		Int("groups", result.TotalGroups).
+		Msg("聚合查詢完成")
+
+	return result, nil
+}
+
+// finalize 計算單一聚合運算的最終值
+func finalize(agg Aggregate, count int, state *aggState) float64 {
+	switch agg.Func {
+	case FuncCount:
+		return float64(count)
+	case FuncCountDistinct:
+		return float64(len(state.distinct))
+	case FuncSum:
+		return state.sum
+	case FuncAvg:
+		if count == 0 {
+			return 0
+		}
+		return state.sum / float64(count)
+	case FuncMin:
+		return state.min
+	case FuncMax:
+		return state.max
+	case FuncPercentile:
+		return Percentile(state.values, agg.Percentile)
+	}
+	return 0
+}
+
+// Percentile 計算百分位數（線性內插）
+// p 為 0-100；會就地排序 values
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return values[lower]
+	}
+	weight := rank - float64(lower)
+	return values[lower]*(1-weight) + values[upper]*weight
+}
+
+// sortRows 依查詢的排序設定排序結果列
+// 未指定 OrderBy 時依分組鍵遞增排序（數字鍵以數值比較）
+func sortRows(result *Result, q Query) {
+	column := -1
+	if q.OrderBy != "" {
+		for i, name := range result.Columns {
+			if name == q.OrderBy {
+				column = i
+				break
+			}
+		}
+	}
+
+	less := func(a, b ResultRow) bool {
+		if column >= result.KeyColumns {
+			return a.Values[column-result.KeyColumns] < b.Values[column-result.KeyColumns]
+		}
+		if column >= 0 {
+			return compareKey(a.Keys[column], b.Keys[column]) < 0
+		}
+		for i := range a.Keys {
+			if c := compareKey(a.Keys[i], b.Keys[i]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		if q.Descending {
+			return less(result.Rows[j], result.Rows[i])
+		}
+		return less(result.Rows[i], result.Rows[j])
+	})
+}
+
+// compareKey 比較兩個分組鍵值
+// 兩者皆為整數時以數值比較，否則以字串比較
+func compareKey(a, b string) int {
+	if ai, err := strconv.Atoi(a); err == nil {
+		if bi, err := strconv.Atoi(b); err == nil {
+			switch {
+			case ai < bi:
+				return -1
+			case ai > bi:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// Table 將結果轉換為二維字串陣列（含標題列）
+// 供 Excel 匯出器等需要表格資料的元件使用
+func (r *Result) Table() [][]string {
+	table := make([][]string, 0, len(r.Rows)+1)
+	table = append(table, append([]string(nil), r.Columns...))
+	for _, row := range r.Rows {
+		line := make([]string, 0, len(row.Keys)+len(row.Values))
+		line = append(line, row.Keys...)
+		for _, v := range row.Values {
+			line = append(line, formatValue(v))
+		}
+		table = append(table, line)
+	}
+	return table
+}
+
+// formatValue 格式化聚合值
+// 整數值不顯示小數，其他保留兩位小數
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
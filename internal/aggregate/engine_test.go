@@ -0,0 +1,157 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEntries 建立聚合測試用的日誌記錄
+func newTestEntries() []models.LogEntry {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	return []models.LogEntry{
+		{IP: "10.0.0.1", Timestamp: base, Method: "GET", URL: "/api/users?id=1", StatusCode: 200, ResponseBytes: 100},
+		{IP: "10.0.0.2", Timestamp: base.Add(10 * time.Minute), Method: "GET", URL: "/api/users?id=2", StatusCode: 404, ResponseBytes: 200},
+		{IP: "10.0.0.2", Timestamp: base.Add(70 * time.Minute), Method: "POST", URL: "/api/login", StatusCode: 401, ResponseBytes: 300},
+		{IP: "10.0.0.3", Timestamp: base.Add(80 * time.Minute), Method: "GET", URL: "/api/users", StatusCode: 403, ResponseBytes: 400},
+		{IP: "10.0.0.1", Timestamp: base.Add(90 * time.Minute), Method: "GET", URL: "/index.html", StatusCode: 404, ResponseBytes: 500},
+		{LineNumber: 6, RawLine: "garbage", ParseError: "無法匹配 log 格式"},
+	}
+}
+
+// TestEngine_分組計數 測試依方法與小時分組的 4xx 計數
+func TestEngine_分組計數(t *testing.T) {
+	engine := NewEngine()
+
+	result, err := engine.Run(newTestEntries(), Query{
+		Filter: &filter.FilterCriteria{
+			StatusCodeRange: &filter.StatusCodeRange{Min: 400, Max: 499},
+			URL:             "/api/",
+		},
+		GroupBy: []GroupKey{
+			{Field: FieldMethod},
+			{Field: FieldTime, Bucket: "1h"},
+		},
+		Aggregates: []Aggregate{{Func: FuncCount}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"method", "time(1h)", "count"}, result.Columns)
+	assert.Equal(t, 3, result.MatchedEntries, "應有 3 筆 /api/ 的 4xx 記錄")
+	require.Len(t, result.Rows, 3)
+
+	assert.Equal(t, []string{"GET", "2024-01-01 10:00:00"}, result.Rows[0].Keys)
+	assert.Equal(t, 1.0, result.Rows[0].Values[0])
+	assert.Equal(t, []string{"GET", "2024-01-01 11:00:00"}, result.Rows[1].Keys)
+	assert.Equal(t, []string{"POST", "2024-01-01 11:00:00"}, result.Rows[2].Keys)
+}
+
+// TestEngine_數值聚合 測試 sum/avg/min/max/percentile/countDistinct
+func TestEngine_數值聚合(t *testing.T) {
+	engine := NewEngine()
+
+	result, err := engine.Run(newTestEntries(), Query{
+		GroupBy: []GroupKey{{Field: FieldPath}},
+		Aggregates: []Aggregate{
+			{Func: FuncCount},
+			{Func: FuncSum, Field: FieldBytes},
+			{Func: FuncAvg, Field: FieldBytes},
+			{Func: FuncMin, Field: FieldBytes},
+			{Func: FuncMax, Field: FieldBytes},
+			{Func: FuncPercentile, Field: FieldBytes, Percentile: 50},
+			{Func: FuncCountDistinct, Field: FieldIP},
+		},
+		OrderBy:    "count",
+		Descending: true,
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, result.Rows)
+	top := result.Rows[0]
+	assert.Equal(t, []string{"/api/users"}, top.Keys, "查詢字串應被移除")
+	assert.Equal(t, []float64{3, 700, 700.0 / 3, 100, 400, 200, 3}, top.Values)
+	assert.Equal(t, "p50(bytes)", result.Columns[6])
+}
+
+// TestEngine_無分組 測試沒有分組鍵時的整體彙總
+func TestEngine_無分組(t *testing.T) {
+	engine := NewEngine()
+
+	result, err := engine.Run(newTestEntries(), Query{
+		Aggregates: []Aggregate{{Func: FuncCount}, {Func: FuncSum, Field: FieldBytes}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []float64{5, 1500}, result.Rows[0].Values, "解析失敗的記錄不應納入")
+}
+
+// TestEngine_Limit 測試列數限制
+func TestEngine_Limit(t *testing.T) {
+	engine := NewEngine()
+
+	result, err := engine.Run(newTestEntries(), Query{
+		GroupBy:    []GroupKey{{Field: FieldIP}},
+		Aggregates: []Aggregate{{Func: FuncCount}},
+		Limit:      2,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, 3, result.TotalGroups)
+}
+
+// TestEngine_無效查詢 測試查詢驗證
+func TestEngine_無效查詢(t *testing.T) {
+	engine := NewEngine()
+
+	testCases := []struct {
+		name  string
+		query Query
+	}{
+		{"缺少聚合", Query{}},
+		{"未知分組欄位", Query{GroupBy: []GroupKey{{Field: "foo"}}, Aggregates: []Aggregate{{Func: FuncCount}}}},
+		{"時間分組缺少區間", Query{GroupBy: []GroupKey{{Field: FieldTime}}, Aggregates: []Aggregate{{Func: FuncCount}}}},
+		{"未知聚合函式", Query{Aggregates: []Aggregate{{Func: "median", Field: FieldBytes}}}},
+		{"百分位超出範圍", Query{Aggregates: []Aggregate{{Func: FuncPercentile, Field: FieldBytes, Percentile: 120}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := engine.Run(newTestEntries(), tc.query)
+			var validationErr *models.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+// TestTable 測試結果轉換為二維字串陣列
+func TestTable(t *testing.T) {
+	result := &Result{
+		Columns:    []string{"status", "count", "avg(bytes)"},
+		KeyColumns: 1,
+		Rows: []ResultRow{
+			{Keys: []string{"200"}, Values: []float64{10, 12.5}},
+		},
+	}
+
+	table := result.Table()
+	assert.Equal(t, [][]string{
+		{"status", "count", "avg(bytes)"},
+		{"200", "10", "12.50"},
+	}, table)
+}
+
+// TestTruncateTime 測試時間區間截斷以記錄時區為基準
+func TestTruncateTime(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	ts := time.Date(2024, 1, 1, 13, 47, 0, 0, loc)
+
+	assert.Equal(t, time.Date(2024, 1, 1, 13, 45, 0, 0, loc), TruncateTime(ts, 15*time.Minute))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, loc), TruncateTime(ts, 24*time.Hour))
+}
@@ -0,0 +1,284 @@
+package aggregate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+)
+
+// 分組欄位名稱
+const (
+	FieldMethod      = "method"      // HTTP 方法
+	FieldStatus      = "status"      // 狀態碼
+	FieldStatusClass = "statusClass" // 狀態碼類別（2xx, 3xx, 4xx, 5xx）
+	FieldIP          = "ip"          // 客戶端 IP
+	FieldPath        = "path"        // URL 路徑（不含查詢字串）
+	FieldURL         = "url"         // 完整 URL
+	FieldProtocol    = "protocol"    // HTTP 協定版本
+	FieldUser        = "user"        // 認證使用者
+	FieldReferer     = "referer"     // 來源頁面
+	FieldUserAgent   = "userAgent"   // User Agent
+	FieldVirtualHost = "vhost"       // 虛擬主機
+	FieldServer      = "server"      // 來源伺服器
+	FieldCacheStatus = "cacheStatus" // 快取狀態
+	FieldHourOfDay   = "hourOfDay"   // 一天中的小時（00-23）
+	FieldDayOfWeek   = "dayOfWeek"   // 星期（0=週日）
+	FieldTime        = "time"        // 時間區間（需搭配 Bucket）
+)
+
+// 數值欄位名稱
+const (
+	FieldBytes       = "bytes"       // 回應大小（位元組）
+	FieldRequestTime = "requestTime" // 請求處理時間（微秒）
+)
+
+// 聚合函式名稱
+const (
+	FuncCount         = "count"         // 記錄數
+	FuncSum           = "sum"           // 總和
+	FuncAvg           = "avg"           // 平均
+	FuncMin           = "min"           // 最小值
+	FuncMax           = "max"           // 最大值
+	FuncPercentile    = "percentile"    // 百分位數
+	FuncCountDistinct = "countDistinct" // 唯一值數量
+)
+
+// GroupKey 分組鍵
+// Field 為 FieldTime 時必須設定 Bucket（例如 "5m", "1h", "1d"）
+type GroupKey struct {
+	Field  string `json:"field"`            // 分組欄位
+	Bucket string `json:"bucket,omitempty"` // 時間區間長度
+}
+
+// Aggregate 聚合運算
+// count 不需要 Field；countDistinct 的 Field 為分組欄位；其他函式的 Field 為數值欄位
+type Aggregate struct {
+	Func       string  `json:"func"`                 // 聚合函式
+	Field      string  `json:"field,omitempty"`      // 作用欄位
+	Percentile float64 `json:"percentile,omitempty"` // 百分位數（0-100，僅 percentile 使用）
+	Alias      string  `json:"alias,omitempty"`      // 結果欄位名稱（留空則自動產生）
+}
+
+// Query 聚合查詢
+// 先套用 Filter，再依 GroupBy 分組並計算 Aggregates
+type Query struct {
+	Filter     *filter.FilterCriteria `json:"filter,omitempty"`     // 篩選條件
+	GroupBy    []GroupKey             `json:"groupBy"`              // 分組鍵（可為空，表示整體彙總）
+	Aggregates []Aggregate            `json:"aggregates"`           // 聚合運算
+	OrderBy    string                 `json:"orderBy,omitempty"`    // 排序欄位名稱（預設依分組鍵）
+	Descending bool                   `json:"descending,omitempty"` // 是否降序
+	Limit      int                    `json:"limit,omitempty"`      // 最多返回的列數（0 表示不限制）
+}
+
+// ColumnName 取得聚合結果的欄位名稱
+func (a Aggregate) ColumnName() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	switch a.Func {
+	case FuncCount:
+		return FuncCount
+	case FuncPercentile:
+		return fmt.Sprintf("p%s(%s)", strconv.FormatFloat(a.Percentile, 'f', -1, 64), a.Field)
+	default:
+		return fmt.Sprintf("%s(%s)", a.Func, a.Field)
+	}
+}
+
+// ColumnName 取得分組鍵的欄位名稱
+func (k GroupKey) ColumnName() string {
+	if k.Field == FieldTime && k.Bucket != "" {
+		return fmt.Sprintf("time(%s)", k.Bucket)
+	}
+	return k.Field
+}
+
+// keyExtractor 從日誌記錄取出分組鍵值
+type keyExtractor func(entry *models.LogEntry) string
+
+// valueExtractor 從日誌記錄取出數值
+type valueExtractor func(entry *models.LogEntry) float64
+
+// newKeyExtractor 根據欄位名稱建立分組鍵取值函式
+func newKeyExtractor(key GroupKey) (keyExtractor, error) {
+	switch key.Field {
+	case FieldMethod:
+		return func(e *models.LogEntry) string { return e.Method }, nil
+	case FieldStatus:
+		return func(e *models.LogEntry) string { return strconv.Itoa(e.StatusCode) }, nil
+	case FieldStatusClass:
+		return func(e *models.LogEntry) string { return e.GetStatusCategory() }, nil
+	case FieldIP:
+		return func(e *models.LogEntry) string { return e.IP }, nil
+	case FieldPath:
+		return func(e *models.LogEntry) string { return StripQuery(e.URL) }, nil
+	case FieldURL:
+		return func(e *models.LogEntry) string { return e.URL }, nil
+	case FieldProtocol:
+		return func(e *models.LogEntry) string { return e.Protocol }, nil
+	case FieldUser:
+		return func(e *models.LogEntry) string { return e.User }, nil
+	case FieldReferer:
+		return func(e *models.LogEntry) string { return e.Referer }, nil
+	case FieldUserAgent:
+		return func(e *models.LogEntry) string { return e.UserAgent }, nil
+	case FieldVirtualHost:
+		return func(e *models.LogEntry) string { return e.VirtualHost }, nil
+	case FieldServer:
+		return func(e *models.LogEntry) string { return e.Server }, nil
+	case FieldCacheStatus:
+		return func(e *models.LogEntry) string { return e.CacheStatus }, nil
+	case FieldHourOfDay:
+		return func(e *models.LogEntry) string { return fmt.Sprintf("%02d", e.Timestamp.Hour()) }, nil
+	case FieldDayOfWeek:
+		return func(e *models.LogEntry) string { return strconv.Itoa(int(e.Timestamp.Weekday())) }, nil
+	case FieldTime:
+		bucket, err := ParseBucket(key.Bucket)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *models.LogEntry) string {
+			return TruncateTime(e.Timestamp, bucket).Format("2006-01-02 15:04:05")
+		}, nil
+	default:
+		return nil, &models.ValidationError{
+			Field:   "GroupBy",
+			Value:   key.Field,
+			Message: "不支援的分組欄位",
+		}
+	}
+}
+
+// newValueExtractor 根據欄位名稱建立數值取值函式
+func newValueExtractor(field string) (valueExtractor, error) {
+	switch field {
+	case FieldBytes:
+		return func(e *models.LogEntry) float64 { return float64(e.ResponseBytes) }, nil
+	case FieldRequestTime:
+		return func(e *models.LogEntry) float64 { return float64(e.RequestTime) }, nil
+	default:
+		return nil, &models.ValidationError{
+			Field:   "Aggregates",
+			Value:   field,
+			Message: "不支援的數值欄位",
+		}
+	}
+}
+
+// ParseBucket 解析時間區間長度
+// 支援 Go duration 格式（例如 "5m", "1h"）以及天數（例如 "1d", "7d"）
+func ParseBucket(bucket string) (time.Duration, error) {
+	if bucket == "" {
+		return 0, &models.ValidationError{
+			Field:   "Bucket",
+			Value:   "",
+			Message: "時間分組必須指定區間長度",
+		}
+	}
+
+	var d time.Duration
+	if strings.HasSuffix(bucket, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(bucket, "d"))
+		if err != nil {
+			return 0, &models.ValidationError{Field: "Bucket", Value: bucket, Message: "無效的時間區間"}
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(bucket)
+		if err != nil {
+			return 0, &models.ValidationError{Field: "Bucket", Value: bucket, Message: "無效的時間區間"}
+		}
+		d = parsed
+	}
+
+	if d < time.Second {
+		return 0, &models.ValidationError{Field: "Bucket", Value: bucket, Message: "時間區間不能小於 1 秒"}
+	}
+	return d, nil
+}
+
+// TruncateTime 將時間截斷至區間起點
+// 可整除一天的區間以記錄所在時區的午夜為基準，避免依 UTC 對齊
+func TruncateTime(t time.Time, bucket time.Duration) time.Time {
+	day := 24 * time.Hour
+	if bucket <= day && day%bucket == 0 {
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return midnight.Add(t.Sub(midnight).Truncate(bucket))
+	}
+	return t.Truncate(bucket)
+}
+
+// StripQuery 移除 URL 的查詢字串和片段
+func StripQuery(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+// Validate 驗證查詢參數
+// 返回 ValidationError 如果驗證失敗
+func (q *Query) Validate() error {
+	if err := q.Filter.Validate(); err != nil {
+		return err
+	}
+
+	if len(q.Aggregates) == 0 {
+		return &models.ValidationError{
+			Field:   "Aggregates",
+			Value:   "",
+			Message: "至少需要一個聚合運算",
+		}
+	}
+
+	for _, key := range q.GroupBy {
+		if _, err := newKeyExtractor(key); err != nil {
+			return err
+		}
+	}
+
+	for _, agg := range q.Aggregates {
+		switch agg.Func {
+		case FuncCount:
+		case FuncCountDistinct:
+			if _, err := newKeyExtractor(GroupKey{Field: agg.Field}); err != nil {
+				return err
+			}
+		case FuncSum, FuncAvg, FuncMin, FuncMax:
+			if _, err := newValueExtractor(agg.Field); err != nil {
+				return err
+			}
+		case FuncPercentile:
+			if _, err := newValueExtractor(agg.Field); err != nil {
+				return err
+			}
+			if agg.Percentile < 0 || agg.Percentile > 100 {
+				return &models.ValidationError{
+					Field:   "Percentile",
+					Value:   strconv.FormatFloat(agg.Percentile, 'f', -1, 64),
+					Message: "百分位數必須在 0-100 之間",
+				}
+			}
+		default:
+			return &models.ValidationError{
+				Field:   "Aggregates",
+				Value:   agg.Func,
+				Message: "不支援的聚合函式",
+			}
+		}
+	}
+
+	if q.Limit < 0 {
+		return &models.ValidationError{
+			Field:   "Limit",
+			Value:   strconv.Itoa(q.Limit),
+			Message: "列數限制不能為負數",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,500 @@
+// Package anomaly 偵測流量時間序列中的異常區段
+// 將日誌依時間區間分組後，以滾動基準線（中位數與 MAD）找出流量突增、驟降、5xx 比例跳升與新 IP 暴增
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+)
+
+// 異常類型
+const (
+	KindSpike       = "spike"        // 請求量突增
+	KindDrop        = "drop"         // 請求量驟降
+	KindOutage      = "outage"       // 請求量降為零
+	KindErrorSpike  = "error-spike"  // 5xx 比例跳升
+	KindNewIPSurge  = "new-ip-surge" // 新出現的 IP 暴增
+	maxBuckets      = 100000         // 時間區間數量上限
+	minBaselineSize = 3              // 計算基準線所需的最少區間數
+)
+
+// 嚴重程度
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+)
+
+// Options 異常偵測的參數，零值欄位使用預設值
+type Options struct {
+	Bucket         string  `json:"bucket"`         // 時間區間長度，例如 "5m"（預設 5m）
+	Baseline       int     `json:"baseline"`       // 滾動基準線的區間數（預設 12）
+	ZThreshold     float64 `json:"zThreshold"`     // 穩健 z 分數門檻（預設 3.5）
+	ErrorRatioJump float64 `json:"errorRatioJump"` // 5xx 比例相對基準線的跳升門檻（預設 0.2，即 20 個百分點）
+	MinErrors      int     `json:"minErrors"`      // 判定 5xx 跳升所需的最少 5xx 數（預設 5）
+	MinNewIPs      int     `json:"minNewIPs"`      // 判定新 IP 暴增所需的最少新 IP 數（預設 10）
+	MinOutageBase  float64 `json:"minOutageBase"`  // 判定流量歸零所需的基準線中位數（預設 10）
+	TopN           int     `json:"topN"`           // 每個異常區段列出的主要路徑與 IP 數（預設 5）
+}
+
+// DefaultOptions 返回預設的偵測參數
+func DefaultOptions() Options {
+	return Options{
+		Bucket:         "5m",
+		Baseline:       12,
+		ZThreshold:     3.5,
+		ErrorRatioJump: 0.2,
+		MinErrors:      5,
+		MinNewIPs:      10,
+		MinOutageBase:  10,
+		TopN:           5,
+	}
+}
+
+// withDefaults 以預設值補上零值欄位
+func (o Options) withDefaults() Options {
+	d := DefaultOptions()
+	aggregate.Default(&o.Bucket, d.Bucket)
+	aggregate.Default(&o.Baseline, d.Baseline)
+	aggregate.Default(&o.ZThreshold, d.ZThreshold)
+	aggregate.Default(&o.ErrorRatioJump, d.ErrorRatioJump)
+	aggregate.Default(&o.MinErrors, d.MinErrors)
+	aggregate.Default(&o.MinNewIPs, d.MinNewIPs)
+	aggregate.Default(&o.MinOutageBase, d.MinOutageBase)
+	aggregate.Default(&o.TopN, d.TopN)
+	return o
+}
+
+// validate 驗證參數
+func (o Options) validate() (time.Duration, error) {
+	bucket, err := aggregate.ParseBucket(o.Bucket)
+	if err != nil {
+		return 0, err
+	}
+	err = aggregate.FirstError(
+		aggregate.AtLeast("Baseline", o.Baseline, minBaselineSize, fmt.Sprintf("基準線至少需要 %d 個區間", minBaselineSize)),
+		aggregate.NonNegative("ZThreshold", o.ZThreshold, "z 分數門檻不可為負數"),
+		aggregate.InRange("ErrorRatioJump", o.ErrorRatioJump, 0, 1, "5xx 比例跳升門檻必須介於 0 到 1"),
+		aggregate.NonNegative("TopN", o.TopN, "TopN 不可為負數"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return bucket, nil
+}
+
+// Report 異常偵測結果
+type Report struct {
+	Options Options   `json:"options"` // 實際使用的參數（已補上預設值）
+	Start   time.Time `json:"start"`   // 第一個時間區間的開始時間
+	End     time.Time `json:"end"`     // 最後一個時間區間的結束時間
+	Buckets int       `json:"buckets"` // 時間區間數
+	Windows []Window  `json:"windows"` // 異常區段（依開始時間排序）
+}
+
+// Window 連續的異常時間區間
+type Window struct {
+	Kind        string        `json:"kind"`        // 異常類型
+	Severity    string        `json:"severity"`    // 嚴重程度（區段內最高者）
+	Start       time.Time     `json:"start"`       // 開始時間
+	End         time.Time     `json:"end"`         // 結束時間（最後一個區間的結束）
+	Buckets     int           `json:"buckets"`     // 區間數
+	Value       float64       `json:"value"`       // 區段內最極端的觀測值（請求數、5xx 比例或新 IP 數）
+	Baseline    float64       `json:"baseline"`    // 對應的基準線
+	Score       float64       `json:"score"`       // 最極端的 z 分數（5xx 跳升為比例差）
+	Requests    int           `json:"requests"`    // 區段內的請求數
+	Errors      int           `json:"errors"`      // 區段內的 5xx 數
+	Description string        `json:"description"` // 說明
+	TopPaths    []Contributor `json:"topPaths"`    // 區段內請求最多的路徑
+	TopIPs      []Contributor `json:"topIPs"`      // 區段內請求最多的 IP
+}
+
+// Contributor 異常區段內的主要路徑或 IP
+type Contributor struct {
+	Key    string `json:"key"`    // 路徑或 IP
+	Count  int    `json:"count"`  // 請求數
+	Errors int    `json:"errors"` // 5xx 數
+}
+
+// bucketStats 單一時間區間的統計
+type bucketStats struct {
+	requests int
+	errors   int // 5xx
+	newIPs   int
+}
+
+// observation 單一區間的異常判定
+type observation struct {
+	kind     string
+	severity string
+	value    float64
+	baseline float64
+	score    float64
+}
+
+// Detector 流量異常偵測器
+type Detector struct {
+	log *logger.Logger
+}
+
+// NewDetector 建立流量異常偵測器
+func NewDetector() *Detector {
+	return &Detector{log: logger.Get().WithModule("anomaly")}
+}
+
+// Detect 偵測日誌記錄的流量異常
+// 第一次遍歷建立各區間的時間序列並判定異常，第二次遍歷只統計異常區段內的主要路徑與 IP
+func (d *Detector) Detect(entries []models.LogEntry, opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	bucket, err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Options: opts, Windows: make([]Window, 0)}
+
+	// 找出時間範圍
+	var first, last time.Time
+	for i := range entries {
+		ts := entries[i].Timestamp
+		if ts.IsZero() {
+			continue
+		}
+		if first.IsZero() || ts.Before(first) {
+			first = ts
+		}
+		if ts.After(last) {
+			last = ts
+		}
+	}
+	if first.IsZero() {
+		return report, nil
+	}
+
+	start := aggregate.TruncateTime(first, bucket)
+	count := int(last.Sub(start)/bucket) + 1
+	if count > maxBuckets {
+		return nil, &models.ValidationError{
+			Field:   "Bucket",
+			Value:   opts.Bucket,
+			Message: fmt.Sprintf("時間區間過多（%d 個），請使用較大的區間長度", count),
+		}
+	}
+	report.Start = start
+	report.End = start.Add(time.Duration(count) * bucket)
+	report.Buckets = count
+
+	// 建立時間序列（沒有請求的區間保留為零）
+	series := make([]bucketStats, count)
+	seenIPs := make(map[string]struct{})
+	for _, i := range aggregate.Chronological(entries, nil) {
+		entry := &entries[i]
+		b := &series[int(entry.Timestamp.Sub(start)/bucket)]
+		b.requests++
+		if entry.StatusCode >= 500 {
+			b.errors++
+		}
+		if _, seen := seenIPs[entry.IP]; !seen {
+			seenIPs[entry.IP] = struct{}{}
+			b.newIPs++
+		}
+	}
+
+	observations := d.observe(series, opts)
+	report.Windows = mergeWindows(observations, series, start, bucket)
+	d.contributors(entries, report.Windows, opts.TopN)
+
+	d.log.Info().
+		Int("buckets", count).
+		Int("windows", len(report.Windows)).
+		Msg("流量異常偵測完成")
+	return report, nil
+}
+
+// observe 依滾動基準線判定每個區間的異常（同一區間可能有多種異常）
+func (d *Detector) observe(series []bucketStats, opts Options) [][]observation {
+	observations := make([][]observation, len(series))
+	requests := make([]float64, 0, opts.Baseline)
+	newIPs := make([]float64, 0, opts.Baseline)
+
+	for i := range series {
+		from := i - opts.Baseline
+		if from < 0 {
+			from = 0
+		}
+		if i-from < minBaselineSize {
+			continue
+		}
+
+		requests, newIPs = requests[:0], newIPs[:0]
+		baseRequests, baseErrors := 0, 0
+		for _, b := range series[from:i] {
+			requests = append(requests, float64(b.requests))
+			newIPs = append(newIPs, float64(b.newIPs))
+			baseRequests += b.requests
+			baseErrors += b.errors
+		}
+		current := series[i]
+
+		// 請求量：突增、驟降與歸零
+		median, z := robustZ(requests, float64(current.requests))
+		switch {
+		case current.requests == 0 && median >= opts.MinOutageBase:
+			observations[i] = append(observations[i], observation{KindOutage, SeverityCritical, 0, median, z})
+		case z >= opts.ZThreshold:
+			observations[i] = append(observations[i], observation{KindSpike, zSeverity(z, opts.ZThreshold), float64(current.requests), median, z})
+		case z <= -opts.ZThreshold:
+			observations[i] = append(observations[i], observation{KindDrop, zSeverity(-z, opts.ZThreshold), float64(current.requests), median, z})
+		}
+
+		// 5xx 比例跳升
+		if current.requests > 0 && current.errors >= opts.MinErrors {
+			ratio := float64(current.errors) / float64(current.requests)
+			baseRatio := 0.0
+			if baseRequests > 0 {
+				baseRatio = float64(baseErrors) / float64(baseRequests)
+			}
+			if jump := ratio - baseRatio; jump >= opts.ErrorRatioJump {
+				severity := SeverityMedium
+				switch {
+				case jump >= 0.5:
+					severity = SeverityCritical
+				case jump >= 2*opts.ErrorRatioJump:
+					severity = SeverityHigh
+				}
+				observations[i] = append(observations[i], observation{KindErrorSpike, severity, ratio, baseRatio, jump})
+			}
+		}
+
+		// 新 IP 暴增
+		if current.newIPs >= opts.MinNewIPs {
+			median, z := robustZ(newIPs, float64(current.newIPs))
+			if z >= opts.ZThreshold {
+				observations[i] = append(observations[i], observation{KindNewIPSurge, zSeverity(z, opts.ZThreshold), float64(current.newIPs), median, z})
+			}
+		}
+	}
+	return observations
+}
+
+// robustZ 以中位數與 MAD 計算穩健 z 分數
+// MAD 為零（基準線完全平穩）時改用標準差；仍為零時以中位數的 10%（至少 1）作為尺度，避免除以零
+func robustZ(baseline []float64, value float64) (float64, float64) {
+	median := medianOf(baseline)
+
+	deviations := make([]float64, len(baseline))
+	for i, v := range baseline {
+		deviations[i] = math.Abs(v - median)
+	}
+	scale := 1.4826 * medianOf(deviations)
+
+	if scale == 0 {
+		mean, sq := 0.0, 0.0
+		for _, v := range baseline {
+			mean += v
+		}
+		mean /= float64(len(baseline))
+		for _, v := range baseline {
+			sq += (v - mean) * (v - mean)
+		}
+		scale = math.Sqrt(sq / float64(len(baseline)))
+	}
+	if scale == 0 {
+		scale = math.Max(1, median*0.1)
+	}
+	return median, (value - median) / scale
+}
+
+// medianOf 計算中位數（會排序傳入切片的副本）
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// zSeverity 依 z 分數超過門檻的程度決定嚴重程度
+func zSeverity(z, threshold float64) string {
+	switch {
+	case z >= 3*threshold:
+		return SeverityCritical
+	case z >= 2*threshold:
+		return SeverityHigh
+	default:
+		return SeverityMedium
+	}
+}
+
+// severityRank 嚴重程度的排序值
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityHigh:
+		return 2
+	case SeverityMedium:
+		return 1
+	}
+	return 0
+}
+
+// mergeWindows 將相同類型的連續異常區間合併為異常區段
+func mergeWindows(observations [][]observation, series []bucketStats, start time.Time, bucket time.Duration) []Window {
+	windows := make([]Window, 0)
+	open := make(map[string]int) // 類型 -> 進行中區段的索引
+
+	for i, list := range observations {
+		active := make(map[string]bool, len(list))
+		for _, obs := range list {
+			active[obs.kind] = true
+			idx, exists := open[obs.kind]
+			if !exists {
+				windows = append(windows, Window{
+					Kind:     obs.kind,
+					Severity: obs.severity,
+					Start:    start.Add(time.Duration(i) * bucket),
+					Value:    obs.value,
+					Baseline: obs.baseline,
+					Score:    obs.score,
+				})
+				idx = len(windows) - 1
+				open[obs.kind] = idx
+			}
+
+			w := &windows[idx]
+			w.Buckets++
+			w.End = start.Add(time.Duration(i+1) * bucket)
+			w.Requests += series[i].requests
+			w.Errors += series[i].errors
+			if severityRank(obs.severity) > severityRank(w.Severity) {
+				w.Severity = obs.severity
+			}
+			if math.Abs(obs.score) > math.Abs(w.Score) {
+				w.Value, w.Baseline, w.Score = obs.value, obs.baseline, obs.score
+			}
+		}
+		// 這個區間沒有延續的類型即結束其區段
+		for kind := range open {
+			if !active[kind] {
+				delete(open, kind)
+			}
+		}
+	}
+
+	for i := range windows {
+		windows[i].Description = describe(&windows[i])
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows
+}
+
+// describe 產生異常區段的說明
+func describe(w *Window) string {
+	switch w.Kind {
+	case KindSpike:
+		return fmt.Sprintf("請求量突增：最高 %.0f 次/區間，基準線 %.0f 次（z=%.1f）", w.Value, w.Baseline, w.Score)
+	case KindDrop:
+		return fmt.Sprintf("請求量驟降：最低 %.0f 次/區間，基準線 %.0f 次（z=%.1f）", w.Value, w.Baseline, w.Score)
+	case KindOutage:
+		return fmt.Sprintf("請求量降為零，基準線 %.0f 次/區間", w.Baseline)
+	case KindErrorSpike:
+		return fmt.Sprintf("5xx 比例跳升至 %.1f%%，基準線 %.1f%%", w.Value*100, w.Baseline*100)
+	case KindNewIPSurge:
+		return fmt.Sprintf("新 IP 暴增：%.0f 個/區間，基準線 %.0f 個（z=%.1f）", w.Value, w.Baseline, w.Score)
+	}
+	return w.Kind
+}
+
+// contributors 統計每個異常區段內請求最多的路徑與 IP
+func (d *Detector) contributors(entries []models.LogEntry, windows []Window, topN int) {
+	if len(windows) == 0 || topN == 0 {
+		return
+	}
+
+	type counter struct {
+		paths map[string]*Contributor
+		ips   map[string]*Contributor
+	}
+	counters := make([]counter, len(windows))
+	for i := range counters {
+		counters[i] = counter{paths: make(map[string]*Contributor), ips: make(map[string]*Contributor)}
+	}
+
+	add := func(m map[string]*Contributor, key string, isError bool) {
+		c, exists := m[key]
+		if !exists {
+			c = &Contributor{Key: key}
+			m[key] = c
+		}
+		c.Count++
+		if isError {
+			c.Errors++
+		}
+	}
+
+	// 同一類型的區段不會重疊且依開始時間排序，每筆記錄在各類型中以二分搜尋找出所在的區段
+	byKind := make(map[string][]int)
+	for w := range windows {
+		byKind[windows[w].Kind] = append(byKind[windows[w].Kind], w)
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		for _, indexes := range byKind {
+			k := sort.Search(len(indexes), func(k int) bool {
+				return entry.Timestamp.Before(windows[indexes[k]].End)
+			})
+			if k == len(indexes) || entry.Timestamp.Before(windows[indexes[k]].Start) {
+				continue
+			}
+			w := indexes[k]
+			isError := entry.StatusCode >= 500
+			add(counters[w].paths, aggregate.StripQuery(entry.URL), isError)
+			add(counters[w].ips, entry.IP, isError)
+		}
+	}
+
+	for w := range windows {
+		windows[w].TopPaths = topContributors(counters[w].paths, topN, windows[w].Kind == KindErrorSpike)
+		windows[w].TopIPs = topContributors(counters[w].ips, topN, windows[w].Kind == KindErrorSpike)
+	}
+}
+
+// topContributors 取出前 N 名（5xx 跳升依 5xx 數排序，其餘依請求數排序）
+func topContributors(m map[string]*Contributor, n int, byErrors bool) []Contributor {
+	list := make([]Contributor, 0, len(m))
+	for _, c := range m {
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if byErrors && a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Key < b.Key
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
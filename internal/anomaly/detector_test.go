@@ -0,0 +1,223 @@
+package anomaly
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBase = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// trafficEntries 依每分鐘的請求數建立日誌記錄（固定 5 個 IP 輪流請求 /index.html）
+func trafficEntries(perMinute []int) []models.LogEntry {
+	entries := make([]models.LogEntry, 0)
+	for minute, n := range perMinute {
+		for i := 0; i < n; i++ {
+			entries = append(entries, models.LogEntry{
+				IP:         fmt.Sprintf("10.0.0.%d", i%5+1),
+				Timestamp:  testBase.Add(time.Duration(minute)*time.Minute + time.Duration(i)*time.Second),
+				Method:     "GET",
+				URL:        "/index.html",
+				StatusCode: 200,
+			})
+		}
+	}
+	return entries
+}
+
+// steady 建立每分鐘請求數在 18 到 22 之間波動的序列
+func steady(minutes int) []int {
+	counts := make([]int, minutes)
+	for i := range counts {
+		counts[i] = 18 + i%5
+	}
+	return counts
+}
+
+// kinds 取出異常區段的類型
+func kinds(report *Report) []string {
+	result := make([]string, 0, len(report.Windows))
+	for _, w := range report.Windows {
+		result = append(result, w.Kind)
+	}
+	return result
+}
+
+// TestDetect_平穩流量 測試平穩流量不會產生異常
+func TestDetect_平穩流量(t *testing.T) {
+	report, err := NewDetector().Detect(trafficEntries(steady(60)), Options{Bucket: "1m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 60, report.Buckets)
+	assert.Equal(t, testBase, report.Start)
+	assert.Equal(t, testBase.Add(time.Hour), report.End)
+	assert.Empty(t, report.Windows)
+}
+
+// TestDetect_流量突增 測試突增區段的合併與主要來源
+func TestDetect_流量突增(t *testing.T) {
+	entries := trafficEntries(steady(30))
+	// 第 20、21 分鐘由單一 IP 大量請求 /api/search
+	for minute := 20; minute <= 21; minute++ {
+		for i := 0; i < 200; i++ {
+			entries = append(entries, models.LogEntry{
+				IP:         "203.0.113.7",
+				Timestamp:  testBase.Add(time.Duration(minute)*time.Minute + time.Duration(i%60)*time.Second),
+				URL:        fmt.Sprintf("/api/search?q=%d", i),
+				StatusCode: 200,
+			})
+		}
+	}
+
+	report, err := NewDetector().Detect(entries, Options{Bucket: "1m", TopN: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{KindSpike}, kinds(report))
+
+	w := report.Windows[0]
+	assert.Equal(t, testBase.Add(20*time.Minute), w.Start)
+	assert.Equal(t, testBase.Add(22*time.Minute), w.End)
+	assert.Equal(t, 2, w.Buckets)
+	assert.Equal(t, SeverityCritical, w.Severity)
+	assert.Equal(t, 400+18+19, w.Requests)
+	assert.Greater(t, w.Score, 3.5)
+	require.Len(t, w.TopPaths, 2)
+	assert.Equal(t, Contributor{Key: "/api/search", Count: 400}, w.TopPaths[0], "路徑不含查詢字串")
+	require.Len(t, w.TopIPs, 2)
+	assert.Equal(t, "203.0.113.7", w.TopIPs[0].Key)
+	assert.Contains(t, w.Description, "請求量突增")
+}
+
+// TestDetect_流量歸零與驟降 測試流量中斷與大幅下降
+func TestDetect_流量歸零與驟降(t *testing.T) {
+	counts := steady(40)
+	counts[20], counts[21], counts[22] = 0, 0, 0
+	counts[30] = 2
+
+	report, err := NewDetector().Detect(trafficEntries(counts), Options{Bucket: "1m"})
+	require.NoError(t, err)
+	require.Equal(t, []string{KindOutage, KindDrop}, kinds(report))
+
+	outage := report.Windows[0]
+	assert.Equal(t, testBase.Add(20*time.Minute), outage.Start)
+	assert.Equal(t, 3, outage.Buckets)
+	assert.Equal(t, SeverityCritical, outage.Severity)
+	assert.Zero(t, outage.Requests)
+	assert.Empty(t, outage.TopPaths)
+
+	drop := report.Windows[1]
+	assert.Equal(t, testBase.Add(30*time.Minute), drop.Start)
+	assert.Equal(t, 2.0, drop.Value)
+	assert.Less(t, drop.Score, -3.5)
+}
+
+// TestDetect_5xx比例跳升 測試 5xx 比例跳升與依錯誤數排序的主要路徑
+func TestDetect_5xx比例跳升(t *testing.T) {
+	entries := trafficEntries(steady(30))
+	for i := range entries {
+		minute := int(entries[i].Timestamp.Sub(testBase) / time.Minute)
+		if minute == 25 && i%2 == 0 {
+			entries[i].URL = "/api/orders"
+			entries[i].StatusCode = 503
+		}
+	}
+
+	report, err := NewDetector().Detect(entries, Options{Bucket: "1m"})
+	require.NoError(t, err)
+	require.Equal(t, []string{KindErrorSpike}, kinds(report))
+
+	w := report.Windows[0]
+	assert.Equal(t, testBase.Add(25*time.Minute), w.Start)
+	assert.Equal(t, SeverityCritical, w.Severity, "比例跳升 50 個百分點")
+	assert.InDelta(t, 0.5, w.Value, 0.05)
+	assert.Zero(t, w.Baseline)
+	assert.Equal(t, "/api/orders", w.TopPaths[0].Key)
+	assert.Equal(t, w.Errors, w.TopPaths[0].Errors)
+}
+
+// TestDetect_新IP暴增 測試大量首次出現的 IP
+func TestDetect_新IP暴增(t *testing.T) {
+	entries := trafficEntries(steady(30))
+	for minute := 0; minute < 30; minute++ {
+		n := 1
+		if minute == 15 {
+			n = 60
+		}
+		for i := 0; i < n; i++ {
+			entries = append(entries, models.LogEntry{
+				IP:         fmt.Sprintf("198.51.%d.%d", minute, i),
+				Timestamp:  testBase.Add(time.Duration(minute)*time.Minute + 30*time.Second),
+				URL:        "/",
+				StatusCode: 200,
+			})
+		}
+	}
+
+	report, err := NewDetector().Detect(entries, Options{Bucket: "1m"})
+	require.NoError(t, err)
+	assert.Contains(t, kinds(report), KindNewIPSurge)
+	for _, w := range report.Windows {
+		if w.Kind == KindNewIPSurge {
+			assert.Equal(t, testBase.Add(15*time.Minute), w.Start)
+			assert.Equal(t, 60.0, w.Value)
+		}
+	}
+}
+
+// TestDetect_無時間戳記 測試沒有可用時間戳記的記錄
+func TestDetect_無時間戳記(t *testing.T) {
+	report, err := NewDetector().Detect([]models.LogEntry{{LineNumber: 1, ParseError: "無法匹配 log 格式"}}, Options{})
+	require.NoError(t, err)
+	assert.Zero(t, report.Buckets)
+	assert.Empty(t, report.Windows)
+	assert.Equal(t, DefaultOptions(), report.Options, "零值欄位應補上預設值")
+}
+
+// TestDetect_無效參數 測試參數驗證
+func TestDetect_無效參數(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  Options
+		field string
+	}{
+		{"無效區間", Options{Bucket: "abc"}, "Bucket"},
+		{"基準線過短", Options{Baseline: 2}, "Baseline"},
+		{"比例超出範圍", Options{ErrorRatioJump: 1.5}, "ErrorRatioJump"},
+		{"負數 TopN", Options{TopN: -1}, "TopN"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewDetector().Detect(trafficEntries(steady(5)), tc.opts)
+			var validationErr *models.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+
+	// 區間過多
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", Timestamp: testBase},
+		{IP: "10.0.0.1", Timestamp: testBase.AddDate(1, 0, 0)},
+	}
+	_, err := NewDetector().Detect(entries, Options{Bucket: "1s"})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Bucket", validationErr.Field)
+}
+
+// TestRobustZ 測試穩健 z 分數
+func TestRobustZ(t *testing.T) {
+	median, z := robustZ([]float64{10, 12, 11, 13, 10}, 30)
+	assert.Equal(t, 11.0, median)
+	assert.InDelta(t, 19/1.4826, z, 0.001)
+
+	// MAD 與標準差皆為零時以中位數的 10% 作為尺度
+	median, z = robustZ([]float64{100, 100, 100}, 120)
+	assert.Equal(t, 100.0, median)
+	assert.InDelta(t, 2.0, z, 0.001)
+}
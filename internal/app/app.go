@@ -1,25 +1,50 @@
 package app
 
 import (
+	"access-log-analyzer/internal/geoip"
+	"access-log-analyzer/internal/stats"
+	"access-log-analyzer/internal/subnet"
+	"access-log-analyzer/internal/useragent"
 	"access-log-analyzer/pkg/logger"
 	"context"
 	"fmt"
+	"os"
+	"sync"
 )
 
 // App 結構表示主應用程式
 // 包含應用程式狀態和 Wails runtime 上下文
 type App struct {
-	ctx   context.Context
-	state *State
-	log   *logger.Logger
+	ctx        context.Context
+	state      *State
+	botRules   *stats.BotRuleSet      // 共用的機器人偵測規則（統計與查詢皆使用）
+	crawlers   *stats.CrawlerVerifier // 共用的爬蟲驗證器（快取跨檔案共用）
+	geo        *geoip.Enricher        // 共用的 GeoIP 與 ASN 查詢（未載入資料庫時不補充）
+	cidrGroups *subnet.GroupSet       // 具名 CIDR 群組（統計中的標籤）
+	uaParser   *useragent.Parser      // 共用的 User-Agent 解析器（快取跨檔案共用）
+	log        *logger.Logger
+
+	watchMu        sync.Mutex         // 保護規則檔監看狀態、爬蟲驗證、網段與本站主機設定
+	botRulesPath   string             // 目前監看的規則檔路徑
+	stopRuleWatch  context.CancelFunc // 停止監看規則檔
+	crawlerDNS     bool               // 是否啟用爬蟲 DNS 驗證
+	subnetOpts     subnet.Options     // 網段彙總的前綴長度
+	cidrGroupsPath string             // 最後載入的 CIDR 群組檔路徑
+	siteHosts      []string           // 本站主機名稱（判斷站內 Referer）
 }
 
 // NewApp 建立新的 App 實例
 // 初始化應用程式狀態和日誌記錄器
 func NewApp() *App {
 	return &App{
-		state: NewState(),
-		log:   logger.Get(),
+		state:      NewState(),
+		botRules:   stats.NewBotRuleSet(),
+		crawlers:   stats.NewCrawlerVerifier(),
+		geo:        geoip.NewEnricher(),
+		cidrGroups: subnet.NewGroupSet(),
+		uaParser:   useragent.NewParser(),
+		subnetOpts: subnet.DefaultOptions(),
+		log:        logger.Get(),
 	}
 }
 
@@ -31,6 +56,47 @@ func (a *App) Startup(ctx context.Context) {
 		Bool("ctx_nil", ctx == nil).
 		Str("ctx_type", fmt.Sprintf("%T", ctx)).
 		Msg("應用程式 startup 完成 - context 已儲存")
+
+	// 載入使用者的機器人規則檔（若存在）並監看變更
+	if path := defaultBotRulesPath(); path != "" {
+		a.watchBotRules(path)
+	}
+
+	// 載入使用者的爬蟲驗證資料檔（若存在），更新內建的官方 IP 範圍
+	if path := defaultCrawlerRangesPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if err := a.crawlers.LoadFile(path); err != nil {
+				a.log.Warn().Err(err).Str("path", path).Msg("載入爬蟲驗證資料檔失敗，使用內建資料")
+			}
+		}
+	}
+
+	// 載入使用者的 GeoIP 與 ASN 資料庫（若存在）
+	if path := defaultGeoIPPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if _, err := a.geo.Load(path); err != nil {
+				a.log.Warn().Err(err).Str("path", path).Msg("載入 GeoIP 資料庫失敗，不補充地理資訊")
+			}
+		}
+	}
+
+	// 載入使用者的 CIDR 群組檔（若存在）
+	if path := defaultCIDRGroupsPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if resp := a.LoadCIDRGroups(path); !resp.Success {
+				a.log.Warn().Str("path", path).Str("error", resp.ErrorMessage).Msg("載入 CIDR 群組檔失敗")
+			}
+		}
+	}
+
+	// 載入使用者的 User-Agent 規則檔（若存在），取代內建規則
+	if path := defaultUserAgentRegexesPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if err := a.uaParser.LoadFile(path); err != nil {
+				a.log.Warn().Err(err).Str("path", path).Msg("載入 User-Agent 規則檔失敗，使用內建規則")
+			}
+		}
+	}
 }
 
 // Shutdown 在應用程式關閉時調用
@@ -38,6 +104,14 @@ func (a *App) Startup(ctx context.Context) {
 func (a *App) Shutdown(ctx context.Context) {
 	a.log.Info().Msg("應用程式正在關閉...")
 
+	// 停止監看機器人規則檔
+	a.watchMu.Lock()
+	if a.stopRuleWatch != nil {
+		a.stopRuleWatch()
+		a.stopRuleWatch = nil
+	}
+	a.watchMu.Unlock()
+
 	// 清理應用程式狀態
 	a.state.Cleanup()
 
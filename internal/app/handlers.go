@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"access-log-analyzer/internal/exporter"
+	"access-log-analyzer/internal/index"
 	"access-log-analyzer/internal/models"
 	"access-log-analyzer/internal/parser"
 	"access-log-analyzer/internal/stats"
@@ -16,15 +18,17 @@ import (
 
 // ParseFileRequest 解析檔案的請求參數
 type ParseFileRequest struct {
-	FilePath string `json:"filePath"` // 檔案路徑
+	FilePath   string `json:"filePath"`         // 檔案路徑
+	BuildIndex bool   `json:"buildIndex"`       // 是否建立反向索引以加速搜尋（會增加記憶體使用）
+	Server     string `json:"server,omitempty"` // 來源伺服器名稱（選填，未填時使用檔名；同時載入多台伺服器的 log 時標記每筆記錄）
 }
 
 // ParseFileResponse 解析檔案的回應
 type ParseFileResponse struct {
-	Success      bool                `json:"success"`      // 是否成功
-	LogFile      *models.LogFile     `json:"logFile"`      // 日誌檔案資料
-	ErrorMessage string              `json:"errorMessage"` // 錯誤訊息
-	ErrorSamples []parser.ParseError `json:"errorSamples"` // 錯誤樣本
+	Success      bool                   `json:"success"`      // 是否成功
+	LogFile      *models.LogFileSummary `json:"logFile"`      // 日誌檔案摘要（不含記錄，記錄透過 GetEntries 分頁取得）
+	ErrorMessage string                 `json:"errorMessage"` // 錯誤訊息
+	ErrorSamples []parser.ParseError    `json:"errorSamples"` // 錯誤樣本
 }
 
 // SelectFileResponse 選擇檔案的回應
@@ -192,8 +196,17 @@ func (a *App) ParseFile(req ParseFileRequest) (response ParseFileResponse) {
 		}
 	}
 
+	// 依第一行偵測格式（Combined、Common 或 vhost_combined）
+	format, err := parser.DetectFileFormat(req.FilePath)
+	if err != nil {
+		return ParseFileResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
 	// 建立解析器（自動使用所有 CPU 核心）
-	logParser := parser.NewParser(parser.FormatCombined, 0)
+	logParser := parser.NewParser(format, 0)
 
 	// 驗證第一行是否為 Apache log 格式
 	// 提供快速回饋，避免解析不正確的檔案
@@ -218,8 +231,16 @@ func (a *App) ParseFile(req ParseFileRequest) (response ParseFileResponse) {
 	a.log.Info().Msg("開始計算統計資訊")
 	statStart := time.Now()
 
-	calculator := stats.NewCalculator()
-	statistics := calculator.Calculate(result.Entries)
+	// 標記來源伺服器（未指定時以檔名區分不同伺服器的 log）
+	server := strings.TrimSpace(req.Server)
+	if server == "" {
+		server = filepath.Base(req.FilePath)
+	}
+	for i := range result.Entries {
+		result.Entries[i].Server = server
+	}
+
+	statistics := a.newCalculator().Calculate(result.Entries)
 
 	statTime := time.Since(statStart)
 
@@ -239,8 +260,24 @@ func (a *App) ParseFile(req ParseFileRequest) (response ParseFileResponse) {
 		MemoryUsed:  result.MemoryUsed,
 	}
 
+	// 建立反向索引（選用）
+	var logIndex *index.Index
+	if req.BuildIndex {
+		logIndex = index.Build(result.Entries)
+		logFile.IndexTime = logIndex.BuildTime().Milliseconds()
+		logFile.IndexMemory = logIndex.MemoryUsage()
+
+		a.log.Info().
+			Int64("index_time_ms", logFile.IndexTime).
+			Int64("index_memory", logFile.IndexMemory).
+			Msg("反向索引建立完成")
+	}
+
 	// 將檔案新增到應用程式狀態
 	a.state.AddFile(req.FilePath, logFile)
+	if logIndex != nil {
+		a.state.SetIndex(req.FilePath, logIndex)
+	}
 
 	// T151: 新增到最近檔案列表
 	a.state.AddRecentFile(logFile)
@@ -256,11 +293,28 @@ func (a *App) ParseFile(req ParseFileRequest) (response ParseFileResponse) {
 
 	return ParseFileResponse{
 		Success:      true,
-		LogFile:      logFile,
+		LogFile:      logFile.Summary(),
 		ErrorSamples: result.ErrorSamples,
 	}
 }
 
+// newCalculator 依目前的機器人規則、爬蟲驗證、GeoIP、網段與本站主機設定建立統計計算器
+func (a *App) newCalculator() *stats.Calculator {
+	calculator := stats.NewCalculator()
+	calculator.SetBotRules(a.botRules)
+	calculator.SetCrawlerVerifier(a.crawlers)
+	calculator.SetGeoIP(a.geo)
+	calculator.SetCIDRGroups(a.cidrGroups)
+	calculator.SetUserAgentParser(a.uaParser)
+	if err := calculator.SetSubnetOptions(a.subnetOptions()); err != nil {
+		a.log.Warn().Err(err).Msg("網段設定無效，使用預設值")
+	}
+	if err := calculator.SetSiteHosts(a.siteHostList()); err != nil {
+		a.log.Warn().Err(err).Msg("本站主機名稱無效，改由日誌推斷本站主機")
+	}
+	return calculator
+}
+
 // ValidateLogFormat 快速驗證 log 檔案格式
 // 讀取前 100 行並檢查是否符合 Apache log 格式
 func (a *App) ValidateLogFormat(req ValidateFormatRequest) ValidateFormatResponse {
@@ -281,7 +335,14 @@ func (a *App) ValidateLogFormat(req ValidateFormatRequest) ValidateFormatRespons
 	}
 
 	// 建立解析器並驗證格式
-	logParser := parser.NewParser(parser.FormatCombined, 1)
+	format, err := parser.DetectFileFormat(req.FilePath)
+	if err != nil {
+		return ValidateFormatResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("驗證失敗: %v", err),
+		}
+	}
+	logParser := parser.NewParser(format, 1)
 	valid, err := logParser.ValidateFormat(req.FilePath, 100)
 
 	if err != nil {
@@ -317,14 +378,14 @@ func (a *App) CloseFile(filePath string) bool {
 	return true
 }
 
-// GetFileData 取得指定檔案的資料
-// 用於切換分頁時重新載入資料
-func (a *App) GetFileData(filePath string) *models.LogFile {
+// GetFileData 取得指定檔案的摘要資料
+// 用於切換分頁時重新載入資料，記錄透過 GetEntries 分頁取得
+func (a *App) GetFileData(filePath string) *models.LogFileSummary {
 	logFile, exists := a.state.GetFile(filePath)
 	if !exists {
 		return nil
 	}
-	return logFile
+	return logFile.Summary()
 }
 
 // SetActiveFile 設定當前活動的檔案
@@ -563,6 +624,7 @@ func (a *App) ExportToExcel(req ExportToExcelRequest) (response ExportToExcelRes
 
 	// 建立匯出器（T097：追蹤進度和日誌）
 	xlsxExporter := exporter.NewXLSXExporter()
+	xlsxExporter.SetGeoIP(a.geo)
 
 	a.log.Info().
 		Int("entries", len(logFile.Entries)).
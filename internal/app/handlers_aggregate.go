@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/exporter"
+)
+
+// AggregateRequest 聚合查詢的請求參數
+type AggregateRequest struct {
+	FilePath string          `json:"filePath"` // 已載入的 log 檔案路徑
+	Query    aggregate.Query `json:"query"`    // 聚合查詢
+}
+
+// AggregateResponse 聚合查詢的回應
+type AggregateResponse struct {
+	Success      bool              `json:"success"`      // 是否成功
+	Result       *aggregate.Result `json:"result"`       // 聚合結果
+	ErrorMessage string            `json:"errorMessage"` // 錯誤訊息
+}
+
+// ExportAggregateRequest 匯出聚合結果的請求參數
+type ExportAggregateRequest struct {
+	FilePath string          `json:"filePath"` // 已載入的 log 檔案路徑
+	SavePath string          `json:"savePath"` // Excel 檔案儲存路徑
+	Query    aggregate.Query `json:"query"`    // 聚合查詢
+}
+
+// Aggregate 對已載入的檔案執行分組聚合查詢
+// 用於在應用程式內完成樞紐分析，不需先匯出全部記錄
+func (a *App) Aggregate(req AggregateRequest) (response AggregateResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("聚合查詢時發生 panic")
+
+			response = AggregateResponse{
+				Success:      false,
+				ErrorMessage: "聚合查詢時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return AggregateResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	result, err := aggregate.NewEngine().Run(logFile.Entries, req.Query)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("聚合查詢失敗")
+		return AggregateResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return AggregateResponse{
+		Success: true,
+		Result:  result,
+	}
+}
+
+// ExportAggregateToExcel 執行聚合查詢並將結果匯出為 Excel
+func (a *App) ExportAggregateToExcel(req ExportAggregateRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出聚合結果時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	aggResp := a.Aggregate(AggregateRequest{FilePath: req.FilePath, Query: req.Query})
+	if !aggResp.Success {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: aggResp.ErrorMessage,
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportAggregate(aggResp.Result, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("聚合結果匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
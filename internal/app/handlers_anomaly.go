@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/anomaly"
+	"access-log-analyzer/internal/exporter"
+)
+
+// AnomalyRequest 流量異常偵測的請求參數
+type AnomalyRequest struct {
+	FilePath string          `json:"filePath"` // 已載入的 log 檔案路徑
+	Options  anomaly.Options `json:"options"`  // 偵測參數（零值欄位使用預設值）
+}
+
+// AnomalyResponse 流量異常偵測的回應
+type AnomalyResponse struct {
+	Success      bool            `json:"success"`      // 是否成功
+	Report       *anomaly.Report `json:"report"`       // 偵測結果
+	ErrorMessage string          `json:"errorMessage"` // 錯誤訊息
+}
+
+// ExportAnomaliesRequest 匯出流量異常偵測結果的請求參數
+type ExportAnomaliesRequest struct {
+	FilePath string          `json:"filePath"` // 已載入的 log 檔案路徑
+	SavePath string          `json:"savePath"` // Excel 檔案儲存路徑
+	Options  anomaly.Options `json:"options"`  // 偵測參數
+}
+
+// DetectAnomalies 偵測已載入檔案的流量異常
+// 依時間區間比對滾動基準線，找出流量突增、驟降、中斷、5xx 比例跳升與新 IP 暴增的區段
+func (a *App) DetectAnomalies(req AnomalyRequest) (response AnomalyResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("偵測流量異常時發生 panic")
+
+			response = AnomalyResponse{
+				Success:      false,
+				ErrorMessage: "偵測流量異常時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return AnomalyResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	report, err := anomaly.NewDetector().Detect(logFile.Entries, req.Options)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("流量異常偵測失敗")
+		return AnomalyResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return AnomalyResponse{
+		Success: true,
+		Report:  report,
+	}
+}
+
+// ExportAnomaliesToExcel 偵測流量異常並將結果匯出為 Excel
+func (a *App) ExportAnomaliesToExcel(req ExportAnomaliesRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出流量異常時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	detectResp := a.DetectAnomalies(AnomalyRequest{FilePath: req.FilePath, Options: req.Options})
+	if !detectResp.Success {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: detectResp.ErrorMessage,
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportAnomalies(detectResp.Report, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("流量異常匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
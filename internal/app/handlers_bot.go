@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"access-log-analyzer/internal/stats"
+)
+
+// botRulesWatchInterval 檢查機器人規則檔是否變更的間隔
+const botRulesWatchInterval = 2 * time.Second
+
+// BotRulesResponse 機器人偵測規則的回應
+type BotRulesResponse struct {
+	Success      bool            `json:"success"`      // 是否成功
+	Rules        []stats.BotRule `json:"rules"`        // 依優先順序排序的有效規則
+	RulesFile    string          `json:"rulesFile"`    // 目前使用（並監看）的規則檔路徑
+	ErrorMessage string          `json:"errorMessage"` // 錯誤訊息
+}
+
+// defaultBotRulesPath 取得使用者機器人規則檔的預設路徑
+// 儲存在使用者主目錄的 .apache-log-analyzer 資料夾
+func defaultBotRulesPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".apache-log-analyzer", "bot-rules.yaml")
+}
+
+// watchBotRules 在背景載入並監看規則檔，取代先前監看的規則檔
+func (a *App) watchBotRules(path string) {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+
+	if a.stopRuleWatch != nil {
+		a.stopRuleWatch()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.botRulesPath = path
+	a.stopRuleWatch = cancel
+
+	go a.botRules.Watch(ctx, path, botRulesWatchInterval)
+}
+
+// GetBotRules 取得目前生效的機器人偵測規則
+func (a *App) GetBotRules() BotRulesResponse {
+	a.watchMu.Lock()
+	path := a.botRulesPath
+	a.watchMu.Unlock()
+
+	return BotRulesResponse{
+		Success:   true,
+		Rules:     a.botRules.Rules(),
+		RulesFile: path,
+	}
+}
+
+// LoadBotRules 載入指定的機器人規則檔（YAML 或 JSON）並監看其變更
+// 規則檔可擴充（mode: extend）或取代（mode: replace）內建規則；
+// 新規則只影響之後的解析與查詢，已載入檔案的統計不會重新計算
+func (a *App) LoadBotRules(path string) (response BotRulesResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("path", path).
+				Msg("載入機器人規則時發生 panic")
+
+			response = BotRulesResponse{
+				Success:      false,
+				ErrorMessage: "載入機器人規則時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if path == "" {
+		return BotRulesResponse{
+			Success:      false,
+			ErrorMessage: "規則檔路徑不可為空",
+		}
+	}
+
+	// 先驗證規則檔，避免監看無效的檔案
+	if err := a.botRules.LoadFile(path); err != nil {
+		return BotRulesResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+	a.watchBotRules(path)
+
+	return a.GetBotRules()
+}
+
+// CrawlerVerificationResponse 爬蟲驗證設定的回應
+type CrawlerVerificationResponse struct {
+	Success      bool                   `json:"success"`      // 是否成功
+	Profiles     []stats.CrawlerProfile `json:"profiles"`     // 目前的爬蟲驗證資料
+	DNSEnabled   bool                   `json:"dnsEnabled"`   // 是否使用反向/正向 DNS 驗證
+	ErrorMessage string                 `json:"errorMessage"` // 錯誤訊息
+}
+
+// defaultCrawlerRangesPath 取得使用者爬蟲驗證資料檔的預設路徑
+func defaultCrawlerRangesPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".apache-log-analyzer", "crawler-ranges.yaml")
+}
+
+// crawlerVerificationResponse 建立目前爬蟲驗證設定的回應
+func (a *App) crawlerVerificationResponse() CrawlerVerificationResponse {
+	a.watchMu.Lock()
+	dnsEnabled := a.crawlerDNS
+	a.watchMu.Unlock()
+
+	return CrawlerVerificationResponse{
+		Success:    true,
+		Profiles:   a.crawlers.Profiles(),
+		DNSEnabled: dnsEnabled,
+	}
+}
+
+// GetCrawlerVerification 取得目前的爬蟲驗證設定
+func (a *App) GetCrawlerVerification() CrawlerVerificationResponse {
+	return a.crawlerVerificationResponse()
+}
+
+// SetCrawlerDNSVerification 啟用或停用反向/正向 DNS 驗證
+// 停用時只比對官方公布的 IP 範圍；啟用後解析大型檔案可能因 DNS 查詢而變慢
+func (a *App) SetCrawlerDNSVerification(enabled bool) CrawlerVerificationResponse {
+	a.watchMu.Lock()
+	a.crawlerDNS = enabled
+	a.watchMu.Unlock()
+
+	if enabled {
+		a.crawlers.SetResolver(net.DefaultResolver, 0)
+	} else {
+		a.crawlers.SetResolver(nil, 0)
+	}
+	a.log.Info().Bool("enabled", enabled).Msg("已變更爬蟲 DNS 驗證設定")
+
+	return a.crawlerVerificationResponse()
+}
+
+// LoadCrawlerRanges 載入爬蟲驗證資料檔（YAML 或 JSON），更新官方 IP 範圍與網域
+// 新資料只影響之後的解析，已載入檔案的統計不會重新計算
+func (a *App) LoadCrawlerRanges(path string) (response CrawlerVerificationResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("path", path).
+				Msg("載入爬蟲驗證資料時發生 panic")
+
+			response = CrawlerVerificationResponse{
+				Success:      false,
+				ErrorMessage: "載入爬蟲驗證資料時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if path == "" {
+		return CrawlerVerificationResponse{
+			Success:      false,
+			ErrorMessage: "資料檔路徑不可為空",
+		}
+	}
+
+	if err := a.crawlers.LoadFile(path); err != nil {
+		return CrawlerVerificationResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+	return a.crawlerVerificationResponse()
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/cacheanalysis"
+	"access-log-analyzer/internal/exporter"
+)
+
+// CacheAnalysisRequest 快取分析的請求參數
+type CacheAnalysisRequest struct {
+	FilePath string                `json:"filePath"` // 已載入的 log 檔案路徑
+	Options  cacheanalysis.Options `json:"options"`  // 分析參數（零值欄位使用預設值）
+}
+
+// CacheAnalysisResponse 快取分析的回應
+type CacheAnalysisResponse struct {
+	Success      bool                  `json:"success"`      // 是否成功
+	Report       *cacheanalysis.Report `json:"report"`       // 分析結果
+	ErrorMessage string                `json:"errorMessage"` // 錯誤訊息
+}
+
+// ExportCacheReportRequest 匯出快取分析結果的請求參數
+type ExportCacheReportRequest struct {
+	FilePath string                `json:"filePath"` // 已載入的 log 檔案路徑
+	Options  cacheanalysis.Options `json:"options"`  // 分析參數（零值欄位使用預設值）
+	SavePath string                `json:"savePath"` // Excel 檔案儲存路徑
+}
+
+// AnalyzeCache 分析已載入檔案的快取成效
+// 統計各路徑與內容類型的 304 比例、估計節省的流量、偵測靜態資源的重複下載，
+// log 記錄快取狀態時另外計算命中率，並列出快取標頭需要檢查的路徑
+func (a *App) AnalyzeCache(req CacheAnalysisRequest) (response CacheAnalysisResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("分析快取時發生 panic")
+
+			response = CacheAnalysisResponse{
+				Success:      false,
+				ErrorMessage: "分析快取時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return CacheAnalysisResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	report, err := cacheanalysis.NewAnalyzer().Analyze(logFile.Entries, req.Options)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("快取分析失敗")
+		return CacheAnalysisResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return CacheAnalysisResponse{
+		Success: true,
+		Report:  report,
+	}
+}
+
+// ExportCacheReportToExcel 分析已載入檔案的快取成效並將結果匯出為 Excel
+func (a *App) ExportCacheReportToExcel(req ExportCacheReportRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出快取分析時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	analysis := a.AnalyzeCache(CacheAnalysisRequest{FilePath: req.FilePath, Options: req.Options})
+	if !analysis.Success {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: analysis.ErrorMessage,
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportCacheReport(analysis.Report, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("快取分析匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/compare"
+	"access-log-analyzer/internal/exporter"
+	"access-log-analyzer/internal/models"
+)
+
+// ComparisonSource 比較的一側：已載入的檔案，可限定時間範圍
+type ComparisonSource struct {
+	FilePath string              `json:"filePath"` // 已載入的 log 檔案路徑
+	Range    aggregate.TimeRange `json:"range"`    // 時間範圍（零值表示全部）
+}
+
+// CompareRequest 比較兩個檔案或兩個時間範圍的請求參數
+type CompareRequest struct {
+	Before  ComparisonSource `json:"before"`  // 比較基準，例如部署前
+	After   ComparisonSource `json:"after"`   // 比較對象，例如部署後
+	Options compare.Options  `json:"options"` // 顯著門檻與保留筆數（零值使用預設值）
+}
+
+// CompareResponse 比較的回應
+type CompareResponse struct {
+	Success      bool            `json:"success"`      // 是否成功
+	Report       *compare.Report `json:"report"`       // 比較結果
+	ErrorMessage string          `json:"errorMessage"` // 錯誤訊息
+}
+
+// ExportComparisonRequest 匯出比較結果的請求參數
+type ExportComparisonRequest struct {
+	Before   ComparisonSource `json:"before"`   // 比較基準
+	After    ComparisonSource `json:"after"`    // 比較對象
+	Options  compare.Options  `json:"options"`  // 顯著門檻與保留筆數
+	SavePath string           `json:"savePath"` // Excel 檔案儲存路徑
+}
+
+// CompareStatistics 比較兩個已載入的檔案，或同一檔案的兩個時間範圍
+// 兩側都以目前的設定重新計算統計（加大 Top-N 讓路徑比較涵蓋更多路徑），
+// 返回新增與消失的路徑、狀態碼比例變化、處理時間退步，以及新進入排名的 IP 與機器人
+func (a *App) CompareStatistics(req CompareRequest) (response CompareResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("before", req.Before.FilePath).
+				Str("after", req.After.FilePath).
+				Msg("比較統計時發生 panic")
+
+			response = CompareResponse{
+				Success:      false,
+				ErrorMessage: "比較過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	before, err := a.comparisonSnapshot(req.Before, "比較基準")
+	if err != nil {
+		return CompareResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+	after, err := a.comparisonSnapshot(req.After, "比較對象")
+	if err != nil {
+		return CompareResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	report, err := compare.Compare(before, after, req.Options)
+	if err != nil {
+		a.log.Warn().Err(err).Msg("比較統計失敗")
+		return CompareResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+	report.BeforeLabel = comparisonLabel(req.Before)
+	report.AfterLabel = comparisonLabel(req.After)
+
+	return CompareResponse{
+		Success: true,
+		Report:  report,
+	}
+}
+
+// ExportComparisonToExcel 比較兩個檔案或時間範圍並將結果匯出為 Excel
+func (a *App) ExportComparisonToExcel(req ExportComparisonRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("before", req.Before.FilePath).
+				Str("after", req.After.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出比較結果時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	compareResp := a.CompareStatistics(CompareRequest{
+		Before:  req.Before,
+		After:   req.After,
+		Options: req.Options,
+	})
+	if !compareResp.Success {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: compareResp.ErrorMessage,
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportComparison(compareResp.Report, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("比較結果匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
+
+// comparisonSnapshot 計算比較一側的統計與全部路徑的請求數（只包含時間範圍內的記錄）
+func (a *App) comparisonSnapshot(source ComparisonSource, side string) (compare.Snapshot, error) {
+	logFile, exists := a.state.GetFile(source.FilePath)
+	if !exists {
+		return compare.Snapshot{}, fmt.Errorf("找不到%s的檔案資料，請先載入檔案", side)
+	}
+	if !source.Range.Start.IsZero() && !source.Range.End.IsZero() && !source.Range.End.After(source.Range.Start) {
+		return compare.Snapshot{}, fmt.Errorf("%s的時間範圍無效：結束時間必須晚於開始時間", side)
+	}
+
+	var inRange func(*models.LogEntry) bool
+	if !source.Range.IsZero() {
+		inRange = func(entry *models.LogEntry) bool { return source.Range.Contains(entry.Timestamp) }
+	}
+
+	calculator := a.newCalculator()
+	calculator.SetTopN(compare.SnapshotTopN)
+	statistics := calculator.CalculateWhere(logFile.Entries, inRange)
+	if statistics.TotalRequests == 0 {
+		return compare.Snapshot{}, fmt.Errorf("%s的時間範圍內沒有記錄", side)
+	}
+	return compare.Snapshot{Statistics: &statistics, PathCounts: compare.CountPaths(logFile.Entries, inRange)}, nil
+}
+
+// comparisonLabel 返回比較一側的顯示名稱（檔名與時間範圍）
+func comparisonLabel(source ComparisonSource) string {
+	label := filepath.Base(source.FilePath)
+	const layout = "2006-01-02 15:04"
+	switch {
+	case !source.Range.Start.IsZero() && !source.Range.End.IsZero():
+		label += fmt.Sprintf(" %s ~ %s", source.Range.Start.Format(layout), source.Range.End.Format(layout))
+	case !source.Range.Start.IsZero():
+		label += fmt.Sprintf(" %s 起", source.Range.Start.Format(layout))
+	case !source.Range.End.IsZero():
+		label += fmt.Sprintf(" %s 前", source.Range.End.Format(layout))
+	}
+	return label
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"access-log-analyzer/internal/erroranalysis"
+)
+
+// ErrorAnalysisRequest 錯誤分析的請求參數
+type ErrorAnalysisRequest struct {
+	FilePath string                `json:"filePath"` // 已載入的 log 檔案路徑
+	Options  erroranalysis.Options `json:"options"`  // 分析參數（零值欄位使用預設值）
+}
+
+// ErrorAnalysisResponse 錯誤分析的回應
+type ErrorAnalysisResponse struct {
+	Success      bool                  `json:"success"`      // 是否成功
+	Report       *erroranalysis.Report `json:"report"`       // 分析結果
+	ErrorMessage string                `json:"errorMessage"` // 錯誤訊息
+}
+
+// AnalyzeErrors 分析已載入檔案的 4xx/5xx 錯誤
+// 依錯誤次數與錯誤率排名路徑，並提供錯誤時間軸、連續錯誤的爆發區段與各狀態碼的記錄樣本；
+// 指定 Options.Path 時只分析該路徑，用於從排名深入查看單一端點
+func (a *App) AnalyzeErrors(req ErrorAnalysisRequest) (response ErrorAnalysisResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("分析錯誤時發生 panic")
+
+			response = ErrorAnalysisResponse{
+				Success:      false,
+				ErrorMessage: "分析錯誤時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return ErrorAnalysisResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	report, err := erroranalysis.NewAnalyzer().Analyze(logFile.Entries, req.Options)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("錯誤分析失敗")
+		return ErrorAnalysisResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return ErrorAnalysisResponse{
+		Success: true,
+		Report:  report,
+	}
+}
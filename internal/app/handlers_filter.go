@@ -0,0 +1,172 @@
+package app
+
+import (
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+)
+
+// 分頁相關常數
+const (
+	defaultPageSize = 100   // 預設每頁筆數
+	maxPageSize     = 10000 // 每頁最多筆數，避免單次傳輸過多資料
+)
+
+// FilterRequest 篩選/搜尋日誌記錄的請求參數
+type FilterRequest struct {
+	FilePath string                 `json:"filePath"` // 已載入的 log 檔案路徑
+	Criteria *filter.FilterCriteria `json:"criteria"` // 篩選與搜尋條件（nil 表示全部）
+	Offset   int                    `json:"offset"`   // 分頁起始位置
+	Limit    int                    `json:"limit"`    // 每頁筆數（0 表示使用預設值）
+}
+
+// FilterResponse 篩選/搜尋日誌記錄的回應
+type FilterResponse struct {
+	Success      bool              `json:"success"`      // 是否成功
+	Entries      []models.LogEntry `json:"entries"`      // 當頁符合條件的記錄
+	Total        int               `json:"total"`        // 成功解析的記錄總數
+	Matched      int               `json:"matched"`      // 符合條件的記錄數
+	Percentage   float64           `json:"percentage"`   // 符合比例（百分比）
+	Offset       int               `json:"offset"`       // 實際使用的起始位置
+	Limit        int               `json:"limit"`        // 實際使用的每頁筆數
+	ErrorMessage string            `json:"errorMessage"` // 錯誤訊息
+}
+
+// Filter 在後端篩選與搜尋已載入檔案的日誌記錄
+// 只返回當頁記錄與符合數量，避免將全部記錄傳送到前端
+func (a *App) Filter(req FilterRequest) (response FilterResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("篩選過程中發生 panic")
+
+			response = FilterResponse{
+				Success:      false,
+				ErrorMessage: "篩選過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return FilterResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	if err := req.Criteria.Validate(); err != nil {
+		return FilterResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	offset, limit := normalizePage(req.Offset, req.Limit)
+	page, matched, _ := a.state.QueryEntries(req.FilePath, req.Criteria, criteriaKey(req.Criteria), nil, offset, limit)
+
+	a.log.Debug().
+		Str("file", req.FilePath).
+		Int("matched", matched).
+		Int("offset", offset).
+		Int("limit", limit).
+		Msg("篩選完成")
+
+	return FilterResponse{
+		Success:    true,
+		Entries:    page,
+		Total:      logFile.ParsedLines,
+		Matched:    matched,
+		Percentage: matchPercentage(matched, logFile.ParsedLines),
+		Offset:     offset,
+		Limit:      limit,
+	}
+}
+
+// GetEntriesResponse 分頁取得日誌記錄的回應
+type GetEntriesResponse struct {
+	Success      bool              `json:"success"`      // 是否成功
+	Entries      []models.LogEntry `json:"entries"`      // 當頁記錄
+	Total        int               `json:"total"`        // 成功解析的記錄總數
+	Matched      int               `json:"matched"`      // 符合篩選條件的記錄數
+	Offset       int               `json:"offset"`       // 實際使用的起始位置
+	Limit        int               `json:"limit"`        // 實際使用的每頁筆數
+	ErrorMessage string            `json:"errorMessage"` // 錯誤訊息
+}
+
+// GetEntries 分頁取得已載入檔案的日誌記錄
+// 支援依任一欄位在後端排序，並可同時套用篩選條件（nil 表示全部）
+func (a *App) GetEntries(filePath string, offset, limit int, sortSpec *filter.SortSpec, criteria *filter.FilterCriteria) (response GetEntriesResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", filePath).
+				Msg("取得記錄時發生 panic")
+
+			response = GetEntriesResponse{
+				Success:      false,
+				ErrorMessage: "取得記錄時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if err := criteria.Validate(); err != nil {
+		return GetEntriesResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+	if err := sortSpec.Validate(); err != nil {
+		return GetEntriesResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	logFile, exists := a.state.GetFile(filePath)
+	if !exists {
+		return GetEntriesResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	offset, limit = normalizePage(offset, limit)
+	page, matched, _ := a.state.QueryEntries(filePath, criteria, criteriaKey(criteria), sortSpec, offset, limit)
+
+	return GetEntriesResponse{
+		Success: true,
+		Entries: page,
+		Total:   logFile.ParsedLines,
+		Matched: matched,
+		Offset:  offset,
+		Limit:   limit,
+	}
+}
+
+// matchPercentage 計算符合比例（百分比）
+func matchPercentage(matched, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total) * 100
+}
+
+// normalizePage 正規化分頁參數
+// 負數起始位置視為 0，每頁筆數限制在 1 到 maxPageSize 之間
+func normalizePage(offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return offset, limit
+}
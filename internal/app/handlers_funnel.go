@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/exporter"
+	"access-log-analyzer/internal/funnel"
+)
+
+// FunnelRequest 漏斗分析的請求參數
+type FunnelRequest struct {
+	FilePath     string               `json:"filePath"`     // 已載入的 log 檔案路徑
+	Definition   funnel.Definition    `json:"definition"`   // 漏斗定義（依序的路徑模式與步驟間隔）
+	Range        aggregate.TimeRange  `json:"range"`        // 分析的時間範圍（零值表示全部）
+	CompareRange *aggregate.TimeRange `json:"compareRange"` // 比較的時間範圍（nil 表示不比較）
+}
+
+// FunnelResponse 漏斗分析的回應
+type FunnelResponse struct {
+	Success      bool           `json:"success"`      // 是否成功
+	Report       *funnel.Report `json:"report"`       // 分析結果
+	ErrorMessage string         `json:"errorMessage"` // 錯誤訊息
+}
+
+// ExportFunnelRequest 匯出漏斗分析結果的請求參數
+type ExportFunnelRequest struct {
+	FilePath     string               `json:"filePath"`     // 已載入的 log 檔案路徑
+	SavePath     string               `json:"savePath"`     // Excel 檔案儲存路徑
+	Definition   funnel.Definition    `json:"definition"`   // 漏斗定義
+	Range        aggregate.TimeRange  `json:"range"`        // 分析的時間範圍
+	CompareRange *aggregate.TimeRange `json:"compareRange"` // 比較的時間範圍
+}
+
+// AnalyzeFunnel 計算已載入檔案的轉換漏斗
+// 依工作階段或訪客計算每一步的到達數與流失率，可另指定時間範圍進行比較
+func (a *App) AnalyzeFunnel(req FunnelRequest) (response FunnelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("漏斗分析時發生 panic")
+
+			response = FunnelResponse{
+				Success:      false,
+				ErrorMessage: "漏斗分析時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return FunnelResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	report, err := funnel.NewAnalyzer(a.botRules).Analyze(logFile.Entries, req.Definition, req.Range, req.CompareRange)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("漏斗分析失敗")
+		return FunnelResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return FunnelResponse{
+		Success: true,
+		Report:  report,
+	}
+}
+
+// ExportFunnelToExcel 計算轉換漏斗並將結果匯出為 Excel
+func (a *App) ExportFunnelToExcel(req ExportFunnelRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出漏斗分析時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	funnelResp := a.AnalyzeFunnel(FunnelRequest{
+		FilePath:     req.FilePath,
+		Definition:   req.Definition,
+		Range:        req.Range,
+		CompareRange: req.CompareRange,
+	})
+	if !funnelResp.Success {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: funnelResp.ErrorMessage,
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportFunnel(funnelResp.Report, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("漏斗分析匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"access-log-analyzer/internal/geoip"
+)
+
+// GeoIPStatusResponse GeoIP 資料庫狀態的回應
+type GeoIPStatusResponse struct {
+	Success      bool                 `json:"success"`      // 是否成功
+	Enabled      bool                 `json:"enabled"`      // 是否已載入資料庫
+	Databases    []geoip.DatabaseInfo `json:"databases"`    // 已載入的資料庫
+	ErrorMessage string               `json:"errorMessage"` // 錯誤訊息
+}
+
+// LookupIPResponse 單一 IP 查詢的回應
+type LookupIPResponse struct {
+	Success      bool           `json:"success"`      // 是否成功
+	Found        bool           `json:"found"`        // 是否查到任何資訊
+	Location     geoip.Location `json:"location"`     // 國家、城市與 ASN
+	ErrorMessage string         `json:"errorMessage"` // 錯誤訊息
+}
+
+// defaultGeoIPPath 取得使用者 GeoIP 資料庫的預設目錄
+// 將 MaxMind GeoLite2 或 DB-IP 的 .mmdb 檔案放在此目錄即可於啟動時自動載入
+func defaultGeoIPPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".apache-log-analyzer", "geoip")
+}
+
+// geoIPStatusResponse 建立目前 GeoIP 資料庫狀態的回應
+func (a *App) geoIPStatusResponse() GeoIPStatusResponse {
+	return GeoIPStatusResponse{
+		Success:   true,
+		Enabled:   a.geo.Enabled(),
+		Databases: a.geo.Databases(),
+	}
+}
+
+// GetGeoIPStatus 取得目前載入的 GeoIP 與 ASN 資料庫
+func (a *App) GetGeoIPStatus() GeoIPStatusResponse {
+	return a.geoIPStatusResponse()
+}
+
+// LoadGeoIPDatabases 載入 GeoIP 與 ASN 資料庫（單一 .mmdb 檔案或目錄），取代目前的資料庫
+// 新資料庫只影響之後的解析與匯出，已載入檔案的統計不會重新計算
+func (a *App) LoadGeoIPDatabases(path string) (response GeoIPStatusResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("path", path).
+				Msg("載入 GeoIP 資料庫時發生 panic")
+
+			response = GeoIPStatusResponse{
+				Success:      false,
+				ErrorMessage: "載入 GeoIP 資料庫時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if path == "" {
+		return GeoIPStatusResponse{
+			Success:      false,
+			ErrorMessage: "資料庫路徑不可為空",
+		}
+	}
+
+	if _, err := a.geo.Load(path); err != nil {
+		return GeoIPStatusResponse{
+			Success:      false,
+			Enabled:      a.geo.Enabled(),
+			Databases:    a.geo.Databases(),
+			ErrorMessage: err.Error(),
+		}
+	}
+	return a.geoIPStatusResponse()
+}
+
+// LookupIP 查詢單一 IP 的國家、城市與 ASN
+func (a *App) LookupIP(ip string) LookupIPResponse {
+	if !a.geo.Enabled() {
+		return LookupIPResponse{
+			Success:      false,
+			ErrorMessage: "尚未載入 GeoIP 資料庫",
+		}
+	}
+
+	location, found := a.geo.Lookup(ip)
+	return LookupIPResponse{
+		Success:  true,
+		Found:    found,
+		Location: location,
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/exporter"
+)
+
+// ExportNotFoundToExcel 將已載入檔案的失效連結（404/410）報表匯出為 Excel
+func (a *App) ExportNotFoundToExcel(req ExportToExcelRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出失效連結報表時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	statsData, ok := fileStatistics(logFile)
+	if !ok {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "統計資料不存在，請先載入檔案",
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportNotFound(&statsData.NotFound, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("失效連結報表匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"errors"
+
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/query"
+)
+
+// QueryRequest 以查詢語言搜尋日誌記錄的請求參數
+type QueryRequest struct {
+	FilePath string           `json:"filePath"`       // 已載入的 log 檔案路徑
+	Query    string           `json:"query"`          // 查詢字串，例如 status>=500 and not bot
+	Sort     *filter.SortSpec `json:"sort,omitempty"` // 排序條件
+	Offset   int              `json:"offset"`         // 分頁起始位置
+	Limit    int              `json:"limit"`          // 每頁筆數（0 表示使用預設值）
+}
+
+// QueryResponse 以查詢語言搜尋日誌記錄的回應
+type QueryResponse struct {
+	Success      bool               `json:"success"`               // 是否成功
+	Entries      []models.LogEntry  `json:"entries"`               // 當頁符合條件的記錄
+	Total        int                `json:"total"`                 // 成功解析的記錄總數
+	Matched      int                `json:"matched"`               // 符合條件的記錄數
+	Offset       int                `json:"offset"`                // 實際使用的起始位置
+	Limit        int                `json:"limit"`                 // 實際使用的每頁筆數
+	SyntaxError  *query.SyntaxError `json:"syntaxError,omitempty"` // 語法錯誤（含位置，供 UI 標示）
+	ErrorMessage string             `json:"errorMessage"`          // 錯誤訊息
+}
+
+// ValidateQueryResponse 驗證查詢字串的回應
+type ValidateQueryResponse struct {
+	Valid       bool               `json:"valid"`                 // 查詢是否有效
+	SyntaxError *query.SyntaxError `json:"syntaxError,omitempty"` // 語法錯誤（含位置）
+}
+
+// Query 以查詢語言搜尋已載入檔案的日誌記錄
+// 支援布林邏輯、比較、正規表達式、萬用字元、IN 列表與相對時間，結果分頁返回
+func (a *App) Query(req QueryRequest) (response QueryResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Str("query", req.Query).
+				Msg("執行查詢時發生 panic")
+
+			response = QueryResponse{
+				Success:      false,
+				ErrorMessage: "執行查詢時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	q, err := query.ParseWith(req.Query, a.queryOptions())
+	if err != nil {
+		resp := QueryResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+		var syntaxErr *query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			resp.SyntaxError = syntaxErr
+		}
+		return resp
+	}
+
+	if err := req.Sort.Validate(); err != nil {
+		return QueryResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return QueryResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	// 相對時間（now-1h）依執行當下計算，首頁請求一律重新查詢；
+	// 翻頁時沿用快取結果，確保分頁之間的一致性
+	if req.Offset <= 0 {
+		a.state.InvalidateView(req.FilePath)
+	}
+
+	offset, limit := normalizePage(req.Offset, req.Limit)
+	page, matched, _ := a.state.QueryEntries(req.FilePath, q, "query:"+req.Query, req.Sort, offset, limit)
+
+	a.log.Debug().
+		Str("file", req.FilePath).
+		Str("query", req.Query).
+		Int("matched", matched).
+		Msg("查詢完成")
+
+	return QueryResponse{
+		Success: true,
+		Entries: page,
+		Total:   logFile.ParsedLines,
+		Matched: matched,
+		Offset:  offset,
+		Limit:   limit,
+	}
+}
+
+// ValidateQuery 驗證查詢字串的語法
+// 供 UI 在輸入時即時標示錯誤位置；使用與 Query 相同的選項，驗證結果與實際執行一致
+func (a *App) ValidateQuery(queryText string) ValidateQueryResponse {
+	if _, err := query.ParseWith(queryText, a.queryOptions()); err != nil {
+		var syntaxErr *query.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			syntaxErr = &query.SyntaxError{Message: err.Error()}
+		}
+		return ValidateQueryResponse{Valid: false, SyntaxError: syntaxErr}
+	}
+	return ValidateQueryResponse{Valid: true}
+}
+
+// queryOptions 取得解析查詢使用的選項（目前的機器人規則與網段前綴長度）
+func (a *App) queryOptions() query.Options {
+	return query.Options{BotRules: a.botRules, Subnets: a.subnetOptions()}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"access-log-analyzer/internal/exporter"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+)
+
+// SiteHostsResponse 本站主機名稱設定的回應
+type SiteHostsResponse struct {
+	Success      bool     `json:"success"`      // 是否成功
+	Hosts        []string `json:"hosts"`        // 正規化後的本站主機名稱
+	ErrorMessage string   `json:"errorMessage"` // 錯誤訊息
+}
+
+// siteHostList 取得目前的本站主機名稱
+func (a *App) siteHostList() []string {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+	return append([]string(nil), a.siteHosts...)
+}
+
+// GetSiteHosts 取得目前的本站主機名稱
+func (a *App) GetSiteHosts() SiteHostsResponse {
+	return SiteHostsResponse{
+		Success: true,
+		Hosts:   a.siteHostList(),
+	}
+}
+
+// SetSiteHosts 設定本站的主機名稱（空清單表示清除），來自這些主機的 Referer 視為站內流量
+// 新設定只影響之後的解析，已載入檔案的統計不會重新計算
+func (a *App) SetSiteHosts(hosts []string) SiteHostsResponse {
+	normalized, err := stats.NormalizeSiteHosts(hosts)
+	if err != nil {
+		return SiteHostsResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	a.watchMu.Lock()
+	a.siteHosts = normalized
+	a.watchMu.Unlock()
+	a.log.Info().Strs("hosts", normalized).Msg("已變更本站主機名稱")
+
+	return a.GetSiteHosts()
+}
+
+// ExportReferersToExcel 將已載入檔案的流量來源分析匯出為 Excel
+func (a *App) ExportReferersToExcel(req ExportToExcelRequest) (response ExportToExcelResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("sourceFile", req.FilePath).
+				Str("savePath", req.SavePath).
+				Msg("匯出來源分析時發生 panic")
+
+			response = ExportToExcelResponse{
+				Success:      false,
+				ErrorMessage: "匯出過程中發生嚴重錯誤",
+			}
+		}
+	}()
+
+	// T146: 路徑驗證 - 驗證儲存路徑
+	savePath, err := filepath.Abs(req.SavePath)
+	if err != nil {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "無效的儲存路徑",
+		}
+	}
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	statsData, ok := fileStatistics(logFile)
+	if !ok {
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: "統計資料不存在，請先載入檔案",
+		}
+	}
+
+	result, err := exporter.NewXLSXExporter().ExportReferers(&statsData.Referers, savePath)
+	if err != nil {
+		a.log.Error().Err(err).Str("savePath", savePath).Msg("來源分析匯出失敗")
+		return ExportToExcelResponse{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("匯出失敗: %v", err),
+		}
+	}
+
+	return ExportToExcelResponse{
+		Success:       true,
+		ExportPath:    result.FilePath,
+		FileSize:      result.FileSize,
+		TotalRecords:  result.TotalRecords,
+		TruncatedRows: result.TruncatedRows,
+		Duration:      result.Duration,
+		Warnings:      result.Warnings,
+	}
+}
+
+// fileStatistics 取得已載入檔案的統計資訊（相容值與指標兩種儲存方式）
+func fileStatistics(logFile *models.LogFile) (*stats.Statistics, bool) {
+	switch statsData := logFile.Statistics.(type) {
+	case stats.Statistics:
+		return &statsData, true
+	case *stats.Statistics:
+		return statsData, statsData != nil
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"access-log-analyzer/internal/security"
+)
+
+// BruteForceRequest 暴力破解偵測的請求參數
+type BruteForceRequest struct {
+	FilePath string                    `json:"filePath"` // 已載入的 log 檔案路徑
+	Config   security.BruteForceConfig `json:"config"`   // 偵測設定（零值欄位使用預設值）
+}
+
+// BruteForceResponse 暴力破解偵測的回應
+type BruteForceResponse struct {
+	Success      bool                       `json:"success"`      // 是否成功
+	Config       security.BruteForceConfig  `json:"config"`       // 實際使用的設定（已補上預設值）
+	Report       *security.BruteForceReport `json:"report"`       // 偵測結果
+	ErrorMessage string                     `json:"errorMessage"` // 錯誤訊息
+}
+
+// DetectBruteForce 偵測已載入檔案中認證端點的暴力破解與撞庫攻擊
+// 找出在滑動時間窗內登入失敗（401/403）超過門檻的 IP 與帳號，以及多個 IP 嘗試同一帳號的情況
+func (a *App) DetectBruteForce(req BruteForceRequest) (response BruteForceResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("偵測暴力破解時發生 panic")
+
+			response = BruteForceResponse{
+				Success:      false,
+				ErrorMessage: "偵測暴力破解時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return BruteForceResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	detector, err := security.NewBruteForceDetector(req.Config)
+	if err != nil {
+		return BruteForceResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	report := detector.Analyze(logFile.Entries)
+	a.log.Info().
+		Str("file", req.FilePath).
+		Int("failedAttempts", report.FailedAttempts).
+		Int("incidents", len(report.Incidents)).
+		Msg("暴力破解偵測完成")
+
+	return BruteForceResponse{
+		Success: true,
+		Config:  detector.Config(),
+		Report:  &report,
+	}
+}
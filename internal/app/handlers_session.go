@@ -0,0 +1,59 @@
+package app
+
+import (
+	"access-log-analyzer/internal/session"
+)
+
+// SessionRequest 訪客工作階段分析的請求參數
+type SessionRequest struct {
+	FilePath string          `json:"filePath"` // 已載入的 log 檔案路徑
+	Options  session.Options `json:"options"`  // 分析參數（零值欄位使用預設值）
+}
+
+// SessionResponse 訪客工作階段分析的回應
+type SessionResponse struct {
+	Success      bool            `json:"success"`      // 是否成功
+	Report       *session.Report `json:"report"`       // 分析結果
+	ErrorMessage string          `json:"errorMessage"` // 錯誤訊息
+}
+
+// AnalyzeSessions 將已載入檔案的請求重建為訪客工作階段並分析瀏覽路徑
+// 機器人請求依共用的機器人規則排除，結果包含跳出率、持續時間與瀏覽頁數分布、入口與離開頁及 Sankey 圖資料
+func (a *App) AnalyzeSessions(req SessionRequest) (response SessionResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Msg("分析工作階段時發生 panic")
+
+			response = SessionResponse{
+				Success:      false,
+				ErrorMessage: "分析工作階段時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return SessionResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	report, err := session.NewSessionizer(a.botRules).Analyze(logFile.Entries, req.Options)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", req.FilePath).Msg("工作階段分析失敗")
+		return SessionResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return SessionResponse{
+		Success: true,
+		Report:  report,
+	}
+}
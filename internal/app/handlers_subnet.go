@@ -0,0 +1,128 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"access-log-analyzer/internal/subnet"
+)
+
+// SubnetSettingsResponse 網段彙總設定的回應
+type SubnetSettingsResponse struct {
+	Success      bool           `json:"success"`      // 是否成功
+	Options      subnet.Options `json:"options"`      // 目前的前綴長度
+	Groups       []subnet.Group `json:"groups"`       // 目前的具名 CIDR 群組
+	GroupsFile   string         `json:"groupsFile"`   // 最後載入的群組檔路徑
+	ErrorMessage string         `json:"errorMessage"` // 錯誤訊息
+}
+
+// defaultCIDRGroupsPath 取得使用者 CIDR 群組檔的預設路徑
+func defaultCIDRGroupsPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".apache-log-analyzer", "cidr-groups.yaml")
+}
+
+// subnetOptions 取得目前的網段前綴長度
+func (a *App) subnetOptions() subnet.Options {
+	a.watchMu.Lock()
+	defer a.watchMu.Unlock()
+	return a.subnetOpts
+}
+
+// subnetSettingsResponse 建立目前網段彙總設定的回應
+func (a *App) subnetSettingsResponse() SubnetSettingsResponse {
+	a.watchMu.Lock()
+	opts, path := a.subnetOpts, a.cidrGroupsPath
+	a.watchMu.Unlock()
+
+	return SubnetSettingsResponse{
+		Success:    true,
+		Options:    opts,
+		Groups:     a.cidrGroups.Groups(),
+		GroupsFile: path,
+	}
+}
+
+// GetSubnetSettings 取得目前的網段前綴長度與具名 CIDR 群組
+func (a *App) GetSubnetSettings() SubnetSettingsResponse {
+	return a.subnetSettingsResponse()
+}
+
+// SetSubnetOptions 設定網段彙總的前綴長度（0 表示預設 IPv4 /24、IPv6 /64）
+// 新設定只影響之後的解析，已載入檔案的統計不會重新計算
+func (a *App) SetSubnetOptions(opts subnet.Options) SubnetSettingsResponse {
+	if err := opts.Validate(); err != nil {
+		return SubnetSettingsResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	a.watchMu.Lock()
+	a.subnetOpts = opts.WithDefaults()
+	a.watchMu.Unlock()
+	a.log.Info().
+		Int("ipv4Prefix", opts.WithDefaults().IPv4Prefix).
+		Int("ipv6Prefix", opts.WithDefaults().IPv6Prefix).
+		Msg("已變更網段前綴長度")
+
+	return a.subnetSettingsResponse()
+}
+
+// SetCIDRGroups 以清單取代目前的具名 CIDR 群組（空清單表示清除）
+func (a *App) SetCIDRGroups(groups []subnet.Group) SubnetSettingsResponse {
+	if err := a.cidrGroups.Set(groups); err != nil {
+		return SubnetSettingsResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	a.watchMu.Lock()
+	a.cidrGroupsPath = ""
+	a.watchMu.Unlock()
+
+	return a.subnetSettingsResponse()
+}
+
+// LoadCIDRGroups 載入 CIDR 群組檔（YAML 或 JSON），取代目前的群組
+// 新群組只影響之後的解析，已載入檔案的統計不會重新計算
+func (a *App) LoadCIDRGroups(path string) (response SubnetSettingsResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("path", path).
+				Msg("載入 CIDR 群組時發生 panic")
+
+			response = SubnetSettingsResponse{
+				Success:      false,
+				ErrorMessage: "載入 CIDR 群組時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if path == "" {
+		return SubnetSettingsResponse{
+			Success:      false,
+			ErrorMessage: "群組檔路徑不可為空",
+		}
+	}
+
+	if err := a.cidrGroups.LoadFile(path); err != nil {
+		return SubnetSettingsResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	a.watchMu.Lock()
+	a.cidrGroupsPath = path
+	a.watchMu.Unlock()
+
+	return a.subnetSettingsResponse()
+}
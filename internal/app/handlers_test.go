@@ -1,14 +1,27 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/anomaly"
+	"access-log-analyzer/internal/cacheanalysis"
+	"access-log-analyzer/internal/compare"
+	"access-log-analyzer/internal/erroranalysis"
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/funnel"
+	"access-log-analyzer/internal/security"
+	"access-log-analyzer/internal/session"
 	"access-log-analyzer/internal/stats"
+	"access-log-analyzer/internal/subnet"
 )
 
 // TestParseFileWithStatistics 測試 ParseFile API 整合統計計算功能（T079）
@@ -141,3 +154,738 @@ func TestParseFilePerformance(t *testing.T) {
 	// 統計耗時應該合理（10K 行應該 < 100ms）
 	assert.Less(t, resp.LogFile.StatTime, int64(100), "StatTime should be < 100ms for 10K records")
 }
+
+// loadTestLog 建立暫存 log 檔案並透過 ParseFile 載入
+// 返回載入後的檔案路徑
+func loadTestLog(t *testing.T, app *App, content string) string {
+	t.Helper()
+
+	testFile := filepath.Join(t.TempDir(), "test.log")
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
+
+	resp := app.ParseFile(ParseFileRequest{FilePath: testFile})
+	require.True(t, resp.Success, "ParseFile should succeed: %s", resp.ErrorMessage)
+	return testFile
+}
+
+// TestAggregate 測試聚合查詢 API
+func TestAggregate(t *testing.T) {
+	testLog := `127.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /api/users HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+127.0.0.1 - - [01/Jan/2024:10:30:00 +0000] "GET /api/users HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:11:00:00 +0000] "POST /api/login HTTP/1.1" 401 50 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:11:05:00 +0000] "GET /index.html HTTP/1.1" 200 1024 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	resp := app.Aggregate(AggregateRequest{
+		FilePath: testFile,
+		Query: aggregate.Query{
+			Filter: &filter.FilterCriteria{
+				StatusCodeRange: &filter.StatusCodeRange{Min: 400, Max: 499},
+				URL:             "/api/",
+			},
+			GroupBy:    []aggregate.GroupKey{{Field: aggregate.FieldMethod}, {Field: aggregate.FieldTime, Bucket: "1h"}},
+			Aggregates: []aggregate.Aggregate{{Func: aggregate.FuncCount}},
+		},
+	})
+
+	require.True(t, resp.Success, resp.ErrorMessage)
+	require.Len(t, resp.Result.Rows, 2)
+	assert.Equal(t, []string{"GET", "2024-01-01 10:00:00"}, resp.Result.Rows[0].Keys)
+	assert.Equal(t, 2.0, resp.Result.Rows[0].Values[0])
+
+	// 未載入的檔案應返回錯誤
+	resp = app.Aggregate(AggregateRequest{FilePath: "missing.log"})
+	assert.False(t, resp.Success)
+}
+
+// TestFilter 測試後端篩選與搜尋 API 的分頁與符合數量
+func TestFilter(t *testing.T) {
+	testLog := `127.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /api/users HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+127.0.0.1 - - [01/Jan/2024:10:01:00 +0000] "GET /api/orders HTTP/1.1" 500 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:02:00 +0000] "POST /api/login HTTP/1.1" 401 50 "-" "curl/7.68.0"
+10.0.0.1 - - [01/Jan/2024:10:03:00 +0000] "GET /index.html HTTP/1.1" 200 1024 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	// 關鍵字搜尋（不區分大小寫）並分頁
+	resp := app.Filter(FilterRequest{
+		FilePath: testFile,
+		Criteria: &filter.FilterCriteria{Keyword: "API"},
+		Offset:   1,
+		Limit:    1,
+	})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 4, resp.Total)
+	assert.Equal(t, 3, resp.Matched)
+	assert.InDelta(t, 75.0, resp.Percentage, 0.01)
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, "/api/orders", resp.Entries[0].URL)
+
+	// 篩選與搜尋組合
+	resp = app.Filter(FilterRequest{
+		FilePath: testFile,
+		Criteria: &filter.FilterCriteria{
+			StatusCodeRange: &filter.StatusCodeRange{Min: 400, Max: 599},
+			UserAgent:       "curl",
+		},
+	})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 1, resp.Matched)
+	assert.Equal(t, defaultPageSize, resp.Limit)
+
+	// 無效條件應返回錯誤
+	resp = app.Filter(FilterRequest{
+		FilePath: testFile,
+		Criteria: &filter.FilterCriteria{StatusCodeRange: &filter.StatusCodeRange{Min: 500, Max: 400}},
+	})
+	assert.False(t, resp.Success)
+}
+
+// TestGetEntries 測試分頁取得記錄與後端排序
+func TestGetEntries(t *testing.T) {
+	testLog := `127.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /a HTTP/1.1" 200 300 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:01:00 +0000] "GET /b HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:02:00 +0000] "POST /c HTTP/1.1" 200 200 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	// ParseFile 與 GetFileData 只返回摘要
+	summary := app.GetFileData(testFile)
+	require.NotNil(t, summary)
+	assert.Equal(t, 3, summary.ParsedLines)
+	assert.NotNil(t, summary.Statistics)
+
+	// 依回應大小遞減排序並分頁
+	resp := app.GetEntries(testFile, 0, 2, &filter.SortSpec{Field: filter.SortResponseBytes, Descending: true}, nil)
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 3, resp.Matched)
+	require.Len(t, resp.Entries, 2)
+	assert.Equal(t, "/a", resp.Entries[0].URL)
+	assert.Equal(t, "/c", resp.Entries[1].URL)
+
+	// 下一頁沿用相同排序
+	resp = app.GetEntries(testFile, 2, 2, &filter.SortSpec{Field: filter.SortResponseBytes, Descending: true}, nil)
+	require.True(t, resp.Success, resp.ErrorMessage)
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, "/b", resp.Entries[0].URL)
+
+	// 排序與篩選組合
+	resp = app.GetEntries(testFile, 0, 10, &filter.SortSpec{Field: filter.SortIP}, &filter.FilterCriteria{Methods: []string{"GET"}})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	require.Len(t, resp.Entries, 2)
+	assert.Equal(t, "10.0.0.1", resp.Entries[0].IP)
+
+	// 超出範圍的起始位置返回空頁
+	resp = app.GetEntries(testFile, 10, 10, nil, nil)
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Empty(t, resp.Entries)
+	assert.Equal(t, 3, resp.Matched)
+
+	// 不支援的排序欄位與未載入的檔案
+	assert.False(t, app.GetEntries(testFile, 0, 10, &filter.SortSpec{Field: "rawLine"}, nil).Success)
+	assert.False(t, app.GetEntries("missing.log", 0, 10, nil, nil).Success)
+	assert.Nil(t, app.GetFileData("missing.log"))
+}
+
+// TestQuery 測試查詢語言 API
+func TestQuery(t *testing.T) {
+	testLog := `127.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /api/users/1 HTTP/1.1" 500 100 "-" "Mozilla/5.0"
+127.0.0.1 - - [01/Jan/2024:10:01:00 +0000] "GET /api/users/2 HTTP/1.1" 503 300 "-" "Googlebot/2.1"
+10.0.0.1 - - [01/Jan/2024:10:02:00 +0000] "POST /api/login HTTP/1.1" 502 200 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:03:00 +0000] "GET /index.html HTTP/1.1" 500 1024 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	resp := app.Query(QueryRequest{
+		FilePath: testFile,
+		Query:    `status>=500 and path~"/api/" and not bot`,
+		Sort:     &filter.SortSpec{Field: filter.SortResponseBytes, Descending: true},
+	})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 2, resp.Matched)
+	require.Len(t, resp.Entries, 2)
+	assert.Equal(t, "/api/login", resp.Entries[0].URL)
+
+	// 語法錯誤應返回位置
+	resp = app.Query(QueryRequest{FilePath: testFile, Query: "status>=500 and"})
+	assert.False(t, resp.Success)
+	require.NotNil(t, resp.SyntaxError)
+	assert.Equal(t, 15, resp.SyntaxError.Position)
+
+	validation := app.ValidateQuery(`route = "/api/users/:id" or ip = 10.0.0.0/8`)
+	assert.True(t, validation.Valid)
+	validation = app.ValidateQuery("foo = 1")
+	assert.False(t, validation.Valid)
+	assert.Equal(t, 0, validation.SyntaxError.Position)
+}
+
+// TestFilterWithIndex 測試建立反向索引後的搜尋結果與線性掃描一致
+func TestFilterWithIndex(t *testing.T) {
+	testLog := `192.168.1.10 - - [01/Jan/2024:10:00:00 +0000] "GET /api/users HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+192.168.2.20 - - [01/Jan/2024:10:01:00 +0000] "GET /api/orders HTTP/1.1" 500 100 "-" "curl/7.68.0"
+10.0.0.1 - - [01/Jan/2024:10:02:00 +0000] "POST /api/login HTTP/1.1" 401 50 "-" "Googlebot/2.1"
+10.0.0.1 - - [01/Jan/2024:10:03:00 +0000] "GET /index.html HTTP/1.1" 200 1024 "https://www.google.com/" "Mozilla/5.0"
+`
+	testFile := filepath.Join(t.TempDir(), "indexed.log")
+	require.NoError(t, os.WriteFile(testFile, []byte(testLog), 0644))
+
+	indexed := NewApp()
+	resp := indexed.ParseFile(ParseFileRequest{FilePath: testFile, BuildIndex: true})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Greater(t, resp.LogFile.IndexMemory, int64(0))
+	_, ok := indexed.state.GetIndex(testFile)
+	assert.True(t, ok)
+
+	plain := NewApp()
+	require.True(t, plain.ParseFile(ParseFileRequest{FilePath: testFile}).Success)
+	_, ok = plain.state.GetIndex(testFile)
+	assert.False(t, ok)
+
+	criteriaList := []*filter.FilterCriteria{
+		{Keyword: "google"},
+		{URL: "API/O"},
+		{IP: "192.168.0.0/16", UserAgent: "curl"},
+		{IP: "10.0"},
+		{URL: "/"},
+	}
+	for _, criteria := range criteriaList {
+		want := plain.Filter(FilterRequest{FilePath: testFile, Criteria: criteria})
+		got := indexed.Filter(FilterRequest{FilePath: testFile, Criteria: criteria})
+		require.True(t, got.Success, got.ErrorMessage)
+		assert.Equal(t, want.Matched, got.Matched, "criteria: %+v", criteria)
+		assert.Equal(t, want.Entries, got.Entries, "criteria: %+v", criteria)
+	}
+}
+
+// TestDetectBruteForce 測試暴力破解偵測 API
+func TestDetectBruteForce(t *testing.T) {
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "POST /login HTTP/1.1" 401 50 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:05 +0000] "POST /login HTTP/1.1" 401 50 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:10 +0000] "POST /login HTTP/1.1" 401 50 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:15 +0000] "POST /login HTTP/1.1" 302 0 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:00:20 +0000] "GET /index.html HTTP/1.1" 200 1024 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	resp := app.DetectBruteForce(BruteForceRequest{
+		FilePath: testFile,
+		Config:   security.BruteForceConfig{Window: "1m", IPThreshold: 3},
+	})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 10, resp.Config.UserThreshold, "未指定的設定使用預設值")
+	assert.Equal(t, 3, resp.Report.FailedAttempts)
+	require.Len(t, resp.Report.Incidents, 1)
+	assert.Equal(t, "10.0.0.1", resp.Report.Incidents[0].Key)
+	assert.True(t, resp.Report.Incidents[0].Succeeded)
+
+	// 無效設定與未載入的檔案應返回錯誤
+	resp = app.DetectBruteForce(BruteForceRequest{FilePath: testFile, Config: security.BruteForceConfig{Window: "abc"}})
+	assert.False(t, resp.Success)
+	resp = app.DetectBruteForce(BruteForceRequest{FilePath: "missing.log"})
+	assert.False(t, resp.Success)
+}
+
+// TestDetectAnomalies 測試流量異常偵測與匯出 API
+func TestDetectAnomalies(t *testing.T) {
+	var testLog strings.Builder
+	for minute := 0; minute < 10; minute++ {
+		n, status := 5, 200
+		if minute == 8 {
+			n, status = 40, 503
+		}
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&testLog, "10.0.0.%d - - [01/Jan/2024:10:%02d:%02d +0000] \"GET /api/orders HTTP/1.1\" %d 100 \"-\" \"Mozilla/5.0\"\n",
+				i%3+1, minute, i, status)
+		}
+	}
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog.String())
+
+	options := anomaly.Options{Bucket: "1m", Baseline: 5}
+	resp := app.DetectAnomalies(AnomalyRequest{FilePath: testFile, Options: options})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 10, resp.Report.Buckets)
+	kinds := make([]string, 0)
+	for _, w := range resp.Report.Windows {
+		kinds = append(kinds, w.Kind)
+	}
+	assert.Equal(t, []string{anomaly.KindSpike, anomaly.KindErrorSpike}, kinds)
+
+	savePath := filepath.Join(t.TempDir(), "anomalies.xlsx")
+	exportResp := app.ExportAnomaliesToExcel(ExportAnomaliesRequest{FilePath: testFile, SavePath: savePath, Options: options})
+	require.True(t, exportResp.Success, exportResp.ErrorMessage)
+	assert.Equal(t, int64(2), exportResp.TotalRecords)
+	assert.FileExists(t, savePath)
+
+	// 無效參數與未載入的檔案應返回錯誤
+	resp = app.DetectAnomalies(AnomalyRequest{FilePath: testFile, Options: anomaly.Options{Bucket: "abc"}})
+	assert.False(t, resp.Success)
+	resp = app.DetectAnomalies(AnomalyRequest{FilePath: "missing.log"})
+	assert.False(t, resp.Success)
+}
+
+// TestAnalyzeSessions 測試訪客工作階段分析 API
+func TestAnalyzeSessions(t *testing.T) {
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:30 +0000] "GET /products HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:31 +0000] "GET /app.js HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:01:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+66.249.66.1 - - [01/Jan/2024:10:02:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Googlebot/2.1"
+10.0.0.1 - - [01/Jan/2024:10:20:00 +0000] "GET /cart HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	resp := app.AnalyzeSessions(SessionRequest{FilePath: testFile, Options: session.Options{Timeout: "10m"}})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 3, resp.Report.Sessions)
+	assert.Equal(t, 2, resp.Report.Visitors)
+	assert.Equal(t, 1, resp.Report.ExcludedBotRequests)
+	assert.InDelta(t, 200.0/3, resp.Report.BounceRate, 0.001)
+
+	// 無效參數與未載入的檔案應返回錯誤
+	resp = app.AnalyzeSessions(SessionRequest{FilePath: testFile, Options: session.Options{Timeout: "abc"}})
+	assert.False(t, resp.Success)
+	resp = app.AnalyzeSessions(SessionRequest{FilePath: "missing.log"})
+	assert.False(t, resp.Success)
+}
+
+// TestAnalyzeFunnel 測試漏斗分析與匯出 API
+func TestAnalyzeFunnel(t *testing.T) {
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /products/1 HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:01:00 +0000] "GET /cart HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:02:00 +0000] "POST /checkout HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:00:00 +0000] "GET /products/2 HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.3 - - [02/Jan/2024:10:00:00 +0000] "GET /products/3 HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.3 - - [02/Jan/2024:10:05:00 +0000] "GET /cart HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	definition := funnel.Definition{Steps: []funnel.Step{
+		{Name: "商品", Pattern: "^/products"},
+		{Name: "購物車", Pattern: "^/cart$"},
+		{Name: "結帳", Pattern: "^/checkout$"},
+	}}
+	day1 := aggregate.TimeRange{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	day2 := aggregate.TimeRange{Start: day1.End, End: day1.End.Add(24 * time.Hour)}
+
+	resp := app.AnalyzeFunnel(FunnelRequest{FilePath: testFile, Definition: definition, Range: day1, CompareRange: &day2})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	counts := make([]int, 0)
+	for _, step := range resp.Report.Current.Steps {
+		counts = append(counts, step.Count)
+	}
+	assert.Equal(t, []int{2, 1, 1}, counts)
+	require.NotNil(t, resp.Report.Previous)
+	assert.Equal(t, 1, resp.Report.Changes[0].CountDelta)
+
+	savePath := filepath.Join(t.TempDir(), "funnel.xlsx")
+	exportResp := app.ExportFunnelToExcel(ExportFunnelRequest{FilePath: testFile, SavePath: savePath, Definition: definition})
+	require.True(t, exportResp.Success, exportResp.ErrorMessage)
+	assert.Equal(t, int64(3), exportResp.TotalRecords)
+
+	// 無效定義與未載入的檔案應返回錯誤
+	resp = app.AnalyzeFunnel(FunnelRequest{FilePath: testFile, Definition: funnel.Definition{Steps: definition.Steps[:1]}})
+	assert.False(t, resp.Success)
+	resp = app.AnalyzeFunnel(FunnelRequest{FilePath: "missing.log", Definition: definition})
+	assert.False(t, resp.Success)
+}
+
+// TestGeoIP 測試載入 GeoIP 資料庫、查詢 IP 與統計中的國家排名
+func TestGeoIP(t *testing.T) {
+	app := NewApp()
+
+	status := app.GetGeoIPStatus()
+	require.True(t, status.Success)
+	assert.False(t, status.Enabled)
+	assert.False(t, app.LookupIP("203.0.113.7").Success, "未載入資料庫時無法查詢")
+
+	status = app.LoadGeoIPDatabases("../geoip/testdata")
+	require.True(t, status.Success, status.ErrorMessage)
+	assert.True(t, status.Enabled)
+	assert.Len(t, status.Databases, 2)
+
+	lookup := app.LookupIP("203.0.113.7")
+	require.True(t, lookup.Success)
+	assert.True(t, lookup.Found)
+	assert.Equal(t, "TW", lookup.Location.Country)
+	assert.Equal(t, uint(3462), lookup.Location.ASN)
+
+	testLog := `203.0.113.7 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+203.0.113.8 - - [01/Jan/2024:10:00:01 +0000] "GET /missing HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+198.51.100.1 - - [01/Jan/2024:10:00:02 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`
+	testFile := loadTestLog(t, app, testLog)
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statistics, ok := logFile.Statistics.(stats.Statistics)
+	require.True(t, ok)
+	require.NotEmpty(t, statistics.Countries)
+	assert.Equal(t, "TW", statistics.Countries[0].Key)
+	assert.Equal(t, 2, statistics.Countries[0].RequestCount)
+	assert.Equal(t, "AS3462", statistics.ASNs[0].Key)
+
+	// 無效路徑應返回錯誤並保留原本的資料庫
+	status = app.LoadGeoIPDatabases(filepath.Join(t.TempDir(), "missing"))
+	assert.False(t, status.Success)
+	assert.True(t, status.Enabled)
+	assert.False(t, app.LoadGeoIPDatabases("").Success)
+}
+
+// TestSubnetSettings 測試網段前綴長度與 CIDR 群組的設定，以及統計中的網段排名
+func TestSubnetSettings(t *testing.T) {
+	app := NewApp()
+
+	settings := app.GetSubnetSettings()
+	require.True(t, settings.Success)
+	assert.Equal(t, subnet.DefaultOptions(), settings.Options)
+	assert.Empty(t, settings.Groups)
+
+	settings = app.SetSubnetOptions(subnet.Options{IPv4Prefix: 16})
+	require.True(t, settings.Success, settings.ErrorMessage)
+	assert.Equal(t, subnet.Options{IPv4Prefix: 16, IPv6Prefix: 64}, settings.Options)
+	assert.False(t, app.SetSubnetOptions(subnet.Options{IPv6Prefix: 200}).Success)
+
+	groupsFile := filepath.Join(t.TempDir(), "cidr-groups.yaml")
+	require.NoError(t, os.WriteFile(groupsFile, []byte("groups:\n  - name: monitoring\n    cidrs: [10.1.0.0/16]\n"), 0644))
+	settings = app.LoadCIDRGroups(groupsFile)
+	require.True(t, settings.Success, settings.ErrorMessage)
+	assert.Equal(t, groupsFile, settings.GroupsFile)
+	require.Len(t, settings.Groups, 1)
+	assert.Equal(t, "monitoring", settings.Groups[0].Name)
+
+	testLog := `10.1.2.3 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.1.9.9 - - [01/Jan/2024:10:00:01 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+[2001:DB8::1] - - [01/Jan/2024:10:00:02 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`
+	testFile := loadTestLog(t, app, testLog)
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statistics, ok := logFile.Statistics.(stats.Statistics)
+	require.True(t, ok)
+	require.Len(t, statistics.TopSubnets, 2)
+	assert.Equal(t, "10.1.0.0/16", statistics.TopSubnets[0].Subnet)
+	assert.Equal(t, "monitoring", statistics.TopSubnets[0].Group)
+	assert.Equal(t, "2001:db8::/64", statistics.TopSubnets[1].Subnet)
+	require.Len(t, statistics.CIDRGroups, 1)
+	assert.Equal(t, 2, statistics.CIDRGroups[0].RequestCount)
+
+	// 以清單取代群組；無效的群組與路徑應返回錯誤
+	settings = app.SetCIDRGroups([]subnet.Group{{Name: "office", CIDRs: []string{"192.0.2.0/24"}}})
+	require.True(t, settings.Success, settings.ErrorMessage)
+	assert.Empty(t, settings.GroupsFile)
+	assert.Equal(t, "office", settings.Groups[0].Name)
+	assert.False(t, app.SetCIDRGroups([]subnet.Group{{Name: "bad", CIDRs: []string{"10.0.0.0/99"}}}).Success)
+	assert.False(t, app.LoadCIDRGroups("").Success)
+	assert.False(t, app.LoadCIDRGroups(filepath.Join(t.TempDir(), "missing.yaml")).Success)
+}
+
+// TestUserAgentRules 測試 User-Agent 解析、規則檔載入與統計中的分布
+func TestUserAgentRules(t *testing.T) {
+	app := NewApp()
+
+	rules := app.GetUserAgentRules()
+	require.True(t, rules.Success)
+	assert.Equal(t, "內建", rules.Source)
+
+	parsed := app.ParseUserAgent("Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1")
+	require.True(t, parsed.Success)
+	assert.Equal(t, "Mobile Safari", parsed.UserAgent.Browser)
+	assert.Equal(t, "iOS", parsed.UserAgent.OS)
+	assert.Equal(t, "mobile", parsed.UserAgent.Device)
+
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+10.0.0.2 - - [01/Jan/2024:10:00:01 +0000] "GET / HTTP/1.1" 200 100 "-" "curl/8.4.0"
+`
+	testFile := loadTestLog(t, app, testLog)
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statistics, ok := logFile.Statistics.(stats.Statistics)
+	require.True(t, ok)
+	require.Len(t, statistics.UserAgents.Browsers, 2)
+	assert.Equal(t, 1, statistics.UserAgents.Browsers[0].Count)
+
+	// 自訂規則檔
+	path := filepath.Join(t.TempDir(), "ua-regexes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("user_agent_parsers:\n  - regex: '(Custom)/(\\d+)'\n"), 0644))
+	rules = app.LoadUserAgentRegexes(path)
+	require.True(t, rules.Success, rules.ErrorMessage)
+	assert.Equal(t, path, rules.Source)
+	assert.Equal(t, "Custom", app.ParseUserAgent("Custom/2").UserAgent.Browser)
+
+	assert.False(t, app.LoadUserAgentRegexes("").Success)
+	rules = app.LoadUserAgentRegexes(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.False(t, rules.Success)
+	assert.Equal(t, path, rules.Source, "載入失敗時保留原本的規則")
+}
+
+// TestRefererAnalysis 測試本站主機設定、統計中的來源分析與匯出
+func TestRefererAnalysis(t *testing.T) {
+	app := NewApp()
+
+	settings := app.GetSiteHosts()
+	require.True(t, settings.Success)
+	assert.Empty(t, settings.Hosts)
+
+	settings = app.SetSiteHosts([]string{"https://www.example.com/", "shop.example.com"})
+	require.True(t, settings.Success, settings.ErrorMessage)
+	assert.Equal(t, []string{"example.com", "shop.example.com"}, settings.Hosts)
+	assert.False(t, app.SetSiteHosts([]string{"bad host"}).Success)
+	assert.Equal(t, []string{"example.com", "shop.example.com"}, app.GetSiteHosts().Hosts, "無效的設定不影響目前的主機名稱")
+
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "https://www.google.com/" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:01 +0000] "GET /about HTTP/1.1" 200 100 "https://www.example.com/" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:00:02 +0000] "GET /?utm_source=newsletter&utm_medium=email HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.3 - - [01/Jan/2024:10:00:03 +0000] "GET /logo.png HTTP/1.1" 200 500 "https://forum.other.net/t/1" "Mozilla/5.0"
+`
+	testFile := loadTestLog(t, app, testLog)
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statistics, ok := logFile.Statistics.(stats.Statistics)
+	require.True(t, ok)
+	assert.Equal(t, 1, statistics.Referers.Internal)
+	assert.Equal(t, 2, statistics.Referers.External)
+	require.Len(t, statistics.Referers.Campaigns, 1)
+	assert.Equal(t, "newsletter", statistics.Referers.Campaigns[0].Source)
+	assert.Equal(t, 1, statistics.Referers.HotlinkRequests)
+
+	savePath := filepath.Join(t.TempDir(), "referers.xlsx")
+	exported := app.ExportReferersToExcel(ExportToExcelRequest{FilePath: testFile, SavePath: savePath})
+	require.True(t, exported.Success, exported.ErrorMessage)
+	assert.FileExists(t, savePath)
+	assert.False(t, app.ExportReferersToExcel(ExportToExcelRequest{FilePath: "missing.log", SavePath: savePath}).Success)
+}
+
+// TestNotFoundReport 測試統計中的失效連結報表與匯出
+func TestNotFoundReport(t *testing.T) {
+	app := NewApp()
+	require.True(t, app.SetSiteHosts([]string{"example.com"}).Success)
+
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /old HTTP/1.1" 404 100 "https://example.com/blog" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:00:01 +0000] "GET /old HTTP/1.1" 404 100 "-" "Googlebot/2.1"
+10.0.0.3 - - [01/Jan/2024:10:00:02 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`
+	testFile := loadTestLog(t, app, testLog)
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statistics, ok := logFile.Statistics.(stats.Statistics)
+	require.True(t, ok)
+	require.Len(t, statistics.NotFound.Paths, 1)
+	assert.Equal(t, "/old", statistics.NotFound.Paths[0].Path)
+	assert.Equal(t, 1, statistics.NotFound.Paths[0].BotHits)
+	require.Len(t, statistics.NotFound.Paths[0].InternalReferers, 1)
+
+	savePath := filepath.Join(t.TempDir(), "not-found.xlsx")
+	exported := app.ExportNotFoundToExcel(ExportToExcelRequest{FilePath: testFile, SavePath: savePath})
+	require.True(t, exported.Success, exported.ErrorMessage)
+	assert.Equal(t, int64(1), exported.TotalRecords)
+	assert.False(t, app.ExportNotFoundToExcel(ExportToExcelRequest{FilePath: "missing.log", SavePath: savePath}).Success)
+}
+
+// TestAnalyzeErrors 測試錯誤分析 API 與單一路徑的深入分析
+func TestAnalyzeErrors(t *testing.T) {
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:00:01 +0000] "GET /api/export HTTP/1.1" 503 100 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:10:00:02 +0000] "GET /api/export HTTP/1.1" 503 100 "-" "Mozilla/5.0"
+10.0.0.3 - - [01/Jan/2024:10:00:03 +0000] "GET /missing HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+
+	resp := app.AnalyzeErrors(ErrorAnalysisRequest{FilePath: testFile, Options: erroranalysis.Options{MinRequests: 2}})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	require.NotEmpty(t, resp.Report.ByRate)
+	assert.Equal(t, "/api/export", resp.Report.ByRate[0].Path)
+	require.Len(t, resp.Report.Samples, 2)
+	assert.Equal(t, 404, resp.Report.Samples[0].StatusCode)
+	assert.Equal(t, 4, resp.Report.Samples[0].Entries[0].LineNumber)
+
+	drill := app.AnalyzeErrors(ErrorAnalysisRequest{FilePath: testFile, Options: erroranalysis.Options{Path: "/api/export"}})
+	require.True(t, drill.Success, drill.ErrorMessage)
+	assert.Equal(t, 2, drill.Report.TotalRequests)
+	require.Len(t, drill.Report.Samples, 1)
+	assert.Equal(t, 503, drill.Report.Samples[0].StatusCode)
+
+	assert.False(t, app.AnalyzeErrors(ErrorAnalysisRequest{FilePath: testFile, Options: erroranalysis.Options{Bucket: "bad"}}).Success)
+	assert.False(t, app.AnalyzeErrors(ErrorAnalysisRequest{FilePath: "missing.log"}).Success)
+}
+
+// TestCompareStatistics 測試同一檔案兩個時間範圍與兩個檔案的比較，以及比較結果匯出
+func TestCompareStatistics(t *testing.T) {
+	testLog := `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:10:10:00 +0000] "GET /legacy HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.1 - - [01/Jan/2024:11:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.2 - - [01/Jan/2024:11:10:00 +0000] "GET /new HTTP/1.1" 500 100 "-" "Mozilla/5.0"
+`
+	app := NewApp()
+	testFile := loadTestLog(t, app, testLog)
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	req := CompareRequest{
+		Before:  ComparisonSource{FilePath: testFile, Range: aggregate.TimeRange{Start: base, End: base.Add(time.Hour)}},
+		After:   ComparisonSource{FilePath: testFile, Range: aggregate.TimeRange{Start: base.Add(time.Hour)}},
+		Options: compare.Options{MinCount: 1},
+	}
+	resp := app.CompareStatistics(req)
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, "test.log 2024-01-01 10:00 ~ 2024-01-01 11:00", resp.Report.BeforeLabel)
+	assert.Equal(t, "test.log 2024-01-01 11:00 起", resp.Report.AfterLabel)
+	require.Len(t, resp.Report.NewPaths, 1)
+	assert.Equal(t, "/new", resp.Report.NewPaths[0].Path)
+	require.Len(t, resp.Report.VanishedPaths, 1)
+	assert.Equal(t, "/legacy", resp.Report.VanishedPaths[0].Path)
+	require.Len(t, resp.Report.NewTopIPs, 1)
+	assert.Equal(t, "10.0.0.2", resp.Report.NewTopIPs[0].Name)
+
+	// 兩個檔案：比較對象沒有變化
+	otherFile := loadTestLog(t, app, testLog)
+	fileResp := app.CompareStatistics(CompareRequest{
+		Before: ComparisonSource{FilePath: testFile},
+		After:  ComparisonSource{FilePath: otherFile},
+	})
+	require.True(t, fileResp.Success, fileResp.ErrorMessage)
+	assert.Empty(t, fileResp.Report.NewPaths)
+	assert.Empty(t, fileResp.Report.VanishedPaths)
+
+	savePath := filepath.Join(t.TempDir(), "comparison.xlsx")
+	exported := app.ExportComparisonToExcel(ExportComparisonRequest{Before: req.Before, After: req.After, Options: req.Options, SavePath: savePath})
+	require.True(t, exported.Success, exported.ErrorMessage)
+	assert.FileExists(t, savePath)
+
+	// 無效的檔案、時間範圍與參數
+	assert.False(t, app.CompareStatistics(CompareRequest{Before: ComparisonSource{FilePath: "missing.log"}, After: req.After}).Success)
+	assert.False(t, app.CompareStatistics(CompareRequest{
+		Before: ComparisonSource{FilePath: testFile, Range: aggregate.TimeRange{Start: base.Add(time.Hour), End: base}},
+		After:  req.After,
+	}).Success)
+	assert.False(t, app.CompareStatistics(CompareRequest{
+		Before: ComparisonSource{FilePath: testFile, Range: aggregate.TimeRange{Start: base.Add(24 * time.Hour)}},
+		After:  req.After,
+	}).Success, "時間範圍內沒有記錄")
+	assert.False(t, app.CompareStatistics(CompareRequest{Before: req.Before, After: req.After, Options: compare.Options{TopN: -1}}).Success)
+}
+
+// TestGetHostStatistics 測試 vhost_combined 格式的載入、來源伺服器標記與單一虛擬主機統計
+func TestGetHostStatistics(t *testing.T) {
+	testLog := `www.example.com:443 10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+www.example.com:443 10.0.0.2 - - [01/Jan/2024:10:00:01 +0000] "GET /about HTTP/1.1" 404 100 "-" "Mozilla/5.0"
+api.example.com:8080 10.0.0.3 - - [01/Jan/2024:10:00:02 +0000] "POST /v1 HTTP/1.1" 500 100 "-" "curl/8.0"
+`
+	testFile := filepath.Join(t.TempDir(), "vhost.log")
+	require.NoError(t, os.WriteFile(testFile, []byte(testLog), 0644))
+
+	app := NewApp()
+	parsed := app.ParseFile(ParseFileRequest{FilePath: testFile, Server: "web-01"})
+	require.True(t, parsed.Success, parsed.ErrorMessage)
+	assert.Equal(t, 3, parsed.LogFile.ParsedLines)
+
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	assert.Equal(t, "www.example.com", logFile.Entries[0].VirtualHost)
+	assert.Equal(t, 443, logFile.Entries[0].ServerPort)
+	assert.Equal(t, "web-01", logFile.Entries[0].Server)
+
+	all := app.GetHostStatistics(HostStatisticsRequest{FilePath: testFile})
+	require.True(t, all.Success, all.ErrorMessage)
+	assert.Equal(t, 3, all.Statistics.TotalRequests)
+	require.Len(t, all.Statistics.VirtualHosts, 2)
+	assert.Equal(t, "www.example.com", all.Statistics.VirtualHosts[0].Host)
+
+	www := app.GetHostStatistics(HostStatisticsRequest{FilePath: testFile, Host: "WWW.example.com"})
+	require.True(t, www.Success, www.ErrorMessage)
+	assert.Equal(t, "www.example.com", www.Host)
+	assert.Equal(t, 2, www.Statistics.TotalRequests)
+	assert.Equal(t, 1, www.Statistics.StatusCodeDistribution.ClientError)
+	assert.Zero(t, www.Statistics.StatusCodeDistribution.ServerError)
+
+	// 篩選記錄時也可以只看單一虛擬主機
+	entries := app.GetEntries(testFile, 0, 10, nil, &filter.FilterCriteria{VirtualHost: "api.example.com"})
+	require.True(t, entries.Success, entries.ErrorMessage)
+	assert.Equal(t, 1, entries.Matched)
+
+	// 來源伺服器摘要與單一伺服器統計
+	require.Len(t, all.Statistics.Servers, 1)
+	assert.Equal(t, "web-01", all.Statistics.Servers[0].Host)
+	web := app.GetHostStatistics(HostStatisticsRequest{FilePath: testFile, Host: "api.example.com", Server: "WEB-01"})
+	require.True(t, web.Success, web.ErrorMessage)
+	assert.Equal(t, "WEB-01", web.Server)
+	assert.Equal(t, 1, web.Statistics.TotalRequests)
+
+	assert.False(t, app.GetHostStatistics(HostStatisticsRequest{FilePath: testFile, Host: "missing.example.com"}).Success)
+	assert.False(t, app.GetHostStatistics(HostStatisticsRequest{FilePath: testFile, Server: "web-02"}).Success)
+	assert.False(t, app.GetHostStatistics(HostStatisticsRequest{FilePath: "missing.log"}).Success)
+
+	// 未指定伺服器時以檔名標記
+	other := filepath.Join(t.TempDir(), "web-02.log")
+	require.NoError(t, os.WriteFile(other, []byte(testLog), 0644))
+	require.True(t, app.ParseFile(ParseFileRequest{FilePath: other}).Success)
+	otherFile, exists := app.state.GetFile(other)
+	require.True(t, exists)
+	assert.Equal(t, "web-02.log", otherFile.Entries[0].Server)
+}
+
+// TestGetUserActivity 測試已認證使用者統計與單一使用者的活動時間軸
+func TestGetUserActivity(t *testing.T) {
+	app := NewApp()
+	testFile := loadTestLog(t, app, `10.0.0.1 - alice [01/Jan/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.2 - alice [01/Jan/2024:12:30:00 +0000] "GET /admin HTTP/1.1" 403 100 "-" "Mozilla/5.0"
+10.0.0.3 - bob [01/Jan/2024:11:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+10.0.0.4 - - [01/Jan/2024:11:00:00 +0000] "GET / HTTP/1.1" 200 100 "-" "Mozilla/5.0"
+`)
+
+	logFile, exists := app.state.GetFile(testFile)
+	require.True(t, exists)
+	statsData, ok := fileStatistics(logFile)
+	require.True(t, ok)
+	users := statsData.Users
+	assert.Equal(t, 3, users.AuthenticatedRequests)
+	assert.Equal(t, 2, users.UniqueUsers)
+	require.NotEmpty(t, users.TopUsers)
+	assert.Equal(t, "alice", users.TopUsers[0].User)
+
+	resp := app.GetUserActivity(UserActivityRequest{FilePath: testFile, User: "alice"})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, "1h", resp.Timeline.Bucket)
+	require.Len(t, resp.Timeline.Points, 3)
+	assert.Equal(t, 1, resp.Timeline.Points[0].Requests)
+	assert.Zero(t, resp.Timeline.Points[1].Requests)
+	assert.Equal(t, 1, resp.Timeline.Points[2].Errors)
+
+	assert.False(t, app.GetUserActivity(UserActivityRequest{FilePath: testFile, User: "-"}).Success)
+	assert.False(t, app.GetUserActivity(UserActivityRequest{FilePath: testFile, User: "carol"}).Success)
+	assert.False(t, app.GetUserActivity(UserActivityRequest{FilePath: "missing.log", User: "alice"}).Success)
+}
+
+// TestAnalyzeCache 測試快取分析 API（含 log 記錄的快取狀態）與匯出
+func TestAnalyzeCache(t *testing.T) {
+	app := NewApp()
+	testFile := loadTestLog(t, app, `10.0.0.1 - - [01/Jan/2024:10:00:00 +0000] "GET /logo.png HTTP/1.1" 200 2000 "-" "Mozilla/5.0" "MISS"
+10.0.0.2 - - [01/Jan/2024:10:00:05 +0000] "GET /logo.png HTTP/1.1" 304 0 "-" "Mozilla/5.0" "HIT"
+10.0.0.3 - - [01/Jan/2024:10:00:06 +0000] "GET /logo.png HTTP/1.1" 304 0 "-" "Mozilla/5.0" "HIT"
+10.0.0.1 - - [01/Jan/2024:10:01:00 +0000] "GET /logo.png HTTP/1.1" 200 2000 "-" "Mozilla/5.0" "HIT"
+`)
+
+	resp := app.AnalyzeCache(CacheAnalysisRequest{FilePath: testFile, Options: cacheanalysis.Options{MinRequests: 1}})
+	require.True(t, resp.Success, resp.ErrorMessage)
+	assert.Equal(t, 2, resp.Report.NotModified)
+	assert.Equal(t, int64(4000), resp.Report.BytesSaved)
+	assert.Equal(t, 1, resp.Report.RepeatFetches)
+	require.NotNil(t, resp.Report.CacheStatus)
+	assert.InDelta(t, 75, resp.Report.CacheStatus.HitRatio, 0.001)
+	assert.NotEmpty(t, resp.Report.Recommendations)
+
+	savePath := filepath.Join(t.TempDir(), "cache.xlsx")
+	exported := app.ExportCacheReportToExcel(ExportCacheReportRequest{FilePath: testFile, SavePath: savePath})
+	require.True(t, exported.Success, exported.ErrorMessage)
+	assert.FileExists(t, savePath)
+
+	assert.False(t, app.AnalyzeCache(CacheAnalysisRequest{FilePath: testFile, Options: cacheanalysis.Options{RepeatWindow: "abc"}}).Success)
+	assert.False(t, app.AnalyzeCache(CacheAnalysisRequest{FilePath: "missing.log"}).Success)
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"access-log-analyzer/internal/useragent"
+)
+
+// UserAgentRulesResponse User-Agent 解析規則的回應
+type UserAgentRulesResponse struct {
+	Success      bool   `json:"success"`      // 是否成功
+	Source       string `json:"source"`       // 目前規則的來源："內建" 或規則檔路徑
+	ErrorMessage string `json:"errorMessage"` // 錯誤訊息
+}
+
+// ParseUserAgentResponse 單一 User-Agent 解析的回應
+type ParseUserAgentResponse struct {
+	Success   bool                `json:"success"`   // 是否成功
+	UserAgent useragent.UserAgent `json:"userAgent"` // 解析結果
+}
+
+// defaultUserAgentRegexesPath 取得使用者 User-Agent 規則檔的預設路徑
+func defaultUserAgentRegexesPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".apache-log-analyzer", "ua-regexes.yaml")
+}
+
+// GetUserAgentRules 取得目前 User-Agent 解析規則的來源
+func (a *App) GetUserAgentRules() UserAgentRulesResponse {
+	return UserAgentRulesResponse{
+		Success: true,
+		Source:  a.uaParser.Source(),
+	}
+}
+
+// LoadUserAgentRegexes 載入 uap-core 格式的 User-Agent 規則檔，取代內建規則
+// 新規則只影響之後的解析，已載入檔案的統計不會重新計算
+func (a *App) LoadUserAgentRegexes(path string) (response UserAgentRulesResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("path", path).
+				Msg("載入 User-Agent 規則時發生 panic")
+
+			response = UserAgentRulesResponse{
+				Success:      false,
+				ErrorMessage: "載入 User-Agent 規則時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	if path == "" {
+		return UserAgentRulesResponse{
+			Success:      false,
+			ErrorMessage: "規則檔路徑不可為空",
+		}
+	}
+
+	if err := a.uaParser.LoadFile(path); err != nil {
+		return UserAgentRulesResponse{
+			Success:      false,
+			Source:       a.uaParser.Source(),
+			ErrorMessage: err.Error(),
+		}
+	}
+	return a.GetUserAgentRules()
+}
+
+// ParseUserAgent 解析單一 User-Agent 的瀏覽器、作業系統、裝置類型與排版引擎
+func (a *App) ParseUserAgent(userAgent string) ParseUserAgentResponse {
+	return ParseUserAgentResponse{
+		Success:   true,
+		UserAgent: a.uaParser.Parse(userAgent),
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"access-log-analyzer/internal/stats"
+)
+
+// UserActivityRequest 取得單一使用者活動時間軸的請求參數
+type UserActivityRequest struct {
+	FilePath string `json:"filePath"`         // 已載入的 log 檔案路徑
+	User     string `json:"user"`             // 使用者名稱（%u）
+	Bucket   string `json:"bucket,omitempty"` // 區間長度，例如 "1h"、"1d"（預設 "1h"）
+}
+
+// UserActivityResponse 使用者活動時間軸的回應
+type UserActivityResponse struct {
+	Success      bool                `json:"success"`      // 是否成功
+	Timeline     *stats.UserTimeline `json:"timeline"`     // 活動時間軸
+	ErrorMessage string              `json:"errorMessage"` // 錯誤訊息
+}
+
+// GetUserActivity 取得已載入檔案中單一已認證使用者的活動時間軸
+func (a *App) GetUserActivity(req UserActivityRequest) (response UserActivityResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Str("user", req.User).
+				Msg("建立使用者活動時間軸時發生 panic")
+
+			response = UserActivityResponse{
+				Success:      false,
+				ErrorMessage: "建立使用者活動時間軸時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return UserActivityResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	timeline, err := stats.BuildUserTimeline(logFile.Entries, req.User, req.Bucket)
+	if err != nil {
+		return UserActivityResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	return UserActivityResponse{
+		Success:  true,
+		Timeline: timeline,
+	}
+}
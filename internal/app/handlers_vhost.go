@@ -0,0 +1,88 @@
+package app
+
+import (
+	"strings"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+)
+
+// HostStatisticsRequest 取得單一虛擬主機或來源伺服器統計的請求參數
+type HostStatisticsRequest struct {
+	FilePath string `json:"filePath"`         // 已載入的 log 檔案路徑
+	Host     string `json:"host"`             // 虛擬主機名稱（空字串表示全部主機）
+	Server   string `json:"server,omitempty"` // 來源伺服器名稱（空字串表示全部伺服器）
+}
+
+// HostStatisticsResponse 虛擬主機統計的回應
+type HostStatisticsResponse struct {
+	Success      bool              `json:"success"`      // 是否成功
+	Host         string            `json:"host"`         // 統計的虛擬主機（全部主機時為空字串）
+	Server       string            `json:"server"`       // 統計的來源伺服器（全部伺服器時為空字串）
+	Statistics   *stats.Statistics `json:"statistics"`   // 統計資訊
+	ErrorMessage string            `json:"errorMessage"` // 錯誤訊息
+}
+
+// GetHostStatistics 取得已載入檔案中單一虛擬主機或來源伺服器的統計資訊
+// Host 與 Server 皆為空字串時返回載入時計算的全部統計；否則只以符合的記錄重新計算
+func (a *App) GetHostStatistics(req HostStatisticsRequest) (response HostStatisticsResponse) {
+	// T150: Panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			a.log.Error().
+				Interface("panic", r).
+				Str("file", req.FilePath).
+				Str("host", req.Host).
+				Str("server", req.Server).
+				Msg("計算虛擬主機統計時發生 panic")
+
+			response = HostStatisticsResponse{
+				Success:      false,
+				ErrorMessage: "計算虛擬主機統計時發生嚴重錯誤",
+			}
+		}
+	}()
+
+	logFile, exists := a.state.GetFile(req.FilePath)
+	if !exists {
+		return HostStatisticsResponse{
+			Success:      false,
+			ErrorMessage: "找不到檔案資料，請先載入檔案",
+		}
+	}
+
+	host := strings.ToLower(strings.TrimSpace(req.Host))
+	server := strings.TrimSpace(req.Server)
+	if host == "" && server == "" {
+		statsData, ok := fileStatistics(logFile)
+		if !ok {
+			return HostStatisticsResponse{
+				Success:      false,
+				ErrorMessage: "統計資料不存在，請先載入檔案",
+			}
+		}
+		return HostStatisticsResponse{
+			Success:    true,
+			Statistics: statsData,
+		}
+	}
+
+	match := func(entry *models.LogEntry) bool {
+		return (host == "" || entry.VirtualHost == host) &&
+			(server == "" || strings.EqualFold(entry.Server, server))
+	}
+	statistics := a.newCalculator().CalculateWhere(logFile.Entries, match)
+	if statistics.TotalRequests == 0 {
+		return HostStatisticsResponse{
+			Success:      false,
+			ErrorMessage: "檔案中沒有此虛擬主機或伺服器的記錄: " + strings.Trim(host+" "+server, " "),
+		}
+	}
+
+	return HostStatisticsResponse{
+		Success:    true,
+		Host:       host,
+		Server:     server,
+		Statistics: &statistics,
+	}
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/index"
 	"access-log-analyzer/internal/models"
 	"encoding/json"
 	"os"
@@ -18,6 +20,15 @@ type State struct {
 	activeTab    string                     // 目前活動頁籤的檔案路徑
 	selectedRows map[string][]int           // 檔案路徑 -> 選中的資料列索引
 	recentFiles  []RecentFileRecord         // T151: 最近開啟的檔案列表
+	views        map[string]*entryView      // 檔案路徑 -> 最近一次查詢的記錄索引
+	indexes      map[string]*index.Index    // 檔案路徑 -> 反向索引（選用）
+}
+
+// entryView 依篩選與排序條件計算出的記錄索引快取
+// 翻頁時重複使用，避免每頁都重新篩選與排序
+type entryView struct {
+	key     string // 篩選與排序條件的快取鍵
+	indexes []int  // 符合條件且已排序的記錄索引
 }
 
 // RecentFileRecord 最近開啟的檔案記錄（內部使用）
@@ -37,6 +48,8 @@ func NewState() *State {
 		tabs:         make([]string, 0),
 		selectedRows: make(map[string][]int),
 		recentFiles:  make([]RecentFileRecord, 0),
+		views:        make(map[string]*entryView),
+		indexes:      make(map[string]*index.Index),
 	}
 	// 載入最近檔案列表
 	s.loadRecentFiles()
@@ -53,6 +66,8 @@ func (s *State) AddFile(path string, logFile *models.LogFile) {
 		s.tabs = append(s.tabs, path)
 	}
 	s.openFiles[path] = logFile
+	delete(s.views, path)
+	delete(s.indexes, path)
 	s.activeTab = path
 }
 
@@ -64,6 +79,8 @@ func (s *State) RemoveFile(path string) {
 
 	delete(s.openFiles, path)
 	delete(s.selectedRows, path)
+	delete(s.views, path)
+	delete(s.indexes, path)
 
 	// 從頁籤列表中移除
 	for i, tab := range s.tabs {
@@ -179,6 +196,8 @@ func (s *State) Cleanup() {
 	s.openFiles = make(map[string]*models.LogFile)
 	s.tabs = make([]string, 0)
 	s.selectedRows = make(map[string][]int)
+	s.views = make(map[string]*entryView)
+	s.indexes = make(map[string]*index.Index)
 	s.activeTab = ""
 }
 
@@ -191,6 +210,128 @@ func (s *State) FileCount() int {
 	return len(s.openFiles)
 }
 
+// entryMatcher 記錄查詢條件
+// filter.FilterCriteria 與 query.Query 皆實作此介面
+type entryMatcher interface {
+	Match(entry *models.LogEntry) bool
+}
+
+// QueryEntries 依查詢條件與排序取得指定檔案的一頁記錄
+// key 用於識別查詢條件，相同 key 與排序的翻頁請求會重複使用快取的索引。
+// 返回當頁記錄、符合條件的總數，以及檔案是否已載入
+func (s *State) QueryEntries(path string, matcher entryMatcher, key string, sortSpec *filter.SortSpec, offset, limit int) ([]models.LogEntry, int, bool) {
+	s.mu.RLock()
+	logFile, exists := s.openFiles[path]
+	view := s.views[path]
+	logIndex := s.indexes[path]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, 0, false
+	}
+
+	key = viewKey(key, sortSpec)
+	if view == nil || view.key != key {
+		indexes := matchEntries(logFile.Entries, matcher, logIndex)
+		sortSpec.SortIndexes(logFile.Entries, indexes)
+		view = &entryView{key: key, indexes: indexes}
+
+		s.mu.Lock()
+		// 檔案可能在計算期間被關閉或重新載入
+		if current, ok := s.openFiles[path]; ok && current == logFile {
+			s.views[path] = view
+		}
+		s.mu.Unlock()
+	}
+
+	matched := len(view.indexes)
+	if offset >= matched {
+		return []models.LogEntry{}, matched, true
+	}
+	end := offset + limit
+	if end > matched {
+		end = matched
+	}
+
+	page := make([]models.LogEntry, 0, end-offset)
+	for _, idx := range view.indexes[offset:end] {
+		page = append(page, logFile.Entries[idx])
+	}
+	return page, matched, true
+}
+
+// matchEntries 找出符合條件的記錄索引
+// 有反向索引且條件包含搜尋欄位時只驗證候選記錄，否則線性掃描
+func matchEntries(entries []models.LogEntry, matcher entryMatcher, logIndex *index.Index) []int {
+	indexes := make([]int, 0)
+
+	if criteria, ok := matcher.(*filter.FilterCriteria); ok && logIndex != nil {
+		if candidates, ok := logIndex.Candidates(criteria); ok {
+			for _, pos := range candidates {
+				if criteria.Match(&entries[pos]) {
+					indexes = append(indexes, int(pos))
+				}
+			}
+			return indexes
+		}
+	}
+
+	for i := range entries {
+		if matcher.Match(&entries[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// SetIndex 設定指定檔案的反向索引
+// 只有當檔案已開啟時才會設定
+func (s *State) SetIndex(path string, logIndex *index.Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.openFiles[path]; !exists {
+		return
+	}
+	s.indexes[path] = logIndex
+}
+
+// GetIndex 取得指定檔案的反向索引
+func (s *State) GetIndex(path string) (*index.Index, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logIndex, exists := s.indexes[path]
+	return logIndex, exists
+}
+
+// InvalidateView 清除指定檔案的查詢索引快取
+// 用於查詢結果會隨時間改變（例如相對時間）時強制重新計算
+func (s *State) InvalidateView(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.views, path)
+}
+
+// criteriaKey 產生篩選條件的快取鍵
+func criteriaKey(criteria *filter.FilterCriteria) string {
+	data, _ := json.Marshal(criteria)
+	return "filter:" + string(data)
+}
+
+// viewKey 產生查詢條件與排序的快取鍵
+func viewKey(key string, sortSpec *filter.SortSpec) string {
+	if sortSpec.IsEmpty() {
+		return key
+	}
+	order := "asc"
+	if sortSpec.Descending {
+		order = "desc"
+	}
+	return key + "|sort:" + sortSpec.Field + ":" + order
+}
+
 // T151: 最近檔案列表管理
 
 // getRecentFilesPath 取得最近檔案列表的儲存路徑
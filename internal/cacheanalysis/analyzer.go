@@ -0,0 +1,461 @@
+// Package cacheanalysis 只從 access log 評估快取與條件式請求的成效
+// 統計各路徑與各內容類型的 304 比例、以 304 節省的流量（依路徑 200 回應的平均大小估算），
+// 偵測同一用戶端在短時間內重複完整下載同一個靜態資源，log 格式記錄快取狀態時另外計算命中率，
+// 最後列出快取標頭需要檢查的路徑
+package cacheanalysis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/parser"
+	"access-log-analyzer/pkg/logger"
+)
+
+// 建議的原因
+const (
+	ReasonRepeatDownloads      = "repeat-downloads"      // 同一用戶端短時間內重複完整下載
+	ReasonFrequentRevalidation = "frequent-revalidation" // 大多數請求都是條件式請求
+	ReasonLowHitRatio          = "low-hit-ratio"         // 快取命中率偏低
+)
+
+// 內容類型
+const (
+	TypePage       = "page"       // 頁面（沒有副檔名或 .html、.php 等）
+	TypeStylesheet = "stylesheet" // CSS
+	TypeScript     = "script"     // JavaScript
+	TypeImage      = "image"      // 圖片
+	TypeFont       = "font"       // 字型
+	TypeMedia      = "media"      // 影音
+	TypeDocument   = "document"   // 文件與壓縮檔
+	TypeData       = "data"       // JSON、XML 等資料
+	TypeOther      = "other"      // 其他
+)
+
+const (
+	repeatRateThreshold       = 20.0 // 重複下載占完整下載的比例（百分比）達此值時建議調整
+	revalidationRateThreshold = 50.0 // 靜態資源的 304 比例（百分比）達此值時建議調整
+	hitRatioThreshold         = 50.0 // 快取命中率（百分比）低於此值時建議調整
+	maxRecommendations        = 50   // 建議數量上限
+)
+
+// contentTypes 副檔名對應的內容類型
+var contentTypes = map[string]string{
+	"": TypePage, ".html": TypePage, ".htm": TypePage, ".php": TypePage, ".asp": TypePage, ".aspx": TypePage, ".jsp": TypePage,
+	".css": TypeStylesheet,
+	".js":  TypeScript, ".mjs": TypeScript, ".map": TypeScript,
+	".png": TypeImage, ".jpg": TypeImage, ".jpeg": TypeImage, ".gif": TypeImage, ".svg": TypeImage, ".webp": TypeImage, ".avif": TypeImage, ".ico": TypeImage, ".bmp": TypeImage,
+	".woff": TypeFont, ".woff2": TypeFont, ".ttf": TypeFont, ".otf": TypeFont, ".eot": TypeFont,
+	".mp4": TypeMedia, ".webm": TypeMedia, ".mp3": TypeMedia, ".ogg": TypeMedia, ".wav": TypeMedia, ".m4a": TypeMedia,
+	".pdf": TypeDocument, ".zip": TypeDocument, ".gz": TypeDocument, ".doc": TypeDocument, ".docx": TypeDocument, ".xls": TypeDocument, ".xlsx": TypeDocument,
+	".json": TypeData, ".xml": TypeData, ".txt": TypeData, ".csv": TypeData, ".rss": TypeData,
+}
+
+// staticTypes 視為靜態資源的內容類型
+var staticTypes = map[string]bool{
+	TypeStylesheet: true, TypeScript: true, TypeImage: true, TypeFont: true, TypeMedia: true, TypeDocument: true,
+}
+
+// ContentType 依副檔名判斷路徑的內容類型
+func ContentType(urlPath string) string {
+	if t, ok := contentTypes[strings.ToLower(path.Ext(urlPath))]; ok {
+		return t
+	}
+	return TypeOther
+}
+
+// Options 快取分析的參數，零值欄位使用預設值
+type Options struct {
+	RepeatWindow string `json:"repeatWindow"` // 同一用戶端再次完整下載視為重複的時間範圍，例如 "5m"（預設 5m）
+	MinRequests  int    `json:"minRequests"`  // 提出建議所需的最低請求數（預設 10）
+	TopN         int    `json:"topN"`         // 各排名保留的路徑數（預設 20）
+}
+
+// DefaultOptions 返回預設的分析參數
+func DefaultOptions() Options {
+	return Options{
+		RepeatWindow: "5m",
+		MinRequests:  10,
+		TopN:         20,
+	}
+}
+
+// withDefaults 以預設值補上零值欄位
+func (o Options) withDefaults() Options {
+	d := DefaultOptions()
+	aggregate.Default(&o.RepeatWindow, d.RepeatWindow)
+	aggregate.Default(&o.MinRequests, d.MinRequests)
+	aggregate.Default(&o.TopN, d.TopN)
+	return o
+}
+
+// validate 驗證參數，返回重複下載的時間範圍
+func (o Options) validate() (time.Duration, error) {
+	window, err := aggregate.ParseWindow("RepeatWindow", o.RepeatWindow, "無效的重複下載時間範圍")
+	if err != nil {
+		return 0, err
+	}
+	err = aggregate.FirstError(
+		aggregate.NonNegative("MinRequests", o.MinRequests, "最低請求數不可為負數"),
+		aggregate.NonNegative("TopN", o.TopN, "TopN 不可為負數"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return window, nil
+}
+
+// Report 快取分析結果
+// 只有 GET 的 200 回應與 GET/HEAD 的 304 回應列入 304 比例與重複下載的計算
+type Report struct {
+	Options           Options           `json:"options"`           // 實際使用的參數（已補上預設值）
+	TotalRequests     int               `json:"totalRequests"`     // 全部請求數
+	CacheableRequests int               `json:"cacheableRequests"` // GET 200 與 GET/HEAD 304 的請求數
+	FullResponses     int               `json:"fullResponses"`     // GET 200 回應數
+	NotModified       int               `json:"notModified"`       // 304 回應數
+	NotModifiedRate   float64           `json:"notModifiedRate"`   // 304 比例（百分比）
+	BytesTransferred  int64             `json:"bytesTransferred"`  // 200 回應的傳輸量（位元組）
+	BytesSaved        int64             `json:"bytesSaved"`        // 以 304 節省的估計傳輸量（位元組）
+	RepeatFetches     int               `json:"repeatFetches"`     // 靜態資源的重複完整下載次數
+	Paths             []PathCache       `json:"paths"`             // 依請求數排名的路徑
+	RepeatedAssets    []PathCache       `json:"repeatedAssets"`    // 依重複下載次數排名的靜態資源
+	ContentTypes      []TypeCache       `json:"contentTypes"`      // 各內容類型（依請求數排序）
+	CacheStatus       *CacheStatusStats `json:"cacheStatus"`       // 快取狀態統計（log 格式沒有記錄快取狀態時為 nil）
+	Recommendations   []Recommendation  `json:"recommendations"`   // 快取標頭需要檢查的路徑（依請求數排序）
+}
+
+// PathCache 單一路徑的快取成效
+type PathCache struct {
+	Path            string  `json:"path"`            // 路徑（不含查詢字串）
+	ContentType     string  `json:"contentType"`     // 內容類型
+	Requests        int     `json:"requests"`        // 200 與 304 回應數
+	FullResponses   int     `json:"fullResponses"`   // 200 回應數
+	NotModified     int     `json:"notModified"`     // 304 回應數
+	NotModifiedRate float64 `json:"notModifiedRate"` // 304 比例（百分比）
+	AverageSize     int64   `json:"averageSize"`     // 200 回應的平均大小（位元組）
+	BytesSaved      int64   `json:"bytesSaved"`      // 以 304 節省的估計傳輸量（304 數 × 平均大小）
+	RepeatFetches   int     `json:"repeatFetches"`   // 同一用戶端在 RepeatWindow 內重複完整下載的次數（只計算靜態資源）
+	RepeatRate      float64 `json:"repeatRate"`      // 重複下載占 200 回應的比例（百分比）
+	CacheHits       int     `json:"cacheHits"`       // 快取狀態為命中的請求數
+	CacheMisses     int     `json:"cacheMisses"`     // 快取狀態為未命中的請求數
+	HitRatio        float64 `json:"hitRatio"`        // 快取命中率（百分比，沒有快取狀態時為 0）
+}
+
+// TypeCache 單一內容類型的快取成效
+type TypeCache struct {
+	ContentType      string  `json:"contentType"`      // 內容類型
+	Requests         int     `json:"requests"`         // 200 與 304 回應數
+	FullResponses    int     `json:"fullResponses"`    // 200 回應數
+	NotModified      int     `json:"notModified"`      // 304 回應數
+	NotModifiedRate  float64 `json:"notModifiedRate"`  // 304 比例（百分比）
+	BytesTransferred int64   `json:"bytesTransferred"` // 200 回應的傳輸量（位元組）
+	BytesSaved       int64   `json:"bytesSaved"`       // 以 304 節省的估計傳輸量（位元組）
+	RepeatFetches    int     `json:"repeatFetches"`    // 重複完整下載次數
+}
+
+// CacheStatusStats log 記錄的快取狀態統計
+// HIT、STALE、UPDATING、REVALIDATED 視為命中，MISS、EXPIRED 視為未命中，BYPASS 不列入命中率
+type CacheStatusStats struct {
+	Requests int           `json:"requests"` // 有快取狀態的請求數
+	Hits     int           `json:"hits"`     // 命中數
+	Misses   int           `json:"misses"`   // 未命中數
+	HitRatio float64       `json:"hitRatio"` // 命中率（百分比）
+	Statuses []StatusCount `json:"statuses"` // 各快取狀態的次數（依次數降序）
+}
+
+// StatusCount 快取狀態與次數
+type StatusCount struct {
+	Status string `json:"status"` // 快取狀態
+	Count  int    `json:"count"`  // 次數
+}
+
+// Recommendation 需要檢查快取標頭的路徑
+type Recommendation struct {
+	Path        string `json:"path"`        // 路徑（不含查詢字串）
+	ContentType string `json:"contentType"` // 內容類型
+	Reason      string `json:"reason"`      // 原因（repeat-downloads、frequent-revalidation、low-hit-ratio）
+	Message     string `json:"message"`     // 說明與建議
+	Requests    int    `json:"requests"`    // 路徑的 200 與 304 回應數
+}
+
+// cacheHit 判斷快取狀態是否視為命中，第二個返回值表示是否列入命中率
+func cacheHit(status string) (hit bool, counted bool) {
+	switch status {
+	case parser.CacheHit, parser.CacheStale, parser.CacheUpdating, parser.CacheRevalidated:
+		return true, true
+	case parser.CacheMiss, parser.CacheExpired:
+		return false, true
+	}
+	return false, false
+}
+
+// pathAccumulator 累積單一路徑的快取成效
+type pathAccumulator struct {
+	contentType string
+	full        int
+	notModified int
+	fullBytes   int64
+	repeats     int
+	hits        int
+	misses      int
+}
+
+// averageSize 返回 200 回應的平均大小
+func (acc *pathAccumulator) averageSize() int64 {
+	if acc.full == 0 {
+		return 0
+	}
+	return acc.fullBytes / int64(acc.full)
+}
+
+// result 轉換為 PathCache
+func (acc *pathAccumulator) result(urlPath string) PathCache {
+	p := PathCache{
+		Path:          urlPath,
+		ContentType:   acc.contentType,
+		Requests:      acc.full + acc.notModified,
+		FullResponses: acc.full,
+		NotModified:   acc.notModified,
+		AverageSize:   acc.averageSize(),
+		RepeatFetches: acc.repeats,
+		CacheHits:     acc.hits,
+		CacheMisses:   acc.misses,
+	}
+	p.BytesSaved = int64(acc.notModified) * p.AverageSize
+	p.NotModifiedRate = aggregate.Percentage(acc.notModified, p.Requests)
+	p.RepeatRate = aggregate.Percentage(acc.repeats, acc.full)
+	p.HitRatio = aggregate.Percentage(acc.hits, acc.hits+acc.misses)
+	return p
+}
+
+// Analyzer 快取分析器
+type Analyzer struct {
+	log *logger.Logger
+}
+
+// NewAnalyzer 建立快取分析器
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{log: logger.Get().WithModule("cacheanalysis")}
+}
+
+// Analyze 分析日誌記錄的快取成效
+// 依時間順序遍歷一次：同一用戶端（IP 與 User-Agent）在 RepeatWindow 內再次以 200 取得同一個靜態資源 URL 時視為重複下載
+// 解析失敗或沒有時間戳記的記錄不列入
+func (a *Analyzer) Analyze(entries []models.LogEntry, opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	window, err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Options:         opts,
+		Paths:           make([]PathCache, 0),
+		RepeatedAssets:  make([]PathCache, 0),
+		ContentTypes:    make([]TypeCache, 0),
+		Recommendations: make([]Recommendation, 0),
+	}
+
+	paths := make(map[string]*pathAccumulator)
+	statuses := make(map[string]int)
+	lastFull := make(map[string]time.Time)
+
+	parsed := func(entry *models.LogEntry) bool { return entry.ParseError == "" }
+	for _, i := range aggregate.Chronological(entries, parsed) {
+		entry := &entries[i]
+		report.TotalRequests++
+
+		if entry.CacheStatus != "" {
+			statuses[entry.CacheStatus]++
+		}
+		switch {
+		case entry.Method == "GET" && entry.StatusCode == 200:
+		case (entry.Method == "GET" || entry.Method == "HEAD") && entry.StatusCode == 304:
+		default:
+			// HEAD 的 200 回應沒有內容，不列入平均大小與重複下載
+			continue
+		}
+		report.CacheableRequests++
+
+		urlPath := aggregate.StripQuery(entry.URL)
+		acc := paths[urlPath]
+		if acc == nil {
+			acc = &pathAccumulator{contentType: ContentType(urlPath)}
+			paths[urlPath] = acc
+		}
+		if hit, counted := cacheHit(entry.CacheStatus); counted {
+			if hit {
+				acc.hits++
+			} else {
+				acc.misses++
+			}
+		}
+
+		if entry.StatusCode == 304 {
+			acc.notModified++
+			continue
+		}
+		acc.full++
+		acc.fullBytes += entry.ResponseBytes
+
+		// 重複下載：以完整 URL 比對，檔名或查詢字串帶版本號的資源視為不同資源
+		if staticTypes[acc.contentType] && !entry.Timestamp.IsZero() {
+			key := entry.IP + "\x00" + entry.UserAgent + "\x00" + entry.URL
+			if previous, ok := lastFull[key]; ok && entry.Timestamp.Sub(previous) <= window {
+				acc.repeats++
+			}
+			lastFull[key] = entry.Timestamp
+		}
+	}
+
+	results := make([]PathCache, 0, len(paths))
+	types := make(map[string]*TypeCache)
+	for urlPath, acc := range paths {
+		p := acc.result(urlPath)
+		results = append(results, p)
+
+		report.FullResponses += p.FullResponses
+		report.NotModified += p.NotModified
+		report.BytesTransferred += acc.fullBytes
+		report.BytesSaved += p.BytesSaved
+		report.RepeatFetches += p.RepeatFetches
+
+		t := types[p.ContentType]
+		if t == nil {
+			t = &TypeCache{ContentType: p.ContentType}
+			types[p.ContentType] = t
+		}
+		t.Requests += p.Requests
+		t.FullResponses += p.FullResponses
+		t.NotModified += p.NotModified
+		t.BytesTransferred += acc.fullBytes
+		t.BytesSaved += p.BytesSaved
+		t.RepeatFetches += p.RepeatFetches
+	}
+	report.NotModifiedRate = aggregate.Percentage(report.NotModified, report.CacheableRequests)
+
+	for _, t := range types {
+		t.NotModifiedRate = aggregate.Percentage(t.NotModified, t.Requests)
+		report.ContentTypes = append(report.ContentTypes, *t)
+	}
+	sort.Slice(report.ContentTypes, func(i, j int) bool {
+		if report.ContentTypes[i].Requests != report.ContentTypes[j].Requests {
+			return report.ContentTypes[i].Requests > report.ContentTypes[j].Requests
+		}
+		return report.ContentTypes[i].ContentType < report.ContentTypes[j].ContentType
+	})
+
+	report.Paths = rank(results, func(p PathCache) int { return p.Requests }, opts.TopN)
+	report.RepeatedAssets = rank(results, func(p PathCache) int { return p.RepeatFetches }, opts.TopN)
+	report.CacheStatus = cacheStatusStats(statuses)
+	report.Recommendations = recommend(results, opts)
+
+	a.log.Info().
+		Int("requests", report.TotalRequests).
+		Int("notModified", report.NotModified).
+		Int("repeatFetches", report.RepeatFetches).
+		Int("recommendations", len(report.Recommendations)).
+		Msg("快取分析完成")
+	return report, nil
+}
+
+// rank 依 value 降序排名（只保留 value 大於 0 的路徑），保留前 n 筆
+func rank(paths []PathCache, value func(PathCache) int, n int) []PathCache {
+	ranked := make([]PathCache, 0)
+	for _, p := range paths {
+		if value(p) > 0 {
+			ranked = append(ranked, p)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if vi, vj := value(ranked[i]), value(ranked[j]); vi != vj {
+			return vi > vj
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// cacheStatusStats 建立快取狀態統計，沒有任何快取狀態時返回 nil
+func cacheStatusStats(statuses map[string]int) *CacheStatusStats {
+	if len(statuses) == 0 {
+		return nil
+	}
+	result := &CacheStatusStats{Statuses: make([]StatusCount, 0, len(statuses))}
+	for status, count := range statuses {
+		result.Requests += count
+		if hit, counted := cacheHit(status); counted {
+			if hit {
+				result.Hits += count
+			} else {
+				result.Misses += count
+			}
+		}
+		result.Statuses = append(result.Statuses, StatusCount{Status: status, Count: count})
+	}
+	result.HitRatio = aggregate.Percentage(result.Hits, result.Hits+result.Misses)
+	sort.Slice(result.Statuses, func(i, j int) bool {
+		if result.Statuses[i].Count != result.Statuses[j].Count {
+			return result.Statuses[i].Count > result.Statuses[j].Count
+		}
+		return result.Statuses[i].Status < result.Statuses[j].Status
+	})
+	return result
+}
+
+// recommend 列出快取標頭需要檢查的路徑（請求數需達 MinRequests）
+func recommend(paths []PathCache, opts Options) []Recommendation {
+	result := make([]Recommendation, 0)
+	add := func(p PathCache, reason, message string) {
+		result = append(result, Recommendation{
+			Path:        p.Path,
+			ContentType: p.ContentType,
+			Reason:      reason,
+			Message:     message,
+			Requests:    p.Requests,
+		})
+	}
+
+	for _, p := range paths {
+		if p.Requests < opts.MinRequests {
+			continue
+		}
+		if p.RepeatFetches > 0 && p.RepeatRate >= repeatRateThreshold {
+			message := fmt.Sprintf("%.0f%% 的完整下載是同一用戶端在 %s 內重複取得，建議設定較長的 Cache-Control max-age", p.RepeatRate, opts.RepeatWindow)
+			if p.NotModified == 0 {
+				message += "；此路徑從未回應 304，也請確認是否提供 ETag 或 Last-Modified"
+			}
+			add(p, ReasonRepeatDownloads, message)
+		}
+		if staticTypes[p.ContentType] && p.NotModifiedRate >= revalidationRateThreshold {
+			add(p, ReasonFrequentRevalidation, fmt.Sprintf(
+				"%.0f%% 的請求是條件式請求（304），快取過期時間可能太短；檔名帶版本號時可改用較長的 max-age 與 immutable", p.NotModifiedRate))
+		}
+		if p.CacheHits+p.CacheMisses >= opts.MinRequests && p.HitRatio < hitRatioThreshold {
+			add(p, ReasonLowHitRatio, fmt.Sprintf(
+				"快取命中率只有 %.0f%%，請檢查 Cache-Control、Vary 或 Set-Cookie 是否讓回應無法被快取", p.HitRatio))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	if len(result) > maxRecommendations {
+		result = result[:maxRecommendations]
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package cacheanalysis
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBase = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// request 建立指定時間（相對 testBase 的秒數）的 GET 記錄
+func request(ip string, seconds int, url string, status int, bytes int64) models.LogEntry {
+	return models.LogEntry{
+		IP:            ip,
+		Timestamp:     testBase.Add(time.Duration(seconds) * time.Second),
+		Method:        "GET",
+		URL:           url,
+		StatusCode:    status,
+		ResponseBytes: bytes,
+		UserAgent:     "Mozilla/5.0",
+	}
+}
+
+// testEntries 建立測試資料
+// /logo.png 多數為 304；/app.js 被同一用戶端每分鐘重複下載；首頁的快取命中率偏低
+func testEntries() []models.LogEntry {
+	var entries []models.LogEntry
+	entries = append(entries,
+		request("10.0.0.1", 0, "/logo.png", 200, 2000),
+		request("10.0.0.2", 0, "/logo.png", 200, 2000),
+	)
+	for i := 0; i < 8; i++ {
+		entries = append(entries, request("10.0.0.3", i*10, "/logo.png", 304, 0))
+	}
+	for i := 0; i < 10; i++ {
+		entries = append(entries, request("10.0.0.1", i*60, "/app.js?v=1", 200, 1000))
+	}
+	// 不同版本的 URL 不算重複下載；超過時間範圍後再次下載也不算
+	entries = append(entries,
+		request("10.0.0.1", 30, "/app.js?v=2", 200, 1000),
+		request("10.0.0.1", 2000, "/app.js?v=2", 200, 1000),
+	)
+	for i := 0; i < 12; i++ {
+		entry := request("10.0.0.4", i, "/", 200, 500)
+		entry.CacheStatus = "MISS"
+		if i < 3 {
+			entry.CacheStatus = "HIT"
+		}
+		entries = append(entries, entry)
+	}
+	login := request("10.0.0.4", 5, "/login", 200, 100)
+	login.Method = "POST"
+	login.CacheStatus = "BYPASS"
+	entries = append(entries, login)
+	return entries
+}
+
+// TestAnalyze_304與節省流量 測試 304 比例、節省流量與各內容類型統計
+func TestAnalyze_304與節省流量(t *testing.T) {
+	report, err := NewAnalyzer().Analyze(testEntries(), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultOptions(), report.Options)
+	assert.Equal(t, 35, report.TotalRequests)
+	assert.Equal(t, 34, report.CacheableRequests, "POST 不列入")
+	assert.Equal(t, 8, report.NotModified)
+	assert.Equal(t, 26, report.FullResponses)
+	assert.Equal(t, int64(16000), report.BytesSaved)
+
+	require.Len(t, report.Paths, 3)
+	paths := make(map[string]PathCache)
+	for _, p := range report.Paths {
+		paths[p.Path] = p
+	}
+	assert.Equal(t, 12, paths["/app.js"].Requests, "查詢字串不影響路徑統計")
+
+	logo := paths["/logo.png"]
+	assert.Equal(t, TypeImage, logo.ContentType)
+	assert.InDelta(t, 80, logo.NotModifiedRate, 0.001)
+	assert.Equal(t, int64(2000), logo.AverageSize)
+	assert.Equal(t, int64(16000), logo.BytesSaved)
+	assert.Zero(t, logo.RepeatFetches)
+
+	types := make(map[string]TypeCache)
+	for _, tc := range report.ContentTypes {
+		types[tc.ContentType] = tc
+	}
+	assert.Equal(t, 12, types[TypeScript].Requests)
+	assert.Equal(t, 12, types[TypePage].Requests)
+	assert.Equal(t, 8, types[TypeImage].NotModified)
+	assert.Equal(t, int64(16000), types[TypeImage].BytesSaved)
+}
+
+// TestAnalyze_重複下載與快取狀態 測試重複下載偵測、快取命中率與建議
+func TestAnalyze_重複下載與快取狀態(t *testing.T) {
+	report, err := NewAnalyzer().Analyze(testEntries(), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, report.RepeatFetches)
+	require.Len(t, report.RepeatedAssets, 1)
+	assert.Equal(t, "/app.js", report.RepeatedAssets[0].Path)
+	assert.Equal(t, 9, report.RepeatedAssets[0].RepeatFetches)
+	assert.InDelta(t, 75, report.RepeatedAssets[0].RepeatRate, 0.001)
+
+	require.NotNil(t, report.CacheStatus)
+	assert.Equal(t, 13, report.CacheStatus.Requests)
+	assert.Equal(t, 3, report.CacheStatus.Hits)
+	assert.Equal(t, 9, report.CacheStatus.Misses)
+	assert.InDelta(t, 25, report.CacheStatus.HitRatio, 0.001)
+	assert.Equal(t, StatusCount{Status: "MISS", Count: 9}, report.CacheStatus.Statuses[0])
+
+	reasons := make(map[string]string)
+	for _, r := range report.Recommendations {
+		reasons[r.Path] = r.Reason
+		if r.Reason == ReasonRepeatDownloads {
+			assert.Contains(t, r.Message, "ETag", "從未回應 304 時提醒檢查驗證標頭")
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"/app.js":   ReasonRepeatDownloads,
+		"/logo.png": ReasonFrequentRevalidation,
+		"/":         ReasonLowHitRatio,
+	}, reasons)
+
+	// 時間範圍縮短後每分鐘一次的下載不再視為重複
+	report, err = NewAnalyzer().Analyze(testEntries(), Options{RepeatWindow: "30s"})
+	require.NoError(t, err)
+	assert.Zero(t, report.RepeatFetches)
+
+	// 沒有快取狀態欄位時不產生快取狀態統計
+	report, err = NewAnalyzer().Analyze(testEntries()[:10], Options{})
+	require.NoError(t, err)
+	assert.Nil(t, report.CacheStatus)
+}
+
+// TestAnalyze_HEAD與解析錯誤 測試 HEAD 的 200 回應與解析失敗的記錄不影響平均大小與重複下載
+func TestAnalyze_HEAD與解析錯誤(t *testing.T) {
+	head := request("10.0.0.1", 10, "/logo.png", 200, 0)
+	head.Method = "HEAD"
+	headNotModified := request("10.0.0.2", 20, "/logo.png", 304, 0)
+	headNotModified.Method = "HEAD"
+	broken := request("10.0.0.1", 30, "/logo.png", 200, 0)
+	broken.ParseError = "無法匹配 log 格式"
+	entries := []models.LogEntry{
+		request("10.0.0.1", 0, "/logo.png", 200, 2000),
+		head,
+		headNotModified,
+		broken,
+	}
+
+	report, err := NewAnalyzer().Analyze(entries, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.TotalRequests, "解析失敗的記錄不列入")
+	assert.Equal(t, 2, report.CacheableRequests)
+	require.Len(t, report.Paths, 1)
+	assert.Equal(t, 1, report.Paths[0].FullResponses)
+	assert.Equal(t, 1, report.Paths[0].NotModified, "HEAD 的 304 仍列入")
+	assert.Equal(t, int64(2000), report.Paths[0].AverageSize)
+	assert.Equal(t, int64(2000), report.BytesSaved)
+	assert.Zero(t, report.RepeatFetches)
+}
+
+// TestAnalyze_參數驗證 測試無效參數
+func TestAnalyze_參數驗證(t *testing.T) {
+	testCases := []Options{
+		{RepeatWindow: "abc"},
+		{RepeatWindow: "-1m"},
+		{MinRequests: -1},
+		{TopN: -1},
+	}
+	for _, opts := range testCases {
+		_, err := NewAnalyzer().Analyze(testEntries(), opts)
+		var validationErr *models.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", opts)
+	}
+}
+
+// TestContentType 測試依副檔名判斷內容類型
+func TestContentType(t *testing.T) {
+	assert.Equal(t, TypePage, ContentType("/"))
+	assert.Equal(t, TypePage, ContentType("/index.HTML"))
+	assert.Equal(t, TypeStylesheet, ContentType("/css/site.css"))
+	assert.Equal(t, TypeFont, ContentType("/fonts/a.woff2"))
+	assert.Equal(t, TypeData, ContentType("/api/feed.json"))
+	assert.Equal(t, TypeOther, ContentType("/download.exe"))
+}
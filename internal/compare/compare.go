@@ -0,0 +1,369 @@
+// Package compare 比較兩份統計結果（兩個檔案或同一檔案的兩個時間範圍）
+// 產生總覽指標、狀態碼比例、新增與消失的路徑、處理時間退步，以及新進入排名的 IP 與機器人，
+// 每項變化都附上絕對差、相對變化與是否顯著
+package compare
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+)
+
+// SnapshotTopN 建立比較用統計時建議的 Top-N，讓路徑與 IP 排名盡量涵蓋全部資料
+const SnapshotTopN = 1000
+
+// Options 比較的參數，零值欄位使用預設值
+type Options struct {
+	MinRelativeChange float64 `json:"minRelativeChange"` // 次數與處理時間的顯著變化門檻（百分比，預設 20）
+	MinRateChange     float64 `json:"minRateChange"`     // 比率的顯著變化門檻（百分點，預設 2）
+	MinCount          int     `json:"minCount"`          // 判定顯著所需的最低請求數（預設 10）
+	TopN              int     `json:"topN"`              // 各清單保留的筆數（預設 20）
+}
+
+// DefaultOptions 返回預設的比較參數
+func DefaultOptions() Options {
+	return Options{
+		MinRelativeChange: 20,
+		MinRateChange:     2,
+		MinCount:          10,
+		TopN:              20,
+	}
+}
+
+// withDefaults 以預設值補上零值欄位
+func (o Options) withDefaults() Options {
+	d := DefaultOptions()
+	aggregate.Default(&o.MinRelativeChange, d.MinRelativeChange)
+	aggregate.Default(&o.MinRateChange, d.MinRateChange)
+	aggregate.Default(&o.MinCount, d.MinCount)
+	aggregate.Default(&o.TopN, d.TopN)
+	return o
+}
+
+// validate 驗證參數
+func (o Options) validate() error {
+	return aggregate.FirstError(
+		aggregate.NonNegative("MinRelativeChange", o.MinRelativeChange, "顯著變化門檻不可為負數"),
+		aggregate.NonNegative("MinRateChange", o.MinRateChange, "比率變化門檻不可為負數"),
+		aggregate.NonNegative("MinCount", o.MinCount, "最低請求數不可為負數"),
+		aggregate.NonNegative("TopN", o.TopN, "保留筆數不可為負數"),
+	)
+}
+
+// Change 單一指標的變化
+type Change struct {
+	Name           string  `json:"name"`           // 指標名稱、路徑、IP 或機器人名稱
+	Before         float64 `json:"before"`         // 比較基準的值
+	After          float64 `json:"after"`          // 比較對象的值
+	Delta          float64 `json:"delta"`          // 絕對變化（After - Before；比率為百分點）
+	RelativeChange float64 `json:"relativeChange"` // 相對變化（百分比，Before 為 0 時為 0）
+	Significant    bool    `json:"significant"`    // 是否超過顯著門檻
+}
+
+// StatusChange 單一狀態碼的變化
+type StatusChange struct {
+	StatusCode int    `json:"statusCode"` // 狀態碼
+	Count      Change `json:"count"`      // 請求數
+	Share      Change `json:"share"`      // 佔總請求的比例（百分比）
+}
+
+// PathChange 單一路徑的變化
+type PathChange struct {
+	Path        string `json:"path"`        // 請求路徑
+	Requests    Change `json:"requests"`    // 請求數
+	ErrorRate   Change `json:"errorRate"`   // 錯誤率（百分比）
+	RequestTime Change `json:"requestTime"` // 平均處理時間（微秒）
+}
+
+// Report 比較結果
+type Report struct {
+	Options            Options        `json:"options"`            // 實際使用的參數（已補上預設值）
+	BeforeLabel        string         `json:"beforeLabel"`        // 比較基準的名稱（檔案或時間範圍）
+	AfterLabel         string         `json:"afterLabel"`         // 比較對象的名稱
+	Summary            []Change       `json:"summary"`            // 總覽指標
+	StatusCodes        []StatusChange `json:"statusCodes"`        // 各狀態碼（依狀態碼排序）
+	NewPaths           []PathChange   `json:"newPaths"`           // 只出現在比較對象的路徑（依請求數降序）
+	VanishedPaths      []PathChange   `json:"vanishedPaths"`      // 只出現在比較基準的路徑（依請求數降序）
+	ChangedPaths       []PathChange   `json:"changedPaths"`       // 請求數或錯誤率顯著變化的路徑（依請求數變化量降序）
+	LatencyRegressions []PathChange   `json:"latencyRegressions"` // 平均處理時間顯著增加的路徑（依增加量降序）
+	NewTopIPs          []Change       `json:"newTopIPs"`          // 新進入 IP 排名的來源（依請求數降序）
+	NewBots            []Change       `json:"newBots"`            // 只出現在比較對象的機器人（依請求數降序）
+	Incomplete         bool           `json:"incomplete"`         // 路徑排名未涵蓋全部路徑，顯著變化與處理時間退步的判定只限於排名內
+}
+
+// Snapshot 比較的一側：統計結果與全部路徑的請求數
+// PathCounts 不受 Top-N 限制，用於判定新增與消失的路徑；路徑排名只用於顯示錯誤率與處理時間
+// PathCounts 為 nil 時以路徑排名代替
+type Snapshot struct {
+	Statistics *stats.Statistics
+	PathCounts map[string]int
+}
+
+// CountPaths 計算各路徑的請求數（路徑與統計的路徑排名相同，為完整的 URL）
+func CountPaths(entries []models.LogEntry, match func(*models.LogEntry) bool) map[string]int {
+	counts := make(map[string]int)
+	for i := range entries {
+		if match != nil && !match(&entries[i]) {
+			continue
+		}
+		counts[entries[i].URL]++
+	}
+	return counts
+}
+
+// Compare 比較兩份統計結果，before 為比較基準，after 為比較對象
+func Compare(beforeSnapshot, afterSnapshot Snapshot, opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	before, after := beforeSnapshot.Statistics, afterSnapshot.Statistics
+
+	report := &Report{
+		Options: opts,
+		Summary: compareSummary(before, after, opts),
+		StatusCodes: compareStatusCodes(
+			before.StatusCodeDistribution.Details, before.TotalRequests,
+			after.StatusCodeDistribution.Details, after.TotalRequests, opts),
+		NewTopIPs: newTopIPs(before.TopIPs, after.TopIPs, opts),
+		NewBots:   newBots(before.BotStats.Agents, after.BotStats.Agents, opts),
+		Incomplete: before.UniquePaths > len(before.TopPaths) ||
+			after.UniquePaths > len(after.TopPaths),
+	}
+	comparePaths(report, beforeSnapshot, afterSnapshot, opts)
+	return report, nil
+}
+
+// compareSummary 比較總覽指標
+func compareSummary(before, after *stats.Statistics, opts Options) []Change {
+	volume := max(before.TotalRequests, after.TotalRequests)
+	latencyVolume := min(before.Latency.Samples, after.Latency.Samples)
+
+	return []Change{
+		countChange("總請求數", float64(before.TotalRequests), float64(after.TotalRequests), volume, opts),
+		countChange("不重複 IP", float64(before.UniqueIPs), float64(after.UniqueIPs), volume, opts),
+		countChange("不重複路徑", float64(before.UniquePaths), float64(after.UniquePaths), volume, opts),
+		countChange("總傳輸量（位元組）", float64(before.TotalBytes), float64(after.TotalBytes), volume, opts),
+		rateChange("錯誤率（%）", errorRate(before), errorRate(after), volume, opts),
+		rateChange("伺服器錯誤率（%）",
+			aggregate.Percentage(before.StatusCodeDistribution.ServerError, before.TotalRequests),
+			aggregate.Percentage(after.StatusCodeDistribution.ServerError, after.TotalRequests), volume, opts),
+		rateChange("機器人比例（%）",
+			aggregate.Percentage(before.BotStats.BotRequests, before.TotalRequests),
+			aggregate.Percentage(after.BotStats.BotRequests, after.TotalRequests), volume, opts),
+		countChange("攻擊請求數", float64(before.Threats.AttackRequests), float64(after.Threats.AttackRequests),
+			max(before.Threats.AttackRequests, after.Threats.AttackRequests), opts),
+		latencyChange("平均處理時間（微秒）", float64(before.Latency.Average), float64(after.Latency.Average), latencyVolume, opts),
+		latencyChange("P95 處理時間（微秒）", float64(before.Latency.P95), float64(after.Latency.P95), latencyVolume, opts),
+	}
+}
+
+// compareStatusCodes 比較各狀態碼的請求數與佔比
+func compareStatusCodes(before map[int]int, beforeTotal int, after map[int]int, afterTotal int, opts Options) []StatusChange {
+	codes := make(map[int]bool, len(before)+len(after))
+	for code := range before {
+		codes[code] = true
+	}
+	for code := range after {
+		codes[code] = true
+	}
+
+	changes := make([]StatusChange, 0, len(codes))
+	for code := range codes {
+		name := strconv.Itoa(code)
+		volume := max(before[code], after[code])
+		changes = append(changes, StatusChange{
+			StatusCode: code,
+			Count:      countChange(name, float64(before[code]), float64(after[code]), volume, opts),
+			Share: rateChange(name, aggregate.Percentage(before[code], beforeTotal),
+				aggregate.Percentage(after[code], afterTotal), volume, opts),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].StatusCode < changes[j].StatusCode })
+	return changes
+}
+
+// comparePaths 以全部路徑的請求數找出新增與消失的路徑，並比較兩側排名內路徑的顯著變化與處理時間退步
+func comparePaths(report *Report, beforeSnapshot, afterSnapshot Snapshot, opts Options) {
+	before, after := beforeSnapshot.Statistics.TopPaths, afterSnapshot.Statistics.TopPaths
+	beforeByPath := rankedPaths(before)
+	afterByPath := rankedPaths(after)
+	beforeCounts := beforeSnapshot.pathCounts()
+	afterCounts := afterSnapshot.pathCounts()
+
+	for path, count := range afterCounts {
+		if beforeCounts[path] == 0 {
+			report.NewPaths = append(report.NewPaths, pathChange(path, stats.PathStatistics{}, rankedOrCount(afterByPath, path, count), opts))
+		}
+	}
+	for path, count := range beforeCounts {
+		if afterCounts[path] == 0 {
+			report.VanishedPaths = append(report.VanishedPaths, pathChange(path, rankedOrCount(beforeByPath, path, count), stats.PathStatistics{}, opts))
+		}
+	}
+
+	for _, a := range after {
+		b, ranked := beforeByPath[a.Path]
+		if !ranked {
+			continue
+		}
+		change := pathChange(a.Path, b, a, opts)
+		if change.Requests.Significant || change.ErrorRate.Significant {
+			report.ChangedPaths = append(report.ChangedPaths, change)
+		}
+		if change.RequestTime.Significant && change.RequestTime.Delta > 0 {
+			report.LatencyRegressions = append(report.LatencyRegressions, change)
+		}
+	}
+
+	sortPaths(report.NewPaths, func(c PathChange) float64 { return c.Requests.After })
+	sortPaths(report.VanishedPaths, func(c PathChange) float64 { return c.Requests.Before })
+	sortPaths(report.ChangedPaths, func(c PathChange) float64 { return math.Abs(c.Requests.Delta) })
+	sortPaths(report.LatencyRegressions, func(c PathChange) float64 { return c.RequestTime.Delta })
+
+	report.NewPaths = limit(report.NewPaths, opts.TopN)
+	report.VanishedPaths = limit(report.VanishedPaths, opts.TopN)
+	report.ChangedPaths = limit(report.ChangedPaths, opts.TopN)
+	report.LatencyRegressions = limit(report.LatencyRegressions, opts.TopN)
+}
+
+// pathCounts 返回全部路徑的請求數，沒有時以路徑排名代替
+func (s Snapshot) pathCounts() map[string]int {
+	if s.PathCounts != nil {
+		return s.PathCounts
+	}
+	counts := make(map[string]int, len(s.Statistics.TopPaths))
+	for _, p := range s.Statistics.TopPaths {
+		counts[p.Path] = p.RequestCount
+	}
+	return counts
+}
+
+// rankedPaths 依路徑索引路徑排名
+func rankedPaths(paths []stats.PathStatistics) map[string]stats.PathStatistics {
+	byPath := make(map[string]stats.PathStatistics, len(paths))
+	for _, p := range paths {
+		byPath[p.Path] = p
+	}
+	return byPath
+}
+
+// rankedOrCount 返回路徑排名中的統計，不在排名內時只有請求數
+func rankedOrCount(byPath map[string]stats.PathStatistics, path string, count int) stats.PathStatistics {
+	if p, ranked := byPath[path]; ranked {
+		return p
+	}
+	return stats.PathStatistics{Path: path, RequestCount: count}
+}
+
+// pathChange 計算單一路徑的變化（不存在的一側傳入零值）
+func pathChange(path string, before, after stats.PathStatistics, opts Options) PathChange {
+	volume := max(before.RequestCount, after.RequestCount)
+	return PathChange{
+		Path:      path,
+		Requests:  countChange(path, float64(before.RequestCount), float64(after.RequestCount), volume, opts),
+		ErrorRate: rateChange(path, before.ErrorRate, after.ErrorRate, volume, opts),
+		RequestTime: latencyChange(path, float64(before.AverageRequestTime), float64(after.AverageRequestTime),
+			min(before.RequestCount, after.RequestCount), opts),
+	}
+}
+
+// sortPaths 依指定數值降序排序，相同時依路徑排序
+func sortPaths(changes []PathChange, key func(PathChange) float64) {
+	sort.Slice(changes, func(i, j int) bool {
+		ki, kj := key(changes[i]), key(changes[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return changes[i].Path < changes[j].Path
+	})
+}
+
+// newTopIPs 找出新進入 IP 排名的來源
+func newTopIPs(before, after []stats.IPStatistics, opts Options) []Change {
+	seen := make(map[string]bool, len(before))
+	for _, ip := range before {
+		seen[ip.IP] = true
+	}
+
+	var changes []Change
+	for _, ip := range after {
+		if !seen[ip.IP] {
+			changes = append(changes, countChange(ip.IP, 0, float64(ip.RequestCount), ip.RequestCount, opts))
+		}
+	}
+	return limit(changes, opts.TopN)
+}
+
+// newBots 找出只出現在比較對象的機器人
+func newBots(before, after []stats.BotAgentStat, opts Options) []Change {
+	seen := make(map[string]bool, len(before))
+	for _, agent := range before {
+		seen[agent.Name] = true
+	}
+
+	var changes []Change
+	for _, agent := range after {
+		if !seen[agent.Name] {
+			changes = append(changes, countChange(agent.Name, 0, float64(agent.Count), agent.Count, opts))
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].After > changes[j].After })
+	return limit(changes, opts.TopN)
+}
+
+// newChange 建立變化並計算絕對差與相對變化
+func newChange(name string, before, after float64) Change {
+	change := Change{
+		Name:   name,
+		Before: before,
+		After:  after,
+		Delta:  after - before,
+	}
+	if before != 0 {
+		change.RelativeChange = change.Delta / before * 100
+	}
+	return change
+}
+
+// countChange 次數的變化：請求數達門檻，且從無到有或相對變化達門檻時視為顯著
+func countChange(name string, before, after float64, volume int, opts Options) Change {
+	change := newChange(name, before, after)
+	if volume >= opts.MinCount && change.Delta != 0 {
+		change.Significant = before == 0 || math.Abs(change.RelativeChange) >= opts.MinRelativeChange
+	}
+	return change
+}
+
+// rateChange 比率的變化：請求數達門檻，且變化的百分點達門檻時視為顯著
+func rateChange(name string, before, after float64, volume int, opts Options) Change {
+	change := newChange(name, before, after)
+	change.Significant = volume >= opts.MinCount && change.Delta != 0 &&
+		math.Abs(change.Delta) >= opts.MinRateChange
+	return change
+}
+
+// latencyChange 處理時間的變化：兩側都有處理時間、樣本數達門檻，且相對變化達門檻時視為顯著
+func latencyChange(name string, before, after float64, volume int, opts Options) Change {
+	change := newChange(name, before, after)
+	change.Significant = before > 0 && after > 0 && volume >= opts.MinCount &&
+		change.Delta != 0 && math.Abs(change.RelativeChange) >= opts.MinRelativeChange
+	return change
+}
+
+// errorRate 計算 4xx/5xx 佔總請求的比例（百分比）
+func errorRate(s *stats.Statistics) float64 {
+	return aggregate.Percentage(s.StatusCodeDistribution.ClientError+s.StatusCodeDistribution.ServerError, s.TotalRequests)
+}
+
+// limit 截取前 n 筆
+func limit[T any](items []T, n int) []T {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
@@ -0,0 +1,157 @@
+package compare
+
+import (
+	"fmt"
+	"testing"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// request 建立 n 筆相同的記錄
+func request(entries *[]models.LogEntry, n int, ip, url string, status int, requestTime int64) {
+	for i := 0; i < n; i++ {
+		*entries = append(*entries, models.LogEntry{
+			IP:          ip,
+			Method:      "GET",
+			URL:         url,
+			StatusCode:  status,
+			RequestTime: requestTime,
+			UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		})
+	}
+}
+
+// snapshot 以指定的 Top-N 計算統計與全部路徑的請求數
+func snapshot(entries []models.LogEntry, topN int) Snapshot {
+	calc := stats.NewCalculator()
+	calc.SetTopN(topN)
+	result := calc.Calculate(entries)
+	return Snapshot{Statistics: &result, PathCounts: CountPaths(entries, nil)}
+}
+
+// TestCompare 測試部署前後的路徑、狀態碼、處理時間與新來源
+func TestCompare(t *testing.T) {
+	var before []models.LogEntry
+	request(&before, 100, "10.0.0.1", "/", 200, 10000)
+	request(&before, 50, "10.0.0.2", "/api", 200, 20000)
+	request(&before, 20, "10.0.0.2", "/legacy", 200, 5000)
+	request(&before, 2, "10.0.0.3", "/rare", 200, 5000)
+
+	var after []models.LogEntry
+	request(&after, 100, "10.0.0.1", "/", 200, 10500)
+	request(&after, 40, "10.0.0.2", "/api", 200, 40000)
+	request(&after, 10, "10.0.0.2", "/api", 500, 40000)
+	request(&after, 20, "10.0.0.9", "/new-feature", 200, 8000)
+	request(&after, 3, "10.0.0.3", "/rare", 200, 5000)
+	bot := "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)"
+	for i := 0; i < 25; i++ {
+		after = append(after, models.LogEntry{IP: "157.55.39.1", URL: "/", StatusCode: 200, UserAgent: bot})
+	}
+
+	report, err := Compare(snapshot(before, SnapshotTopN), snapshot(after, SnapshotTopN), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOptions(), report.Options)
+	assert.False(t, report.Incomplete)
+
+	summary := make(map[string]Change)
+	for _, change := range report.Summary {
+		summary[change.Name] = change
+	}
+	total := summary["總請求數"]
+	assert.Equal(t, 172.0, total.Before)
+	assert.Equal(t, 198.0, total.After)
+	assert.Equal(t, 26.0, total.Delta)
+	assert.InDelta(t, 15.12, total.RelativeChange, 0.01)
+	assert.False(t, total.Significant, "未達 20% 相對變化")
+	assert.True(t, summary["伺服器錯誤率（%）"].Significant)
+	assert.True(t, summary["平均處理時間（微秒）"].Significant)
+
+	require.Len(t, report.NewPaths, 1)
+	assert.Equal(t, "/new-feature", report.NewPaths[0].Path)
+	assert.Equal(t, 20.0, report.NewPaths[0].Requests.After)
+	assert.True(t, report.NewPaths[0].Requests.Significant)
+
+	require.Len(t, report.VanishedPaths, 1)
+	assert.Equal(t, "/legacy", report.VanishedPaths[0].Path)
+	assert.Equal(t, -20.0, report.VanishedPaths[0].Requests.Delta)
+	assert.InDelta(t, -100, report.VanishedPaths[0].Requests.RelativeChange, 0.001)
+
+	require.Len(t, report.ChangedPaths, 2, "/rare 的請求數過少，不視為顯著")
+	assert.Equal(t, "/", report.ChangedPaths[0].Path, "機器人請求讓 / 的請求數增加")
+	assert.Equal(t, "/api", report.ChangedPaths[1].Path)
+	assert.InDelta(t, 20, report.ChangedPaths[1].ErrorRate.Delta, 0.001)
+	assert.True(t, report.ChangedPaths[1].ErrorRate.Significant)
+
+	require.Len(t, report.LatencyRegressions, 1, "/ 的處理時間只增加 5%")
+	regression := report.LatencyRegressions[0]
+	assert.Equal(t, "/api", regression.Path)
+	assert.Equal(t, 20000.0, regression.RequestTime.Before)
+	assert.Equal(t, 40000.0, regression.RequestTime.After)
+	assert.InDelta(t, 100, regression.RequestTime.RelativeChange, 0.001)
+
+	var codes []int
+	for _, status := range report.StatusCodes {
+		codes = append(codes, status.StatusCode)
+	}
+	assert.Equal(t, []int{200, 500}, codes)
+	serverErrors := report.StatusCodes[1]
+	assert.Equal(t, 0.0, serverErrors.Count.Before)
+	assert.Equal(t, 10.0, serverErrors.Count.After)
+	assert.InDelta(t, 5.05, serverErrors.Share.After, 0.01)
+	assert.True(t, serverErrors.Share.Significant)
+
+	var newIPs []string
+	for _, ip := range report.NewTopIPs {
+		newIPs = append(newIPs, ip.Name)
+	}
+	assert.Equal(t, []string{"157.55.39.1", "10.0.0.9"}, newIPs)
+
+	require.Len(t, report.NewBots, 1)
+	assert.Equal(t, 25.0, report.NewBots[0].After)
+	assert.True(t, report.NewBots[0].Significant)
+}
+
+// TestCompare_排名不完整 測試路徑排名被截斷時標記為不完整，新增與消失的判定仍涵蓋全部路徑
+func TestCompare_排名不完整(t *testing.T) {
+	var before, after []models.LogEntry
+	for i := 0; i < 30; i++ {
+		request(&before, 30-i, "10.0.0.1", fmt.Sprintf("/page/%d", i), 200, 0)
+		// 比較對象的請求數順序相反：排名後段的路徑仍有請求，不是消失的路徑
+		request(&after, i+1, "10.0.0.1", fmt.Sprintf("/page/%d", i), 200, 0)
+	}
+	request(&after, 1, "10.0.0.1", "/page/new", 200, 0)
+
+	report, err := Compare(snapshot(before, 10), snapshot(after, 10), Options{TopN: 5})
+	require.NoError(t, err)
+	assert.True(t, report.Incomplete)
+	require.Len(t, report.NewPaths, 1)
+	assert.Equal(t, "/page/new", report.NewPaths[0].Path)
+	assert.Empty(t, report.VanishedPaths)
+
+	// 沒有全部路徑的請求數時以路徑排名代替，並套用保留筆數
+	report, err = Compare(Snapshot{Statistics: &stats.Statistics{}}, Snapshot{Statistics: snapshot(after, 10).Statistics}, Options{TopN: 5})
+	require.NoError(t, err)
+	assert.Len(t, report.NewPaths, 5)
+	assert.Empty(t, report.VanishedPaths)
+	for _, change := range report.Summary {
+		assert.Zero(t, change.RelativeChange, "基準為 0 時不計算相對變化：%s", change.Name)
+	}
+}
+
+// TestCompare_參數驗證 測試無效的比較參數
+func TestCompare_參數驗證(t *testing.T) {
+	for _, opts := range []Options{
+		{MinRelativeChange: -1},
+		{MinRateChange: -1},
+		{MinCount: -1},
+		{TopN: -1},
+	} {
+		_, err := Compare(Snapshot{Statistics: &stats.Statistics{}}, Snapshot{Statistics: &stats.Statistics{}}, opts)
+		var validationErr *models.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", opts)
+	}
+}
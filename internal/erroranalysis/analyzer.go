@@ -0,0 +1,455 @@
+// Package erroranalysis 分析 4xx/5xx 錯誤，協助找出失敗的端點
+// 依錯誤次數與錯誤率（需達最低請求數）排名路徑，提供各路徑的錯誤時間軸、連續錯誤的爆發區段，
+// 以及各狀態碼附行號的原始記錄樣本；低流量但全部失敗的端點也會出現在錯誤率排名中
+package erroranalysis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+)
+
+// maxBuckets 時間軸的區間數量上限
+const maxBuckets = 10000
+
+// maxBursts 保留的爆發區段上限（保留錯誤數最多者）
+const maxBursts = 100
+
+// maxBurstContributors 每個爆發區段列出的主要路徑數
+const maxBurstContributors = 5
+
+// Options 錯誤分析的參數，零值欄位使用預設值
+type Options struct {
+	Path           string `json:"path"`           // 只分析此路徑（不含查詢字串），空字串表示全部
+	Bucket         string `json:"bucket"`         // 時間軸的區間長度，例如 "1h"（預設 1h）
+	MinRequests    int    `json:"minRequests"`    // 錯誤率排名所需的最低請求數（預設 10）
+	TopN           int    `json:"topN"`           // 各排名保留的路徑數（預設 10）
+	SampleSize     int    `json:"sampleSize"`     // 每個狀態碼的樣本數（預設 5）
+	BurstMinLength int    `json:"burstMinLength"` // 判定爆發所需的最少連續錯誤數（預設 10）
+	BurstGap       string `json:"burstGap"`       // 連續錯誤之間允許的最長間隔，例如 "1m"（預設 1m）
+}
+
+// DefaultOptions 返回預設的分析參數
+func DefaultOptions() Options {
+	return Options{
+		Bucket:         "1h",
+		MinRequests:    10,
+		TopN:           10,
+		SampleSize:     5,
+		BurstMinLength: 10,
+		BurstGap:       "1m",
+	}
+}
+
+// withDefaults 以預設值補上零值欄位
+func (o Options) withDefaults() Options {
+	d := DefaultOptions()
+	aggregate.Default(&o.Bucket, d.Bucket)
+	aggregate.Default(&o.MinRequests, d.MinRequests)
+	aggregate.Default(&o.TopN, d.TopN)
+	aggregate.Default(&o.SampleSize, d.SampleSize)
+	aggregate.Default(&o.BurstMinLength, d.BurstMinLength)
+	aggregate.Default(&o.BurstGap, d.BurstGap)
+	return o
+}
+
+// validate 驗證參數，返回時間軸區間長度與爆發間隔
+func (o Options) validate() (time.Duration, time.Duration, error) {
+	bucket, err := aggregate.ParseBucket(o.Bucket)
+	if err != nil {
+		return 0, 0, err
+	}
+	gap, err := aggregate.ParseWindow("BurstGap", o.BurstGap, "無效的爆發間隔")
+	if err != nil {
+		return 0, 0, err
+	}
+	err = aggregate.FirstError(
+		aggregate.NonNegative("MinRequests", o.MinRequests, "最低請求數不可為負數"),
+		aggregate.NonNegative("TopN", o.TopN, "TopN 不可為負數"),
+		aggregate.NonNegative("SampleSize", o.SampleSize, "樣本數不可為負數"),
+		aggregate.AtLeast("BurstMinLength", o.BurstMinLength, 2, "爆發至少需要 2 個連續錯誤"),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	return bucket, gap, nil
+}
+
+// Report 錯誤分析結果
+type Report struct {
+	Options       Options        `json:"options"`       // 實際使用的參數（已補上預設值）
+	Start         time.Time      `json:"start"`         // 第一個時間區間的開始時間
+	End           time.Time      `json:"end"`           // 最後一個時間區間的結束時間
+	Buckets       int            `json:"buckets"`       // 時間區間數
+	TotalRequests int            `json:"totalRequests"` // 分析範圍內的請求數
+	ClientErrors  int            `json:"clientErrors"`  // 4xx 數
+	ServerErrors  int            `json:"serverErrors"`  // 5xx 數
+	ByCount       []PathErrors   `json:"byCount"`       // 依錯誤次數排名的路徑
+	ByRate        []PathErrors   `json:"byRate"`        // 依錯誤率排名的路徑（請求數需達 MinRequests）
+	Bursts        []Burst        `json:"bursts"`        // 連續錯誤的爆發區段（依開始時間排序）
+	Samples       []StatusSample `json:"samples"`       // 各錯誤狀態碼的樣本（依狀態碼排序）
+}
+
+// PathErrors 單一路徑的錯誤統計
+type PathErrors struct {
+	Path         string          `json:"path"`         // 路徑（不含查詢字串）
+	Requests     int             `json:"requests"`     // 請求數
+	ClientErrors int             `json:"clientErrors"` // 4xx 數
+	ServerErrors int             `json:"serverErrors"` // 5xx 數
+	ErrorRate    float64         `json:"errorRate"`    // 錯誤率（百分比）
+	StatusCodes  []StatusCount   `json:"statusCodes"`  // 錯誤狀態碼分布（依次數降序）
+	Timeline     []TimelinePoint `json:"timeline"`     // 4xx/5xx 時間軸（涵蓋整個分析範圍）
+}
+
+// StatusCount 狀態碼與次數
+type StatusCount struct {
+	StatusCode int `json:"statusCode"` // 狀態碼
+	Count      int `json:"count"`      // 次數
+}
+
+// TimelinePoint 時間軸上的單一區間
+type TimelinePoint struct {
+	Start        time.Time `json:"start"`        // 區間開始時間
+	Requests     int       `json:"requests"`     // 請求數
+	ClientErrors int       `json:"clientErrors"` // 4xx 數
+	ServerErrors int       `json:"serverErrors"` // 5xx 數
+}
+
+// Burst 連續錯誤的爆發區段
+// 依時間順序連續的錯誤回應（中間沒有成功的請求，且相鄰錯誤的間隔不超過 BurstGap）
+type Burst struct {
+	Start        time.Time     `json:"start"`        // 第一個錯誤的時間
+	End          time.Time     `json:"end"`          // 最後一個錯誤的時間
+	Count        int           `json:"count"`        // 連續錯誤數
+	ClientErrors int           `json:"clientErrors"` // 4xx 數
+	ServerErrors int           `json:"serverErrors"` // 5xx 數
+	FirstLine    int           `json:"firstLine"`    // 第一個錯誤的行號
+	LastLine     int           `json:"lastLine"`     // 最後一個錯誤的行號
+	StatusCodes  []StatusCount `json:"statusCodes"`  // 區段內的狀態碼分布
+	TopPaths     []PathCount   `json:"topPaths"`     // 區段內錯誤最多的路徑
+}
+
+// PathCount 路徑與次數
+type PathCount struct {
+	Path  string `json:"path"`  // 路徑（不含查詢字串）
+	Count int    `json:"count"` // 次數
+}
+
+// StatusSample 單一錯誤狀態碼的記錄樣本
+type StatusSample struct {
+	StatusCode int               `json:"statusCode"` // 狀態碼
+	Count      int               `json:"count"`      // 此狀態碼的總次數
+	Entries    []models.LogEntry `json:"entries"`    // 最早出現的記錄（含行號與原始日誌行）
+}
+
+// pathAccumulator 累積單一路徑的錯誤
+type pathAccumulator struct {
+	requests     int
+	clientErrors int
+	serverErrors int
+	statusCodes  map[int]int
+}
+
+// errors 返回錯誤總數
+func (acc *pathAccumulator) errors() int {
+	return acc.clientErrors + acc.serverErrors
+}
+
+// burstAccumulator 累積進行中的爆發區段
+type burstAccumulator struct {
+	burst       Burst
+	statusCodes map[int]int
+	paths       map[string]int
+}
+
+// Analyzer 錯誤分析器
+type Analyzer struct {
+	log *logger.Logger
+}
+
+// NewAnalyzer 建立錯誤分析器
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{log: logger.Get().WithModule("erroranalysis")}
+}
+
+// Analyze 分析日誌記錄中的 4xx/5xx 錯誤
+// 第一次遍歷（依時間順序）統計各路徑、偵測爆發並收集樣本，第二次遍歷只為排名中的路徑建立時間軸
+func (a *Analyzer) Analyze(entries []models.LogEntry, opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	bucket, gap, err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Options: opts,
+		ByCount: make([]PathErrors, 0),
+		ByRate:  make([]PathErrors, 0),
+		Bursts:  make([]Burst, 0),
+		Samples: make([]StatusSample, 0),
+	}
+
+	var onPath func(*models.LogEntry) bool
+	if opts.Path != "" {
+		onPath = func(entry *models.LogEntry) bool { return aggregate.StripQuery(entry.URL) == opts.Path }
+	}
+	order := aggregate.Chronological(entries, onPath)
+	if len(order) == 0 {
+		return report, nil
+	}
+
+	start := aggregate.TruncateTime(entries[order[0]].Timestamp, bucket)
+	last := entries[order[len(order)-1]].Timestamp
+	count := int(last.Sub(start)/bucket) + 1
+	if count > maxBuckets {
+		return nil, &models.ValidationError{
+			Field:   "Bucket",
+			Value:   opts.Bucket,
+			Message: fmt.Sprintf("時間區間過多（%d 個），請使用較大的區間長度", count),
+		}
+	}
+	report.Start = start
+	report.End = start.Add(time.Duration(count) * bucket)
+	report.Buckets = count
+
+	paths := make(map[string]*pathAccumulator)
+	samples := make(map[int]*StatusSample)
+	var current *burstAccumulator
+	var lastError time.Time
+
+	// 結束進行中的爆發區段，達到最少連續錯誤數才保留
+	closeBurst := func() {
+		if current != nil && current.burst.Count >= opts.BurstMinLength {
+			current.burst.StatusCodes = statusCounts(current.statusCodes)
+			current.burst.TopPaths = topPaths(current.paths, maxBurstContributors)
+			report.Bursts = append(report.Bursts, current.burst)
+		}
+		current = nil
+	}
+
+	for _, i := range order {
+		entry := &entries[i]
+		report.TotalRequests++
+
+		urlPath := aggregate.StripQuery(entry.URL)
+		acc := paths[urlPath]
+		if acc == nil {
+			acc = &pathAccumulator{statusCodes: make(map[int]int)}
+			paths[urlPath] = acc
+		}
+		acc.requests++
+
+		if !entry.IsError() {
+			closeBurst()
+			continue
+		}
+
+		acc.statusCodes[entry.StatusCode]++
+		if entry.IsServerError() {
+			acc.serverErrors++
+			report.ServerErrors++
+		} else {
+			acc.clientErrors++
+			report.ClientErrors++
+		}
+
+		// 樣本：每個狀態碼保留最早出現的記錄
+		sample := samples[entry.StatusCode]
+		if sample == nil {
+			sample = &StatusSample{StatusCode: entry.StatusCode, Entries: make([]models.LogEntry, 0, opts.SampleSize)}
+			samples[entry.StatusCode] = sample
+		}
+		sample.Count++
+		if len(sample.Entries) < opts.SampleSize {
+			sample.Entries = append(sample.Entries, *entry)
+		}
+
+		// 爆發：相鄰錯誤的間隔過長時視為新的區段
+		if current != nil && entry.Timestamp.Sub(lastError) > gap {
+			closeBurst()
+		}
+		if current == nil {
+			current = &burstAccumulator{
+				burst:       Burst{Start: entry.Timestamp, FirstLine: entry.LineNumber},
+				statusCodes: make(map[int]int),
+				paths:       make(map[string]int),
+			}
+		}
+		current.burst.End = entry.Timestamp
+		current.burst.LastLine = entry.LineNumber
+		current.burst.Count++
+		if entry.IsServerError() {
+			current.burst.ServerErrors++
+		} else {
+			current.burst.ClientErrors++
+		}
+		current.statusCodes[entry.StatusCode]++
+		current.paths[urlPath]++
+		lastError = entry.Timestamp
+	}
+	closeBurst()
+
+	report.ByCount, report.ByRate = rankPaths(paths, opts)
+	report.Bursts = limitBursts(report.Bursts)
+	for _, sample := range samples {
+		report.Samples = append(report.Samples, *sample)
+	}
+	sort.Slice(report.Samples, func(i, j int) bool {
+		return report.Samples[i].StatusCode < report.Samples[j].StatusCode
+	})
+
+	a.timelines(entries, order, report, start, bucket)
+
+	a.log.Info().
+		Int("requests", report.TotalRequests).
+		Int("clientErrors", report.ClientErrors).
+		Int("serverErrors", report.ServerErrors).
+		Int("bursts", len(report.Bursts)).
+		Msg("錯誤分析完成")
+	return report, nil
+}
+
+// rankPaths 依錯誤次數與錯誤率排名路徑（只包含有錯誤的路徑）
+func rankPaths(paths map[string]*pathAccumulator, opts Options) ([]PathErrors, []PathErrors) {
+	all := make([]PathErrors, 0)
+	for urlPath, acc := range paths {
+		if acc.errors() == 0 {
+			continue
+		}
+		all = append(all, PathErrors{
+			Path:         urlPath,
+			Requests:     acc.requests,
+			ClientErrors: acc.clientErrors,
+			ServerErrors: acc.serverErrors,
+			ErrorRate:    aggregate.Percentage(acc.errors(), acc.requests),
+			StatusCodes:  statusCounts(acc.statusCodes),
+		})
+	}
+
+	byCount := append([]PathErrors(nil), all...)
+	sort.Slice(byCount, func(i, j int) bool {
+		ei, ej := byCount[i].ClientErrors+byCount[i].ServerErrors, byCount[j].ClientErrors+byCount[j].ServerErrors
+		if ei != ej {
+			return ei > ej
+		}
+		return byCount[i].Path < byCount[j].Path
+	})
+
+	byRate := make([]PathErrors, 0)
+	for _, p := range all {
+		if p.Requests >= opts.MinRequests {
+			byRate = append(byRate, p)
+		}
+	}
+	sort.Slice(byRate, func(i, j int) bool {
+		if byRate[i].ErrorRate != byRate[j].ErrorRate {
+			return byRate[i].ErrorRate > byRate[j].ErrorRate
+		}
+		if byRate[i].Requests != byRate[j].Requests {
+			return byRate[i].Requests > byRate[j].Requests
+		}
+		return byRate[i].Path < byRate[j].Path
+	})
+
+	return limit(byCount, opts.TopN), limit(byRate, opts.TopN)
+}
+
+// limit 保留前 n 筆
+func limit(paths []PathErrors, n int) []PathErrors {
+	if len(paths) > n {
+		return paths[:n]
+	}
+	return paths
+}
+
+// limitBursts 保留錯誤數最多的爆發區段，並依開始時間排序
+func limitBursts(bursts []Burst) []Burst {
+	if len(bursts) > maxBursts {
+		sort.SliceStable(bursts, func(i, j int) bool {
+			return bursts[i].Count > bursts[j].Count
+		})
+		bursts = bursts[:maxBursts]
+	}
+	sort.SliceStable(bursts, func(i, j int) bool {
+		return bursts[i].Start.Before(bursts[j].Start)
+	})
+	return bursts
+}
+
+// statusCounts 將狀態碼計數轉為依次數降序的清單
+func statusCounts(counts map[int]int) []StatusCount {
+	result := make([]StatusCount, 0, len(counts))
+	for code, count := range counts {
+		result = append(result, StatusCount{StatusCode: code, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].StatusCode < result[j].StatusCode
+	})
+	return result
+}
+
+// topPaths 返回次數最多的 n 個路徑
+func topPaths(counts map[string]int, n int) []PathCount {
+	result := make([]PathCount, 0, len(counts))
+	for urlPath, count := range counts {
+		result = append(result, PathCount{Path: urlPath, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Path < result[j].Path
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// timelines 為排名中的路徑建立涵蓋整個分析範圍的 4xx/5xx 時間軸
+func (a *Analyzer) timelines(entries []models.LogEntry, order []int, report *Report, start time.Time, bucket time.Duration) {
+	series := make(map[string][]TimelinePoint)
+	for _, ranking := range [][]PathErrors{report.ByCount, report.ByRate} {
+		for _, p := range ranking {
+			if _, exists := series[p.Path]; exists {
+				continue
+			}
+			points := make([]TimelinePoint, report.Buckets)
+			for i := range points {
+				points[i].Start = start.Add(time.Duration(i) * bucket)
+			}
+			series[p.Path] = points
+		}
+	}
+	if len(series) == 0 {
+		return
+	}
+
+	for _, i := range order {
+		entry := &entries[i]
+		points, ok := series[aggregate.StripQuery(entry.URL)]
+		if !ok {
+			continue
+		}
+		point := &points[int(entry.Timestamp.Sub(start)/bucket)]
+		point.Requests++
+		if entry.IsServerError() {
+			point.ServerErrors++
+		} else if entry.IsError() {
+			point.ClientErrors++
+		}
+	}
+
+	for _, ranking := range [][]PathErrors{report.ByCount, report.ByRate} {
+		for i := range ranking {
+			ranking[i].Timeline = series[ranking[i].Path]
+		}
+	}
+}
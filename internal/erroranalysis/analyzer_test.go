@@ -0,0 +1,173 @@
+package erroranalysis
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBase = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// entryAt 建立指定時間（相對 testBase 的秒數）的記錄，行號依序遞增
+func entryAt(entries *[]models.LogEntry, seconds int, url string, status int) {
+	*entries = append(*entries, models.LogEntry{
+		IP:         "10.0.0.1",
+		Timestamp:  testBase.Add(time.Duration(seconds) * time.Second),
+		Method:     "GET",
+		URL:        url,
+		StatusCode: status,
+		LineNumber: len(*entries) + 1,
+	})
+}
+
+// TestAnalyze_排名 測試依錯誤次數與錯誤率排名，低流量但全部失敗的路徑也會出現
+func TestAnalyze_排名(t *testing.T) {
+	var entries []models.LogEntry
+	// /api/search：100 次請求，20 次 500
+	for i := 0; i < 100; i++ {
+		status := 200
+		if i%5 == 0 {
+			status = 500
+		}
+		entryAt(&entries, i*60, "/api/search?q=x", status)
+	}
+	// /api/export：低流量但全部失敗
+	for i := 0; i < 3; i++ {
+		entryAt(&entries, i*600+30, "/api/export", 503)
+	}
+	// /missing：只有 1 次 404，未達最低請求數
+	entryAt(&entries, 45, "/missing", 404)
+
+	report, err := NewAnalyzer().Analyze(entries, Options{MinRequests: 3, BurstMinLength: 50})
+	require.NoError(t, err)
+
+	assert.Equal(t, 104, report.TotalRequests)
+	assert.Equal(t, 1, report.ClientErrors)
+	assert.Equal(t, 23, report.ServerErrors)
+
+	require.Len(t, report.ByCount, 3)
+	assert.Equal(t, "/api/search", report.ByCount[0].Path, "路徑不含查詢字串")
+	assert.Equal(t, 20, report.ByCount[0].ServerErrors)
+	assert.InDelta(t, 20, report.ByCount[0].ErrorRate, 0.001)
+
+	require.Len(t, report.ByRate, 2, "未達最低請求數的路徑不列入錯誤率排名")
+	assert.Equal(t, "/api/export", report.ByRate[0].Path)
+	assert.InDelta(t, 100, report.ByRate[0].ErrorRate, 0.001)
+	assert.Equal(t, []StatusCount{{StatusCode: 503, Count: 3}}, report.ByRate[0].StatusCodes)
+
+	// 時間軸涵蓋整個分析範圍（預設 1h 區間）
+	assert.Equal(t, testBase, report.Start)
+	assert.Equal(t, 2, report.Buckets)
+	timeline := report.ByCount[0].Timeline
+	require.Len(t, timeline, 2)
+	assert.Equal(t, TimelinePoint{Start: testBase, Requests: 60, ServerErrors: 12}, timeline[0])
+	assert.Equal(t, TimelinePoint{Start: testBase.Add(time.Hour), Requests: 40, ServerErrors: 8}, timeline[1])
+	assert.Len(t, report.ByRate[0].Timeline, 2)
+}
+
+// TestAnalyze_爆發與樣本 測試連續錯誤的爆發偵測與各狀態碼的樣本
+func TestAnalyze_爆發與樣本(t *testing.T) {
+	var entries []models.LogEntry
+	entryAt(&entries, 0, "/", 200)
+	// 第一段：12 個連續錯誤
+	for i := 0; i < 12; i++ {
+		status := 502
+		if i%4 == 0 {
+			status = 404
+		}
+		entryAt(&entries, 10+i, "/checkout", status)
+	}
+	entryAt(&entries, 30, "/", 200)
+	// 第二段：5 個連續錯誤，未達門檻
+	for i := 0; i < 5; i++ {
+		entryAt(&entries, 40+i, "/checkout", 502)
+	}
+	// 第三段：間隔超過 BurstGap 而拆成兩段，各 6 個
+	for i := 0; i < 6; i++ {
+		entryAt(&entries, 100+i, "/api", 500)
+	}
+	for i := 0; i < 6; i++ {
+		entryAt(&entries, 300+i, "/api", 500)
+	}
+
+	report, err := NewAnalyzer().Analyze(entries, Options{BurstMinLength: 6, BurstGap: "30s", SampleSize: 2})
+	require.NoError(t, err)
+
+	require.Len(t, report.Bursts, 3)
+	first := report.Bursts[0]
+	assert.Equal(t, 12, first.Count)
+	assert.Equal(t, 3, first.ClientErrors)
+	assert.Equal(t, 9, first.ServerErrors)
+	assert.Equal(t, 2, first.FirstLine)
+	assert.Equal(t, 13, first.LastLine)
+	assert.Equal(t, testBase.Add(10*time.Second), first.Start)
+	assert.Equal(t, []StatusCount{{StatusCode: 502, Count: 9}, {StatusCode: 404, Count: 3}}, first.StatusCodes)
+	assert.Equal(t, []PathCount{{Path: "/checkout", Count: 12}}, first.TopPaths)
+	assert.Equal(t, 6, report.Bursts[1].Count)
+	assert.Equal(t, 6, report.Bursts[2].Count)
+
+	require.Len(t, report.Samples, 3)
+	assert.Equal(t, 404, report.Samples[0].StatusCode)
+	assert.Equal(t, 3, report.Samples[0].Count)
+	require.Len(t, report.Samples[0].Entries, 2)
+	assert.Equal(t, 2, report.Samples[0].Entries[0].LineNumber)
+	assert.Equal(t, 500, report.Samples[1].StatusCode)
+	assert.Equal(t, 502, report.Samples[2].StatusCode)
+	assert.Equal(t, 14, report.Samples[2].Count)
+}
+
+// TestAnalyze_路徑篩選 測試只分析單一路徑與未依時間排序的記錄
+func TestAnalyze_路徑篩選(t *testing.T) {
+	var entries []models.LogEntry
+	entryAt(&entries, 120, "/api?id=2", 500)
+	entryAt(&entries, 0, "/api?id=1", 200)
+	entryAt(&entries, 60, "/other", 500)
+
+	report, err := NewAnalyzer().Analyze(entries, Options{Path: "/api", Bucket: "1m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.TotalRequests)
+	assert.Equal(t, 3, report.Buckets)
+	require.Len(t, report.ByCount, 1)
+	assert.Equal(t, "/api", report.ByCount[0].Path)
+	assert.Equal(t, []int{1, 0, 1}, []int{
+		report.ByCount[0].Timeline[0].Requests,
+		report.ByCount[0].Timeline[1].Requests,
+		report.ByCount[0].Timeline[2].Requests,
+	})
+	assert.Equal(t, 1, report.Samples[0].Entries[0].LineNumber)
+}
+
+// TestAnalyze_參數驗證 測試無效參數與空資料
+func TestAnalyze_參數驗證(t *testing.T) {
+	analyzer := NewAnalyzer()
+
+	for _, opts := range []Options{
+		{Bucket: "abc"},
+		{BurstGap: "-1s"},
+		{MinRequests: -1},
+		{TopN: -1},
+		{SampleSize: -1},
+		{BurstMinLength: 1},
+	} {
+		_, err := analyzer.Analyze(nil, opts)
+		var validationErr *models.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", opts)
+	}
+
+	var entries []models.LogEntry
+	entryAt(&entries, 0, "/", 500)
+	entryAt(&entries, 400*24*3600, "/", 500)
+	_, err := analyzer.Analyze(entries, Options{Bucket: "1m"})
+	assert.Error(t, err, "時間區間過多")
+
+	report, err := analyzer.Analyze(nil, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOptions(), report.Options)
+	assert.Empty(t, report.ByCount)
+	assert.Empty(t, report.Samples)
+}
@@ -7,13 +7,18 @@ import (
 	"strings"
 	"time"
 
+	"access-log-analyzer/internal/geoip"
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
 )
 
 // Formatter 負責將 Go 資料結構轉換為 Excel 友善的格式
 // 提供一致的資料格式化和表格結構
 type Formatter struct {
-	timeFormat string // 時間格式化字串
+	timeFormat string                 // 時間格式化字串
+	geo        *geoip.Enricher        // GeoIP 與 ASN 查詢（nil 表示不加上地理欄位）
+	botRules   *stats.BotRuleSet      // 機器人偵測規則（nil 表示使用預設規則）
+	crawlers   *stats.CrawlerVerifier // 爬蟲驗證器（nil 表示只比對官方 IP 範圍）
 }
 
 // NewFormatter 建立新的格式化器實例
@@ -23,6 +28,17 @@ func NewFormatter() *Formatter {
 	}
 }
 
+// SetGeoIP 設定 GeoIP 與 ASN 查詢，已載入資料庫時日誌條目會加上國家、城市、ASN 與組織欄位
+func (f *Formatter) SetGeoIP(enricher *geoip.Enricher) {
+	f.geo = enricher
+}
+
+// SetBotDetection 設定機器人偵測規則與爬蟲驗證器，使匯出的分類與統計計算器一致
+func (f *Formatter) SetBotDetection(rules *stats.BotRuleSet, crawlers *stats.CrawlerVerifier) {
+	f.botRules = rules
+	f.crawlers = crawlers
+}
+
 // FormatLogEntries 格式化日誌條目為二維字串陣列
 // 返回包含標題行和資料行的二維陣列，適用於 Excel 匯出
 func (f *Formatter) FormatLogEntries(logs []*models.LogEntry) [][]string {
@@ -38,6 +54,10 @@ func (f *Formatter) FormatLogEntries(logs []*models.LogEntry) [][]string {
 		"來源頁面",
 		"User Agent",
 	}
+	withGeo := f.geo.Enabled()
+	if withGeo {
+		headers = append(headers, "國家", "城市", "ASN", "組織")
+	}
 
 	// 初始化結果陣列
 	result := make([][]string, 0, len(logs)+1)
@@ -61,6 +81,9 @@ func (f *Formatter) FormatLogEntries(logs []*models.LogEntry) [][]string {
 			f.formatReferer(log.Referer),
 			log.UserAgent,
 		}
+		if withGeo {
+			row = append(row, f.formatLocation(log.IP)...)
+		}
 
 		result = append(result, row)
 	}
@@ -68,6 +91,19 @@ func (f *Formatter) FormatLogEntries(logs []*models.LogEntry) [][]string {
 	return result
 }
 
+// formatLocation 查詢 IP 的國家、城市、ASN 與組織，查無資料時為空白
+func (f *Formatter) formatLocation(ip string) []string {
+	location, found := f.geo.Lookup(ip)
+	if !found {
+		return []string{"", "", "", ""}
+	}
+	asn := ""
+	if location.ASN != 0 {
+		asn = fmt.Sprintf("AS%d", location.ASN)
+	}
+	return []string{location.Country, location.City, asn, location.Organization}
+}
+
 // FormatStatistics 格式化統計資料為二維字串陣列
 // 創建包含各種統計指標的結構化表格
 func (f *Formatter) FormatStatistics(stats *models.Statistics) [][]string {
@@ -202,195 +238,151 @@ func (f *Formatter) FormatStatistics(stats *models.Statistics) [][]string {
 }
 
 // FormatBotDetection 格式化機器人偵測結果為二維字串陣列
-// 分析 User Agent 並識別機器人請求
+// 使用 SetBotDetection 設定的規則與驗證器（與統計資訊相同的 stats.BotDetector）分析 User Agent
 func (f *Formatter) FormatBotDetection(logs []*models.LogEntry) [][]string {
-	// 建立標題行
-	headers := []string{"IP位址", "機器人類型", "信心分數", "請求次數"}
-	result := [][]string{headers}
+	botStats := f.botStatsFromLogs(logs)
+	return f.FormatBotIPStats(&botStats)
+}
 
-	if logs == nil || len(logs) == 0 {
-		return result
+// botStatsFromLogs 以 stats.BotDetector 分析日誌條目的機器人活動
+func (f *Formatter) botStatsFromLogs(logs []*models.LogEntry) stats.BotStats {
+	detector := stats.NewBotDetectorWithRules(f.botRules)
+	crawlers := f.crawlers
+	if crawlers == nil {
+		// 與統計計算器的預設相同，只比對官方 IP 範圍，不進行 DNS 查詢
+		crawlers = stats.NewCrawlerVerifier()
 	}
-
-	// 統計每個IP的機器人活動
-	botIPs := make(map[string]*BotIPStat)
-
+	detector.SetVerifier(crawlers)
 	for _, log := range logs {
 		if log == nil {
 			continue
 		}
-
-		// 使用內建的機器人偵測邏輯
-		isBot, botType := f.detectBot(log.UserAgent)
-
-		if isBot {
-			if stat, exists := botIPs[log.IP]; exists {
-				stat.Count++
-				// 如果發現更具體的機器人類型，更新類型
-				if f.getBotTypeSpecificity(botType) > f.getBotTypeSpecificity(stat.BotType) {
-					stat.BotType = botType
-				}
-			} else {
-				botIPs[log.IP] = &BotIPStat{
-					IP:      log.IP,
-					BotType: botType,
-					Count:   1,
-				}
-			}
-		}
+		detector.Observe(log)
 	}
+	return detector.GetStats()
+}
 
-	// 轉換為切片並排序（按請求次數降序）
-	var botList []*BotIPStat
-	for _, stat := range botIPs {
-		botList = append(botList, stat)
-	}
+// FormatBotIPStats 將統計資訊中各機器人 IP 的分類結果格式化為二維字串陣列
+// 確保匯出的機器人偵測工作表與儀表板的 BotStats 一致
+func (f *Formatter) FormatBotIPStats(botStats *stats.BotStats) [][]string {
+	// 建立標題行
+	headers := []string{"IP位址", "機器人類型", "信心分數", "請求次數"}
+	result := [][]string{headers}
 
-	sort.Slice(botList, func(i, j int) bool {
-		return botList[i].Count > botList[j].Count
-	})
+	if botStats == nil {
+		return result
+	}
 
-	// 格式化為表格行
-	for _, bot := range botList {
-		confidence := f.calculateConfidence(bot.BotType, bot.Count)
-		row := []string{
+	// BotIPs 已依請求次數降序排序
+	for _, bot := range botStats.BotIPs {
+		result = append(result, []string{
 			bot.IP,
 			bot.BotType,
-			confidence,
+			bot.Confidence,
 			strconv.Itoa(bot.Count),
-		}
-		result = append(result, row)
+		})
 	}
 
 	return result
 }
 
-// BotIPStat 機器人IP統計資料
-type BotIPStat struct {
-	IP      string
-	BotType string
-	Count   int
-}
+// FormatBotAgents 將各機器人代理程式（Googlebot、curl 等）的統計格式化為二維字串陣列
+func (f *Formatter) FormatBotAgents(botStats *stats.BotStats) [][]string {
+	// 建立標題行
+	headers := []string{"代理程式", "提供者", "機器人類型", "版本", "請求次數", "百分比", "傳輸量", "不重複IP數", "首次出現", "最後出現"}
+	result := [][]string{headers}
 
-// formatTime 格式化時間為字串
-func (f *Formatter) formatTime(t time.Time) string {
-	if t.IsZero() {
-		return "0001-01-01 00:00:00"
+	if botStats == nil {
+		return result
 	}
-	return t.Format(f.timeFormat)
-}
 
-// formatReferer 格式化 Referer 欄位
-func (f *Formatter) formatReferer(referer string) string {
-	if referer == "" || referer == "-" {
-		return "-"
+	// Agents 已依請求次數降序排序
+	for _, agent := range botStats.Agents {
+		result = append(result, []string{
+			agent.Name,
+			agent.Vendor,
+			agent.Category,
+			strings.Join(agent.Versions, ", "),
+			strconv.Itoa(agent.Count),
+			fmt.Sprintf("%.2f%%", agent.Percentage),
+			f.FormatFileSize(agent.Bytes),
+			strconv.Itoa(agent.DistinctIPs),
+			f.formatTime(agent.FirstSeen),
+			f.formatTime(agent.LastSeen),
+		})
 	}
-	return referer
+
+	return result
 }
 
-// getBotTypeSpecificity 取得機器人類型的特異性評分
-// 特異性越高的類型越具體，優先度越高
-func (f *Formatter) getBotTypeSpecificity(botType string) int {
-	specificity := map[string]int{
-		"搜尋引擎":   5,
-		"社交媒體":   4,
-		"SEO 工具": 4,
-		"監控工具":   3,
-		"安全掃描":   3,
-		"爬蟲":     1, // 最通用的類別
-	}
+// FormatSpoofedCrawlers 將冒充主要爬蟲（IP 不屬於該爬蟲）的來源格式化為二維字串陣列
+func (f *Formatter) FormatSpoofedCrawlers(botStats *stats.BotStats) [][]string {
+	// 建立標題行
+	headers := []string{"冒充來源IP", "自稱爬蟲", "機器人類型", "請求次數", "首次出現", "最後出現"}
+	result := [][]string{headers}
 
-	if score, exists := specificity[botType]; exists {
-		return score
+	if botStats == nil {
+		return result
 	}
-	return 0
-}
 
-// detectBot 內建機器人偵測功能（簡化版）
-func (f *Formatter) detectBot(userAgent string) (bool, string) {
-	if userAgent == "" || userAgent == "-" {
-		return false, ""
+	// SpoofedCrawlers 已依請求次數降序排序
+	for _, spoofed := range botStats.SpoofedCrawlers {
+		result = append(result, []string{
+			spoofed.IP,
+			spoofed.ClaimedName,
+			spoofed.Category,
+			strconv.Itoa(spoofed.Count),
+			f.formatTime(spoofed.FirstSeen),
+			f.formatTime(spoofed.LastSeen),
+		})
 	}
 
-	lowerUA := strings.ToLower(userAgent)
-
-	// 搜尋引擎機器人
-	searchEnginePatterns := []string{
-		"googlebot", "bingbot", "slurp", "duckduckbot",
-		"baiduspider", "yandexbot", "sogou", "exabot",
-	}
-	for _, pattern := range searchEnginePatterns {
-		if strings.Contains(lowerUA, pattern) {
-			return true, "搜尋引擎"
-		}
-	}
+	return result
+}
 
-	// 社交媒體機器人
-	socialMediaPatterns := []string{
-		"facebookexternalhit", "twitterbot", "linkedinbot",
-		"pinterest", "slackbot", "telegrambot", "whatsapp",
-		"discordbot",
-	}
-	for _, pattern := range socialMediaPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			return true, "社交媒體"
-		}
-	}
+// FormatSuspectedBots 將 User-Agent 未識別但行為像機器人的用戶端格式化為二維字串陣列
+func (f *Formatter) FormatSuspectedBots(botStats *stats.BotStats) [][]string {
+	// 建立標題行
+	headers := []string{"疑似機器人IP", "User-Agent", "行為評分", "可能性", "請求次數", "原因"}
+	result := [][]string{headers}
 
-	// 監控工具
-	monitoringPatterns := []string{
-		"pingdom", "uptimerobot", "statuscake", "monitor",
-		"site24x7", "newrelic", "datadog", "nagios",
-	}
-	for _, pattern := range monitoringPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			return true, "監控工具"
-		}
+	if botStats == nil {
+		return result
 	}
 
-	// 通用爬蟲關鍵字
-	crawlerPatterns := []string{
-		"bot", "crawler", "spider", "scraper", "scraping",
-		"python-requests", "curl", "wget", "httpclient",
-		"scrapy", "beautifulsoup", "mechanize", "pycurl",
-	}
-	for _, pattern := range crawlerPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			return true, "爬蟲"
+	// SuspectedBots 已依分數降序排序
+	for _, suspect := range botStats.SuspectedBots {
+		reasons := make([]string, 0, len(suspect.Reasons))
+		for _, reason := range suspect.Reasons {
+			reasons = append(reasons, fmt.Sprintf("%s (+%d)", reason.Detail, reason.Points))
 		}
+		result = append(result, []string{
+			suspect.IP,
+			suspect.UserAgent,
+			strconv.Itoa(suspect.Score),
+			suspect.Level,
+			strconv.Itoa(suspect.Requests),
+			strings.Join(reasons, "；"),
+		})
 	}
 
-	return false, ""
+	return result
 }
-func (f *Formatter) calculateConfidence(botType string, requestCount int) string {
-	// 基於機器人類型的基礎信心分數
-	baseScore := f.getBotTypeSpecificity(botType)
-
-	// 基於請求次數調整信心分數
-	// 機器人通常會有較高的請求頻率
-	var frequencyScore int
-	if requestCount >= 100 {
-		frequencyScore = 3
-	} else if requestCount >= 50 {
-		frequencyScore = 2
-	} else if requestCount >= 10 {
-		frequencyScore = 1
-	} else {
-		frequencyScore = 0
-	}
 
-	totalScore := baseScore + frequencyScore
+// formatTime 格式化時間為字串
+func (f *Formatter) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "0001-01-01 00:00:00"
+	}
+	return t.Format(f.timeFormat)
+}
 
-	// 轉換為信心等級
-	if totalScore >= 7 {
-		return "極高"
-	} else if totalScore >= 5 {
-		return "高"
-	} else if totalScore >= 3 {
-		return "中"
-	} else {
-		return "低"
+// formatReferer 格式化 Referer 欄位
+func (f *Formatter) formatReferer(referer string) string {
+	if referer == "" || referer == "-" {
+		return "-"
 	}
+	return referer
 }
 
 // SetTimeFormat 設定時間格式化字串
@@ -0,0 +1,14 @@
+package exporter
+
+import (
+	"access-log-analyzer/internal/aggregate"
+)
+
+// FormatAggregateResult 格式化聚合查詢結果為二維字串陣列
+// 第一列為欄位名稱，其餘為分組結果
+func (f *Formatter) FormatAggregateResult(result *aggregate.Result) [][]string {
+	if result == nil {
+		return [][]string{{"無資料"}}
+	}
+	return result.Table()
+}
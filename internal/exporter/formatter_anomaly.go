@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"access-log-analyzer/internal/anomaly"
+)
+
+// anomalyKindLabels 異常類型的顯示名稱
+var anomalyKindLabels = map[string]string{
+	anomaly.KindSpike:      "流量突增",
+	anomaly.KindDrop:       "流量驟降",
+	anomaly.KindOutage:     "流量中斷",
+	anomaly.KindErrorSpike: "5xx 比例跳升",
+	anomaly.KindNewIPSurge: "新 IP 暴增",
+}
+
+// anomalySeverityLabels 嚴重程度的顯示名稱
+var anomalySeverityLabels = map[string]string{
+	anomaly.SeverityCritical: "嚴重",
+	anomaly.SeverityHigh:     "高",
+	anomaly.SeverityMedium:   "中",
+}
+
+// FormatAnomalies 將流量異常區段格式化為二維字串陣列
+func (f *Formatter) FormatAnomalies(report *anomaly.Report) [][]string {
+	// 建立標題行
+	headers := []string{"開始時間", "結束時間", "異常類型", "嚴重程度", "區間數", "請求數", "5xx 數", "說明", "主要路徑", "主要 IP"}
+	result := [][]string{headers}
+
+	if report == nil {
+		return result
+	}
+
+	for _, w := range report.Windows {
+		kind, ok := anomalyKindLabels[w.Kind]
+		if !ok {
+			kind = w.Kind
+		}
+		severity, ok := anomalySeverityLabels[w.Severity]
+		if !ok {
+			severity = w.Severity
+		}
+		result = append(result, []string{
+			f.formatTime(w.Start),
+			f.formatTime(w.End),
+			kind,
+			severity,
+			strconv.Itoa(w.Buckets),
+			strconv.Itoa(w.Requests),
+			strconv.Itoa(w.Errors),
+			w.Description,
+			formatContributors(w.TopPaths),
+			formatContributors(w.TopIPs),
+		})
+	}
+
+	return result
+}
+
+// formatContributors 將主要路徑或 IP 合併為單一儲存格，例如 "/api (120)；/login (30)"
+func formatContributors(contributors []anomaly.Contributor) string {
+	parts := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		parts = append(parts, fmt.Sprintf("%s (%d)", c.Key, c.Count))
+	}
+	return strings.Join(parts, "；")
+}
@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"strconv"
+
+	"access-log-analyzer/internal/cacheanalysis"
+)
+
+// contentTypeLabels 內容類型的顯示名稱
+var contentTypeLabels = map[string]string{
+	cacheanalysis.TypePage:       "頁面",
+	cacheanalysis.TypeStylesheet: "CSS",
+	cacheanalysis.TypeScript:     "JavaScript",
+	cacheanalysis.TypeImage:      "圖片",
+	cacheanalysis.TypeFont:       "字型",
+	cacheanalysis.TypeMedia:      "影音",
+	cacheanalysis.TypeDocument:   "文件",
+	cacheanalysis.TypeData:       "資料",
+	cacheanalysis.TypeOther:      "其他",
+}
+
+// cacheReasonLabels 快取建議原因的顯示名稱
+var cacheReasonLabels = map[string]string{
+	cacheanalysis.ReasonRepeatDownloads:      "重複下載",
+	cacheanalysis.ReasonFrequentRevalidation: "頻繁重新驗證",
+	cacheanalysis.ReasonLowHitRatio:          "命中率偏低",
+}
+
+// contentTypeLabel 返回內容類型的顯示名稱
+func contentTypeLabel(contentType string) string {
+	if label, ok := contentTypeLabels[contentType]; ok {
+		return label
+	}
+	return contentType
+}
+
+// cacheReasonLabel 返回快取建議原因的顯示名稱
+func cacheReasonLabel(reason string) string {
+	if label, ok := cacheReasonLabels[reason]; ok {
+		return label
+	}
+	return reason
+}
+
+// FormatCacheReport 將快取分析結果格式化為二維字串陣列
+// 第一段為各路徑的快取成效，其後依序為內容類型、快取狀態（log 有記錄時）與建議
+func (f *Formatter) FormatCacheReport(report *cacheanalysis.Report) [][]string {
+	// 建立標題行
+	result := [][]string{{
+		"路徑", "內容類型", "請求數", "200 次數", "304 次數", "304 比例", "平均大小(位元組)",
+		"節省流量(位元組)", "重複下載", "重複下載比例", "快取命中", "快取未命中", "命中率",
+	}}
+
+	if report == nil {
+		return result
+	}
+
+	for _, p := range report.Paths {
+		result = append(result, []string{
+			p.Path,
+			contentTypeLabel(p.ContentType),
+			strconv.Itoa(p.Requests),
+			strconv.Itoa(p.FullResponses),
+			strconv.Itoa(p.NotModified),
+			formatPercent(p.NotModifiedRate),
+			strconv.FormatInt(p.AverageSize, 10),
+			strconv.FormatInt(p.BytesSaved, 10),
+			strconv.Itoa(p.RepeatFetches),
+			formatPercent(p.RepeatRate),
+			strconv.Itoa(p.CacheHits),
+			strconv.Itoa(p.CacheMisses),
+			formatPercent(p.HitRatio),
+		})
+	}
+
+	result = append(result, []string{""})
+	result = append(result, []string{"===== 內容類型 ====="})
+	result = append(result, []string{"內容類型", "請求數", "200 次數", "304 次數", "304 比例", "傳輸量(位元組)", "節省流量(位元組)", "重複下載"})
+	for _, t := range report.ContentTypes {
+		result = append(result, []string{
+			contentTypeLabel(t.ContentType),
+			strconv.Itoa(t.Requests),
+			strconv.Itoa(t.FullResponses),
+			strconv.Itoa(t.NotModified),
+			formatPercent(t.NotModifiedRate),
+			strconv.FormatInt(t.BytesTransferred, 10),
+			strconv.FormatInt(t.BytesSaved, 10),
+			strconv.Itoa(t.RepeatFetches),
+		})
+	}
+
+	if report.CacheStatus != nil {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 快取狀態 ====="})
+		result = append(result, []string{"命中率", formatPercent(report.CacheStatus.HitRatio)})
+		result = append(result, []string{"快取狀態", "次數"})
+		for _, s := range report.CacheStatus.Statuses {
+			result = append(result, []string{s.Status, strconv.Itoa(s.Count)})
+		}
+	}
+
+	if len(report.Recommendations) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 建議 ====="})
+		result = append(result, []string{"路徑", "內容類型", "原因", "說明", "請求數"})
+		for _, r := range report.Recommendations {
+			result = append(result, []string{
+				r.Path,
+				contentTypeLabel(r.ContentType),
+				cacheReasonLabel(r.Reason),
+				r.Message,
+				strconv.Itoa(r.Requests),
+			})
+		}
+	}
+
+	return result
+}
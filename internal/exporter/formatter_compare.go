@@ -0,0 +1,170 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+
+	"access-log-analyzer/internal/compare"
+)
+
+// FormatComparison 將比較結果格式化為二維字串陣列
+// 第一段為總覽指標，其後依序為狀態碼、新增路徑、消失路徑、顯著變化路徑、處理時間退步、新進入排名的 IP 與新機器人
+func (f *Formatter) FormatComparison(report *compare.Report) [][]string {
+	before, after := "比較基準", "比較對象"
+	if report != nil && report.BeforeLabel != "" {
+		before = report.BeforeLabel
+	}
+	if report != nil && report.AfterLabel != "" {
+		after = report.AfterLabel
+	}
+	changeHeaders := func(name string) []string {
+		return []string{name, before, after, "變化", "相對變化(%)", "顯著"}
+	}
+
+	// 建立標題行
+	result := [][]string{changeHeaders("指標")}
+
+	if report == nil {
+		return result
+	}
+
+	for _, change := range report.Summary {
+		result = append(result, formatChange(change.Name, change))
+	}
+
+	result = append(result, []string{""})
+	result = append(result, []string{"===== 狀態碼 ====="})
+	result = append(result, []string{"狀態碼", before, after, "變化", before + "佔比(%)", after + "佔比(%)", "佔比變化（百分點）", "顯著"})
+	for _, status := range report.StatusCodes {
+		result = append(result, []string{
+			strconv.Itoa(status.StatusCode),
+			formatChangeValue(status.Count.Before),
+			formatChangeValue(status.Count.After),
+			formatChangeDelta(status.Count.Delta),
+			fmt.Sprintf("%.2f", status.Share.Before),
+			fmt.Sprintf("%.2f", status.Share.After),
+			fmt.Sprintf("%+.2f", status.Share.Delta),
+			formatSignificant(status.Share.Significant),
+		})
+	}
+
+	pathSections := []struct {
+		title string
+		paths []compare.PathChange
+	}{
+		{"新增路徑", report.NewPaths},
+		{"消失路徑", report.VanishedPaths},
+		{"請求數或錯誤率顯著變化的路徑", report.ChangedPaths},
+		{"處理時間退步的路徑", report.LatencyRegressions},
+	}
+	for _, section := range pathSections {
+		if len(section.paths) == 0 {
+			continue
+		}
+		result = append(result, []string{""})
+		result = append(result, []string{"===== " + section.title + " ====="})
+		result = append(result, []string{
+			"路徑",
+			before + "請求數", after + "請求數", "請求數變化(%)",
+			before + "錯誤率(%)", after + "錯誤率(%)",
+			before + "平均處理時間(ms)", after + "平均處理時間(ms)", "處理時間變化(%)",
+			"顯著",
+		})
+		for _, path := range section.paths {
+			result = append(result, []string{
+				path.Path,
+				formatChangeValue(path.Requests.Before),
+				formatChangeValue(path.Requests.After),
+				formatRelativeChange(path.Requests),
+				fmt.Sprintf("%.2f", path.ErrorRate.Before),
+				fmt.Sprintf("%.2f", path.ErrorRate.After),
+				formatMilliseconds(path.RequestTime.Before),
+				formatMilliseconds(path.RequestTime.After),
+				formatRelativeChange(path.RequestTime),
+				formatSignificant(path.Requests.Significant || path.ErrorRate.Significant || path.RequestTime.Significant),
+			})
+		}
+	}
+
+	sourceSections := []struct {
+		title   string
+		header  string
+		changes []compare.Change
+	}{
+		{"新進入排名的 IP", "IP 位址", report.NewTopIPs},
+		{"新出現的機器人", "機器人", report.NewBots},
+	}
+	for _, section := range sourceSections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		result = append(result, []string{""})
+		result = append(result, []string{"===== " + section.title + " ====="})
+		result = append(result, changeHeaders(section.header))
+		for _, change := range section.changes {
+			result = append(result, formatChange(change.Name, change))
+		}
+	}
+
+	if report.Incomplete {
+		result = append(result, []string{""})
+		result = append(result, []string{"註：路徑排名未涵蓋全部路徑，顯著變化與處理時間退步的判定只限於排名內"})
+	}
+
+	return result
+}
+
+// formatChange 將單一變化格式化為一列
+func formatChange(name string, change compare.Change) []string {
+	return []string{
+		name,
+		formatChangeValue(change.Before),
+		formatChangeValue(change.After),
+		formatChangeDelta(change.Delta),
+		formatRelativeChange(change),
+		formatSignificant(change.Significant),
+	}
+}
+
+// formatChangeValue 格式化數值，整數不顯示小數
+func formatChangeValue(value float64) string {
+	if value == float64(int64(value)) {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// formatChangeDelta 格式化帶正負號的變化量
+func formatChangeDelta(value float64) string {
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%+d", int64(value))
+	}
+	return fmt.Sprintf("%+.2f", value)
+}
+
+// formatRelativeChange 格式化相對變化，基準為 0 時無法計算
+func formatRelativeChange(change compare.Change) string {
+	if change.Before == 0 {
+		if change.After == 0 {
+			return "0.00"
+		}
+		return "新增"
+	}
+	return fmt.Sprintf("%+.2f", change.RelativeChange)
+}
+
+// formatMilliseconds 將微秒格式化為毫秒，0 表示沒有處理時間
+func formatMilliseconds(microseconds float64) string {
+	if microseconds == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", microseconds/1000)
+}
+
+// formatSignificant 格式化顯著標記
+func formatSignificant(significant bool) string {
+	if significant {
+		return "是"
+	}
+	return ""
+}
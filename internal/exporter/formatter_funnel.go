@@ -0,0 +1,52 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+
+	"access-log-analyzer/internal/funnel"
+)
+
+// FormatFunnel 將漏斗分析結果格式化為二維字串陣列
+// 有比較時間範圍時，另附比較期間的到達數、轉換率與變化
+func (f *Formatter) FormatFunnel(report *funnel.Report) [][]string {
+	// 建立標題行
+	headers := []string{"步驟", "名稱", "比對模式", "到達數", "轉換率", "步驟轉換率", "流失數", "流失率"}
+	if report != nil && report.Previous != nil {
+		headers = append(headers, "比較期間到達數", "比較期間轉換率", "轉換率變化（百分點）")
+	}
+	result := [][]string{headers}
+
+	if report == nil || report.Current == nil {
+		return result
+	}
+
+	for i, step := range report.Current.Steps {
+		row := []string{
+			strconv.Itoa(i + 1),
+			step.Name,
+			step.Pattern,
+			strconv.Itoa(step.Count),
+			formatPercent(step.ConversionRate),
+			formatPercent(step.StepConversion),
+			strconv.Itoa(step.DropOff),
+			formatPercent(step.DropOffRate),
+		}
+		if report.Previous != nil && i < len(report.Previous.Steps) && i < len(report.Changes) {
+			previous := report.Previous.Steps[i]
+			row = append(row,
+				strconv.Itoa(previous.Count),
+				formatPercent(previous.ConversionRate),
+				fmt.Sprintf("%+.2f", report.Changes[i].ConversionDelta),
+			)
+		}
+		result = append(result, row)
+	}
+
+	return result
+}
+
+// formatPercent 將百分比格式化為字串，例如 "66.67%"
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.2f%%", value)
+}
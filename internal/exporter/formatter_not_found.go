@@ -0,0 +1,55 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"access-log-analyzer/internal/stats"
+)
+
+// FormatNotFound 將失效連結（404/410）報表格式化為二維字串陣列
+func (f *Formatter) FormatNotFound(report *stats.NotFoundReport) [][]string {
+	// 建立標題行
+	headers := []string{
+		"路徑", "次數", "410 次數", "首次出現", "最後出現", "機器人", "一般用戶",
+		"站內連結來源", "外部連結來源", "轉址次數", "最後轉址", "轉址來源",
+	}
+	result := [][]string{headers}
+
+	if report == nil {
+		return result
+	}
+
+	for _, p := range report.Paths {
+		lastRedirect := ""
+		if !p.LastRedirect.IsZero() {
+			lastRedirect = f.formatTime(p.LastRedirect)
+		}
+		result = append(result, []string{
+			p.Path,
+			strconv.Itoa(p.Count),
+			strconv.Itoa(p.GoneCount),
+			f.formatTime(p.FirstSeen),
+			f.formatTime(p.LastSeen),
+			strconv.Itoa(p.BotHits),
+			strconv.Itoa(p.HumanHits),
+			formatDistributionItems(p.InternalReferers),
+			formatDistributionItems(p.ExternalReferers),
+			strconv.Itoa(p.RedirectCount),
+			lastRedirect,
+			formatDistributionItems(p.RedirectedFrom),
+		})
+	}
+
+	return result
+}
+
+// formatDistributionItems 將分布項目合併為單一儲存格，例如 "https://example.com/ (3)；/old (1)"
+func formatDistributionItems(items []stats.DistributionItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s (%d)", item.Name, item.Count))
+	}
+	return strings.Join(parts, "；")
+}
@@ -0,0 +1,119 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"access-log-analyzer/internal/stats"
+)
+
+// refererChannelLabels 流量來源管道的顯示名稱
+var refererChannelLabels = map[string]string{
+	stats.ChannelDirect:   "直接造訪",
+	stats.ChannelInternal: "站內",
+	stats.ChannelSearch:   "搜尋引擎",
+	stats.ChannelSocial:   "社群網站",
+	stats.ChannelEmail:    "電子郵件",
+	stats.ChannelReferral: "其他網站",
+}
+
+// siteHostsSourceLabels 本站主機來源的顯示名稱
+var siteHostsSourceLabels = map[string]string{
+	stats.SiteHostsConfigured: "使用者設定",
+	stats.SiteHostsVirtual:    "由虛擬主機推斷",
+	stats.SiteHostsReferer:    "由最常見的來源網域推斷",
+	stats.SiteHostsNone:       "無法判斷（不區分站內外、不偵測盜連）",
+}
+
+// refererChannelLabel 返回管道的顯示名稱
+func refererChannelLabel(channel string) string {
+	if label, ok := refererChannelLabels[channel]; ok {
+		return label
+	}
+	return channel
+}
+
+// FormatReferers 將流量來源分析格式化為二維字串陣列
+// 第一段為管道分布，其後依序為來源網域、搜尋引擎、行銷活動與盜連
+func (f *Formatter) FormatReferers(r *stats.RefererStatistics) [][]string {
+	// 建立標題行
+	result := [][]string{{"管道", "請求次數", "百分比(%)"}}
+
+	if r == nil {
+		return result
+	}
+
+	for _, item := range r.Channels {
+		result = append(result, []string{
+			refererChannelLabel(item.Name),
+			strconv.Itoa(item.Count),
+			fmt.Sprintf("%.2f", item.Percentage),
+		})
+	}
+
+	if r.SiteHostsSource != "" {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 本站主機 ====="})
+		result = append(result, []string{"來源", siteHostsSourceLabels[r.SiteHostsSource]})
+		result = append(result, []string{"主機", strings.Join(r.SiteHosts, ", ")})
+	}
+
+	result = append(result, []string{""})
+	result = append(result, []string{"===== 來源網域 ====="})
+	result = append(result, []string{"網域", "管道", "請求次數", "佔外部來源(%)"})
+	for _, domain := range r.Domains {
+		result = append(result, []string{
+			domain.Domain,
+			refererChannelLabel(domain.Channel),
+			strconv.Itoa(domain.Count),
+			fmt.Sprintf("%.2f", domain.Percentage),
+		})
+	}
+
+	if len(r.SearchEngines) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 搜尋引擎 ====="})
+		result = append(result, []string{"搜尋引擎", "請求次數", "百分比(%)"})
+		for _, item := range r.SearchEngines {
+			result = append(result, []string{
+				item.Name,
+				strconv.Itoa(item.Count),
+				fmt.Sprintf("%.2f", item.Percentage),
+			})
+		}
+	}
+
+	if len(r.Campaigns) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 行銷活動 ====="})
+		result = append(result, []string{"utm_source", "utm_medium", "utm_campaign", "請求次數", "IP數量"})
+		for _, campaign := range r.Campaigns {
+			result = append(result, []string{
+				campaign.Source,
+				campaign.Medium,
+				campaign.Campaign,
+				strconv.Itoa(campaign.Count),
+				strconv.Itoa(campaign.UniqueIPs),
+			})
+		}
+	}
+
+	if len(r.Hotlinks) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== 盜連 ====="})
+		result = append(result, []string{"盜連請求總數", strconv.Itoa(r.HotlinkRequests)})
+		result = append(result, []string{"盜連傳輸量(位元組)", strconv.FormatInt(r.HotlinkBytes, 10)})
+		result = append(result, []string{"來源網域", "資源路徑", "請求次數", "傳輸量(位元組)"})
+		for _, hotlink := range r.Hotlinks {
+			result = append(result, []string{
+				hotlink.Domain,
+				hotlink.Path,
+				strconv.Itoa(hotlink.Count),
+				strconv.FormatInt(hotlink.Bytes, 10),
+			})
+		}
+	}
+
+	return result
+}
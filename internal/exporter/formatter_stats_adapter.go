@@ -30,18 +30,76 @@ func (f *Formatter) FormatStatsStatistics(s *stats.Statistics) [][]string {
 	// Top IP統計
 	result = append(result, []string{""}) // 空行分隔
 	result = append(result, []string{"===== Top 10 IP 位址 ====="})
-	result = append(result, []string{"IP位址", "請求次數", "總流量(位元組)"})
+	withGeo := len(s.Countries) > 0 || len(s.ASNs) > 0
+	ipHeader := []string{"IP位址", "請求次數", "總流量(位元組)"}
+	if withGeo {
+		ipHeader = append(ipHeader, "國家", "城市", "ASN", "組織")
+	}
+	withGroups := len(s.CIDRGroups) > 0
+	if withGroups {
+		ipHeader = append(ipHeader, "群組")
+	}
+	result = append(result, ipHeader)
 	topIPsCount := len(s.TopIPs)
 	if topIPsCount > 10 {
 		topIPsCount = 10
 	}
 	for i := 0; i < topIPsCount; i++ {
 		ip := s.TopIPs[i]
-		result = append(result, []string{
+		row := []string{
 			ip.IP,
 			strconv.Itoa(ip.RequestCount),
 			strconv.FormatInt(ip.TotalBytes, 10),
-		})
+		}
+		if withGeo {
+			asn := ""
+			if ip.ASN != 0 {
+				asn = fmt.Sprintf("AS%d", ip.ASN)
+			}
+			row = append(row, ip.Country, ip.City, asn, ip.Organization)
+		}
+		if withGroups {
+			row = append(row, ip.Group)
+		}
+		result = append(result, row)
+	}
+
+	// 網段排名
+	if len(s.TopSubnets) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== Top 網段 ====="})
+		result = append(result, []string{"網段", "群組", "請求次數", "總流量(位元組)", "IP數量", "錯誤率(%)", "機器人佔比(%)"})
+		for _, item := range s.TopSubnets {
+			result = append(result, []string{
+				item.Subnet,
+				item.Group,
+				strconv.Itoa(item.RequestCount),
+				strconv.FormatInt(item.TotalBytes, 10),
+				strconv.Itoa(item.UniqueIPs),
+				fmt.Sprintf("%.2f", item.ErrorRate),
+				fmt.Sprintf("%.2f", item.BotShare),
+			})
+		}
+	}
+	if withGroups {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== CIDR 群組 ====="})
+		result = append(result, geoRankingHeader("群組"))
+		result = append(result, formatGeoRanking(s.CIDRGroups)...)
+	}
+
+	// 國家與 ASN 排名（需載入 GeoIP 資料庫）
+	if len(s.Countries) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== Top 國家 ====="})
+		result = append(result, geoRankingHeader("國家"))
+		result = append(result, formatGeoRanking(s.Countries)...)
+	}
+	if len(s.ASNs) > 0 {
+		result = append(result, []string{""})
+		result = append(result, []string{"===== Top ASN ====="})
+		result = append(result, geoRankingHeader("ASN"))
+		result = append(result, formatGeoRanking(s.ASNs)...)
 	}
 
 	// Top路徑統計
@@ -124,5 +182,53 @@ func (f *Formatter) FormatStatsStatistics(s *stats.Statistics) [][]string {
 		}
 	}
 
+	// User-Agent 分布
+	for _, section := range []struct {
+		title string
+		items []stats.DistributionItem
+	}{
+		{"瀏覽器分布", s.UserAgents.Browsers},
+		{"瀏覽器版本分布", s.UserAgents.BrowserVersions},
+		{"作業系統分布", s.UserAgents.OperatingSystems},
+		{"裝置類型分布", s.UserAgents.Devices},
+		{"排版引擎分布", s.UserAgents.Engines},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		result = append(result, []string{""})
+		result = append(result, []string{"===== " + section.title + " ====="})
+		result = append(result, []string{"名稱", "請求次數", "百分比(%)"})
+		for _, item := range section.items {
+			result = append(result, []string{
+				item.Name,
+				strconv.Itoa(item.Count),
+				fmt.Sprintf("%.2f", item.Percentage),
+			})
+		}
+	}
+
 	return result
 }
+
+// geoRankingHeader 國家、ASN 或 CIDR 群組排名的標題行
+func geoRankingHeader(keyName string) []string {
+	return []string{keyName, "名稱", "請求次數", "總流量(位元組)", "IP數量", "錯誤率(%)", "機器人佔比(%)"}
+}
+
+// formatGeoRanking 格式化國家、ASN 或 CIDR 群組排名
+func formatGeoRanking(ranking []stats.GeoStatistics) [][]string {
+	rows := make([][]string, 0, len(ranking))
+	for _, item := range ranking {
+		rows = append(rows, []string{
+			item.Key,
+			item.Name,
+			strconv.Itoa(item.RequestCount),
+			strconv.FormatInt(item.TotalBytes, 10),
+			strconv.Itoa(item.UniqueIPs),
+			fmt.Sprintf("%.2f", item.ErrorRate),
+			fmt.Sprintf("%.2f", item.BotShare),
+		})
+	}
+	return rows
+}
@@ -1,10 +1,14 @@
 package exporter
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
+	"access-log-analyzer/internal/geoip"
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -104,6 +108,89 @@ func TestFormatLogEntriesEmpty(t *testing.T) {
 	assert.Equal(t, expectedHeaders, result[0], "標題行應該正確")
 }
 
+// TestFormatLogEntriesGeoIP 測試載入 GeoIP 資料庫後加上的地理欄位
+func TestFormatLogEntriesGeoIP(t *testing.T) {
+	enricher := geoip.NewEnricher()
+	_, err := enricher.Load("../geoip/testdata")
+	require.NoError(t, err)
+
+	formatter := NewFormatter()
+	formatter.SetGeoIP(enricher)
+	result := formatter.FormatLogEntries([]*models.LogEntry{
+		{IP: "203.0.113.7", StatusCode: 200},
+		{IP: "10.0.0.1", StatusCode: 200},
+	})
+
+	require.Len(t, result, 3)
+	assert.Equal(t, []string{"國家", "城市", "ASN", "組織"}, result[0][9:])
+	assert.Equal(t, []string{"TW", "Taipei", "AS3462", "Data Communication Business Group"}, result[1][9:])
+	assert.Equal(t, []string{"", "", "", ""}, result[2][9:], "查無資料時為空白")
+}
+
+// TestFormatStatsStatisticsGeoIP 測試統計工作表的國家與 ASN 排名
+func TestFormatStatsStatisticsGeoIP(t *testing.T) {
+	s := &stats.Statistics{
+		TopIPs: []stats.IPStatistics{
+			{IP: "203.0.113.7", RequestCount: 3, TotalBytes: 300, Country: "TW", City: "Taipei", ASN: 3462, Organization: "Data Communication Business Group"},
+		},
+		Countries: []stats.GeoStatistics{
+			{Key: "TW", Name: "Taiwan", RequestCount: 3, TotalBytes: 300, UniqueIPs: 1, ErrorRate: 33.333, BotShare: 0},
+		},
+		ASNs: []stats.GeoStatistics{
+			{Key: "AS3462", Name: "Data Communication Business Group", RequestCount: 3, TotalBytes: 300, UniqueIPs: 1},
+		},
+	}
+
+	result := NewFormatter().FormatStatsStatistics(s)
+	assert.Contains(t, result, []string{"203.0.113.7", "3", "300", "TW", "Taipei", "AS3462", "Data Communication Business Group"})
+	assert.Contains(t, result, []string{"===== Top 國家 ====="})
+	assert.Contains(t, result, []string{"TW", "Taiwan", "3", "300", "1", "33.33", "0.00"})
+	assert.Contains(t, result, []string{"===== Top ASN ====="})
+
+	// 沒有地理資訊時不加上欄位與區塊
+	result = NewFormatter().FormatStatsStatistics(&stats.Statistics{TopIPs: s.TopIPs})
+	assert.Contains(t, result, []string{"IP位址", "請求次數", "總流量(位元組)"})
+	assert.NotContains(t, result, []string{"===== Top 國家 ====="})
+}
+
+// TestFormatStatsStatisticsSubnets 測試統計工作表的網段與 CIDR 群組排名
+func TestFormatStatsStatisticsSubnets(t *testing.T) {
+	s := &stats.Statistics{
+		TopIPs: []stats.IPStatistics{{IP: "10.0.0.1", RequestCount: 3, TotalBytes: 300, Group: "office"}},
+		TopSubnets: []stats.SubnetStatistics{
+			{Subnet: "203.0.113.0/24", RequestCount: 4, TotalBytes: 40, UniqueIPs: 4, ErrorRate: 25},
+			{Subnet: "10.0.0.0/24", Group: "office", RequestCount: 3, TotalBytes: 300, UniqueIPs: 1},
+		},
+		CIDRGroups: []stats.GeoStatistics{{Key: "office", Name: "10.0.0.0/8", RequestCount: 3, TotalBytes: 300, UniqueIPs: 1}},
+	}
+
+	result := NewFormatter().FormatStatsStatistics(s)
+	assert.Contains(t, result, []string{"IP位址", "請求次數", "總流量(位元組)", "群組"})
+	assert.Contains(t, result, []string{"10.0.0.1", "3", "300", "office"})
+	assert.Contains(t, result, []string{"===== Top 網段 ====="})
+	assert.Contains(t, result, []string{"203.0.113.0/24", "", "4", "40", "4", "25.00", "0.00"})
+	assert.Contains(t, result, []string{"10.0.0.0/24", "office", "3", "300", "1", "0.00", "0.00"})
+	assert.Contains(t, result, []string{"===== CIDR 群組 ====="})
+	assert.Contains(t, result, []string{"office", "10.0.0.0/8", "3", "300", "1", "0.00", "0.00"})
+}
+
+// TestFormatStatsStatisticsUserAgents 測試統計工作表的 User-Agent 分布
+func TestFormatStatsStatisticsUserAgents(t *testing.T) {
+	s := &stats.Statistics{
+		UserAgents: stats.UserAgentStatistics{
+			Browsers: []stats.DistributionItem{{Name: "Chrome", Count: 3, Percentage: 75}},
+			Devices:  []stats.DistributionItem{{Name: "mobile", Count: 1, Percentage: 25}},
+		},
+	}
+
+	result := NewFormatter().FormatStatsStatistics(s)
+	assert.Contains(t, result, []string{"===== 瀏覽器分布 ====="})
+	assert.Contains(t, result, []string{"Chrome", "3", "75.00"})
+	assert.Contains(t, result, []string{"===== 裝置類型分布 ====="})
+	assert.Contains(t, result, []string{"mobile", "1", "25.00"})
+	assert.NotContains(t, result, []string{"===== 作業系統分布 ====="}, "沒有資料的分布不輸出")
+}
+
 // TestFormatStatistics 測試統計資料的格式化
 func TestFormatStatistics(t *testing.T) {
 	stats := &models.Statistics{
@@ -248,6 +335,164 @@ func TestFormatBotDetectionNoBots(t *testing.T) {
 	assert.Equal(t, expectedHeaders, result[0], "標題行應該正確")
 }
 
+// TestFormatBotDetectionConsistency 測試機器人偵測工作表與統計資訊的 BotStats 一致
+func TestFormatBotDetectionConsistency(t *testing.T) {
+	userAgents := map[string]string{
+		"10.0.0.1": "Mozilla/5.0 (compatible; YandexBot/3.0; +http://yandex.com/bots)",
+		"10.0.0.2": "libwww-perl/6.67",
+		"10.0.0.3": "okhttp/4.9.0",
+		"10.0.0.4": "Go-http-client/1.1",
+		"10.0.0.5": "Mozilla/5.0 (compatible; SemrushBot/7~bl)",
+		"10.0.0.6": "Mozilla/5.00 (Nikto/2.1.6)",
+		"10.0.0.7": "Googlebot/2.1 (+http://www.google.com/bot.html)",
+		"10.0.0.8": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0",
+	}
+
+	var entries []models.LogEntry
+	for i := 0; i < 3; i++ {
+		for ip, ua := range userAgents {
+			entries = append(entries, models.LogEntry{IP: ip, UserAgent: ua, StatusCode: 200, URL: "/"})
+		}
+	}
+	// 同一 IP 出現多種類型時取最具體者
+	entries = append(entries, models.LogEntry{IP: "10.0.0.7", UserAgent: "curl/7.68.0", StatusCode: 200, URL: "/"})
+
+	logs := make([]*models.LogEntry, len(entries))
+	for i := range entries {
+		logs[i] = &entries[i]
+	}
+
+	statistics := stats.NewCalculator().Calculate(entries)
+	formatter := NewFormatter()
+	sheet := formatter.FormatBotDetection(logs)
+
+	// 工作表與 BotStats 的格式化結果完全相同
+	assert.Equal(t, formatter.FormatBotIPStats(&statistics.BotStats), sheet)
+
+	// 工作表的請求次數總和等於 BotStats 的機器人請求數
+	total := 0
+	types := make(map[string]string)
+	for _, row := range sheet[1:] {
+		count, err := strconv.Atoi(row[3])
+		require.NoError(t, err)
+		total += count
+		types[row[0]] = row[1]
+	}
+	assert.Equal(t, statistics.BotStats.BotRequests, total)
+
+	// 舊版匯出器遺漏的類型都應被偵測
+	assert.Len(t, types, 7, "除了瀏覽器以外的 IP 都應被識別為機器人")
+	assert.Equal(t, "搜尋引擎", types["10.0.0.1"])
+	assert.Equal(t, "爬蟲", types["10.0.0.2"])
+	assert.Equal(t, "爬蟲", types["10.0.0.3"])
+	assert.Equal(t, "爬蟲", types["10.0.0.4"])
+	assert.Equal(t, "SEO 工具", types["10.0.0.5"])
+	assert.Equal(t, "安全掃描", types["10.0.0.6"])
+	assert.Equal(t, "搜尋引擎", types["10.0.0.7"])
+
+	// 每種類型的 IP 數量與 BotTypes 的類型一致
+	for _, botType := range types {
+		assert.Contains(t, statistics.BotStats.BotTypes, botType)
+	}
+}
+
+// TestFormatBotDetectionCustomRules 測試機器人偵測工作表使用設定的規則集合
+func TestFormatBotDetectionCustomRules(t *testing.T) {
+	logs := []*models.LogEntry{
+		{IP: "10.0.0.1", UserAgent: "Zorblax/1.0", LineNumber: 1},
+	}
+
+	formatter := NewFormatter()
+	require.Len(t, formatter.FormatBotDetection(logs), 1, "預設規則不認得自訂代理程式")
+
+	rules := stats.NewBotRuleSet()
+	require.NoError(t, rules.Add(stats.BotRule{Name: "Zorblax", Category: "內部工具", Substring: "zorblax", Priority: 100}))
+	formatter.SetBotDetection(rules, nil)
+
+	result := formatter.FormatBotDetection(logs)
+	require.Len(t, result, 2)
+	assert.Equal(t, "10.0.0.1", result[1][0])
+	assert.Equal(t, "內部工具", result[1][1])
+}
+
+// TestFormatBotAgents 測試機器人代理程式統計的格式化
+func TestFormatBotAgents(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	logs := []*models.LogEntry{
+		{IP: "66.249.66.1", UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1)", ResponseBytes: 1024, Timestamp: ts},
+		{IP: "66.249.66.2", UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1)", ResponseBytes: 1024, Timestamp: ts.Add(time.Minute)},
+		{IP: "20.15.240.64", UserAgent: "Mozilla/5.0 (compatible; GPTBot/1.2; +https://openai.com/gptbot)", ResponseBytes: 10, Timestamp: ts},
+	}
+
+	formatter := NewFormatter()
+	botStats := formatter.botStatsFromLogs(logs)
+	result := formatter.FormatBotAgents(&botStats)
+
+	require.Len(t, result, 3, "應該有標題行和兩個代理程式")
+	assert.Equal(t, []string{
+		"Googlebot", "Google", "搜尋引擎", "2.1", "2", "66.67%", "2.0 KB", "2",
+		"2024-01-01 08:00:00", "2024-01-01 08:01:00",
+	}, result[1])
+	assert.Equal(t, "GPTBot", result[2][0])
+	assert.Equal(t, "OpenAI", result[2][1])
+}
+
+// TestFormatSpoofedCrawlers 測試冒充爬蟲來源的格式化
+func TestFormatSpoofedCrawlers(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	botStats := stats.BotStats{
+		SpoofedCrawlers: []stats.SpoofedCrawlerStat{{
+			IP:          "203.0.113.9",
+			ClaimedName: "Googlebot",
+			Category:    "搜尋引擎",
+			Count:       2,
+			FirstSeen:   ts,
+			LastSeen:    ts.Add(time.Minute),
+		}},
+	}
+
+	formatter := NewFormatter()
+	result := formatter.FormatSpoofedCrawlers(&botStats)
+
+	require.Len(t, result, 2, "應該有標題行和一個冒充來源")
+	assert.Equal(t, []string{
+		"203.0.113.9", "Googlebot", "搜尋引擎", "2", "2024-01-01 08:00:00", "2024-01-01 08:01:00",
+	}, result[1])
+	assert.Len(t, formatter.FormatSpoofedCrawlers(nil), 1)
+}
+
+// TestFormatSuspectedBots 測試行為像機器人的用戶端格式化
+func TestFormatSuspectedBots(t *testing.T) {
+	chrome := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	logs := []*models.LogEntry{
+		{IP: "203.0.113.10", UserAgent: chrome, Method: "GET", URL: "/robots.txt", StatusCode: 200, Timestamp: base},
+	}
+	for i := 1; i <= 30; i++ {
+		logs = append(logs, &models.LogEntry{
+			IP:         "203.0.113.10",
+			UserAgent:  chrome,
+			Method:     "GET",
+			URL:        fmt.Sprintf("/product/%d", i),
+			StatusCode: 200,
+			Timestamp:  base.Add(time.Duration(i) * 2 * time.Second),
+		})
+	}
+
+	formatter := NewFormatter()
+	botStats := formatter.botStatsFromLogs(logs)
+	result := formatter.FormatSuspectedBots(&botStats)
+
+	require.Len(t, result, 2, "應該有標題行和一個疑似機器人")
+	row := result[1]
+	require.Len(t, row, 6)
+	assert.Equal(t, "203.0.113.10", row[0])
+	assert.Equal(t, chrome, row[1])
+	assert.Equal(t, "31", row[4])
+	assert.Contains(t, row[5], "讀取 robots.txt (+10)")
+	assert.Len(t, formatter.FormatSuspectedBots(nil), 1)
+}
+
 // TestTimeFormatting 測試時間格式化
 func TestTimeFormatting(t *testing.T) {
 	// 測試不同時區的時間
@@ -6,6 +6,12 @@ import (
 	"path/filepath"
 	"time"
 
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/anomaly"
+	"access-log-analyzer/internal/cacheanalysis"
+	"access-log-analyzer/internal/compare"
+	"access-log-analyzer/internal/funnel"
+	"access-log-analyzer/internal/geoip"
 	"access-log-analyzer/internal/models"
 	"access-log-analyzer/internal/stats"
 	"access-log-analyzer/pkg/logger"
@@ -48,6 +54,17 @@ func NewXLSXExporter() *XLSXExporter {
 	}
 }
 
+// SetGeoIP 設定 GeoIP 與 ASN 查詢，已載入資料庫時日誌條目工作表會加上地理欄位
+func (e *XLSXExporter) SetGeoIP(enricher *geoip.Enricher) {
+	e.formatter.SetGeoIP(enricher)
+}
+
+// SetBotDetection 設定機器人偵測規則與爬蟲驗證器
+// 僅在統計資料不含機器人分類（Export）時使用；ExportWithStatsStatistics 直接沿用統計資訊的 BotStats
+func (e *XLSXExporter) SetBotDetection(rules *stats.BotRuleSet, crawlers *stats.CrawlerVerifier) {
+	e.formatter.SetBotDetection(rules, crawlers)
+}
+
 // Export 執行完整的 Excel 檔案匯出
 // 包含日誌條目、統計資料和機器人偵測三個工作表
 func (e *XLSXExporter) Export(logs []*models.LogEntry, stats *models.Statistics, filePath string) (*ExportResult, error) {
@@ -67,6 +84,9 @@ func (e *XLSXExporter) Export(logs []*models.LogEntry, stats *models.Statistics,
 	// 資料驗證
 	warnings := e.formatter.ValidateData(logs, stats)
 
+	// models.Statistics 不含機器人分類，在截斷前以完整日誌計算，與統計資料涵蓋相同範圍
+	botStats := e.formatter.botStatsFromLogs(logs)
+
 	// 檢查 Excel 行數限制
 	totalRows := int64(len(logs)) + 1 // +1 for header
 	truncatedRows := int64(0)
@@ -89,7 +109,7 @@ func (e *XLSXExporter) Export(logs []*models.LogEntry, stats *models.Statistics,
 		return nil, fmt.Errorf("建立統計資料工作表失敗: %w", err)
 	}
 
-	if err := e.createBotDetectionWorksheet(f, logs); err != nil {
+	if err := e.createBotDetectionWorksheet(f, &botStats); err != nil {
 		return nil, fmt.Errorf("建立機器人偵測工作表失敗: %w", err)
 	}
 
@@ -201,20 +221,41 @@ func (e *XLSXExporter) createStatisticsWorksheet(f *excelize.File, stats *models
 }
 
 // createBotDetectionWorksheet 建立機器人偵測工作表
-func (e *XLSXExporter) createBotDetectionWorksheet(f *excelize.File, logs []*models.LogEntry) error {
+// 上方為各機器人 IP 的分類結果，下方依序為各代理程式的統計、冒充爬蟲的來源與行為像機器人的用戶端，表格間空一行
+func (e *XLSXExporter) createBotDetectionWorksheet(f *excelize.File, botStats *stats.BotStats) error {
 	sheetName := "機器人偵測"
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
 		return fmt.Errorf("建立機器人偵測工作表失敗: %w", err)
 	}
 
-	// 格式化機器人偵測資料
-	data := e.formatter.FormatBotDetection(logs)
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 11, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#D3D3D3"}, Pattern: 1},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "000000", Style: 1},
+		},
+	})
 
-	// 寫入資料
+	row := 1
+	for _, data := range [][][]string{
+		e.formatter.FormatBotIPStats(botStats),
+		e.formatter.FormatBotAgents(botStats),
+		e.formatter.FormatSpoofedCrawlers(botStats),
+		e.formatter.FormatSuspectedBots(botStats),
+	} {
+		e.writeTable(f, sheetName, row, data, headerStyle)
+		row += len(data) + 1
+	}
+
+	return nil
+}
+
+// writeTable 從指定列開始寫入表格，第一行套用標題樣式
+func (e *XLSXExporter) writeTable(f *excelize.File, sheetName string, startRow int, data [][]string, headerStyle int) {
 	for rowIdx, row := range data {
 		for colIdx, cell := range row {
-			cellRef, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx+1)
+			cellRef, err := excelize.CoordinatesToCellName(colIdx+1, startRow+rowIdx)
 			if err != nil {
 				continue
 			}
@@ -222,24 +263,12 @@ func (e *XLSXExporter) createBotDetectionWorksheet(f *excelize.File, logs []*mod
 		}
 	}
 
-	// 設定標題列樣式
 	if len(data) > 0 {
-		headerStyle, _ := f.NewStyle(&excelize.Style{
-			Font: &excelize.Font{Bold: true, Size: 11, Color: "FFFFFF"},
-			Fill: excelize.Fill{Type: "pattern", Color: []string{"#D3D3D3"}, Pattern: 1},
-			Border: []excelize.Border{
-				{Type: "bottom", Color: "000000", Style: 1},
-			},
-		})
-
-		// 套用到第一行
 		for colIdx := range data[0] {
-			cellRef, _ := excelize.CoordinatesToCellName(colIdx+1, 1)
+			cellRef, _ := excelize.CoordinatesToCellName(colIdx+1, startRow)
 			f.SetCellStyle(sheetName, cellRef, cellRef, headerStyle)
 		}
 	}
-
-	return nil
 }
 
 // writeDataWithStreaming 使用串流模式寫入大量資料
@@ -397,7 +426,8 @@ func (e *XLSXExporter) exportBotDetectionOnly(logs []*models.LogEntry, filePath
 	f.DeleteSheet(DefaultSheetName)
 
 	// 建立機器人偵測工作表
-	if err := e.createBotDetectionWorksheet(f, logs); err != nil {
+	botStats := e.formatter.botStatsFromLogs(logs)
+	if err := e.createBotDetectionWorksheet(f, &botStats); err != nil {
 		return err
 	}
 
@@ -478,8 +508,8 @@ func (e *XLSXExporter) ExportWithStatsStatistics(logs []*models.LogEntry, statsD
 		return nil, fmt.Errorf("建立統計資料工作表失敗: %w", err)
 	}
 
-	// 創建機器人偵測工作表
-	if err := e.createBotDetectionWorksheet(f, logs); err != nil {
+	// 創建機器人偵測工作表（沿用統計資訊的分類結果，與儀表板一致）
+	if err := e.createBotDetectionWorksheet(f, &statsData.BotStats); err != nil {
 		return nil, fmt.Errorf("建立機器人偵測工作表失敗: %w", err)
 	}
 
@@ -549,3 +579,127 @@ func (e *XLSXExporter) createStatsStatisticsWorksheet(f *excelize.File, statsDat
 	}
 	return e.writeDataNormal(f, sheetName, data)
 }
+
+// ExportAggregate 將聚合查詢結果匯出為單一工作表的 Excel 檔案
+// 聚合結果列數遠少於原始記錄，因此不受 MaxExcelRows 影響
+func (e *XLSXExporter) ExportAggregate(result *aggregate.Result, filePath string) (*ExportResult, error) {
+	if result == nil {
+		return nil, fmt.Errorf("聚合結果不能為空")
+	}
+	return e.exportSingleSheet("彙總查詢", e.formatter.FormatAggregateResult(result), filePath)
+}
+
+// ExportAnomalies 將流量異常偵測結果匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportAnomalies(report *anomaly.Report, filePath string) (*ExportResult, error) {
+	if report == nil {
+		return nil, fmt.Errorf("異常偵測結果不能為空")
+	}
+	return e.exportSingleSheet("異常偵測", e.formatter.FormatAnomalies(report), filePath)
+}
+
+// ExportFunnel 將漏斗分析結果匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportFunnel(report *funnel.Report, filePath string) (*ExportResult, error) {
+	if report == nil {
+		return nil, fmt.Errorf("漏斗分析結果不能為空")
+	}
+	return e.exportSingleSheet("漏斗分析", e.formatter.FormatFunnel(report), filePath)
+}
+
+// ExportReferers 將流量來源分析匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportReferers(referers *stats.RefererStatistics, filePath string) (*ExportResult, error) {
+	if referers == nil {
+		return nil, fmt.Errorf("來源分析結果不能為空")
+	}
+	return e.exportSingleSheet("來源分析", e.formatter.FormatReferers(referers), filePath)
+}
+
+// ExportNotFound 將失效連結（404/410）報表匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportNotFound(report *stats.NotFoundReport, filePath string) (*ExportResult, error) {
+	if report == nil {
+		return nil, fmt.Errorf("失效連結報表不能為空")
+	}
+	return e.exportSingleSheet("失效連結", e.formatter.FormatNotFound(report), filePath)
+}
+
+// ExportComparison 將比較結果匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportComparison(report *compare.Report, filePath string) (*ExportResult, error) {
+	if report == nil {
+		return nil, fmt.Errorf("比較結果不能為空")
+	}
+	return e.exportSingleSheet("比較", e.formatter.FormatComparison(report), filePath)
+}
+
+// ExportCacheReport 將快取分析結果匯出為單一工作表的 Excel 檔案
+func (e *XLSXExporter) ExportCacheReport(report *cacheanalysis.Report, filePath string) (*ExportResult, error) {
+	if report == nil {
+		return nil, fmt.Errorf("快取分析結果不能為空")
+	}
+	return e.exportSingleSheet("快取分析", e.formatter.FormatCacheReport(report), filePath)
+}
+
+// exportSingleSheet 將已格式化的表格（第一列為標題）寫入單一工作表並儲存
+func (e *XLSXExporter) exportSingleSheet(sheetName string, data [][]string, filePath string) (*ExportResult, error) {
+	startTime := time.Now()
+
+	if err := e.validateInputs(nil, nil, filePath); err != nil {
+		return nil, fmt.Errorf("輸入驗證失敗: %w", err)
+	}
+
+	warnings := make([]string, 0)
+	totalRecords := int64(len(data) - 1)
+
+	// 檢查 Excel 行數限制
+	truncatedRows := int64(0)
+	if int64(len(data)) > MaxExcelRows {
+		truncatedRows = int64(len(data)) - MaxExcelRows
+		data = data[:MaxExcelRows]
+		warnings = append(warnings, fmt.Sprintf("資料超過 Excel 限制，截斷了 %d 行", truncatedRows))
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if _, err := f.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("建立%s工作表失敗: %w", sheetName, err)
+	}
+
+	var err error
+	if e.streamingMode && len(data) > 1000 {
+		err = e.writeDataWithStreaming(f, sheetName, data)
+	} else {
+		err = e.writeDataNormal(f, sheetName, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("寫入%s工作表失敗: %w", sheetName, err)
+	}
+
+	f.DeleteSheet(DefaultSheetName)
+
+	if err := f.SaveAs(filePath); err != nil {
+		return nil, fmt.Errorf("儲存檔案失敗: %w", err)
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("取得檔案資訊失敗: %w", err)
+	}
+
+	duration := time.Since(startTime)
+
+	e.logger.Info().
+		Str("filePath", filePath).
+		Str("sheet", sheetName).
+		Int64("rows", totalRecords).
+		Str("duration", duration.String()).
+		Msg("工作表匯出完成")
+
+	return &ExportResult{
+		FilePath:      filePath,
+		TotalRecords:  totalRecords,
+		FileSize:      fileInfo.Size(),
+		TruncatedRows: truncatedRows,
+		Duration:      duration.String(),
+		Warnings:      warnings,
+		CreatedAt:     time.Now(),
+	}, nil
+}
@@ -7,7 +7,13 @@ import (
 	"testing"
 	"time"
 
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/anomaly"
+	"access-log-analyzer/internal/cacheanalysis"
+	"access-log-analyzer/internal/compare"
+	"access-log-analyzer/internal/funnel"
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -275,6 +281,253 @@ func TestInvalidFilePath(t *testing.T) {
 
 // Helper functions for test data
 
+// TestExportAggregate 測試聚合查詢結果的匯出
+func TestExportAggregate(t *testing.T) {
+	result := &aggregate.Result{
+		Columns:    []string{"method", "count"},
+		KeyColumns: 1,
+		Rows: []aggregate.ResultRow{
+			{Keys: []string{"GET"}, Values: []float64{3}},
+			{Keys: []string{"POST"}, Values: []float64{1}},
+		},
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "aggregate.xlsx")
+	exportResult, err := NewXLSXExporter().ExportAggregate(result, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+	assert.Equal(t, int64(2), exportResult.TotalRecords)
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"彙總查詢"}, f.GetSheetList())
+	rows, err := f.GetRows("彙總查詢")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"method", "count"}, {"GET", "3"}, {"POST", "1"}}, rows)
+}
+
+// TestExportAnomalies 測試流量異常偵測結果的匯出
+func TestExportAnomalies(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := &anomaly.Report{
+		Windows: []anomaly.Window{
+			{
+				Kind:        anomaly.KindSpike,
+				Severity:    anomaly.SeverityCritical,
+				Start:       start,
+				End:         start.Add(10 * time.Minute),
+				Buckets:     2,
+				Requests:    450,
+				Description: "請求量突增",
+				TopPaths:    []anomaly.Contributor{{Key: "/api/search", Count: 400}, {Key: "/", Count: 50}},
+				TopIPs:      []anomaly.Contributor{{Key: "203.0.113.7", Count: 400}},
+			},
+		},
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "anomalies.xlsx")
+	exportResult, err := NewXLSXExporter().ExportAnomalies(report, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+	assert.Equal(t, int64(1), exportResult.TotalRecords)
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"異常偵測"}, f.GetSheetList())
+	rows, err := f.GetRows("異常偵測")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		"2024-01-01 10:00:00", "2024-01-01 10:10:00", "流量突增", "嚴重", "2", "450", "0",
+		"請求量突增", "/api/search (400)；/ (50)", "203.0.113.7 (400)",
+	}, rows[1])
+
+	_, err = NewXLSXExporter().ExportAnomalies(nil, tempFile)
+	assert.Error(t, err)
+}
+
+// TestExportFunnel 測試漏斗分析結果與時間範圍比較的匯出
+func TestExportFunnel(t *testing.T) {
+	report := &funnel.Report{
+		Current: &funnel.Result{Steps: []funnel.StepResult{
+			{Name: "商品", Pattern: "^/products", Count: 3, ConversionRate: 100, StepConversion: 100, DropOff: 1, DropOffRate: 100.0 / 3},
+			{Name: "購物車", Pattern: "^/cart$", Count: 2, ConversionRate: 200.0 / 3, StepConversion: 200.0 / 3},
+		}},
+		Previous: &funnel.Result{Steps: []funnel.StepResult{
+			{Name: "商品", Count: 4, ConversionRate: 100},
+			{Name: "購物車", Count: 1, ConversionRate: 25},
+		}},
+		Changes: []funnel.StepChange{
+			{Name: "商品", CountDelta: -1},
+			{Name: "購物車", CountDelta: 1, ConversionDelta: 200.0/3 - 25},
+		},
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "funnel.xlsx")
+	exportResult, err := NewXLSXExporter().ExportFunnel(report, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+	assert.Equal(t, int64(2), exportResult.TotalRecords)
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"漏斗分析"}, f.GetSheetList())
+	rows, err := f.GetRows("漏斗分析")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Len(t, rows[0], 11, "有比較時間範圍時附加比較欄位")
+	assert.Equal(t, []string{"2", "購物車", "^/cart$", "2", "66.67%", "66.67%", "0", "0.00%", "1", "25.00%", "+41.67"}, rows[2])
+
+	// 沒有比較時間範圍時只有 8 欄
+	report.Previous, report.Changes = nil, nil
+	data := NewFormatter().FormatFunnel(report)
+	assert.Len(t, data[0], 8)
+	assert.Len(t, data[1], 8)
+}
+
+// TestExportReferers 測試流量來源分析的匯出
+func TestExportReferers(t *testing.T) {
+	referers := &stats.RefererStatistics{
+		Direct:   5,
+		External: 3,
+		Channels: []stats.DistributionItem{
+			{Name: stats.ChannelDirect, Count: 5, Percentage: 62.5},
+			{Name: stats.ChannelSearch, Count: 3, Percentage: 37.5},
+		},
+		Domains:         []stats.RefererDomain{{Domain: "google.com", Channel: stats.ChannelSearch, Count: 3, Percentage: 100}},
+		SearchEngines:   []stats.DistributionItem{{Name: "Google", Count: 3, Percentage: 37.5}},
+		Campaigns:       []stats.CampaignStatistics{{Source: "newsletter", Medium: "email", Campaign: "spring", Count: 2, UniqueIPs: 1}},
+		Hotlinks:        []stats.HotlinkStatistics{{Domain: "forum.other.net", Path: "/logo.png", Count: 2, Bytes: 2048}},
+		HotlinkRequests: 2,
+		HotlinkBytes:    2048,
+		SiteHosts:       []string{"example.com"},
+		SiteHostsSource: stats.SiteHostsVirtual,
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "referers.xlsx")
+	_, err := NewXLSXExporter().ExportReferers(referers, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"來源分析"}, f.GetSheetList())
+	rows, err := f.GetRows("來源分析")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"直接造訪", "5", "62.50"}, rows[1])
+	assert.Equal(t, []string{"搜尋引擎", "3", "37.50"}, rows[2])
+
+	sections := make(map[string]bool)
+	for _, row := range rows {
+		if len(row) > 0 && strings.HasPrefix(row[0], "=====") {
+			sections[row[0]] = true
+		}
+	}
+	assert.Contains(t, rows, []string{"主機", "example.com"})
+	for _, section := range []string{"===== 本站主機 =====", "===== 來源網域 =====", "===== 搜尋引擎 =====", "===== 行銷活動 =====", "===== 盜連 ====="} {
+		assert.True(t, sections[section], "應該包含區段：%s", section)
+	}
+	assert.Equal(t, []string{"forum.other.net", "/logo.png", "2", "2048"}, rows[len(rows)-1])
+
+	_, err = NewXLSXExporter().ExportReferers(nil, tempFile)
+	assert.Error(t, err)
+}
+
+// TestExportNotFound 測試失效連結報表的匯出
+func TestExportNotFound(t *testing.T) {
+	seen := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := &stats.NotFoundReport{
+		TotalRequests: 3,
+		UniquePaths:   2,
+		Paths: []stats.NotFoundPath{
+			{
+				Path: "/old-page", Count: 2, GoneCount: 1, FirstSeen: seen, LastSeen: seen.Add(time.Hour),
+				BotHits: 1, HumanHits: 1,
+				InternalReferers: []stats.DistributionItem{{Name: "https://example.com/blog", Count: 1}},
+				RedirectCount:    1, LastRedirect: seen,
+			},
+			{
+				Path: "/new-page", Count: 1, FirstSeen: seen, LastSeen: seen, HumanHits: 1,
+				RedirectedFrom: []stats.DistributionItem{{Name: "/old-page", Count: 1}},
+			},
+		},
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "not-found.xlsx")
+	exportResult, err := NewXLSXExporter().ExportNotFound(report, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+	assert.Equal(t, int64(2), exportResult.TotalRecords)
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"失效連結"}, f.GetSheetList())
+	rows, err := f.GetRows("失效連結")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{
+		"/old-page", "2", "1", "2024-01-01 10:00:00", "2024-01-01 11:00:00", "1", "1",
+		"https://example.com/blog (1)", "", "1", "2024-01-01 10:00:00",
+	}, rows[1])
+	assert.Equal(t, "/old-page (1)", rows[2][11])
+
+	_, err = NewXLSXExporter().ExportNotFound(nil, tempFile)
+	assert.Error(t, err)
+}
+
+// TestExportComparison 測試比較結果的匯出
+func TestExportComparison(t *testing.T) {
+	report := &compare.Report{
+		BeforeLabel: "昨天",
+		AfterLabel:  "今天",
+		Summary: []compare.Change{
+			{Name: "總請求數", Before: 100, After: 150, Delta: 50, RelativeChange: 50, Significant: true},
+		},
+		StatusCodes: []compare.StatusChange{
+			{
+				StatusCode: 500,
+				Count:      compare.Change{Name: "500", After: 12, Delta: 12, Significant: true},
+				Share:      compare.Change{Name: "500", After: 8, Delta: 8, Significant: true},
+			},
+		},
+		NewPaths: []compare.PathChange{
+			{
+				Path:        "/new",
+				Requests:    compare.Change{Name: "/new", After: 20, Delta: 20, Significant: true},
+				RequestTime: compare.Change{Name: "/new", After: 1500, Delta: 1500},
+			},
+		},
+		Incomplete: true,
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "comparison.xlsx")
+	exportResult, err := NewXLSXExporter().ExportComparison(report, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"比較"}, f.GetSheetList())
+	rows, err := f.GetRows("比較")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(rows)-1), exportResult.TotalRecords)
+	assert.Equal(t, []string{"指標", "昨天", "今天", "變化", "相對變化(%)", "顯著"}, rows[0])
+	assert.Equal(t, []string{"總請求數", "100", "150", "+50", "+50.00", "是"}, rows[1])
+	assert.Equal(t, []string{"500", "0", "12", "+12", "0.00", "8.00", "+8.00", "是"}, rows[5])
+	assert.Equal(t, []string{"===== 新增路徑 ====="}, rows[7])
+	assert.Equal(t, []string{"/new", "0", "20", "新增", "0.00", "0.00", "-", "1.50", "新增", "是"}, rows[9])
+	assert.Contains(t, rows[len(rows)-1][0], "路徑排名未涵蓋全部路徑")
+
+	_, err = NewXLSXExporter().ExportComparison(nil, tempFile)
+	assert.Error(t, err)
+}
+
 // createTestLogEntries 創建測試用的日誌條目
 func createTestLogEntries() []*models.LogEntry {
 	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
@@ -360,3 +613,46 @@ func createTestStatistics() *models.Statistics {
 
 	return stats
 }
+
+// TestExportCacheReport 測試快取分析結果的匯出
+func TestExportCacheReport(t *testing.T) {
+	report := &cacheanalysis.Report{
+		Paths: []cacheanalysis.PathCache{
+			{
+				Path: "/logo.png", ContentType: cacheanalysis.TypeImage, Requests: 10, FullResponses: 2, NotModified: 8,
+				NotModifiedRate: 80, AverageSize: 2000, BytesSaved: 16000,
+			},
+		},
+		ContentTypes: []cacheanalysis.TypeCache{
+			{ContentType: cacheanalysis.TypeImage, Requests: 10, FullResponses: 2, NotModified: 8, NotModifiedRate: 80, BytesTransferred: 4000, BytesSaved: 16000},
+		},
+		CacheStatus: &cacheanalysis.CacheStatusStats{
+			Requests: 4, Hits: 1, Misses: 3, HitRatio: 25,
+			Statuses: []cacheanalysis.StatusCount{{Status: "MISS", Count: 3}, {Status: "HIT", Count: 1}},
+		},
+		Recommendations: []cacheanalysis.Recommendation{
+			{Path: "/logo.png", ContentType: cacheanalysis.TypeImage, Reason: cacheanalysis.ReasonFrequentRevalidation, Message: "快取過期時間可能太短", Requests: 10},
+		},
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "cache.xlsx")
+	_, err := NewXLSXExporter().ExportCacheReport(report, tempFile)
+	require.NoError(t, err, "匯出應該成功")
+
+	f, err := excelize.OpenFile(tempFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"快取分析"}, f.GetSheetList())
+	rows, err := f.GetRows("快取分析")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/logo.png", "圖片", "10", "2", "8", "80.00%", "2000", "16000", "0", "0.00%", "0", "0", "0.00%",
+	}, rows[1])
+	assert.Equal(t, []string{"圖片", "10", "2", "8", "80.00%", "4000", "16000", "0"}, rows[5])
+	assert.Equal(t, []string{"命中率", "25.00%"}, rows[8])
+	assert.Equal(t, []string{"/logo.png", "圖片", "頻繁重新驗證", "快取過期時間可能太短", "10"}, rows[len(rows)-1])
+
+	_, err = NewXLSXExporter().ExportCacheReport(nil, tempFile)
+	assert.Error(t, err)
+}
@@ -0,0 +1,297 @@
+package filter
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// StatusCodeRange 狀態碼範圍（包含上下界）
+type StatusCodeRange struct {
+	Min int `json:"min"` // 最小狀態碼
+	Max int `json:"max"` // 最大狀態碼
+}
+
+// TimeRange 時間範圍（包含上下界）
+// 任一端為零值時表示不限制該端
+type TimeRange struct {
+	Start time.Time `json:"start"` // 開始時間
+	End   time.Time `json:"end"`   // 結束時間
+}
+
+// SizeRange 回應大小範圍（位元組，包含上下界）
+// 使用指標區分「未設定」與「0」
+type SizeRange struct {
+	Min *int64 `json:"min,omitempty"` // 最小回應大小
+	Max *int64 `json:"max,omitempty"` // 最大回應大小
+}
+
+// FilterCriteria 日誌記錄的篩選與搜尋條件
+// 對應前端 FilterPanel 的篩選欄位與 SearchBar 的搜尋欄位，所有條件之間為 AND 關係
+type FilterCriteria struct {
+	// 篩選條件
+	StatusCodes     []int            `json:"statusCodes,omitempty"`     // 特定狀態碼列表（精確匹配）
+	StatusCodeRange *StatusCodeRange `json:"statusCodeRange,omitempty"` // 狀態碼範圍
+	TimeRange       *TimeRange       `json:"timeRange,omitempty"`       // 時間範圍
+	Methods         []string         `json:"methods,omitempty"`         // HTTP 方法列表
+	SizeRange       *SizeRange       `json:"sizeRange,omitempty"`       // 回應大小範圍
+	VirtualHost     string           `json:"virtualHost,omitempty"`     // 虛擬主機（完整比對，不區分大小寫）
+	Server          string           `json:"server,omitempty"`          // 來源伺服器（完整比對，不區分大小寫）
+	MatchNone       bool             `json:"matchNone,omitempty"`       // 不符合任何記錄（前端合併的條件互相矛盾時使用）
+
+	// 搜尋條件（子字串匹配）
+	IP            string `json:"ip,omitempty"`            // IP 位址（CIDR 格式時比對網段）
+	URL           string `json:"url,omitempty"`           // URL 路徑
+	UserAgent     string `json:"userAgent,omitempty"`     // User Agent
+	User          string `json:"user,omitempty"`          // 認證使用者名稱
+	Keyword       string `json:"keyword,omitempty"`       // 通用關鍵字（搜尋所有文字欄位）
+	CaseSensitive bool   `json:"caseSensitive,omitempty"` // 是否區分大小寫（預設不區分）
+}
+
+// IsEmpty 檢查篩選條件是否為空
+// 所有條件都未設定時返回 true
+func (c *FilterCriteria) IsEmpty() bool {
+	if c == nil {
+		return true
+	}
+	return !c.MatchNone &&
+		len(c.StatusCodes) == 0 &&
+		c.StatusCodeRange == nil &&
+		c.TimeRange == nil &&
+		len(c.Methods) == 0 &&
+		c.SizeRange == nil &&
+		c.VirtualHost == "" &&
+		c.Server == "" &&
+		c.IP == "" &&
+		c.URL == "" &&
+		c.UserAgent == "" &&
+		c.User == "" &&
+		c.Keyword == ""
+}
+
+// Validate 驗證篩選條件的有效性
+// 返回 ValidationError 如果驗證失敗
+func (c *FilterCriteria) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if r := c.StatusCodeRange; r != nil {
+		if r.Min < 100 || r.Min > 599 || r.Max < 100 || r.Max > 599 {
+			return &models.ValidationError{
+				Field:   "StatusCodeRange",
+				Value:   "",
+				Message: "狀態碼範圍必須在 100-599 之間",
+			}
+		}
+		if r.Min > r.Max {
+			return &models.ValidationError{
+				Field:   "StatusCodeRange",
+				Value:   "",
+				Message: "狀態碼最小值不能大於最大值",
+			}
+		}
+	}
+
+	if r := c.TimeRange; r != nil {
+		if !r.Start.IsZero() && !r.End.IsZero() && r.Start.After(r.End) {
+			return &models.ValidationError{
+				Field:   "TimeRange",
+				Value:   "",
+				Message: "開始時間不能晚於結束時間",
+			}
+		}
+	}
+
+	if r := c.SizeRange; r != nil {
+		if (r.Min != nil && *r.Min < 0) || (r.Max != nil && *r.Max < 0) {
+			return &models.ValidationError{
+				Field:   "SizeRange",
+				Value:   "",
+				Message: "回應大小不能為負數",
+			}
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return &models.ValidationError{
+				Field:   "SizeRange",
+				Value:   "",
+				Message: "回應大小最小值不能大於最大值",
+			}
+		}
+	}
+
+	return nil
+}
+
+// Match 檢查日誌記錄是否符合所有篩選條件
+// 解析失敗的記錄一律不符合
+func (c *FilterCriteria) Match(entry *models.LogEntry) bool {
+	if entry.ParseError != "" {
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	if c.MatchNone {
+		return false
+	}
+
+	// 檢查特定狀態碼列表
+	if len(c.StatusCodes) > 0 && !containsInt(c.StatusCodes, entry.StatusCode) {
+		return false
+	}
+
+	// 檢查狀態碼範圍
+	if r := c.StatusCodeRange; r != nil {
+		if entry.StatusCode < r.Min || entry.StatusCode > r.Max {
+			return false
+		}
+	}
+
+	// 檢查時間範圍
+	if r := c.TimeRange; r != nil {
+		if !r.Start.IsZero() && entry.Timestamp.Before(r.Start) {
+			return false
+		}
+		if !r.End.IsZero() && entry.Timestamp.After(r.End) {
+			return false
+		}
+	}
+
+	// 檢查 HTTP 方法列表
+	if len(c.Methods) > 0 && !containsFold(c.Methods, entry.Method) {
+		return false
+	}
+
+	// 檢查回應大小範圍
+	if r := c.SizeRange; r != nil {
+		if r.Min != nil && entry.ResponseBytes < *r.Min {
+			return false
+		}
+		if r.Max != nil && entry.ResponseBytes > *r.Max {
+			return false
+		}
+	}
+
+	// 檢查虛擬主機
+	if c.VirtualHost != "" && !strings.EqualFold(entry.VirtualHost, c.VirtualHost) {
+		return false
+	}
+
+	// 檢查來源伺服器
+	if c.Server != "" && !strings.EqualFold(entry.Server, c.Server) {
+		return false
+	}
+
+	// 檢查各欄位的子字串搜尋
+	if c.IP != "" {
+		if network, ok := c.IPNetwork(); ok {
+			ip := net.ParseIP(entry.IP)
+			if ip == nil || !network.Contains(ip) {
+				return false
+			}
+		} else if !c.contains(entry.IP, c.IP) {
+			return false
+		}
+	}
+	if c.URL != "" && !c.contains(entry.URL, c.URL) {
+		return false
+	}
+	if c.UserAgent != "" && !c.contains(entry.UserAgent, c.UserAgent) {
+		return false
+	}
+	// 沒有使用者欄位的記錄（日誌格式未記錄 %u）不受使用者條件限制
+	if c.User != "" && entry.User != "" && !c.contains(entry.User, c.User) {
+		return false
+	}
+
+	// 檢查通用關鍵字（任一文字欄位符合即可）
+	if c.Keyword != "" {
+		fields := [...]string{
+			entry.IP, entry.User, entry.Method, entry.URL,
+			entry.Protocol, entry.Referer, entry.UserAgent,
+		}
+		matched := false
+		for _, field := range fields {
+			if c.contains(field, c.Keyword) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IPNetwork 解析 CIDR 格式的 IP 搜尋條件（例如 10.0.0.0/8）
+// IP 不是 CIDR 格式時返回 false，此時以子字串比對
+func (c *FilterCriteria) IPNetwork() (*net.IPNet, bool) {
+	if c == nil || !strings.Contains(c.IP, "/") {
+		return nil, false
+	}
+	_, network, err := net.ParseCIDR(c.IP)
+	if err != nil {
+		return nil, false
+	}
+	return network, true
+}
+
+// contains 依大小寫設定檢查子字串
+func (c *FilterCriteria) contains(field, search string) bool {
+	if c.CaseSensitive {
+		return strings.Contains(field, search)
+	}
+	return containsIgnoreCase(field, search)
+}
+
+// Apply 篩選日誌記錄並返回符合條件的索引
+// 使用索引避免複製大量 LogEntry
+func (c *FilterCriteria) Apply(entries []models.LogEntry) []int {
+	indexes := make([]int, 0)
+	for i := range entries {
+		if c.Match(&entries[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// containsIgnoreCase 不區分大小寫的子字串檢查
+// 逐一比較視窗而不建立小寫副本，避免大量記錄掃描時的記憶體配置
+func containsIgnoreCase(s, substr string) bool {
+	n := len(substr)
+	if n == 0 {
+		return true
+	}
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt 檢查整數切片是否包含指定值
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold 檢查字串切片是否包含指定值（不區分大小寫）
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
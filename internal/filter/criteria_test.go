@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFilterCriteria_Match 測試篩選條件的匹配邏輯
+func TestFilterCriteria_Match(t *testing.T) {
+	entry := &models.LogEntry{
+		IP:            "192.168.1.1",
+		Timestamp:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Method:        "GET",
+		URL:           "/API/users",
+		Protocol:      "HTTP/1.1",
+		StatusCode:    404,
+		ResponseBytes: 512,
+		Referer:       "https://example.com/",
+		UserAgent:     "Mozilla/5.0 (compatible; Googlebot/2.1)",
+		User:          "alice",
+		VirtualHost:   "www.example.com",
+		Server:        "web-01",
+	}
+	minSize, maxSize := int64(100), int64(1000)
+
+	testCases := []struct {
+		name     string
+		criteria *FilterCriteria
+		expected bool
+	}{
+		{"空條件", &FilterCriteria{}, true},
+		{"nil 條件", nil, true},
+		{"不符合任何記錄", &FilterCriteria{MatchNone: true}, false},
+		{"狀態碼列表符合", &FilterCriteria{StatusCodes: []int{404, 500}}, true},
+		{"狀態碼列表不符", &FilterCriteria{StatusCodes: []int{200}}, false},
+		{"狀態碼範圍", &FilterCriteria{StatusCodeRange: &StatusCodeRange{Min: 400, Max: 499}}, true},
+		{"時間範圍之外", &FilterCriteria{TimeRange: &TimeRange{Start: entry.Timestamp.Add(time.Hour)}}, false},
+		{"IP 網段符合", &FilterCriteria{IP: "192.168.0.0/16"}, true},
+		{"IP 網段不符", &FilterCriteria{IP: "10.0.0.0/8"}, false},
+		{"方法不區分大小寫", &FilterCriteria{Methods: []string{"get"}}, true},
+		{"大小範圍", &FilterCriteria{SizeRange: &SizeRange{Min: &minSize, Max: &maxSize}}, true},
+		{"虛擬主機不區分大小寫", &FilterCriteria{VirtualHost: "WWW.example.com"}, true},
+		{"虛擬主機須完整比對", &FilterCriteria{VirtualHost: "example.com"}, false},
+		{"來源伺服器不區分大小寫", &FilterCriteria{Server: "WEB-01"}, true},
+		{"來源伺服器須完整比對", &FilterCriteria{Server: "web"}, false},
+		{"URL 子字串", &FilterCriteria{URL: "/api/"}, true},
+		{"URL 不符", &FilterCriteria{URL: "/admin"}, false},
+		{"URL 區分大小寫", &FilterCriteria{URL: "/api/", CaseSensitive: true}, false},
+		{"IP 部分符合", &FilterCriteria{IP: "192.168"}, true},
+		{"User Agent 不區分大小寫", &FilterCriteria{UserAgent: "googlebot"}, true},
+		{"使用者", &FilterCriteria{User: "ALI"}, true},
+		{"使用者區分大小寫", &FilterCriteria{User: "ALI", CaseSensitive: true}, false},
+		{"關鍵字符合 Referer", &FilterCriteria{Keyword: "example.com"}, true},
+		{"關鍵字不符", &FilterCriteria{Keyword: "bingbot"}, false},
+		{"篩選與搜尋組合", &FilterCriteria{Methods: []string{"GET"}, Keyword: "users"}, true},
+		{"多項條件全部符合", &FilterCriteria{
+			StatusCodeRange: &StatusCodeRange{Min: 400, Max: 499},
+			Methods:         []string{"POST", "GET"},
+			TimeRange:       &TimeRange{Start: entry.Timestamp.Add(-time.Hour), End: entry.Timestamp},
+			URL:             "/api",
+			User:            "alice",
+		}, true},
+		{"多項條件其中一項不符", &FilterCriteria{
+			StatusCodeRange: &StatusCodeRange{Min: 400, Max: 499},
+			Methods:         []string{"GET"},
+			URL:             "/api",
+			User:            "bob",
+		}, false},
+		{"方法列表不符", &FilterCriteria{Methods: []string{"POST", "PUT"}}, false},
+		{"關鍵字符合協定", &FilterCriteria{Keyword: "http/1.1"}, true},
+		{"關鍵字符合 Referer 且區分大小寫", &FilterCriteria{Keyword: "https://example.com", CaseSensitive: true}, true},
+		{"關鍵字區分大小寫不符", &FilterCriteria{Keyword: "HTTPS://", CaseSensitive: true}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.criteria.Match(entry))
+		})
+	}
+
+	// 解析失敗的記錄一律不符合
+	assert.False(t, (&FilterCriteria{}).Match(&models.LogEntry{ParseError: "無法匹配 log 格式"}))
+}
+
+// TestFilterCriteria_Match_空白欄位 測試記錄缺少欄位時的匹配
+// 沒有使用者欄位的記錄（User 為空）不受使用者條件限制，大小寫不同的方法仍符合方法列表
+func TestFilterCriteria_Match_空白欄位(t *testing.T) {
+	entry := &models.LogEntry{
+		IP:         "10.0.0.1",
+		Method:     "post",
+		URL:        "/login",
+		Protocol:   "HTTP/2.0",
+		StatusCode: 200,
+	}
+
+	assert.True(t, (&FilterCriteria{User: "alice"}).Match(entry), "沒有使用者欄位的記錄不受使用者條件限制")
+	assert.False(t, (&FilterCriteria{User: "alice"}).Match(&models.LogEntry{User: "-"}), "未認證（-）的記錄不符合使用者條件")
+	assert.True(t, (&FilterCriteria{}).Match(entry))
+	assert.True(t, (&FilterCriteria{Methods: []string{"GET", "POST"}}).Match(entry), "方法不區分大小寫")
+	assert.False(t, (&FilterCriteria{Methods: []string{"GET"}}).Match(entry))
+	assert.True(t, (&FilterCriteria{Keyword: "HTTP/2"}).Match(entry), "關鍵字比對協定")
+	assert.False(t, (&FilterCriteria{Keyword: "example.com"}).Match(entry), "空白的 Referer 不符合關鍵字")
+}
+
+// TestFilterCriteria_Validate 測試篩選條件驗證
+func TestFilterCriteria_Validate(t *testing.T) {
+	negative := int64(-1)
+
+	assert.NoError(t, (&FilterCriteria{}).Validate())
+	assert.Error(t, (&FilterCriteria{StatusCodeRange: &StatusCodeRange{Min: 500, Max: 400}}).Validate())
+	assert.Error(t, (&FilterCriteria{StatusCodeRange: &StatusCodeRange{Min: 0, Max: 400}}).Validate())
+	assert.Error(t, (&FilterCriteria{SizeRange: &SizeRange{Min: &negative}}).Validate())
+	assert.Error(t, (&FilterCriteria{TimeRange: &TimeRange{
+		Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}).Validate())
+}
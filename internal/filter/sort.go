@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"sort"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 可排序的欄位名稱（與 LogEntry 的 JSON 欄位名稱一致，方便前端表格直接使用）
+const (
+	SortLineNumber    = "lineNumber"
+	SortIP            = "ip"
+	SortTimestamp     = "timestamp"
+	SortMethod        = "method"
+	SortURL           = "url"
+	SortProtocol      = "protocol"
+	SortStatusCode    = "statusCode"
+	SortResponseBytes = "responseBytes"
+	SortReferer       = "referer"
+	SortUserAgent     = "userAgent"
+	SortUser          = "user"
+	SortRequestTime   = "requestTime"
+)
+
+// SortSpec 日誌記錄的排序條件
+// Field 為空時保持原始檔案順序
+type SortSpec struct {
+	Field      string `json:"field"`      // 排序欄位
+	Descending bool   `json:"descending"` // 是否遞減排序
+}
+
+// lessFunc 比較兩筆記錄的函式
+type lessFunc func(a, b *models.LogEntry) bool
+
+// sortLessFuncs 各排序欄位對應的比較函式
+var sortLessFuncs = map[string]lessFunc{
+	SortLineNumber:    func(a, b *models.LogEntry) bool { return a.LineNumber < b.LineNumber },
+	SortIP:            func(a, b *models.LogEntry) bool { return a.IP < b.IP },
+	SortTimestamp:     func(a, b *models.LogEntry) bool { return a.Timestamp.Before(b.Timestamp) },
+	SortMethod:        func(a, b *models.LogEntry) bool { return a.Method < b.Method },
+	SortURL:           func(a, b *models.LogEntry) bool { return a.URL < b.URL },
+	SortProtocol:      func(a, b *models.LogEntry) bool { return a.Protocol < b.Protocol },
+	SortStatusCode:    func(a, b *models.LogEntry) bool { return a.StatusCode < b.StatusCode },
+	SortResponseBytes: func(a, b *models.LogEntry) bool { return a.ResponseBytes < b.ResponseBytes },
+	SortReferer:       func(a, b *models.LogEntry) bool { return a.Referer < b.Referer },
+	SortUserAgent:     func(a, b *models.LogEntry) bool { return a.UserAgent < b.UserAgent },
+	SortUser:          func(a, b *models.LogEntry) bool { return a.User < b.User },
+	SortRequestTime:   func(a, b *models.LogEntry) bool { return a.RequestTime < b.RequestTime },
+}
+
+// IsEmpty 檢查是否未指定排序
+func (s *SortSpec) IsEmpty() bool {
+	return s == nil || s.Field == ""
+}
+
+// Validate 驗證排序欄位是否支援
+// 返回 ValidationError 如果驗證失敗
+func (s *SortSpec) Validate() error {
+	if s.IsEmpty() {
+		return nil
+	}
+	if _, ok := sortLessFuncs[s.Field]; !ok {
+		return &models.ValidationError{
+			Field:   "Sort",
+			Value:   s.Field,
+			Message: "不支援的排序欄位",
+		}
+	}
+	return nil
+}
+
+// SortIndexes 依排序條件就地排序記錄索引
+// 使用穩定排序，相同值的記錄保持原始檔案順序
+func (s *SortSpec) SortIndexes(entries []models.LogEntry, indexes []int) {
+	if s.IsEmpty() {
+		return
+	}
+	less, ok := sortLessFuncs[s.Field]
+	if !ok {
+		return
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := &entries[indexes[i]], &entries[indexes[j]]
+		if s.Descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
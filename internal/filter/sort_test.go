@@ -0,0 +1,44 @@
+package filter
+
+import (
+	"testing"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSortSpec_SortIndexes 測試依欄位排序記錄索引
+func TestSortSpec_SortIndexes(t *testing.T) {
+	entries := []models.LogEntry{
+		{LineNumber: 1, IP: "10.0.0.2", StatusCode: 404, ResponseBytes: 300},
+		{LineNumber: 2, IP: "10.0.0.1", StatusCode: 200, ResponseBytes: 100},
+		{LineNumber: 3, IP: "10.0.0.3", StatusCode: 200, ResponseBytes: 200},
+	}
+
+	testCases := []struct {
+		name     string
+		spec     *SortSpec
+		expected []int
+	}{
+		{"未指定排序保持原順序", nil, []int{0, 1, 2}},
+		{"IP 遞增", &SortSpec{Field: SortIP}, []int{1, 0, 2}},
+		{"回應大小遞減", &SortSpec{Field: SortResponseBytes, Descending: true}, []int{0, 2, 1}},
+		{"狀態碼遞增且穩定", &SortSpec{Field: SortStatusCode}, []int{1, 2, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexes := []int{0, 1, 2}
+			tc.spec.SortIndexes(entries, indexes)
+			assert.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+// TestSortSpec_Validate 測試排序欄位驗證
+func TestSortSpec_Validate(t *testing.T) {
+	assert.NoError(t, (*SortSpec)(nil).Validate())
+	assert.NoError(t, (&SortSpec{Field: SortTimestamp}).Validate())
+	assert.Error(t, (&SortSpec{Field: "rawLine"}).Validate())
+}
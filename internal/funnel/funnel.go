@@ -0,0 +1,262 @@
+// Package funnel 分析訪客依序經過指定頁面的轉換漏斗
+// 以工作階段或訪客為單位，計算每一步的到達數與流失率，並可比較兩個時間範圍
+package funnel
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/session"
+	"access-log-analyzer/internal/stats"
+	"access-log-analyzer/pkg/logger"
+)
+
+// 漏斗的計算單位
+const (
+	KeyBySession = "session" // 同一工作階段內完成的步驟
+	KeyByVisitor = "visitor" // 同一訪客跨工作階段完成的步驟
+)
+
+// maxSteps 漏斗步驟數上限
+const maxSteps = 20
+
+// Step 漏斗的單一步驟
+type Step struct {
+	Name    string `json:"name"`    // 顯示名稱，空白時使用 Pattern
+	Pattern string `json:"pattern"` // 正規表示式，比對不含查詢字串的路徑，例如 "^/products"
+}
+
+// Definition 漏斗定義
+type Definition struct {
+	Steps      []Step          `json:"steps"`      // 依序的步驟（至少 2 步）
+	MaxStepGap string          `json:"maxStepGap"` // 相鄰步驟間允許的最長間隔（預設 30m）
+	KeyBy      string          `json:"keyBy"`      // 計算單位：session（預設）或 visitor
+	Session    session.Options `json:"session"`    // 工作階段重建參數（逾時、是否包含機器人等）
+}
+
+// Report 漏斗分析結果
+type Report struct {
+	Definition Definition   `json:"definition"` // 實際使用的定義（已補上預設值）
+	Current    *Result      `json:"current"`    // 目前時間範圍的結果
+	Previous   *Result      `json:"previous"`   // 比較時間範圍的結果（未比較時為 nil）
+	Changes    []StepChange `json:"changes"`    // 各步驟的變化（未比較時為 nil）
+}
+
+// Result 單一時間範圍的漏斗結果
+type Result struct {
+	Range             aggregate.TimeRange `json:"range"`             // 時間範圍
+	Units             int                 `json:"units"`             // 範圍內的工作階段或訪客數
+	Steps             []StepResult        `json:"steps"`             // 各步驟的結果
+	OverallConversion float64             `json:"overallConversion"` // 完成最後一步佔進入第一步的比例（百分比）
+}
+
+// StepResult 單一步驟的結果
+type StepResult struct {
+	Name           string  `json:"name"`           // 步驟名稱
+	Pattern        string  `json:"pattern"`        // 比對模式
+	Count          int     `json:"count"`          // 到達此步驟的工作階段或訪客數
+	ConversionRate float64 `json:"conversionRate"` // 佔進入第一步的比例（百分比）
+	StepConversion float64 `json:"stepConversion"` // 佔到達上一步的比例（百分比，第一步為 100）
+	DropOff        int     `json:"dropOff"`        // 到達此步驟但未到達下一步的數量
+	DropOffRate    float64 `json:"dropOffRate"`    // 流失率（百分比）
+}
+
+// StepChange 比較兩個時間範圍的單一步驟變化
+type StepChange struct {
+	Name             string  `json:"name"`             // 步驟名稱
+	CountDelta       int     `json:"countDelta"`       // 到達數變化（目前 - 比較）
+	ConversionDelta  float64 `json:"conversionDelta"`  // 相對第一步的轉換率變化（百分點）
+	DropOffRateDelta float64 `json:"dropOffRateDelta"` // 流失率變化（百分點）
+}
+
+// Analyzer 漏斗分析器
+type Analyzer struct {
+	sessionizer *session.Sessionizer
+	log         *logger.Logger
+}
+
+// NewAnalyzer 建立漏斗分析器，rules 為 nil 時使用預設的機器人規則
+func NewAnalyzer(rules *stats.BotRuleSet) *Analyzer {
+	return &Analyzer{
+		sessionizer: session.NewSessionizer(rules),
+		log:         logger.Get().WithModule("funnel"),
+	}
+}
+
+// compiledStep 已編譯的步驟
+type compiledStep struct {
+	Step
+	pattern *regexp.Regexp
+}
+
+// prepare 補上預設值並驗證漏斗定義
+func prepare(def Definition) (Definition, []compiledStep, time.Duration, error) {
+	if def.MaxStepGap == "" {
+		def.MaxStepGap = "30m"
+	}
+	if def.KeyBy == "" {
+		def.KeyBy = KeyBySession
+	}
+
+	if len(def.Steps) < 2 || len(def.Steps) > maxSteps {
+		return def, nil, 0, &models.ValidationError{
+			Field:   "Steps",
+			Value:   fmt.Sprint(len(def.Steps)),
+			Message: fmt.Sprintf("漏斗需要 2 到 %d 個步驟", maxSteps),
+		}
+	}
+	if def.KeyBy != KeyBySession && def.KeyBy != KeyByVisitor {
+		return def, nil, 0, &models.ValidationError{Field: "KeyBy", Value: def.KeyBy, Message: "計算單位必須是 session 或 visitor"}
+	}
+	gap, err := time.ParseDuration(def.MaxStepGap)
+	if err != nil || gap <= 0 {
+		return def, nil, 0, &models.ValidationError{Field: "MaxStepGap", Value: def.MaxStepGap, Message: "無效的步驟間隔"}
+	}
+
+	steps := make([]compiledStep, len(def.Steps))
+	def.Steps = append([]Step(nil), def.Steps...)
+	for i, step := range def.Steps {
+		pattern, err := regexp.Compile(step.Pattern)
+		if err != nil || step.Pattern == "" {
+			return def, nil, 0, &models.ValidationError{
+				Field:   fmt.Sprintf("Steps[%d].Pattern", i),
+				Value:   step.Pattern,
+				Message: "無效的正規表示式",
+			}
+		}
+		if step.Name == "" {
+			def.Steps[i].Name = step.Pattern
+		}
+		steps[i] = compiledStep{Step: def.Steps[i], pattern: pattern}
+	}
+	return def, steps, gap, nil
+}
+
+// Analyze 計算漏斗；previous 不為 nil 時另計算比較時間範圍並列出各步驟的變化
+func (a *Analyzer) Analyze(entries []models.LogEntry, def Definition, current aggregate.TimeRange, previous *aggregate.TimeRange) (*Report, error) {
+	def, steps, gap, err := prepare(def)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Definition: def}
+	if report.Current, err = a.run(entries, def, steps, gap, current); err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		if report.Previous, err = a.run(entries, def, steps, gap, *previous); err != nil {
+			return nil, err
+		}
+		report.Changes = compare(report.Current, report.Previous)
+	}
+
+	a.log.Info().
+		Int("steps", len(steps)).
+		Str("keyBy", def.KeyBy).
+		Int("units", report.Current.Units).
+		Float64("conversion", report.Current.OverallConversion).
+		Msg("漏斗分析完成")
+	return report, nil
+}
+
+// run 計算單一時間範圍的漏斗
+func (a *Analyzer) run(entries []models.LogEntry, def Definition, steps []compiledStep, gap time.Duration, rng aggregate.TimeRange) (*Result, error) {
+	var inRange func(*models.LogEntry) bool
+	if !rng.IsZero() {
+		inRange = func(entry *models.LogEntry) bool { return rng.Contains(entry.Timestamp) }
+	}
+
+	sessions, _, err := a.sessionizer.BuildWhere(entries, def.Session, inRange)
+	if err != nil {
+		return nil, err
+	}
+
+	// 依計算單位整理頁面瀏覽（工作階段已依開始時間排序，同一訪客串接後仍為時間順序）
+	units := make([][]session.PageView, 0, len(sessions))
+	if def.KeyBy == KeyByVisitor {
+		visitorIndex := make(map[string]int)
+		for i := range sessions {
+			idx, exists := visitorIndex[sessions[i].Key]
+			if !exists {
+				units = append(units, nil)
+				idx = len(units) - 1
+				visitorIndex[sessions[i].Key] = idx
+			}
+			units[idx] = append(units[idx], sessions[i].Pages...)
+		}
+	} else {
+		for i := range sessions {
+			units = append(units, sessions[i].Pages)
+		}
+	}
+
+	counts := make([]int, len(steps))
+	for _, pages := range units {
+		for step := 0; step < furthestStep(pages, steps, gap); step++ {
+			counts[step]++
+		}
+	}
+
+	result := &Result{Range: rng, Units: len(units), Steps: make([]StepResult, len(steps))}
+	for i, step := range steps {
+		sr := StepResult{Name: step.Name, Pattern: step.Pattern, Count: counts[i], StepConversion: 100}
+		if counts[0] > 0 {
+			sr.ConversionRate = float64(counts[i]) / float64(counts[0]) * 100
+		}
+		if i > 0 && counts[i-1] > 0 {
+			sr.StepConversion = float64(counts[i]) / float64(counts[i-1]) * 100
+		} else if i > 0 {
+			sr.StepConversion = 0
+		}
+		if i+1 < len(steps) {
+			sr.DropOff = counts[i] - counts[i+1]
+			if counts[i] > 0 {
+				sr.DropOffRate = float64(sr.DropOff) / float64(counts[i]) * 100
+			}
+		}
+		result.Steps[i] = sr
+	}
+	result.OverallConversion = result.Steps[len(steps)-1].ConversionRate
+	return result, nil
+}
+
+// furthestStep 返回依序完成的步驟數
+// 記錄每一步最近一次到達的時間：越晚到達，下一步的間隔限制越寬鬆，因此不會錯過可完成的路徑
+func furthestStep(pages []session.PageView, steps []compiledStep, gap time.Duration) int {
+	reached := make([]time.Time, len(steps))
+	furthest := 0
+	for _, page := range pages {
+		// 由後往前檢查，避免同一個頁面瀏覽同時推進兩步
+		for k := len(steps) - 1; k >= 0; k-- {
+			if !steps[k].pattern.MatchString(page.Path) {
+				continue
+			}
+			if k > 0 && (reached[k-1].IsZero() || page.Timestamp.Sub(reached[k-1]) > gap) {
+				continue
+			}
+			reached[k] = page.Timestamp
+			if k+1 > furthest {
+				furthest = k + 1
+			}
+		}
+	}
+	return furthest
+}
+
+// compare 計算兩個時間範圍各步驟的變化
+func compare(current, previous *Result) []StepChange {
+	changes := make([]StepChange, len(current.Steps))
+	for i := range current.Steps {
+		cur, prev := current.Steps[i], previous.Steps[i]
+		changes[i] = StepChange{
+			Name:             cur.Name,
+			CountDelta:       cur.Count - prev.Count,
+			ConversionDelta:  cur.ConversionRate - prev.ConversionRate,
+			DropOffRateDelta: cur.DropOffRate - prev.DropOffRate,
+		}
+	}
+	return changes
+}
@@ -0,0 +1,179 @@
+package funnel
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+var testBase = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// journey 建立單一訪客依序瀏覽的頁面（每頁間隔 step）
+func journey(ip string, start time.Duration, step time.Duration, paths ...string) []models.LogEntry {
+	entries := make([]models.LogEntry, 0, len(paths))
+	for i, path := range paths {
+		entries = append(entries, models.LogEntry{
+			IP:         ip,
+			UserAgent:  chrome,
+			Timestamp:  testBase.Add(start + time.Duration(i)*step),
+			Method:     "GET",
+			URL:        path,
+			StatusCode: 200,
+		})
+	}
+	return entries
+}
+
+// checkoutFunnel 商品 -> 購物車 -> 結帳 -> 完成訂單
+func checkoutFunnel() Definition {
+	return Definition{
+		Steps: []Step{
+			{Name: "商品", Pattern: "^/products"},
+			{Name: "購物車", Pattern: "^/cart$"},
+			{Name: "結帳", Pattern: "^/checkout"},
+			{Pattern: "^/order/complete$"},
+		},
+	}
+}
+
+// newTestEntries 建立漏斗測試用的日誌記錄
+func newTestEntries() []models.LogEntry {
+	var entries []models.LogEntry
+	// 完成整個流程（中間穿插其他頁面與查詢字串）
+	entries = append(entries, journey("10.0.0.1", 0, time.Minute, "/", "/products/42?ref=home", "/about", "/cart", "/checkout", "/order/complete")...)
+	// 加入購物車後離開
+	entries = append(entries, journey("10.0.0.2", 0, time.Minute, "/products", "/cart")...)
+	// 順序錯誤：先結帳再看商品，只算到第一步
+	entries = append(entries, journey("10.0.0.3", 0, time.Minute, "/checkout", "/products/1")...)
+	// 沒有進入漏斗
+	entries = append(entries, journey("10.0.0.4", 0, time.Minute, "/", "/about")...)
+	// 第二天：兩個訪客都只看商品
+	entries = append(entries, journey("10.0.0.5", 24*time.Hour, time.Minute, "/products")...)
+	entries = append(entries, journey("10.0.0.6", 24*time.Hour, time.Minute, "/products", "/cart")...)
+	return entries
+}
+
+// counts 取出各步驟的到達數
+func counts(result *Result) []int {
+	values := make([]int, 0, len(result.Steps))
+	for _, step := range result.Steps {
+		values = append(values, step.Count)
+	}
+	return values
+}
+
+// TestAnalyze_每步到達數與流失率 測試各步驟的到達數、轉換率與流失率
+func TestAnalyze_每步到達數與流失率(t *testing.T) {
+	day1 := aggregate.TimeRange{Start: testBase, End: testBase.Add(24 * time.Hour)}
+	report, err := NewAnalyzer(nil).Analyze(newTestEntries(), checkoutFunnel(), day1, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "^/order/complete$", report.Definition.Steps[3].Name, "未命名的步驟使用比對模式")
+	assert.Equal(t, "30m", report.Definition.MaxStepGap)
+	assert.Equal(t, KeyBySession, report.Definition.KeyBy)
+	assert.Nil(t, report.Previous)
+	assert.Nil(t, report.Changes)
+
+	result := report.Current
+	assert.Equal(t, 4, result.Units)
+	assert.Equal(t, []int{3, 2, 1, 1}, counts(result))
+
+	cart := result.Steps[1]
+	assert.Equal(t, "購物車", cart.Name)
+	assert.InDelta(t, 200.0/3, cart.ConversionRate, 0.001)
+	assert.InDelta(t, 200.0/3, cart.StepConversion, 0.001)
+	assert.Equal(t, 1, cart.DropOff)
+	assert.InDelta(t, 50.0, cart.DropOffRate, 0.001)
+
+	assert.Equal(t, 100.0, result.Steps[0].StepConversion)
+	assert.Zero(t, result.Steps[3].DropOff, "最後一步沒有流失")
+	assert.InDelta(t, 100.0/3, result.OverallConversion, 0.001)
+}
+
+// TestAnalyze_步驟間隔 測試超過步驟間隔不算完成下一步
+func TestAnalyze_步驟間隔(t *testing.T) {
+	entries := journey("10.0.0.1", 0, 10*time.Minute, "/products", "/cart", "/checkout")
+	def := checkoutFunnel()
+	def.MaxStepGap = "5m"
+
+	report, err := NewAnalyzer(nil).Analyze(entries, def, aggregate.TimeRange{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 0, 0}, counts(report.Current))
+
+	// 重新瀏覽商品頁後在間隔內加入購物車
+	entries = append(entries, journey("10.0.0.1", 25*time.Minute, 2*time.Minute, "/products/9", "/cart")...)
+	report, err = NewAnalyzer(nil).Analyze(entries, def, aggregate.TimeRange{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 1, 0, 0}, counts(report.Current))
+}
+
+// TestAnalyze_以訪客計算 測試跨工作階段完成的流程
+func TestAnalyze_以訪客計算(t *testing.T) {
+	entries := journey("10.0.0.1", 0, time.Minute, "/products", "/cart")
+	entries = append(entries, journey("10.0.0.1", 2*time.Hour, time.Minute, "/checkout", "/order/complete")...)
+
+	def := checkoutFunnel()
+	def.MaxStepGap = "3h"
+	report, err := NewAnalyzer(nil).Analyze(entries, def, aggregate.TimeRange{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Current.Units)
+	assert.Equal(t, []int{1, 1, 0, 0}, counts(report.Current), "工作階段逾時後重新計算")
+
+	def.KeyBy = KeyByVisitor
+	report, err = NewAnalyzer(nil).Analyze(entries, def, aggregate.TimeRange{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Current.Units)
+	assert.Equal(t, []int{1, 1, 1, 1}, counts(report.Current))
+}
+
+// TestAnalyze_比較時間範圍 測試兩個時間範圍的比較
+func TestAnalyze_比較時間範圍(t *testing.T) {
+	day1 := aggregate.TimeRange{Start: testBase, End: testBase.Add(24 * time.Hour)}
+	day2 := aggregate.TimeRange{Start: testBase.Add(24 * time.Hour), End: testBase.Add(48 * time.Hour)}
+
+	report, err := NewAnalyzer(nil).Analyze(newTestEntries(), checkoutFunnel(), day2, &day1)
+	require.NoError(t, err)
+	require.NotNil(t, report.Previous)
+
+	assert.Equal(t, []int{2, 1, 0, 0}, counts(report.Current))
+	assert.Equal(t, []int{3, 2, 1, 1}, counts(report.Previous))
+	require.Len(t, report.Changes, 4)
+	assert.Equal(t, -1, report.Changes[0].CountDelta)
+	assert.InDelta(t, 50.0-200.0/3, report.Changes[1].ConversionDelta, 0.001)
+	assert.InDelta(t, 100.0-50.0, report.Changes[1].DropOffRateDelta, 0.001)
+	assert.InDelta(t, -100.0/3, report.Changes[3].ConversionDelta, 0.001)
+}
+
+// TestAnalyze_無效定義 測試漏斗定義驗證
+func TestAnalyze_無效定義(t *testing.T) {
+	valid := checkoutFunnel().Steps
+	testCases := []struct {
+		name  string
+		def   Definition
+		field string
+	}{
+		{"步驟過少", Definition{Steps: valid[:1]}, "Steps"},
+		{"無效的正規表示式", Definition{Steps: []Step{{Pattern: "^/a"}, {Pattern: "("}}}, "Steps[1].Pattern"},
+		{"空白模式", Definition{Steps: []Step{{Pattern: ""}, {Pattern: "^/a"}}}, "Steps[0].Pattern"},
+		{"無效間隔", Definition{Steps: valid, MaxStepGap: "abc"}, "MaxStepGap"},
+		{"無效計算單位", Definition{Steps: valid, KeyBy: "ip"}, "KeyBy"},
+		{"無效工作階段參數", Definition{Steps: valid, Session: session.Options{Timeout: "abc"}}, "Timeout"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAnalyzer(nil).Analyze(newTestEntries(), tc.def, aggregate.TimeRange{}, nil)
+			var validationErr *models.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+}
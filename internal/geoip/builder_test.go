@@ -0,0 +1,289 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateTestdata = flag.Bool("update", false, "重新產生 testdata 中的 .mmdb 檔案")
+
+// testNetwork 測試資料庫中的單一網段
+type testNetwork struct {
+	cidr   string
+	record map[string]any
+}
+
+// trieNode 建立搜尋樹用的節點
+type trieNode struct {
+	children [2]*trieNode
+	data     [2]int // 資料索引 + 1，0 表示沒有資料
+}
+
+// buildMMDB 建立測試用的 MaxMind DB
+// IPv6 資料庫中的 IPv4 網段放在 ::/96 之下，與官方資料庫相同
+func buildMMDB(t testing.TB, databaseType string, ipVersion, recordSize int, networks []testNetwork) []byte {
+	t.Helper()
+
+	root := &trieNode{}
+	for i, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		require.NoError(t, err)
+		ones, _ := ipNet.Mask.Size()
+
+		address := []byte(ipNet.IP.To16())
+		if v4 := ipNet.IP.To4(); v4 != nil {
+			if ipVersion == 4 {
+				address = v4
+			} else {
+				address = append(make([]byte, 12), v4...)
+				ones += 96
+			}
+		}
+
+		node := root
+		for bit := 0; bit < ones; bit++ {
+			b := (address[bit/8] >> (7 - uint(bit%8))) & 1
+			if bit == ones-1 {
+				node.data[b] = i + 1
+				break
+			}
+			if node.children[b] == nil {
+				node.children[b] = &trieNode{}
+			}
+			node = node.children[b]
+		}
+	}
+
+	// 依廣度優先編號節點
+	nodes := []*trieNode{root}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil {
+				nodes = append(nodes, child)
+			}
+		}
+	}
+	index := make(map[*trieNode]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+
+	// 資料區
+	var data bytes.Buffer
+	offsets := make([]int, len(networks))
+	for i, network := range networks {
+		offsets[i] = data.Len()
+		encodeValue(&data, network.record)
+	}
+
+	// 搜尋樹
+	nodeCount := len(nodes)
+	var tree bytes.Buffer
+	for _, node := range nodes {
+		var records [2]uint32
+		for b := 0; b < 2; b++ {
+			switch {
+			case node.children[b] != nil:
+				records[b] = uint32(index[node.children[b]])
+			case node.data[b] != 0:
+				records[b] = uint32(nodeCount + dataSectionSeparator + offsets[node.data[b]-1])
+			default:
+				records[b] = uint32(nodeCount)
+			}
+		}
+		tree.Write(encodeNode(recordSize, records[0], records[1]))
+	}
+
+	var out bytes.Buffer
+	out.Write(tree.Bytes())
+	out.Write(make([]byte, dataSectionSeparator))
+	out.Write(data.Bytes())
+	out.Write(metadataMarker)
+	encodeValue(&out, map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(1704067200),
+		"database_type":               databaseType,
+		"description":                 map[string]any{"en": "access-log-analyzer test database"},
+		"ip_version":                  uint16(ipVersion),
+		"languages":                   []any{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(recordSize),
+	})
+	return out.Bytes()
+}
+
+// encodeNode 依記錄大小編碼單一節點
+func encodeNode(recordSize int, left, right uint32) []byte {
+	switch recordSize {
+	case 24:
+		return []byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)}
+	case 28:
+		return []byte{
+			byte(left >> 16), byte(left >> 8), byte(left),
+			byte((left>>24)&0x0F)<<4 | byte((right>>24)&0x0F),
+			byte(right >> 16), byte(right >> 8), byte(right),
+		}
+	default:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint32(b[0:4], left)
+		binary.BigEndian.PutUint32(b[4:8], right)
+		return b
+	}
+}
+
+// encodeControl 寫入控制位元組與大小
+func encodeControl(buf *bytes.Buffer, dataType, size int) {
+	var first byte
+	extended := dataType > 7
+	if !extended {
+		first = byte(dataType) << 5
+	}
+
+	var extra []byte
+	switch {
+	case size < 29:
+		first |= byte(size)
+	case size < 285:
+		first |= 29
+		extra = []byte{byte(size - 29)}
+	case size < 65821:
+		first |= 30
+		extra = []byte{byte((size - 285) >> 8), byte(size - 285)}
+	default:
+		first |= 31
+		n := size - 65821
+		extra = []byte{byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+
+	buf.WriteByte(first)
+	if extended {
+		buf.WriteByte(byte(dataType - 7))
+	}
+	buf.Write(extra)
+}
+
+// encodeUint 以最少位元組編碼無號整數
+func encodeUint(buf *bytes.Buffer, dataType int, value uint64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, value)
+	b = bytes.TrimLeft(b, "\x00")
+	encodeControl(buf, dataType, len(b))
+	buf.Write(b)
+}
+
+// encodeValue 編碼資料區的值
+func encodeValue(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case string:
+		encodeControl(buf, typeString, len(v))
+		buf.WriteString(v)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		encodeControl(buf, typeMap, len(v))
+		for _, key := range keys {
+			encodeValue(buf, key)
+			encodeValue(buf, v[key])
+		}
+	case []any:
+		encodeControl(buf, typeArray, len(v))
+		for _, item := range v {
+			encodeValue(buf, item)
+		}
+	case uint16:
+		encodeUint(buf, typeUint16, uint64(v))
+	case uint32:
+		encodeUint(buf, typeUint32, uint64(v))
+	case uint64:
+		encodeUint(buf, typeUint64, v)
+	case float64:
+		encodeControl(buf, typeDouble, 8)
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		buf.Write(b)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		encodeControl(buf, typeBool, size)
+	default:
+		panic("不支援的測試資料類型")
+	}
+}
+
+// country 建立國家資料
+func country(code, name string) map[string]any {
+	return map[string]any{"iso_code": code, "names": map[string]any{"en": name}}
+}
+
+// testCityNetworks 測試用的 City 資料庫內容（使用文件保留的位址區段）
+func testCityNetworks() []testNetwork {
+	return []testNetwork{
+		{"203.0.113.0/24", map[string]any{
+			"city":      map[string]any{"names": map[string]any{"en": "Taipei", "zh-CN": "台北"}},
+			"continent": map[string]any{"code": "AS"},
+			"country":   country("TW", "Taiwan"),
+			"location":  map[string]any{"latitude": 25.0478, "longitude": 121.5319},
+		}},
+		{"198.51.100.0/24", map[string]any{
+			"city":    map[string]any{"names": map[string]any{"en": "Ashburn"}},
+			"country": country("US", "United States"),
+		}},
+		{"192.0.2.0/25", map[string]any{
+			"registered_country": country("DE", "Germany"),
+			"traits":             map[string]any{"is_anonymous_proxy": true},
+		}},
+		{"2001:db8::/32", map[string]any{
+			"country": country("JP", "Japan"),
+		}},
+	}
+}
+
+// testASNNetworks 測試用的 ASN 資料庫內容
+func testASNNetworks() []testNetwork {
+	return []testNetwork{
+		{"203.0.113.0/24", map[string]any{
+			"autonomous_system_number":       uint32(3462),
+			"autonomous_system_organization": "Data Communication Business Group",
+		}},
+		{"198.51.100.0/24", map[string]any{
+			"autonomous_system_number":       uint32(14618),
+			"autonomous_system_organization": "AMAZON-AES",
+		}},
+	}
+}
+
+// TestTestdata 確認 testdata 中的測試資料庫與產生器一致（以 -update 重新產生）
+// 其他套件的測試使用這些檔案作為 GeoIP 資料庫
+func TestTestdata(t *testing.T) {
+	files := map[string][]byte{
+		"test-city.mmdb": buildMMDB(t, "GeoLite2-City", 6, 28, testCityNetworks()),
+		"test-asn.mmdb":  buildMMDB(t, "GeoLite2-ASN", 4, 24, testASNNetworks()),
+	}
+
+	for name, content := range files {
+		path := filepath.Join("testdata", name)
+		if *updateTestdata {
+			require.NoError(t, os.MkdirAll("testdata", 0755))
+			require.NoError(t, os.WriteFile(path, content, 0644))
+			continue
+		}
+		existing, err := os.ReadFile(path)
+		require.NoError(t, err, "請執行 go test ./internal/geoip -run TestTestdata -update")
+		require.Equal(t, content, existing, "%s 與產生器不一致，請以 -update 重新產生", name)
+	}
+}
@@ -0,0 +1,272 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"access-log-analyzer/pkg/logger"
+)
+
+// maxCacheEntries 查詢快取的上限，超過時清空重建
+const maxCacheEntries = 200000
+
+// Location IP 的地理位置與網路資訊
+type Location struct {
+	Country      string `json:"country,omitempty"`      // ISO 3166-1 國家代碼，例如 TW
+	CountryName  string `json:"countryName,omitempty"`  // 國家名稱（英文）
+	City         string `json:"city,omitempty"`         // 城市名稱（英文）
+	ASN          uint   `json:"asn,omitempty"`          // 自治系統編號
+	Organization string `json:"organization,omitempty"` // ASN 所屬組織
+}
+
+// IsZero 判斷是否沒有任何資訊
+func (l Location) IsZero() bool {
+	return l == Location{}
+}
+
+// DatabaseInfo 已載入資料庫的資訊
+type DatabaseInfo struct {
+	Path         string    `json:"path"`         // 檔案路徑
+	DatabaseType string    `json:"databaseType"` // 資料庫類型，例如 GeoLite2-City
+	Kind         string    `json:"kind"`         // 用途：geo（國家/城市）或 asn
+	IPVersion    uint      `json:"ipVersion"`    // 4 或 6
+	BuildTime    time.Time `json:"buildTime"`    // 建置時間
+}
+
+// 資料庫用途
+const (
+	KindGeo = "geo"
+	KindASN = "asn"
+)
+
+// Enricher 以本機資料庫補上 IP 的國家、城市與 ASN
+// 可同時載入地理位置與 ASN 資料庫，查詢結果會快取，可在多個 goroutine 間共用
+type Enricher struct {
+	mu        sync.RWMutex
+	geo       []*Reader
+	asn       []*Reader
+	databases []DatabaseInfo
+	cacheMu   sync.Mutex
+	cache     map[string]Location
+	log       *logger.Logger
+}
+
+// NewEnricher 建立尚未載入資料庫的 Enricher
+func NewEnricher() *Enricher {
+	return &Enricher{
+		cache: make(map[string]Location),
+		log:   logger.Get().WithModule("geoip"),
+	}
+}
+
+// databaseKind 依資料庫類型判斷用途
+func databaseKind(databaseType string) string {
+	upper := strings.ToUpper(databaseType)
+	if strings.Contains(upper, "ASN") || strings.Contains(upper, "ISP") {
+		return KindASN
+	}
+	return KindGeo
+}
+
+// Load 載入資料庫，取代目前已載入的資料庫
+// path 可以是單一 .mmdb 檔案或包含 .mmdb 檔案的目錄；返回載入的資料庫數
+func (e *Enricher) Load(path string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("無法存取 GeoIP 資料庫路徑: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.mmdb")); err != nil {
+			return 0, fmt.Errorf("搜尋 GeoIP 資料庫失敗: %w", err)
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			return 0, fmt.Errorf("目錄中沒有 .mmdb 檔案: %s", path)
+		}
+	}
+
+	var geo, asn []*Reader
+	databases := make([]DatabaseInfo, 0, len(files))
+	for _, file := range files {
+		reader, err := Open(file)
+		if err != nil {
+			return 0, err
+		}
+		meta := reader.Metadata()
+		kind := databaseKind(meta.DatabaseType)
+		if kind == KindASN {
+			asn = append(asn, reader)
+		} else {
+			geo = append(geo, reader)
+		}
+		databases = append(databases, DatabaseInfo{
+			Path:         file,
+			DatabaseType: meta.DatabaseType,
+			Kind:         kind,
+			IPVersion:    meta.IPVersion,
+			BuildTime:    meta.BuildTime,
+		})
+	}
+
+	// City 資料庫同時包含國家資訊，優先查詢
+	sort.SliceStable(geo, func(i, j int) bool {
+		return strings.Contains(strings.ToUpper(geo[i].meta.DatabaseType), "CITY") &&
+			!strings.Contains(strings.ToUpper(geo[j].meta.DatabaseType), "CITY")
+	})
+
+	e.mu.Lock()
+	e.geo, e.asn, e.databases = geo, asn, databases
+	e.mu.Unlock()
+	e.clearCache()
+
+	e.log.Info().
+		Str("path", path).
+		Int("geo", len(geo)).
+		Int("asn", len(asn)).
+		Msg("GeoIP 資料庫載入完成")
+	return len(databases), nil
+}
+
+// Enabled 判斷是否已載入任何資料庫
+func (e *Enricher) Enabled() bool {
+	if e == nil {
+		return false
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.databases) > 0
+}
+
+// Databases 返回已載入的資料庫資訊
+func (e *Enricher) Databases() []DatabaseInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]DatabaseInfo(nil), e.databases...)
+}
+
+// Lookup 查詢 IP 的地理位置與 ASN，找不到任何資訊時返回 false
+// 接受一般的 IPv4/IPv6 字串，以及 "[::1]" 這類加上中括號的 IPv6
+func (e *Enricher) Lookup(ip string) (Location, bool) {
+	if e == nil {
+		return Location{}, false
+	}
+
+	e.cacheMu.Lock()
+	location, cached := e.cache[ip]
+	e.cacheMu.Unlock()
+	if cached {
+		return location, !location.IsZero()
+	}
+
+	location = e.lookup(ip)
+
+	e.cacheMu.Lock()
+	if len(e.cache) >= maxCacheEntries {
+		e.cache = make(map[string]Location)
+	}
+	e.cache[ip] = location
+	e.cacheMu.Unlock()
+	return location, !location.IsZero()
+}
+
+// lookup 實際查詢所有資料庫
+func (e *Enricher) lookup(ip string) Location {
+	address := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]"))
+	if address == nil {
+		return Location{}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var location Location
+	for _, reader := range e.geo {
+		record, found, err := reader.Lookup(address)
+		if err != nil {
+			e.log.Debug().Err(err).Str("ip", ip).Msg("GeoIP 查詢失敗")
+			continue
+		}
+		if !found {
+			continue
+		}
+		fillGeo(&location, record)
+		if location.Country != "" {
+			break
+		}
+	}
+	for _, reader := range e.asn {
+		record, found, err := reader.Lookup(address)
+		if err != nil {
+			e.log.Debug().Err(err).Str("ip", ip).Msg("ASN 查詢失敗")
+			continue
+		}
+		if !found {
+			continue
+		}
+		fillASN(&location, record)
+		if location.ASN != 0 {
+			break
+		}
+	}
+	return location
+}
+
+// clearCache 清空查詢快取
+func (e *Enricher) clearCache() {
+	e.cacheMu.Lock()
+	e.cache = make(map[string]Location)
+	e.cacheMu.Unlock()
+}
+
+// fillGeo 從 City/Country 資料庫的記錄取出國家與城市
+// 沒有 country 時（例如匿名代理）改用 registered_country
+func fillGeo(location *Location, record any) {
+	fields, ok := record.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, key := range []string{"country", "registered_country"} {
+		country, ok := fields[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		if location.Country == "" {
+			location.Country = stringValue(country["iso_code"])
+			location.CountryName = englishName(country)
+		}
+	}
+	if city, ok := fields["city"].(map[string]any); ok && location.City == "" {
+		location.City = englishName(city)
+	}
+}
+
+// fillASN 從 ASN 資料庫的記錄取出自治系統編號與組織
+func fillASN(location *Location, record any) {
+	fields, ok := record.(map[string]any)
+	if !ok {
+		return
+	}
+	if location.ASN == 0 {
+		location.ASN = uint(uintValue(fields["autonomous_system_number"]))
+	}
+	if location.Organization == "" {
+		location.Organization = stringValue(fields["autonomous_system_organization"])
+	}
+}
+
+// englishName 取出 names 中的英文名稱
+func englishName(fields map[string]any) string {
+	names, ok := fields["names"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	return stringValue(names["en"])
+}
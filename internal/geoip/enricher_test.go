@@ -0,0 +1,91 @@
+package geoip
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnricher_Lookup 測試同時載入 City 與 ASN 資料庫的查詢
+func TestEnricher_Lookup(t *testing.T) {
+	enricher := NewEnricher()
+	assert.False(t, enricher.Enabled())
+
+	count, err := enricher.Load("testdata")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, enricher.Enabled())
+
+	databases := enricher.Databases()
+	require.Len(t, databases, 2)
+	assert.Equal(t, KindASN, databases[0].Kind, "依檔名排序：test-asn.mmdb")
+	assert.Equal(t, "GeoLite2-City", databases[1].DatabaseType)
+	assert.Equal(t, KindGeo, databases[1].Kind)
+
+	testCases := []struct {
+		name     string
+		ip       string
+		expected Location
+		found    bool
+	}{
+		{"城市與 ASN", "203.0.113.7", Location{Country: "TW", CountryName: "Taiwan", City: "Taipei", ASN: 3462, Organization: "Data Communication Business Group"}, true},
+		{"另一個國家", "198.51.100.1", Location{Country: "US", CountryName: "United States", City: "Ashburn", ASN: 14618, Organization: "AMAZON-AES"}, true},
+		{"只有註冊國家", "192.0.2.10", Location{Country: "DE", CountryName: "Germany"}, true},
+		{"IPv6", "2001:db8::1", Location{Country: "JP", CountryName: "Japan"}, true},
+		{"中括號 IPv6", "[2001:db8::1]", Location{Country: "JP", CountryName: "Japan"}, true},
+		{"IPv4 對應的 IPv6", "::ffff:203.0.113.7", Location{Country: "TW", CountryName: "Taiwan", City: "Taipei", ASN: 3462, Organization: "Data Communication Business Group"}, true},
+		{"沒有資料", "10.0.0.1", Location{}, false},
+		{"無效位址", "not-an-ip", Location{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			location, found := enricher.Lookup(tc.ip)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, location)
+
+			// 第二次查詢使用快取
+			cached, found := enricher.Lookup(tc.ip)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, location, cached)
+		})
+	}
+}
+
+// TestEnricher_Load 測試載入單一檔案與錯誤處理
+func TestEnricher_Load(t *testing.T) {
+	enricher := NewEnricher()
+
+	count, err := enricher.Load(filepath.Join("testdata", "test-asn.mmdb"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	location, found := enricher.Lookup("203.0.113.7")
+	assert.True(t, found)
+	assert.Equal(t, Location{ASN: 3462, Organization: "Data Communication Business Group"}, location)
+
+	// 重新載入會取代原本的資料庫並清空快取
+	_, err = enricher.Load(filepath.Join("testdata", "test-city.mmdb"))
+	require.NoError(t, err)
+	location, _ = enricher.Lookup("203.0.113.7")
+	assert.Zero(t, location.ASN)
+	assert.Equal(t, "Taipei", location.City)
+
+	// 錯誤：路徑不存在、空目錄、無效檔案；失敗時保留原本的資料庫
+	_, err = enricher.Load(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+	_, err = enricher.Load(t.TempDir())
+	assert.Error(t, err)
+	invalid := filepath.Join(t.TempDir(), "broken.mmdb")
+	require.NoError(t, os.WriteFile(invalid, []byte("broken"), 0644))
+	_, err = enricher.Load(invalid)
+	assert.Error(t, err)
+	assert.True(t, enricher.Enabled())
+
+	var nilEnricher *Enricher
+	assert.False(t, nilEnricher.Enabled())
+	_, found = nilEnricher.Lookup("203.0.113.7")
+	assert.False(t, found)
+}
@@ -0,0 +1,404 @@
+// Package geoip 讀取本機的 MaxMind DB（.mmdb）資料庫，為 IP 補上國家、城市與 ASN 資訊
+// 支援 GeoLite2/GeoIP2 City、Country、ASN 以及 DB-IP 的相容資料庫，不需連線查詢
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// MaxMind DB 格式常數
+const (
+	dataSectionSeparator = 16         // 搜尋樹與資料區之間的 16 個零位元組
+	metadataSearchWindow = 128 * 1024 // 在檔案尾端搜尋 metadata 標記的範圍
+	maxDecodeDepth       = 64         // 巢狀資料的最大深度，避免惡意檔案造成無限遞迴
+)
+
+// metadataMarker metadata 區段的開頭標記
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// 資料區的資料類型
+const (
+	typeExtended = 0
+	typePointer  = 1
+	typeString   = 2
+	typeDouble   = 3
+	typeBytes    = 4
+	typeUint16   = 5
+	typeUint32   = 6
+	typeMap      = 7
+	typeInt32    = 8
+	typeUint64   = 9
+	typeUint128  = 10
+	typeArray    = 11
+	typeBool     = 14
+	typeFloat    = 15
+)
+
+// Metadata 資料庫的 metadata
+type Metadata struct {
+	DatabaseType string    // 資料庫類型，例如 GeoLite2-City、DBIP-ASN-Lite
+	IPVersion    uint      // 4 或 6
+	RecordSize   uint      // 搜尋樹每筆記錄的位元數（24、28 或 32）
+	NodeCount    uint      // 搜尋樹節點數
+	Languages    []string  // 名稱支援的語言
+	BuildTime    time.Time // 建置時間
+}
+
+// Reader 單一 MaxMind DB 檔案的讀取器
+// 整個檔案載入記憶體後唯讀使用，可在多個 goroutine 間共用
+type Reader struct {
+	buf       []byte
+	data      []byte // 資料區
+	meta      Metadata
+	nodeBytes uint // 每個節點的位元組數
+	ipv4Start uint // IPv6 資料庫中 ::/96（IPv4）子樹的起始節點
+}
+
+// Open 讀取 .mmdb 檔案
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取資料庫失敗: %w", err)
+	}
+	reader, err := FromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return reader, nil
+}
+
+// FromBytes 從記憶體中的資料建立讀取器
+func FromBytes(buf []byte) (*Reader, error) {
+	searchFrom := 0
+	if len(buf) > metadataSearchWindow {
+		searchFrom = len(buf) - metadataSearchWindow
+	}
+	idx := bytes.LastIndex(buf[searchFrom:], metadataMarker)
+	if idx < 0 {
+		return nil, fmt.Errorf("不是有效的 MaxMind DB 檔案：找不到 metadata")
+	}
+	metaStart := searchFrom + idx + len(metadataMarker)
+
+	raw, _, err := (&decoder{buf: buf[metaStart:]}).decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析 metadata 失敗: %w", err)
+	}
+	fields, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("metadata 格式錯誤")
+	}
+
+	meta := Metadata{
+		DatabaseType: stringValue(fields["database_type"]),
+		IPVersion:    uint(uintValue(fields["ip_version"])),
+		RecordSize:   uint(uintValue(fields["record_size"])),
+		NodeCount:    uint(uintValue(fields["node_count"])),
+	}
+	if epoch := uintValue(fields["build_epoch"]); epoch > 0 {
+		meta.BuildTime = time.Unix(int64(epoch), 0).UTC()
+	}
+	if languages, ok := fields["languages"].([]any); ok {
+		for _, lang := range languages {
+			meta.Languages = append(meta.Languages, stringValue(lang))
+		}
+	}
+
+	switch meta.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("不支援的記錄大小: %d", meta.RecordSize)
+	}
+	if meta.IPVersion != 4 && meta.IPVersion != 6 {
+		return nil, fmt.Errorf("不支援的 IP 版本: %d", meta.IPVersion)
+	}
+
+	nodeBytes := meta.RecordSize / 4
+	treeSize := meta.NodeCount * nodeBytes
+	dataStart := treeSize + dataSectionSeparator
+	if dataStart > uint(searchFrom+idx) {
+		return nil, fmt.Errorf("搜尋樹大小超出檔案範圍")
+	}
+
+	r := &Reader{
+		buf:       buf,
+		data:      buf[dataStart : searchFrom+idx],
+		meta:      meta,
+		nodeBytes: nodeBytes,
+	}
+
+	// IPv6 資料庫的 IPv4 位址位於 ::/96，預先找出該子樹的起始節點
+	if meta.IPVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < meta.NodeCount; i++ {
+			if node, err = r.readRecord(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// Metadata 返回資料庫的 metadata
+func (r *Reader) Metadata() Metadata {
+	return r.meta
+}
+
+// Lookup 查詢 IP 的資料，找不到時 found 為 false
+// 返回的資料為解碼後的通用結構（map[string]any、[]any、string、uint64、float64 等）
+func (r *Reader) Lookup(ip net.IP) (record any, found bool, err error) {
+	var address []byte
+	node := uint(0)
+	if v4 := ip.To4(); v4 != nil {
+		address = v4
+		if r.meta.IPVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if v6 := ip.To16(); v6 != nil && r.meta.IPVersion == 6 {
+		address = v6
+	} else {
+		return nil, false, nil
+	}
+
+	bitCount := len(address) * 8
+	for i := 0; i < bitCount && node < r.meta.NodeCount; i++ {
+		bit := (address[i>>3] >> (7 - uint(i&7))) & 1
+		if node, err = r.readRecord(node, bit); err != nil {
+			return nil, false, err
+		}
+	}
+
+	switch {
+	case node == r.meta.NodeCount:
+		return nil, false, nil
+	case node < r.meta.NodeCount:
+		return nil, false, fmt.Errorf("搜尋樹格式錯誤：位元用盡仍未到達資料")
+	}
+
+	offset := node - r.meta.NodeCount - dataSectionSeparator
+	record, _, err = (&decoder{buf: r.data}).decode(offset, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("解析資料失敗: %w", err)
+	}
+	return record, true, nil
+}
+
+// readRecord 讀取節點的左（bit=0）或右（bit=1）記錄
+func (r *Reader) readRecord(node uint, bit byte) (uint, error) {
+	offset := node * r.nodeBytes
+	if offset+r.nodeBytes > uint(len(r.buf)) {
+		return 0, fmt.Errorf("搜尋樹節點 %d 超出檔案範圍", node)
+	}
+	b := r.buf[offset : offset+r.nodeBytes]
+
+	switch r.meta.RecordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5]), nil
+	case 28:
+		// 中間位元組的高 4 位元屬於左記錄、低 4 位元屬於右記錄
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4])), nil
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8])), nil
+	}
+}
+
+// decoder 資料區解碼器
+type decoder struct {
+	buf []byte
+}
+
+// decode 解碼 offset 位置的資料，返回值與下一筆資料的位置
+func (d *decoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, fmt.Errorf("資料巢狀過深")
+	}
+	if offset >= uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("資料位置 %d 超出範圍", offset)
+	}
+
+	ctrl := d.buf[offset]
+	offset++
+	dataType := uint(ctrl >> 5)
+
+	if dataType == typePointer {
+		pointer, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	if dataType == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, fmt.Errorf("擴充類型超出範圍")
+		}
+		dataType = 7 + uint(d.buf[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1F)
+	if size >= 29 {
+		n := size - 28 // 額外的大小位元組數：29→1、30→2、31→3
+		extra, err := d.slice(offset, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + uint(extra[0])
+		case 30:
+			size = 285 + (uint(extra[0])<<8 | uint(extra[1]))
+		default:
+			size = 65821 + (uint(extra[0])<<16 | uint(extra[1])<<8 | uint(extra[2]))
+		}
+	}
+
+	switch dataType {
+	case typeMap:
+		m := make(map[string]any, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map 的鍵不是字串")
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[keyString] = value
+			offset = next
+		}
+		return m, offset, nil
+
+	case typeArray:
+		array := make([]any, 0, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			array = append(array, value)
+			offset = next
+		}
+		return array, offset, nil
+
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	payload, err := d.slice(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset += size
+
+	switch dataType {
+	case typeString:
+		return string(payload), offset, nil
+	case typeBytes:
+		return append([]byte(nil), payload...), offset, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("double 長度錯誤: %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), offset, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("float 長度錯誤: %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(payload))), offset, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("整數長度錯誤: %d", size)
+		}
+		value := uint64(0)
+		for _, b := range payload {
+			value = value<<8 | uint64(b)
+		}
+		return value, offset, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("int32 長度錯誤: %d", size)
+		}
+		value := uint32(0)
+		for _, b := range payload {
+			value = value<<8 | uint32(b)
+		}
+		return int64(int32(value)), offset, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(payload), offset, nil
+	}
+	return nil, 0, fmt.Errorf("不支援的資料類型: %d", dataType)
+}
+
+// pointer 解析指標，返回指向的位置與指標之後的位置
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl>>3)&0x3 + 1
+	b, err := d.slice(offset, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	vvv := uint(ctrl & 0x7)
+
+	var pointer uint
+	switch size {
+	case 1:
+		pointer = vvv<<8 | uint(b[0])
+	case 2:
+		pointer = (vvv<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		pointer = (vvv<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+	return pointer, offset + size, nil
+}
+
+// slice 取出 offset 起 n 個位元組
+func (d *decoder) slice(offset, n uint) ([]byte, error) {
+	if offset+n > uint(len(d.buf)) {
+		return nil, fmt.Errorf("資料長度超出範圍")
+	}
+	return d.buf[offset : offset+n], nil
+}
+
+// stringValue 將解碼後的值轉為字串
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// uintValue 將解碼後的值轉為無號整數
+func uintValue(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package geoip
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_記錄大小 測試 24、28、32 位元記錄與 IPv4/IPv6 資料庫的查詢
+func TestReader_記錄大小(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		for _, ipVersion := range []int{4, 6} {
+			networks := testCityNetworks()
+			if ipVersion == 4 {
+				networks = networks[:3]
+			}
+			reader, err := FromBytes(buildMMDB(t, "GeoLite2-City", ipVersion, recordSize, networks))
+			require.NoError(t, err)
+
+			meta := reader.Metadata()
+			assert.Equal(t, uint(recordSize), meta.RecordSize)
+			assert.Equal(t, uint(ipVersion), meta.IPVersion)
+			assert.Equal(t, "GeoLite2-City", meta.DatabaseType)
+			assert.Equal(t, []string{"en"}, meta.Languages)
+			assert.Equal(t, int64(1704067200), meta.BuildTime.Unix())
+
+			record, found, err := reader.Lookup(net.ParseIP("203.0.113.42"))
+			require.NoError(t, err)
+			require.True(t, found, "record size %d, IPv%d", recordSize, ipVersion)
+			fields := record.(map[string]any)
+			assert.Equal(t, "TW", fields["country"].(map[string]any)["iso_code"])
+			assert.InDelta(t, 25.0478, fields["location"].(map[string]any)["latitude"], 0.0001)
+
+			_, found, err = reader.Lookup(net.ParseIP("8.8.8.8"))
+			require.NoError(t, err)
+			assert.False(t, found)
+
+			_, found, err = reader.Lookup(net.ParseIP("2001:db8::1"))
+			require.NoError(t, err)
+			assert.Equal(t, ipVersion == 6, found, "IPv4 資料庫查不到 IPv6 位址")
+		}
+	}
+}
+
+// TestReader_無效檔案 測試非 MaxMind DB 的資料
+func TestReader_無效檔案(t *testing.T) {
+	_, err := FromBytes([]byte("not a database"))
+	assert.Error(t, err)
+
+	// metadata 中的記錄大小無效
+	var buf bytes.Buffer
+	buf.Write(metadataMarker)
+	encodeValue(&buf, map[string]any{"record_size": uint16(20), "ip_version": uint16(4), "node_count": uint32(0)})
+	_, err = FromBytes(buf.Bytes())
+	assert.Error(t, err)
+
+	// 搜尋樹超出檔案範圍
+	buf.Reset()
+	buf.Write(metadataMarker)
+	encodeValue(&buf, map[string]any{"record_size": uint16(24), "ip_version": uint16(4), "node_count": uint32(1000)})
+	_, err = FromBytes(buf.Bytes())
+	assert.Error(t, err)
+}
+
+// TestDecoder 測試資料區各類型與指標的解碼
+func TestDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	encodeValue(&buf, "shared")                           // offset 0
+	pointerAt := buf.Len()                                // offset 7
+	buf.Write([]byte{0x20, 0x00})                         // 指標（size 0）指向 offset 0
+	encodeValue(&buf, uint64(1)<<40)                      // uint64（擴充類型）
+	encodeValue(&buf, []any{true, false, 1.5})            // 陣列（擴充類型）
+	buf.Write([]byte{0x04, 0x01, 0xFF, 0xFF, 0xFF, 0xFE}) // int32 = -2（擴充類型 8，長度 4）
+	longString := string(bytes.Repeat([]byte("a"), 300))
+	encodeValue(&buf, longString) // 需要 2 個額外大小位元組
+
+	d := &decoder{buf: buf.Bytes()}
+	value, next, err := d.decode(uint(pointerAt), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "shared", value)
+	assert.Equal(t, uint(pointerAt+2), next, "指標之後繼續解碼")
+
+	value, next, err = d.decode(next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1)<<40, value)
+
+	value, next, err = d.decode(next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []any{true, false, 1.5}, value)
+
+	value, next, err = d.decode(next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-2), value)
+
+	value, _, err = d.decode(next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, longString, value)
+
+	// 超出範圍與自我參照的指標
+	_, _, err = d.decode(uint(buf.Len()), 0)
+	assert.Error(t, err)
+	_, _, err = (&decoder{buf: []byte{0x20, 0x00}}).decode(0, 0)
+	assert.Error(t, err, "自我參照的指標應因深度限制而失敗")
+}
@@ -0,0 +1,256 @@
+package index
+
+import (
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+)
+
+// Field 建立索引的欄位
+type Field string
+
+// 建立索引的欄位
+const (
+	FieldIP        Field = "ip"
+	FieldURL       Field = "url"
+	FieldUserAgent Field = "userAgent"
+	FieldReferer   Field = "referer"
+	FieldUser      Field = "user"
+	FieldMethod    Field = "method"
+	FieldProtocol  Field = "protocol"
+)
+
+// allFields 所有建立索引的欄位（通用關鍵字搜尋時使用）
+var allFields = []Field{
+	FieldIP, FieldURL, FieldUserAgent, FieldReferer,
+	FieldUser, FieldMethod, FieldProtocol,
+}
+
+// fieldValue 取得記錄中指定欄位的值
+func fieldValue(entry *models.LogEntry, field Field) string {
+	switch field {
+	case FieldIP:
+		return entry.IP
+	case FieldURL:
+		return entry.URL
+	case FieldUserAgent:
+		return entry.UserAgent
+	case FieldReferer:
+		return entry.Referer
+	case FieldUser:
+		return entry.User
+	case FieldMethod:
+		return entry.Method
+	case FieldProtocol:
+		return entry.Protocol
+	}
+	return ""
+}
+
+// Index 日誌記錄的記憶體內反向索引
+// 各欄位依英數字元切分為小寫詞彙，詞彙對應到包含它的記錄索引；
+// IP 另外建立前綴樹以支援 CIDR 查詢
+type Index struct {
+	terms      map[Field]*termDict // 欄位 -> 詞典
+	ips        *ipTrie             // IP 前綴樹
+	noUser     Postings            // 沒有使用者欄位的記錄（使用者條件不限制這些記錄）
+	entryCount int                 // 建立索引時的記錄數
+	buildTime  time.Duration       // 建立耗時
+}
+
+// Build 為日誌記錄建立反向索引
+// 解析失敗的記錄不會被索引
+func Build(entries []models.LogEntry) *Index {
+	start := time.Now()
+
+	idx := &Index{
+		terms:      make(map[Field]*termDict, len(allFields)),
+		ips:        newIPTrie(),
+		entryCount: len(entries),
+	}
+	fieldTerms := make(map[Field]map[string]Postings, len(allFields))
+	for _, field := range allFields {
+		fieldTerms[field] = make(map[string]Postings)
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.ParseError != "" {
+			continue
+		}
+		pos := uint32(i)
+
+		for _, field := range allFields {
+			terms := fieldTerms[field]
+			for _, term := range Tokenize(fieldValue(entry, field)) {
+				terms[term] = terms[term].add(pos)
+			}
+		}
+
+		if ip := net.ParseIP(entry.IP); ip != nil {
+			idx.ips.insert(ip, pos)
+		}
+		if entry.User == "" {
+			idx.noUser = idx.noUser.add(pos)
+		}
+	}
+	for field, terms := range fieldTerms {
+		idx.terms[field] = newTermDict(terms)
+	}
+
+	idx.buildTime = time.Since(start)
+	return idx
+}
+
+// Tokenize 將文字切分為小寫的英數字詞彙
+// 例如 "/api/v1/users?id=5" -> ["api", "v1", "users", "id", "5"]
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// BuildTime 返回建立索引的耗時
+func (idx *Index) BuildTime() time.Duration {
+	return idx.buildTime
+}
+
+// EntryCount 返回建立索引時的記錄數
+func (idx *Index) EntryCount() int {
+	return idx.entryCount
+}
+
+// MemoryUsage 估算索引的記憶體使用量（位元組）
+func (idx *Index) MemoryUsage() int64 {
+	var total int64
+	for _, terms := range idx.terms {
+		total += terms.memoryUsage()
+	}
+
+	var lists []Postings
+	collect(idx.ips.root, &lists)
+	for _, postings := range lists {
+		total += int64(cap(postings)) * 4
+	}
+	total += int64(cap(idx.noUser)) * 4
+	return total + idx.ips.memoryUsage()
+}
+
+// Lookup 查詢包含完整詞彙的記錄
+func (idx *Index) Lookup(field Field, term string) Postings {
+	return idx.terms[field].postings[strings.ToLower(term)]
+}
+
+// Search 查詢欄位值可能包含指定子字串的記錄（候選集合）
+// 子字串中被分隔字元隔開的詞彙必須完整出現：開頭的詞彙可以是詞彙的後綴、結尾的詞彙可以是前綴，
+// 前後都沒有分隔字元的單一詞彙可以是任一詞彙的子字串。結果為實際符合記錄的超集，
+// 呼叫者仍需以原始條件驗證。子字串不含任何英數字時返回 false，表示無法使用索引
+func (idx *Index) Search(field Field, text string) (Postings, bool) {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return nil, false
+	}
+
+	// 子字串開頭（結尾）是英數字時，第一個（最後一個）詞彙可能只是詞彙的一部分
+	lower := strings.ToLower(text)
+	openStart := strings.HasPrefix(lower, tokens[0])
+	openEnd := strings.HasSuffix(lower, tokens[len(tokens)-1])
+
+	terms := idx.terms[field]
+	var result Postings
+	for i, token := range tokens {
+		var lists []Postings
+		switch first, last := i == 0 && openStart, i == len(tokens)-1 && openEnd; {
+		case first && last:
+			lists = terms.containing(token)
+		case first:
+			lists = terms.withSuffix(token)
+		case last:
+			lists = terms.withPrefix(token)
+		default:
+			lists = terms.exact(token)
+		}
+
+		matched := unionAll(lists)
+		if i == 0 {
+			result = matched
+		} else {
+			result = intersect(result, matched)
+		}
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result, true
+}
+
+// SearchAny 查詢任一欄位可能包含指定子字串的記錄（候選集合）
+// 對應 FilterCriteria 的通用關鍵字搜尋
+func (idx *Index) SearchAny(text string) (Postings, bool) {
+	lists := make([]Postings, 0, len(allFields))
+	for _, field := range allFields {
+		postings, ok := idx.Search(field, text)
+		if !ok {
+			return nil, false
+		}
+		lists = append(lists, postings)
+	}
+	return unionAll(lists), true
+}
+
+// LookupCIDR 查詢 IP 位於指定網段的記錄
+func (idx *Index) LookupCIDR(network *net.IPNet) Postings {
+	return idx.ips.lookup(network)
+}
+
+// Candidates 依篩選條件中的搜尋欄位取得候選記錄
+// 返回 false 表示條件中沒有可使用索引的欄位，需線性掃描
+func (idx *Index) Candidates(criteria *filter.FilterCriteria) (Postings, bool) {
+	if criteria == nil {
+		return nil, false
+	}
+	if criteria.MatchNone {
+		return Postings{}, true
+	}
+
+	var result Postings
+	used := false
+	narrow := func(postings Postings, ok bool) {
+		if !ok {
+			return
+		}
+		if !used {
+			result, used = postings, true
+			return
+		}
+		result = intersect(result, postings)
+	}
+
+	if criteria.IP != "" {
+		if network, ok := criteria.IPNetwork(); ok {
+			narrow(idx.LookupCIDR(network), true)
+		} else {
+			narrow(idx.Search(FieldIP, criteria.IP))
+		}
+	}
+	if criteria.URL != "" {
+		narrow(idx.Search(FieldURL, criteria.URL))
+	}
+	if criteria.UserAgent != "" {
+		narrow(idx.Search(FieldUserAgent, criteria.UserAgent))
+	}
+	if criteria.User != "" {
+		if postings, ok := idx.Search(FieldUser, criteria.User); ok {
+			narrow(union(postings, idx.noUser), true)
+		}
+	}
+	if criteria.Keyword != "" {
+		narrow(idx.SearchAny(criteria.Keyword))
+	}
+
+	return result, used
+}
@@ -0,0 +1,178 @@
+package index
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"access-log-analyzer/internal/filter"
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEntries 建立測試用的日誌記錄
+func testEntries() []models.LogEntry {
+	return []models.LogEntry{
+		{IP: "192.168.1.10", Method: "GET", URL: "/api/users/1", UserAgent: "Mozilla/5.0", Referer: "-"},
+		{IP: "192.168.2.20", Method: "POST", URL: "/api/login", UserAgent: "curl/7.68.0", Referer: "-"},
+		{IP: "10.0.0.1", Method: "GET", URL: "/index.html", UserAgent: "Googlebot/2.1", Referer: "https://www.google.com/"},
+		{LineNumber: 4, RawLine: "garbage", ParseError: "無效格式"},
+		{IP: "2001:db8::1", Method: "GET", URL: "/API/Users/2", UserAgent: "Mozilla/5.0", Referer: "-"},
+	}
+}
+
+// TestTokenize 測試詞彙切分
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"api", "v1", "users", "id", "5"}, Tokenize("/API/v1/users?id=5"))
+	assert.Equal(t, []string{"192", "168", "1", "10"}, Tokenize("192.168.1.10"))
+	assert.Empty(t, Tokenize("/-/"))
+}
+
+// TestIndex_Search 測試子字串候選查詢
+func TestIndex_Search(t *testing.T) {
+	idx := Build(testEntries())
+
+	postings, ok := idx.Search(FieldURL, "users")
+	require.True(t, ok)
+	assert.Equal(t, Postings{0, 4}, postings)
+
+	// 子字串跨越詞彙邊界
+	postings, ok = idx.Search(FieldURL, "pi/us")
+	require.True(t, ok)
+	assert.Equal(t, Postings{0, 4}, postings)
+
+	// 不含英數字的子字串無法使用索引
+	_, ok = idx.Search(FieldURL, "/")
+	assert.False(t, ok)
+
+	postings, ok = idx.SearchAny("google")
+	require.True(t, ok)
+	assert.Equal(t, Postings{2}, postings)
+
+	assert.Equal(t, Postings{1}, idx.Lookup(FieldMethod, "post"))
+}
+
+// TestIndex_Search_詞彙邊界 測試子字串的分隔字元限制詞彙只能以前綴、後綴或完整詞彙比對
+func TestIndex_Search_詞彙邊界(t *testing.T) {
+	idx := Build([]models.LogEntry{
+		{URL: "/api/users"},
+		{URL: "/apis/v2"},
+		{URL: "/rapid/api"},
+		{URL: "/shop/item-42/detail"},
+	})
+
+	cases := []struct {
+		text     string
+		expected Postings
+	}{
+		{"api", Postings{0, 1, 2}},      // 子字串：api、apis、rapid
+		{"/api/", Postings{0, 2}},       // 完整詞彙
+		{"/api", Postings{0, 1, 2}},     // 前綴：api、apis
+		{"pi/", Postings{0, 2}},         // 後綴：api
+		{"m-4", Postings{3}},            // 後綴 item 與前綴 42
+		{"item-42/detail", Postings{3}}, // 中間詞彙完整比對
+		{"tem-4/", nil},
+		{"etai", Postings{3}}, // 超過 trigram 長度的子字串
+		{"etax", nil},
+	}
+	for _, tc := range cases {
+		postings, ok := idx.Search(FieldURL, tc.text)
+		require.True(t, ok, tc.text)
+		if tc.expected == nil {
+			assert.Empty(t, postings, tc.text)
+		} else {
+			assert.Equal(t, tc.expected, postings, tc.text)
+		}
+	}
+}
+
+// TestIndex_LookupCIDR 測試 IP 前綴樹的網段查詢
+func TestIndex_LookupCIDR(t *testing.T) {
+	idx := Build(testEntries())
+
+	testCases := []struct {
+		cidr     string
+		expected Postings
+	}{
+		{"192.168.0.0/16", Postings{0, 1}},
+		{"192.168.1.0/24", Postings{0}},
+		{"192.168.1.10/32", Postings{0}},
+		{"10.0.0.0/8", Postings{2}},
+		{"172.16.0.0/12", Postings{}},
+		{"0.0.0.0/0", Postings{0, 1, 2}},
+		{"2001:db8::/32", Postings{4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cidr, func(t *testing.T) {
+			_, network, err := net.ParseCIDR(tc.cidr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, idx.LookupCIDR(network))
+		})
+	}
+}
+
+// TestIndex_Candidates 測試候選集合必須包含所有實際符合的記錄
+func TestIndex_Candidates(t *testing.T) {
+	entries := make([]models.LogEntry, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		entries = append(entries, models.LogEntry{
+			IP:        fmt.Sprintf("10.%d.%d.%d", i%4, i%50, i%200),
+			Method:    []string{"GET", "POST", "HEAD"}[i%3],
+			URL:       fmt.Sprintf("/shop/item-%d/detail?ref=%d", i%97, i%7),
+			UserAgent: []string{"Mozilla/5.0", "curl/7.68.0", "Googlebot/2.1"}[i%3],
+			Referer:   "-",
+		})
+	}
+	idx := Build(entries)
+	assert.Greater(t, idx.MemoryUsage(), int64(0))
+	assert.Equal(t, 2000, idx.EntryCount())
+
+	criteriaList := []*filter.FilterCriteria{
+		{URL: "item-42/"},
+		{URL: "M-4", CaseSensitive: true},
+		{IP: "10.1.0.0/16", UserAgent: "curl"},
+		{Keyword: "bot"},
+		{IP: ".3.1", URL: "detail"},
+	}
+
+	for _, criteria := range criteriaList {
+		candidates, ok := idx.Candidates(criteria)
+		require.True(t, ok)
+
+		expected := criteria.Apply(entries)
+		verified := make([]int, 0)
+		for _, pos := range candidates {
+			if criteria.Match(&entries[pos]) {
+				verified = append(verified, int(pos))
+			}
+		}
+		assert.Equal(t, expected, verified, "criteria: %+v", criteria)
+		assert.Less(t, len(candidates), len(entries), "criteria: %+v", criteria)
+	}
+
+	_, ok := idx.Candidates(&filter.FilterCriteria{Methods: []string{"GET"}})
+	assert.False(t, ok)
+
+	candidates, ok := idx.Candidates(&filter.FilterCriteria{MatchNone: true, URL: "detail"})
+	assert.True(t, ok)
+	assert.Empty(t, candidates, "不符合任何記錄時不需掃描")
+}
+
+// TestIndex_Candidates_沒有使用者 測試使用者條件的候選集合包含沒有使用者欄位的記錄
+func TestIndex_Candidates_沒有使用者(t *testing.T) {
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", User: "alice"},
+		{IP: "10.0.0.2", User: "bob"},
+		{IP: "10.0.0.3", User: "-"},
+		{IP: "10.0.0.4"},
+	}
+	criteria := &filter.FilterCriteria{User: "ali"}
+
+	candidates, ok := Build(entries).Candidates(criteria)
+	require.True(t, ok)
+	assert.Equal(t, Postings{0, 3}, candidates)
+	assert.Equal(t, []int{0, 3}, criteria.Apply(entries))
+}
@@ -0,0 +1,141 @@
+package index
+
+import (
+	"net"
+	"unsafe"
+)
+
+// ipBits IP 位址的位元數（IPv4 以 IPv4-mapped IPv6 形式儲存）
+const ipBits = 128
+
+// ipTrie IP 前綴樹（路徑壓縮的二元 radix trie）
+// 只在分岔處建立節點，節點數約為不重複 IP 數的兩倍；
+// CIDR 查詢只需沿前綴走訪，再收集子樹的記錄索引
+type ipTrie struct {
+	root  *trieNode
+	nodes int // 節點數量（用於估算記憶體）
+}
+
+// trieNode 前綴樹節點
+type trieNode struct {
+	prefix   [16]byte     // 從根節點到此節點的前綴（只有前 length 個位元有效）
+	length   int          // 前綴長度（位元）
+	children [2]*trieNode // 下一個位元為 0/1 的子節點
+	postings Postings     // 完整位址的記錄索引（僅 length 為 128 的葉節點）
+}
+
+// newIPTrie 建立空的 IP 前綴樹
+func newIPTrie() *ipTrie {
+	return &ipTrie{root: &trieNode{}, nodes: 1}
+}
+
+// insert 新增一筆 IP 對應的記錄索引
+func (t *ipTrie) insert(ip net.IP, idx uint32) {
+	var key [16]byte
+	copy(key[:], ip.To16())
+
+	node := t.root
+	for {
+		// 不變式：node 的前綴與 key 的前 node.length 個位元相同
+		if node.length == ipBits {
+			node.postings = node.postings.add(idx)
+			return
+		}
+
+		b := bitAt(key, node.length)
+		child := node.children[b]
+		if child == nil {
+			node.children[b] = &trieNode{prefix: key, length: ipBits, postings: Postings{idx}}
+			t.nodes++
+			return
+		}
+
+		common := commonPrefix(child.prefix, key, child.length)
+		if common == child.length {
+			node = child
+			continue
+		}
+
+		// 在分岔處插入中間節點
+		mid := &trieNode{prefix: maskPrefix(key, common), length: common}
+		mid.children[bitAt(child.prefix, common)] = child
+		mid.children[bitAt(key, common)] = &trieNode{prefix: key, length: ipBits, postings: Postings{idx}}
+		node.children[b] = mid
+		t.nodes += 2
+		return
+	}
+}
+
+// lookup 查詢 CIDR 網段內所有 IP 的記錄索引
+func (t *ipTrie) lookup(network *net.IPNet) Postings {
+	ones, bits := network.Mask.Size()
+	if bits == 32 {
+		// IPv4 網段在 IPv4-mapped 形式中前綴需加上 96 位元
+		ones += 96
+	}
+	var key [16]byte
+	copy(key[:], network.IP.To16())
+
+	node := t.root
+	for node.length < ones {
+		child := node.children[bitAt(key, node.length)]
+		if child == nil {
+			return Postings{}
+		}
+		n := child.length
+		if n > ones {
+			n = ones
+		}
+		if commonPrefix(child.prefix, key, n) < n {
+			return Postings{}
+		}
+		node = child
+	}
+
+	var lists []Postings
+	collect(node, &lists)
+	return unionAll(lists)
+}
+
+// collect 收集子樹中所有葉節點的記錄索引
+func collect(node *trieNode, lists *[]Postings) {
+	if node == nil {
+		return
+	}
+	if len(node.postings) > 0 {
+		*lists = append(*lists, node.postings)
+	}
+	collect(node.children[0], lists)
+	collect(node.children[1], lists)
+}
+
+// memoryUsage 估算前綴樹節點的記憶體使用量（位元組，不含記錄索引）
+func (t *ipTrie) memoryUsage() int64 {
+	return int64(t.nodes) * int64(unsafe.Sizeof(trieNode{}))
+}
+
+// bitAt 取得第 i 個位元（從最高位開始）
+func bitAt(key [16]byte, i int) byte {
+	return (key[i/8] >> (7 - uint(i%8))) & 1
+}
+
+// commonPrefix 計算兩個位址在前 limit 個位元中的共同前綴長度
+func commonPrefix(a, b [16]byte, limit int) int {
+	for i := 0; i < limit; i++ {
+		if bitAt(a, i) != bitAt(b, i) {
+			return i
+		}
+	}
+	return limit
+}
+
+// maskPrefix 只保留前 length 個位元
+func maskPrefix(key [16]byte, length int) [16]byte {
+	var out [16]byte
+	for i := 0; i < length; i++ {
+		if bitAt(key, i) == 1 {
+			out[i/8] |= 1 << (7 - uint(i%8))
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package index
+
+// Postings 記錄索引列表（遞增排序、不重複）
+type Postings []uint32
+
+// intersect 取兩個已排序列表的交集
+func intersect(a, b Postings) Postings {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	result := make(Postings, 0, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// union 取兩個已排序列表的聯集
+func union(a, b Postings) Postings {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	result := make(Postings, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case a[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// unionAll 取多個已排序列表的聯集
+// 兩兩合併，避免單一大列表被反覆複製
+func unionAll(lists []Postings) Postings {
+	if len(lists) == 0 {
+		return Postings{}
+	}
+	for len(lists) > 1 {
+		merged := make([]Postings, 0, (len(lists)+1)/2)
+		for i := 0; i < len(lists); i += 2 {
+			if i+1 < len(lists) {
+				merged = append(merged, union(lists[i], lists[i+1]))
+			} else {
+				merged = append(merged, lists[i])
+			}
+		}
+		lists = merged
+	}
+	return lists[0]
+}
+
+// add 新增記錄索引（建立索引時依序新增，只需檢查最後一筆避免重複）
+func (p Postings) add(idx uint32) Postings {
+	if n := len(p); n > 0 && p[n-1] == idx {
+		return p
+	}
+	return append(p, idx)
+}
@@ -0,0 +1,165 @@
+package index
+
+import (
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// maxGram 詞彙 n-gram 的最大長度（位元組）
+// 長度不超過 maxGram 的子字串直接查詢，較長的子字串取各 trigram 的交集後再驗證
+const maxGram = 3
+
+// termDict 單一欄位的詞典
+// 建立後不再變動：sorted 供前綴查詢、reversed 供後綴查詢（二分搜尋），
+// grams 將 1 到 3 位元組的 n-gram 對應到包含它的詞彙，供子字串查詢
+type termDict struct {
+	postings map[string]Postings // 詞彙 -> 記錄索引
+	sorted   []string            // 排序後的詞彙（詞彙編號即為此處的位置）
+	reversed []reversedTerm      // 依反轉字串排序的詞彙
+	grams    map[string][]int32  // n-gram -> 詞彙編號（遞增、不重複）
+}
+
+// reversedTerm 反轉後的詞彙與其詞彙編號
+type reversedTerm struct {
+	text string
+	id   int32
+}
+
+// newTermDict 由詞彙對應表建立詞典
+func newTermDict(postings map[string]Postings) *termDict {
+	d := &termDict{
+		postings: postings,
+		sorted:   make([]string, 0, len(postings)),
+		grams:    make(map[string][]int32),
+	}
+	for term := range postings {
+		d.sorted = append(d.sorted, term)
+	}
+	sort.Strings(d.sorted)
+
+	d.reversed = make([]reversedTerm, len(d.sorted))
+	for i, term := range d.sorted {
+		id := int32(i)
+		d.reversed[i] = reversedTerm{text: reverse(term), id: id}
+		for n := 1; n <= maxGram && n <= len(term); n++ {
+			for start := 0; start+n <= len(term); start++ {
+				gram := term[start : start+n]
+				ids := d.grams[gram]
+				if len(ids) == 0 || ids[len(ids)-1] != id {
+					d.grams[gram] = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Slice(d.reversed, func(i, j int) bool {
+		return d.reversed[i].text < d.reversed[j].text
+	})
+	return d
+}
+
+// exact 返回完整詞彙的記錄索引
+func (d *termDict) exact(token string) []Postings {
+	if postings, ok := d.postings[token]; ok {
+		return []Postings{postings}
+	}
+	return nil
+}
+
+// withPrefix 返回以 prefix 開頭的詞彙的記錄索引
+func (d *termDict) withPrefix(prefix string) []Postings {
+	lo := sort.SearchStrings(d.sorted, prefix)
+	var lists []Postings
+	for i := lo; i < len(d.sorted) && strings.HasPrefix(d.sorted[i], prefix); i++ {
+		lists = append(lists, d.postings[d.sorted[i]])
+	}
+	return lists
+}
+
+// withSuffix 返回以 suffix 結尾的詞彙的記錄索引
+func (d *termDict) withSuffix(suffix string) []Postings {
+	key := reverse(suffix)
+	lo := sort.Search(len(d.reversed), func(i int) bool { return d.reversed[i].text >= key })
+	var lists []Postings
+	for i := lo; i < len(d.reversed) && strings.HasPrefix(d.reversed[i].text, key); i++ {
+		lists = append(lists, d.postings[d.sorted[d.reversed[i].id]])
+	}
+	return lists
+}
+
+// containing 返回包含 substr 的詞彙的記錄索引
+func (d *termDict) containing(substr string) []Postings {
+	var ids []int32
+	if len(substr) <= maxGram {
+		ids = d.grams[substr]
+	} else {
+		for start := 0; start+maxGram <= len(substr); start++ {
+			gramIDs := d.grams[substr[start:start+maxGram]]
+			if start == 0 {
+				ids = gramIDs
+			} else {
+				ids = intersectIDs(ids, gramIDs)
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+		}
+	}
+
+	lists := make([]Postings, 0, len(ids))
+	for _, id := range ids {
+		term := d.sorted[id]
+		// trigram 全部出現不代表子字串出現，需再驗證
+		if len(substr) > maxGram && !strings.Contains(term, substr) {
+			continue
+		}
+		lists = append(lists, d.postings[term])
+	}
+	return lists
+}
+
+// memoryUsage 估算詞典的記憶體使用量（位元組）
+func (d *termDict) memoryUsage() int64 {
+	const mapEntryOverhead = int64(unsafe.Sizeof("")) + int64(unsafe.Sizeof(Postings{})) + 8
+
+	var total int64
+	for term, postings := range d.postings {
+		total += int64(len(term)) + mapEntryOverhead + int64(cap(postings))*4
+	}
+	total += int64(cap(d.sorted)) * int64(unsafe.Sizeof(""))
+	for _, r := range d.reversed {
+		total += int64(len(r.text)) + int64(unsafe.Sizeof(r))
+	}
+	for gram, ids := range d.grams {
+		total += int64(len(gram)) + mapEntryOverhead + int64(cap(ids))*4
+	}
+	return total
+}
+
+// intersectIDs 取兩個已排序詞彙編號列表的交集
+func intersectIDs(a, b []int32) []int32 {
+	result := make([]int32, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// reverse 反轉字串的位元組（只用於後綴比對，不需保持 UTF-8 有效）
+func reverse(s string) string {
+	b := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		b[len(s)-1-i] = s[i]
+	}
+	return string(b)
+}
@@ -21,6 +21,10 @@ type LogEntry struct {
 	// 擴展欄位
 	User        string `json:"user,omitempty"`        // 認證使用者名稱（如果有）
 	RequestTime int64  `json:"requestTime,omitempty"` // 請求處理時間（微秒）
+	VirtualHost string `json:"virtualHost,omitempty"` // 虛擬主機名稱（%v 或 vhost_combined 格式，小寫）
+	ServerPort  int    `json:"serverPort,omitempty"`  // 伺服器連接埠（vhost_combined 格式的 %p）
+	Server      string `json:"server,omitempty"`      // 來源伺服器（同時載入多台伺服器的 log 時用於區分）
+	CacheStatus string `json:"cacheStatus,omitempty"` // 快取狀態（HIT、MISS 等，log 格式在 User-Agent 之後記錄快取狀態時才有）
 
 	// 內部欄位
 	LineNumber int    `json:"lineNumber"`           // 原始檔案中的行號
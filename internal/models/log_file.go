@@ -25,9 +25,37 @@ type LogFile struct {
 	Statistics interface{} `json:"statistics"` // 統計分析結果
 
 	// 效能指標
-	ParseTime  int64 `json:"parseTime"`  // 解析耗時（毫秒）
-	StatTime   int64 `json:"statTime"`   // 統計計算耗時（毫秒）
-	MemoryUsed int64 `json:"memoryUsed"` // 記憶體使用量（位元組）
+	ParseTime   int64 `json:"parseTime"`   // 解析耗時（毫秒）
+	StatTime    int64 `json:"statTime"`    // 統計計算耗時（毫秒）
+	MemoryUsed  int64 `json:"memoryUsed"`  // 記憶體使用量（位元組）
+	IndexTime   int64 `json:"indexTime"`   // 反向索引建立耗時（毫秒，未建立索引時為 0）
+	IndexMemory int64 `json:"indexMemory"` // 反向索引記憶體使用量（位元組，未建立索引時為 0）
+}
+
+// LogFileSummary 日誌檔案摘要
+// 包含 LogFile 的元資料與統計資訊，但不含日誌記錄，
+// 用於前後端傳輸避免序列化大量記錄
+type LogFileSummary struct {
+	// 檔案資訊
+	Path     string    `json:"path"`     // 檔案完整路徑
+	Name     string    `json:"name"`     // 檔案名稱
+	Size     int64     `json:"size"`     // 檔案大小（位元組）
+	LoadedAt time.Time `json:"loadedAt"` // 載入時間
+
+	// 解析統計
+	TotalLines  int `json:"totalLines"`  // 總行數
+	ParsedLines int `json:"parsedLines"` // 成功解析的行數
+	ErrorLines  int `json:"errorLines"`  // 解析失敗的行數
+
+	// 統計資訊（User Story 2）
+	Statistics interface{} `json:"statistics"` // 統計分析結果
+
+	// 效能指標
+	ParseTime   int64 `json:"parseTime"`   // 解析耗時（毫秒）
+	StatTime    int64 `json:"statTime"`    // 統計計算耗時（毫秒）
+	MemoryUsed  int64 `json:"memoryUsed"`  // 記憶體使用量（位元組）
+	IndexTime   int64 `json:"indexTime"`   // 反向索引建立耗時（毫秒，未建立索引時為 0）
+	IndexMemory int64 `json:"indexMemory"` // 反向索引記憶體使用量（位元組，未建立索引時為 0）
 }
 
 // NewLogFile 建立新的 LogFile 實例
@@ -61,6 +89,25 @@ func (f *LogFile) AddErrorEntry(lineNumber int, rawLine, errorMsg string) {
 	f.ErrorLines++
 }
 
+// Summary 取得不含日誌記錄的檔案摘要
+func (f *LogFile) Summary() *LogFileSummary {
+	return &LogFileSummary{
+		Path:        f.Path,
+		Name:        f.Name,
+		Size:        f.Size,
+		LoadedAt:    f.LoadedAt,
+		TotalLines:  f.TotalLines,
+		ParsedLines: f.ParsedLines,
+		ErrorLines:  f.ErrorLines,
+		Statistics:  f.Statistics,
+		ParseTime:   f.ParseTime,
+		StatTime:    f.StatTime,
+		MemoryUsed:  f.MemoryUsed,
+		IndexTime:   f.IndexTime,
+		IndexMemory: f.IndexMemory,
+	}
+}
+
 // GetSuccessRate 取得解析成功率（百分比）
 // 返回 0-100 的浮點數
 func (f *LogFile) GetSuccessRate() float64 {
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 正規化後的快取狀態
+const (
+	CacheHit         = "HIT"         // 由快取回應
+	CacheMiss        = "MISS"        // 快取中沒有，向後端取得
+	CacheExpired     = "EXPIRED"     // 快取已過期，向後端重新取得
+	CacheStale       = "STALE"       // 回應過期的快取內容
+	CacheUpdating    = "UPDATING"    // 快取更新中，回應舊的快取內容
+	CacheRevalidated = "REVALIDATED" // 向後端驗證後沿用快取內容
+	CacheBypass      = "BYPASS"      // 略過快取
+)
+
+// cacheStatusValues 各種伺服器記錄的快取狀態值（小寫）與正規化後的狀態
+// 涵蓋 nginx $upstream_cache_status、Apache mod_cache %{cache-status}e 與常見的 X-Cache 標頭
+var cacheStatusValues = map[string]string{
+	"hit":         CacheHit,
+	"miss":        CacheMiss,
+	"expired":     CacheExpired,
+	"stale":       CacheStale,
+	"updating":    CacheUpdating,
+	"revalidated": CacheRevalidated,
+	"bypass":      CacheBypass,
+	"dynamic":     CacheBypass,
+	"invalidated": CacheMiss,
+	"tcp_hit":     CacheHit,
+	"tcp_mem_hit": CacheHit,
+	"tcp_miss":    CacheMiss,
+}
+
+// trailingFieldPattern 匹配 User-Agent 之後的欄位（引號字串或不含空白的值）
+var trailingFieldPattern = regexp.MustCompile(`"([^"]*)"|(\S+)`)
+
+// parseCacheStatus 從 User-Agent 之後的欄位找出快取狀態，找不到時返回空字串
+// 接受 "HIT"、cache=MISS、X-Cache:HIT、"HIT from cdn" 與 Apache 的 "cache hit"、"conditional cache hit"、
+// "cache miss: attempting entity save" 等寫法
+func parseCacheStatus(trailing string) string {
+	for _, match := range trailingFieldPattern.FindAllStringSubmatch(trailing, -1) {
+		value := match[1]
+		if value == "" {
+			value = match[2]
+		}
+		// 去除 name=value 或 name:value 的欄位名稱（只在第一個空白之前，
+		// 避免截斷 Apache 的 "cache miss: attempting entity save"）
+		if i := strings.IndexAny(value, "=:"); i >= 0 && !strings.ContainsAny(value[:i], " \t") {
+			value = value[i+1:]
+		}
+		words := strings.Fields(strings.ToLower(value))
+		// Apache mod_cache 的狀態以 "conditional cache" 或 "cache" 開頭，狀態是其後的第一個字
+		for len(words) > 1 && (words[0] == "conditional" || words[0] == "cache") {
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if status, ok := cacheStatusValues[strings.TrimRight(words[0], ":;,")]; ok {
+			return status
+		}
+	}
+	return ""
+}
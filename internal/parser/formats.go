@@ -10,11 +10,16 @@ type LogFormat int
 const (
 	// FormatCombined 對應 Apache Combined Log Format
 	// 格式: %h %l %u %t \"%r\" %>s %b \"%{Referer}i\" \"%{User-agent}i\"
+	// User-Agent 之後的欄位會被忽略，但其中的快取狀態（例如 \"%{cache-status}e\"）會被讀取
 	FormatCombined LogFormat = iota
 
 	// FormatCommon 對應 Apache Common Log Format
 	// 格式: %h %l %u %t \"%r\" %>s %b
 	FormatCommon
+
+	// FormatVhostCombined 對應 Apache vhost_combined 格式（或以 %v 開頭的 Combined 格式）
+	// 格式: %v:%p %h %l %u %t \"%r\" %>s %O \"%{Referer}i\" \"%{User-Agent}i\"（連接埠可省略）
+	FormatVhostCombined
 )
 
 // 正規表達式模式定義
@@ -33,6 +38,21 @@ var (
 			`"([^"]*)"`, // User-Agent
 	)
 
+	// vhostCombinedLogPattern 匹配 vhost_combined 格式
+	// 範例: www.example.com:443 192.168.1.1 - - [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234 "https://example.com" "Mozilla/5.0"
+	vhostCombinedLogPattern = regexp.MustCompile(
+		`^(\[[0-9A-Fa-f:.]+\]|[^\s:\[]+)(?::(\d+))? ` + // 虛擬主機（IPv6 位址加中括號）與連接埠（連接埠可省略）
+			`(\S+) ` + // IP 位址
+			`(\S+) ` + // 識別符號（通常是 -）
+			`(\S+) ` + // 使用者 ID（通常是 -）
+			`\[([^\]]+)\] ` + // 時間戳 [日期時間]
+			`"([A-Z]+) ([^\s]+) ([^"]+)" ` + // 請求方法 URL 協定
+			`(\d{3}) ` + // 狀態碼
+			`(\S+) ` + // 回應大小（可能是 -）
+			`"([^"]*)" ` + // Referer
+			`"([^"]*)"`, // User-Agent
+	)
+
 	// commonLogPattern 匹配 Common Log Format
 	// 範例: 192.168.1.1 - - [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234
 	commonLogPattern = regexp.MustCompile(
@@ -53,6 +73,8 @@ func GetPattern(format LogFormat) *regexp.Regexp {
 		return combinedLogPattern
 	case FormatCommon:
 		return commonLogPattern
+	case FormatVhostCombined:
+		return vhostCombinedLogPattern
 	default:
 		return combinedLogPattern
 	}
@@ -66,6 +88,11 @@ func DetectFormat(line string) LogFormat {
 		return FormatCombined
 	}
 
+	// 嘗試匹配 vhost_combined 格式（多出虛擬主機與連接埠）
+	if vhostCombinedLogPattern.MatchString(line) {
+		return FormatVhostCombined
+	}
+
 	// 嘗試匹配 Common 格式（10 個群組）
 	if commonLogPattern.MatchString(line) {
 		return FormatCommon
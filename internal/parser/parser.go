@@ -5,10 +5,12 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/subnet"
 	"access-log-analyzer/pkg/apachelog"
 	"access-log-analyzer/pkg/logger"
 )
@@ -188,71 +190,75 @@ func (p *Parser) parseLine(lineNum int, line string, pattern *regexp.Regexp) (*m
 	entry.LineNumber = lineNum
 	entry.RawLine = line
 
-	if p.format == FormatCombined {
-		// Combined 格式: IP, ident, user, time, method, url, protocol, status, size, referer, ua
-		entry.IP = matches[1]
-		// matches[2] 是 ident（通常是 -）
-		entry.User = matches[3]
-
-		// 解析時間戳
-		timestamp, err := parseApacheTime(matches[4])
-		if err != nil {
-			return nil, fmt.Errorf("無法解析時間戳: %w", err)
+	switch p.format {
+	case FormatVhostCombined:
+		// vhost_combined 格式: 虛擬主機, 連接埠, 其後與 Combined 格式相同
+		entry.VirtualHost = strings.ToLower(matches[1])
+		if matches[2] != "" {
+			if port, err := strconv.Atoi(matches[2]); err == nil {
+				entry.ServerPort = port
+			}
 		}
-		entry.Timestamp = timestamp
-
-		entry.Method = matches[5]
-		entry.URL = matches[6]
-		entry.Protocol = matches[7]
-
-		// 解析狀態碼
-		statusCode, err := strconv.Atoi(matches[8])
-		if err != nil {
-			return nil, fmt.Errorf("無法解析狀態碼: %w", err)
+		// 略過虛擬主機欄位，讓請求欄位的索引與 Combined 格式一致
+		if err := parseRequestFields(&entry, matches[2:]); err != nil {
+			return nil, err
 		}
-		entry.StatusCode = statusCode
+		entry.Referer = matches[12]
+		entry.UserAgent = matches[13]
+		entry.CacheStatus = parseCacheStatus(line[len(matches[0]):])
 
-		// 解析回應大小
-		if matches[9] != "-" {
-			size, err := strconv.ParseInt(matches[9], 10, 64)
-			if err == nil {
-				entry.ResponseBytes = size
-			}
+	case FormatCombined:
+		// Combined 格式: IP, ident, user, time, method, url, protocol, status, size, referer, ua
+		if err := parseRequestFields(&entry, matches); err != nil {
+			return nil, err
 		}
-
 		entry.Referer = matches[10]
 		entry.UserAgent = matches[11]
+		entry.CacheStatus = parseCacheStatus(line[len(matches[0]):])
 
-	} else if p.format == FormatCommon {
+	case FormatCommon:
 		// Common 格式: IP, ident, user, time, method, url, protocol, status, size
-		entry.IP = matches[1]
-		entry.User = matches[3]
-
-		timestamp, err := parseApacheTime(matches[4])
-		if err != nil {
-			return nil, fmt.Errorf("無法解析時間戳: %w", err)
+		if err := parseRequestFields(&entry, matches); err != nil {
+			return nil, err
 		}
-		entry.Timestamp = timestamp
+	}
 
-		entry.Method = matches[5]
-		entry.URL = matches[6]
-		entry.Protocol = matches[7]
+	return &entry, nil
+}
 
-		statusCode, err := strconv.Atoi(matches[8])
-		if err != nil {
-			return nil, fmt.Errorf("無法解析狀態碼: %w", err)
-		}
-		entry.StatusCode = statusCode
+// parseRequestFields 解析 Common 與 Combined 格式共用的欄位
+// matches[1] 到 matches[9] 依序為 IP, ident, user, time, method, url, protocol, status, size
+func parseRequestFields(entry *models.LogEntry, matches []string) error {
+	entry.IP = subnet.Canonical(matches[1])
+	// matches[2] 是 ident（通常是 -）
+	entry.User = matches[3]
 
-		if matches[9] != "-" {
-			size, err := strconv.ParseInt(matches[9], 10, 64)
-			if err == nil {
-				entry.ResponseBytes = size
-			}
-		}
+	// 解析時間戳
+	timestamp, err := parseApacheTime(matches[4])
+	if err != nil {
+		return fmt.Errorf("無法解析時間戳: %w", err)
 	}
+	entry.Timestamp = timestamp
 
-	return &entry, nil
+	entry.Method = matches[5]
+	entry.URL = matches[6]
+	entry.Protocol = matches[7]
+
+	// 解析狀態碼
+	statusCode, err := strconv.Atoi(matches[8])
+	if err != nil {
+		return fmt.Errorf("無法解析狀態碼: %w", err)
+	}
+	entry.StatusCode = statusCode
+
+	// 解析回應大小
+	if matches[9] != "-" {
+		size, err := strconv.ParseInt(matches[9], 10, 64)
+		if err == nil {
+			entry.ResponseBytes = size
+		}
+	}
+	return nil
 }
 
 // parseApacheTime 解析 Apache log 時間格式
@@ -322,6 +328,22 @@ func (p *Parser) ValidateFormat(filepath string, sampleLines int) (bool, error)
 	return successRate >= 0.8, nil
 }
 
+// DetectFileFormat 依檔案第一行自動偵測 log 格式
+// 檔案為空或無法辨識時返回 FormatCombined，由 ValidateFirstLine 回報格式錯誤
+func DetectFileFormat(filepath string) (LogFormat, error) {
+	reader, err := apachelog.NewReader(filepath)
+	if err != nil {
+		return FormatCombined, fmt.Errorf("無法開啟檔案: %w", err)
+	}
+	defer reader.Close()
+
+	_, line, hasMore := reader.ReadLine()
+	if !hasMore {
+		return FormatCombined, nil
+	}
+	return DetectFormat(line), nil
+}
+
 // ValidateFirstLine 快速驗證檔案第一行是否為 Apache log 格式
 // 用於在選擇檔案後立即檢查格式，提供快速回饋
 func (p *Parser) ValidateFirstLine(filepath string) error {
@@ -340,7 +362,7 @@ func (p *Parser) ValidateFirstLine(filepath string) error {
 	// 檢查是否符合 Apache log 格式
 	pattern := GetPattern(p.format)
 	if !pattern.MatchString(line) {
-		return fmt.Errorf("第一行不符合 Apache Access Log 格式。請確認檔案是 Apache Combined 或 Common 格式的 access log")
+		return fmt.Errorf("第一行不符合 Apache Access Log 格式。請確認檔案是 Apache Combined、Common 或 vhost_combined 格式的 access log")
 	}
 
 	return nil
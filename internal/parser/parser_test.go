@@ -91,6 +91,109 @@ func TestParseLine_CommonFormat(t *testing.T) {
 	assert.Empty(t, entry.Referer) // Common 格式沒有 Referer
 }
 
+// TestParseLine_IPv6 測試用戶端位址的標準化
+func TestParseLine_IPv6(t *testing.T) {
+	parser := NewParser(FormatCombined, 1)
+	pattern := GetPattern(FormatCombined)
+
+	testCases := map[string]string{
+		"2001:DB8:0:0::1":     "2001:db8::1",
+		"[2001:db8::1]:443":   "2001:db8::1",
+		"[fe80::1%25eth0]":    "fe80::1%eth0",
+		"::ffff:192.168.1.10": "192.168.1.10",
+		"host.example.com":    "host.example.com", // HostnameLookups 的主機名稱原樣保留
+	}
+	for raw, expected := range testCases {
+		line := raw + ` - - [06/Nov/2025:14:30:15 +0800] "GET / HTTP/1.1" 200 1234 "-" "curl/8.0"`
+		entry, err := parser.parseLine(1, line, pattern)
+		require.NoError(t, err, raw)
+		assert.Equal(t, expected, entry.IP, raw)
+	}
+}
+
+// TestParseLine_VhostCombined 測試 vhost_combined 格式的虛擬主機與連接埠
+func TestParseLine_VhostCombined(t *testing.T) {
+	parser := NewParser(FormatVhostCombined, 1)
+	pattern := GetPattern(FormatVhostCombined)
+
+	line := `WWW.Example.com:443 192.168.1.100 - admin [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234 "https://example.com" "Mozilla/5.0"`
+	entry, err := parser.parseLine(1, line, pattern)
+	require.NoError(t, err)
+	assert.Equal(t, "www.example.com", entry.VirtualHost)
+	assert.Equal(t, 443, entry.ServerPort)
+	assert.Equal(t, "192.168.1.100", entry.IP)
+	assert.Equal(t, "admin", entry.User)
+	assert.Equal(t, "/index.html", entry.URL)
+	assert.Equal(t, 200, entry.StatusCode)
+	assert.Equal(t, int64(1234), entry.ResponseBytes)
+	assert.Equal(t, "https://example.com", entry.Referer)
+	assert.Equal(t, "Mozilla/5.0", entry.UserAgent)
+
+	// 只有 %v，沒有連接埠
+	line = `api.example.com 2001:db8::1 - - [06/Nov/2025:14:30:15 +0800] "POST /v1 HTTP/1.1" 201 - "-" "curl/8.0"`
+	entry, err = parser.parseLine(2, line, pattern)
+	require.NoError(t, err)
+	assert.Equal(t, "api.example.com", entry.VirtualHost)
+	assert.Zero(t, entry.ServerPort)
+	assert.Equal(t, "2001:db8::1", entry.IP)
+	assert.Equal(t, 201, entry.StatusCode)
+
+	// 以 IPv6 位址作為虛擬主機時以中括號與連接埠分隔
+	line = `[2001:DB8::1]:443 192.168.1.100 - - [06/Nov/2025:14:30:15 +0800] "GET / HTTP/1.1" 200 10 "-" "curl/8.0"`
+	entry, err = parser.parseLine(3, line, pattern)
+	require.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]", entry.VirtualHost)
+	assert.Equal(t, 443, entry.ServerPort)
+	assert.Equal(t, "192.168.1.100", entry.IP)
+
+	line = `[2001:db8::1] 192.168.1.100 - - [06/Nov/2025:14:30:15 +0800] "GET / HTTP/1.1" 200 10 "-" "curl/8.0"`
+	entry, err = parser.parseLine(4, line, pattern)
+	require.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]", entry.VirtualHost)
+	assert.Zero(t, entry.ServerPort)
+	assert.Equal(t, FormatVhostCombined, DetectFormat(line))
+}
+
+// TestParseLine_CacheStatus 測試讀取 User-Agent 之後的快取狀態欄位
+func TestParseLine_CacheStatus(t *testing.T) {
+	prefix := `192.168.1.100 - - [06/Nov/2025:14:30:15 +0800] "GET /app.js HTTP/1.1" 200 1234 "-" "Mozilla/5.0"`
+	testCases := []struct {
+		name     string
+		trailing string
+		expected string
+	}{
+		{name: "沒有額外欄位", trailing: "", expected: ""},
+		{name: "nginx upstream_cache_status", trailing: ` "HIT"`, expected: "HIT"},
+		{name: "欄位名稱與值", trailing: ` cache=miss 0.012`, expected: "MISS"},
+		{name: "X-Cache 標頭", trailing: ` "TCP_MEM_HIT from cdn-01"`, expected: "HIT"},
+		{name: "Apache mod_cache", trailing: ` "conditional cache hit"`, expected: "HIT"},
+		{name: "Apache 重新驗證", trailing: ` "cache revalidated"`, expected: "REVALIDATED"},
+		{name: "Apache 未命中並儲存", trailing: ` "cache miss: attempting entity save"`, expected: "MISS"},
+		{name: "Apache 未命中不儲存", trailing: ` "cache miss: cache unwilling to store response"`, expected: "MISS"},
+		{name: "Apache 命中", trailing: ` "cache hit"`, expected: "HIT"},
+		{name: "Apache 已失效", trailing: ` "cache invalidated"`, expected: "MISS"},
+		{name: "略過快取", trailing: ` 1234 "BYPASS"`, expected: "BYPASS"},
+		{name: "無法辨識", trailing: ` "-" 0.012`, expected: ""},
+	}
+
+	parser := NewParser(FormatCombined, 1)
+	pattern := GetPattern(FormatCombined)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, err := parser.parseLine(1, prefix+tc.trailing, pattern)
+			require.NoError(t, err)
+			assert.Equal(t, "Mozilla/5.0", entry.UserAgent)
+			assert.Equal(t, tc.expected, entry.CacheStatus)
+		})
+	}
+
+	// vhost_combined 格式同樣讀取快取狀態
+	vhostParser := NewParser(FormatVhostCombined, 1)
+	entry, err := vhostParser.parseLine(1, "www.example.com:443 "+prefix+` "STALE"`, GetPattern(FormatVhostCombined))
+	require.NoError(t, err)
+	assert.Equal(t, "STALE", entry.CacheStatus)
+}
+
 // TestParseApacheTime 測試時間解析
 func TestParseApacheTime(t *testing.T) {
 	testCases := []struct {
@@ -158,6 +261,16 @@ func TestDetectFormat(t *testing.T) {
 			line:         `192.168.1.1 - - [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234`,
 			expectFormat: FormatCommon,
 		},
+		{
+			name:         "vhost_combined 格式",
+			line:         `www.example.com:443 192.168.1.1 - - [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234 "https://example.com" "Mozilla/5.0"`,
+			expectFormat: FormatVhostCombined,
+		},
+		{
+			name:         "用戶端為 IPv6 的 Combined 格式",
+			line:         `2001:db8::1 - - [06/Nov/2025:14:30:15 +0800] "GET /index.html HTTP/1.1" 200 1234 "-" "Mozilla/5.0"`,
+			expectFormat: FormatCombined,
+		},
 		{
 			name:         "無法辨識（預設 Combined）",
 			line:         "invalid log line",
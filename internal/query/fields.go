@@ -0,0 +1,324 @@
+package query
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/subnet"
+)
+
+// fieldKind 欄位的值類型
+type fieldKind int
+
+const (
+	kindString fieldKind = iota // 字串欄位
+	kindNumber                  // 數值欄位
+	kindTime                    // 時間欄位
+	kindBool                    // 布林欄位
+)
+
+// field 查詢可用的欄位
+// 依類型只會設定對應的取值函式
+type field struct {
+	name string
+	kind fieldKind
+	str  func(env *env, e *models.LogEntry) string
+	num  func(e *models.LogEntry) float64
+	tm   func(e *models.LogEntry) time.Time
+	flag func(env *env, e *models.LogEntry) bool
+}
+
+// fields 查詢可用的欄位（名稱不區分大小寫，可有多個別名）
+var fields = map[string]*field{}
+
+func init() {
+	register := func(f *field, aliases ...string) {
+		fields[strings.ToLower(f.name)] = f
+		for _, alias := range aliases {
+			fields[strings.ToLower(alias)] = f
+		}
+	}
+
+	// LogEntry 原始欄位
+	register(&field{name: "ip", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.IP }})
+	register(&field{name: "method", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.Method }})
+	register(&field{name: "url", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.URL }})
+	register(&field{name: "path", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return aggregate.StripQuery(e.URL) }})
+	register(&field{name: "protocol", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.Protocol }})
+	register(&field{name: "referer", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.Referer }}, "referrer")
+	register(&field{name: "userAgent", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.UserAgent }}, "ua")
+	register(&field{name: "user", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.User }})
+	register(&field{name: "status", kind: kindNumber, num: func(e *models.LogEntry) float64 { return float64(e.StatusCode) }}, "statusCode")
+	register(&field{name: "bytes", kind: kindNumber, num: func(e *models.LogEntry) float64 { return float64(e.ResponseBytes) }}, "size")
+	register(&field{name: "vhost", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.VirtualHost }}, "virtualHost")
+	register(&field{name: "port", kind: kindNumber, num: func(e *models.LogEntry) float64 { return float64(e.ServerPort) }}, "serverPort")
+	register(&field{name: "server", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.Server }})
+	register(&field{name: "cacheStatus", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return e.CacheStatus }}, "cache")
+	register(&field{name: "requestTime", kind: kindNumber, num: func(e *models.LogEntry) float64 { return float64(e.RequestTime) }})
+	register(&field{name: "line", kind: kindNumber, num: func(e *models.LogEntry) float64 { return float64(e.LineNumber) }}, "lineNumber")
+	register(&field{name: "time", kind: kindTime, tm: func(e *models.LogEntry) time.Time { return e.Timestamp }}, "timestamp")
+
+	// 衍生欄位
+	register(&field{name: "statusClass", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return StatusClass(e.StatusCode) }})
+	register(&field{name: "route", kind: kindString, str: func(_ *env, e *models.LogEntry) string { return NormalizeRoute(e.URL) }})
+	register(&field{name: "subnet", kind: kindString, str: func(env *env, e *models.LogEntry) string { return Subnet(e.IP, env.subnets) }})
+	register(&field{name: "botType", kind: kindString, str: func(env *env, e *models.LogEntry) string { return env.botType(e.UserAgent) }})
+	register(&field{name: "bot", kind: kindBool, flag: func(env *env, e *models.LogEntry) bool { return env.botType(e.UserAgent) != "" }})
+}
+
+// lookupField 依名稱查詢欄位（不區分大小寫）
+func lookupField(name string) (*field, bool) {
+	f, ok := fields[strings.ToLower(name)]
+	return f, ok
+}
+
+// compileBool 編譯單獨使用的布林欄位
+func (f *field) compileBool(env *env) predicate {
+	return func(e *models.LogEntry) bool { return f.flag(env, e) }
+}
+
+// compile 依欄位類型編譯比較運算
+func (f *field) compile(env *env, op token, val value) (predicate, error) {
+	switch f.kind {
+	case kindString:
+		return f.compileString(env, op, val)
+	case kindNumber:
+		return f.compileNumber(op, val)
+	case kindTime:
+		return f.compileTime(op, val)
+	default:
+		return f.compileBoolComparison(env, op, val)
+	}
+}
+
+// compileString 編譯字串欄位的比較運算
+// = 與 != 不區分大小寫；~ 與 !~ 為正規表達式比對；ip 欄位可使用 CIDR
+func (f *field) compileString(env *env, op token, val value) (predicate, error) {
+	get := f.str
+
+	switch op.text {
+	case "=", "!=":
+		var eq func(e *models.LogEntry) bool
+		if f.name == "ip" && strings.Contains(val.text, "/") {
+			_, network, err := net.ParseCIDR(val.text)
+			if err != nil {
+				return nil, &SyntaxError{Position: val.pos, Message: fmt.Sprintf("無效的 CIDR %q", val.text)}
+			}
+			eq = func(e *models.LogEntry) bool {
+				ip := net.ParseIP(e.IP)
+				return ip != nil && network.Contains(ip)
+			}
+		} else {
+			eq = func(e *models.LogEntry) bool { return strings.EqualFold(get(env, e), val.text) }
+		}
+		if op.text == "!=" {
+			return func(e *models.LogEntry) bool { return !eq(e) }, nil
+		}
+		return eq, nil
+
+	case "~", "!~":
+		re, err := regexp.Compile("(?i)" + val.text)
+		if err != nil {
+			return nil, &SyntaxError{Position: val.pos, Message: fmt.Sprintf("無效的正規表達式: %v", err)}
+		}
+		if op.text == "!~" {
+			return func(e *models.LogEntry) bool { return !re.MatchString(get(env, e)) }, nil
+		}
+		return func(e *models.LogEntry) bool { return re.MatchString(get(env, e)) }, nil
+	}
+
+	return nil, &SyntaxError{Position: op.pos, Message: fmt.Sprintf("欄位 %q 不支援運算子 %q", f.name, op.text)}
+}
+
+// compileGlob 編譯萬用字元比對（* 任意字元，? 單一字元，不區分大小寫）
+func (f *field) compileGlob(env *env, val value) (predicate, error) {
+	if f.kind != kindString {
+		return nil, &SyntaxError{Position: val.pos, Message: fmt.Sprintf("欄位 %q 不支援 like", f.name)}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("(?i)^")
+	for _, r := range val.text {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	re := regexp.MustCompile(sb.String())
+
+	get := f.str
+	return func(e *models.LogEntry) bool { return re.MatchString(get(env, e)) }, nil
+}
+
+// compileNumber 編譯數值欄位的比較運算
+func (f *field) compileNumber(op token, val value) (predicate, error) {
+	n, err := strconv.ParseFloat(val.text, 64)
+	if err != nil || val.isTime {
+		return nil, &SyntaxError{Position: val.pos, Message: fmt.Sprintf("欄位 %q 需要數值，但遇到 %q", f.name, val.text)}
+	}
+
+	cmp, err := compareFunc(op)
+	if err != nil {
+		return nil, err
+	}
+	get := f.num
+	return func(e *models.LogEntry) bool {
+		v := get(e)
+		switch {
+		case v < n:
+			return cmp(-1)
+		case v > n:
+			return cmp(1)
+		default:
+			return cmp(0)
+		}
+	}, nil
+}
+
+// compileTime 編譯時間欄位的比較運算
+// 值可為 now 相對時間或絕對時間字串
+func (f *field) compileTime(op token, val value) (predicate, error) {
+	t := val.time
+	if !val.isTime {
+		var err error
+		t, err = parseTime(val.text)
+		if err != nil {
+			return nil, &SyntaxError{Position: val.pos, Message: err.Error()}
+		}
+	}
+
+	cmp, err := compareFunc(op)
+	if err != nil {
+		return nil, err
+	}
+	get := f.tm
+	return func(e *models.LogEntry) bool { return cmp(get(e).Compare(t)) }, nil
+}
+
+// compileBoolComparison 編譯布林欄位的比較運算（bot = false）
+func (f *field) compileBoolComparison(env *env, op token, val value) (predicate, error) {
+	want, err := strconv.ParseBool(val.text)
+	if err != nil {
+		return nil, &SyntaxError{Position: val.pos, Message: fmt.Sprintf("欄位 %q 需要 true 或 false", f.name)}
+	}
+	if op.text != "=" && op.text != "!=" {
+		return nil, &SyntaxError{Position: op.pos, Message: fmt.Sprintf("欄位 %q 不支援運算子 %q", f.name, op.text)}
+	}
+	if op.text == "!=" {
+		want = !want
+	}
+	get := f.flag
+	return func(e *models.LogEntry) bool { return get(env, e) == want }, nil
+}
+
+// compareFunc 將比較運算子轉換為比較結果（-1, 0, 1）的判斷函式
+func compareFunc(op token) (func(c int) bool, error) {
+	switch op.text {
+	case "=":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	}
+	return nil, &SyntaxError{Position: op.pos, Message: fmt.Sprintf("運算子 %q 只能用於字串欄位", op.text)}
+}
+
+// 可接受的絕對時間格式
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime 解析絕對時間字串（未指定時區時使用本地時區）
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("無效的時間 %q，請使用 now-1h 或 2006-01-02 15:04:05 格式", s)
+}
+
+// parseDuration 解析時間長度，除標準格式外另支援 d（天）與 w（週）
+func parseDuration(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err == nil && count >= 0 {
+			unit := 24 * time.Hour
+			if s[n-1] == 'w' {
+				unit *= 7
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("無效的時間長度 %q，例如 30m、1h、7d", s)
+	}
+	return d, nil
+}
+
+// StatusClass 取得狀態碼類別（例如 404 -> "4xx"）
+func StatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "other"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// routeIDPattern 視為識別碼的路徑片段：純數字、UUID、長十六進位字串
+var routeIDPattern = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// NormalizeRoute 將 URL 正規化為路由樣板
+// 去除查詢字串，並將識別碼片段替換為 :id（例如 /users/123/orders -> /users/:id/orders）
+func NormalizeRoute(url string) string {
+	segments := strings.Split(aggregate.StripQuery(url), "/")
+	for i, seg := range segments {
+		if seg != "" && routeIDPattern.MatchString(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Subnet 依前綴長度設定取得 IP 所屬的網段（預設 IPv4 為 /24，IPv6 為 /64）
+// 無效的 IP 返回原字串
+func Subnet(ip string, opts subnet.Options) string {
+	addr, err := subnet.Parse(ip)
+	if err != nil {
+		return ip
+	}
+	return opts.Of(addr).String()
+}
+
+// botType 取得 User-Agent 的機器人類型（非機器人返回空字串）
+// 同一查詢中相同的 User-Agent 只偵測一次
+func (env *env) botType(userAgent string) string {
+	if cached, ok := env.botCache.Load(userAgent); ok {
+		return cached.(string)
+	}
+	_, botType := env.botDetector.IsBot(userAgent)
+	env.botCache.Store(userAgent, botType)
+	return botType
+}
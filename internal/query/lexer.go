@@ -0,0 +1,142 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 詞法單元類型
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota // 輸入結束
+	tokenWord                    // 未加引號的字詞（欄位名稱、關鍵字、數字、時間長度等）
+	tokenString                  // 加引號的字串
+	tokenOp                      // 比較運算子（= != > >= < <= ~ !~）
+	tokenLParen                  // (
+	tokenRParen                  // )
+	tokenComma                   // ,
+	tokenPlus                    // +
+	tokenMinus                   // -
+)
+
+// token 詞法單元
+type token struct {
+	kind tokenKind
+	text string // 字詞內容（字串已去除引號並處理跳脫字元）
+	pos  int    // 在查詢字串中的起始位置（以字元計，從 0 開始）
+}
+
+// SyntaxError 查詢語法錯誤
+// Position 為錯誤在查詢字串中的位置（以字元計，從 0 開始），方便 UI 標示
+type SyntaxError struct {
+	Position int    `json:"position"` // 錯誤位置
+	Message  string `json:"message"`  // 錯誤訊息
+}
+
+// Error 實現 error 介面
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("查詢語法錯誤（第 %d 個字元）: %s", e.Position+1, e.Message)
+}
+
+// lex 將查詢字串切分為詞法單元
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0, len(runes)/2)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '+':
+			tokens = append(tokens, token{kind: tokenPlus, text: "+", pos: i})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokenMinus, text: "-", pos: i})
+			i++
+
+		case r == '=' || r == '~':
+			tokens = append(tokens, token{kind: tokenOp, text: string(r), pos: i})
+			i++
+		case r == '!' || r == '>' || r == '<':
+			// 兩字元運算子：!= !~ >= <=
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				tokens = append(tokens, token{kind: tokenOp, text: string(runes[i : i+2]), pos: i})
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, &SyntaxError{Position: i, Message: "'!' 之後必須是 '=' 或 '~'"}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: string(r), pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if c == r {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Position: start, Message: "字串缺少結尾引號"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+
+		case isWordRune(r):
+			start := i
+			for i < len(runes) {
+				c := runes[i]
+				// '-' 在 now 之後是相對時間的運算子，其他位置屬於字詞
+				if c == '-' && strings.EqualFold(string(runes[start:i]), "now") {
+					break
+				}
+				if !isWordRune(c) && c != '-' {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+
+		default:
+			return nil, &SyntaxError{Position: i, Message: fmt.Sprintf("無法識別的字元 %q", r)}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// isWordRune 檢查字元是否可作為未加引號字詞的開頭
+// 允許 IP、CIDR、路徑與萬用字元不加引號直接輸入
+// '-' 在字詞中間時也屬於字詞（例如 /foo-bar、python-requests、2024-01-01），
+// 只有在 now 之後才是相對時間的運算子
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) ||
+		r == '_' || r == '.' || r == ':' || r == '/' || r == '*' || r == '?'
+}
@@ -0,0 +1,353 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+	"access-log-analyzer/internal/subnet"
+)
+
+// predicate 編譯後的查詢條件
+type predicate func(entry *models.LogEntry) bool
+
+// Query 已編譯的查詢
+// 查詢語法範例：status>=500 and path~"/api/" and not bot and time>now-1h
+type Query struct {
+	source string    // 原始查詢字串
+	match  predicate // 編譯後的條件
+}
+
+// Options 解析查詢時使用的選項
+type Options struct {
+	Now      time.Time         // 相對時間（now-1h）的基準，零值表示呼叫當下
+	BotRules *stats.BotRuleSet // bot/botType 欄位使用的機器人規則，nil 表示預設規則
+	Subnets  subnet.Options    // subnet 欄位的前綴長度，零值表示預設 IPv4 /24、IPv6 /64
+}
+
+// Parse 解析並編譯查詢字串
+// 相對時間（now-1h）以呼叫當下的時間為基準
+func Parse(input string) (*Query, error) {
+	return ParseWith(input, Options{})
+}
+
+// ParseAt 以指定時間作為 now 解析並編譯查詢字串
+func ParseAt(input string, now time.Time) (*Query, error) {
+	return ParseWith(input, Options{Now: now})
+}
+
+// ParseWith 以指定選項解析並編譯查詢字串
+// 空白查詢表示符合所有記錄
+func ParseWith(input string, opts Options) (*Query, error) {
+	if err := opts.Subnets.Validate(); err != nil {
+		return nil, err
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	p := &parser{
+		tokens: tokens,
+		now:    now,
+		env:    newEnv(opts.BotRules, opts.Subnets),
+	}
+
+	q := &Query{source: input}
+	if p.peek().kind == tokenEOF {
+		q.match = func(*models.LogEntry) bool { return true }
+		return q, nil
+	}
+
+	q.match, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Position: tok.pos, Message: fmt.Sprintf("預期查詢結束，但遇到 %q", tok.text)}
+	}
+	return q, nil
+}
+
+// String 返回原始查詢字串
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match 檢查日誌記錄是否符合查詢
+// 解析失敗的記錄一律不符合
+func (q *Query) Match(entry *models.LogEntry) bool {
+	if entry.ParseError != "" {
+		return false
+	}
+	return q.match(entry)
+}
+
+// parser 遞迴下降解析器
+// 文法：
+//
+//	expr       := and ("or" and)*
+//	and        := unary ("and" unary)*
+//	unary      := "not" unary | primary
+//	primary    := "(" expr ")" | comparison | field
+//	comparison := field op value | field ["not"] "in" "(" value ("," value)* ")" | field ["not"] "like" value
+//	value      := word | string | "now" [("+"|"-") duration]
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+	env    *env
+}
+
+// peek 查看目前的詞法單元
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next 取得目前的詞法單元並前進
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// peekKeyword 檢查目前的詞法單元是否為指定關鍵字（不區分大小寫）
+func (p *parser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+// parseOr 解析以 or 連接的條件
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *models.LogEntry) bool { return l(e) || right(e) }
+	}
+	return left, nil
+}
+
+// parseAnd 解析以 and 連接的條件
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *models.LogEntry) bool { return l(e) && right(e) }
+	}
+	return left, nil
+}
+
+// parseUnary 解析 not 前綴
+func (p *parser) parseUnary() (predicate, error) {
+	if p.peekKeyword("not") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *models.LogEntry) bool { return !inner(e) }, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary 解析括號、比較運算或布林欄位
+func (p *parser) parsePrimary() (predicate, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Position: closing.pos, Message: "缺少對應的 ')'"}
+		}
+		return inner, nil
+	case tokenWord:
+		// 欄位名稱，稍後處理
+	case tokenEOF:
+		return nil, &SyntaxError{Position: tok.pos, Message: "查詢不完整，預期欄位名稱"}
+	default:
+		return nil, &SyntaxError{Position: tok.pos, Message: fmt.Sprintf("預期欄位名稱，但遇到 %q", tok.text)}
+	}
+
+	f, ok := lookupField(tok.text)
+	if !ok {
+		return nil, &SyntaxError{Position: tok.pos, Message: fmt.Sprintf("未知的欄位 %q", tok.text)}
+	}
+
+	next := p.peek()
+	switch {
+	case next.kind == tokenOp:
+		p.next()
+		return p.parseComparison(f, next)
+	case p.peekKeyword("in"):
+		p.next()
+		return p.parseIn(f, tok)
+	case p.peekKeyword("like"):
+		p.next()
+		return p.parseLike(f, tok)
+	case p.peekKeyword("not"):
+		// field not in (...) / field not like "..."
+		notTok := p.next()
+		var inner predicate
+		var err error
+		switch {
+		case p.peekKeyword("in"):
+			p.next()
+			inner, err = p.parseIn(f, tok)
+		case p.peekKeyword("like"):
+			p.next()
+			inner, err = p.parseLike(f, tok)
+		default:
+			return nil, &SyntaxError{Position: notTok.pos, Message: "'not' 之後預期 'in' 或 'like'"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return func(e *models.LogEntry) bool { return !inner(e) }, nil
+	}
+
+	// 單獨的布林欄位，例如 bot
+	if f.kind != kindBool {
+		return nil, &SyntaxError{Position: next.pos, Message: fmt.Sprintf("欄位 %q 之後預期比較運算子", tok.text)}
+	}
+	return f.compileBool(p.env), nil
+}
+
+// parseComparison 解析比較運算的右側值並編譯
+func (p *parser) parseComparison(f *field, op token) (predicate, error) {
+	val, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return f.compile(p.env, op, val)
+}
+
+// parseIn 解析 IN 列表並編譯
+func (p *parser) parseIn(f *field, fieldTok token) (predicate, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, &SyntaxError{Position: open.pos, Message: "'in' 之後預期 '('"}
+	}
+
+	var preds []predicate
+	eq := token{kind: tokenOp, text: "=", pos: fieldTok.pos}
+	for {
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		pred, err := f.compile(p.env, eq, val)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+
+		sep := p.next()
+		if sep.kind == tokenRParen {
+			break
+		}
+		if sep.kind != tokenComma {
+			return nil, &SyntaxError{Position: sep.pos, Message: "IN 列表中預期 ',' 或 ')'"}
+		}
+	}
+
+	return func(e *models.LogEntry) bool {
+		for _, pred := range preds {
+			if pred(e) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseLike 解析萬用字元比對並編譯
+func (p *parser) parseLike(f *field, fieldTok token) (predicate, error) {
+	val, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return f.compileGlob(p.env, val)
+}
+
+// value 比較運算的右側值
+type value struct {
+	text   string    // 原始文字
+	pos    int       // 在查詢字串中的位置
+	time   time.Time // 相對時間運算結果（isTime 為 true 時有效）
+	isTime bool      // 是否為 now 相對時間
+}
+
+// parseValue 解析比較運算的右側值
+func (p *parser) parseValue() (value, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return value{text: tok.text, pos: tok.pos}, nil
+	case tokenWord:
+		if !strings.EqualFold(tok.text, "now") {
+			return value{text: tok.text, pos: tok.pos}, nil
+		}
+	case tokenEOF:
+		return value{}, &SyntaxError{Position: tok.pos, Message: "查詢不完整，預期比較值"}
+	default:
+		return value{}, &SyntaxError{Position: tok.pos, Message: fmt.Sprintf("預期比較值，但遇到 %q", tok.text)}
+	}
+
+	// 相對時間：now、now-1h、now+30m
+	t := p.now
+	if sign := p.peek(); sign.kind == tokenPlus || sign.kind == tokenMinus {
+		p.next()
+		durTok := p.next()
+		if durTok.kind != tokenWord {
+			return value{}, &SyntaxError{Position: durTok.pos, Message: "預期時間長度，例如 1h、30m、7d"}
+		}
+		d, err := parseDuration(durTok.text)
+		if err != nil {
+			return value{}, &SyntaxError{Position: durTok.pos, Message: err.Error()}
+		}
+		if sign.kind == tokenMinus {
+			d = -d
+		}
+		t = t.Add(d)
+	}
+	return value{text: tok.text, pos: tok.pos, time: t, isTime: true}, nil
+}
+
+// env 編譯時共用的資源
+type env struct {
+	botDetector *stats.BotDetector
+	botCache    sync.Map       // User-Agent -> 機器人類型
+	subnets     subnet.Options // subnet 欄位的前綴長度
+}
+
+// newEnv 建立編譯環境
+func newEnv(rules *stats.BotRuleSet, subnets subnet.Options) *env {
+	return &env{botDetector: stats.NewBotDetectorWithRules(rules), subnets: subnets.WithDefaults()}
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/subnet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuery_Match 測試查詢語言的比對結果
+func TestQuery_Match(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := &models.LogEntry{
+		IP:            "192.168.1.20",
+		Timestamp:     now.Add(-30 * time.Minute),
+		Method:        "GET",
+		URL:           "/api/users/12345/orders?page=2",
+		Protocol:      "HTTP/1.1",
+		StatusCode:    503,
+		ResponseBytes: 2048,
+		Referer:       "https://example.com/",
+		UserAgent:     "Mozilla/5.0 (Windows NT 10.0)",
+	}
+	botEntry := &models.LogEntry{
+		IP:         "66.249.66.1",
+		Timestamp:  now,
+		Method:     "GET",
+		URL:        "/robots.txt",
+		StatusCode: 200,
+		UserAgent:  "Mozilla/5.0 (compatible; Googlebot/2.1)",
+	}
+
+	toolEntry := &models.LogEntry{
+		IP:         "10.0.0.1",
+		Timestamp:  now,
+		Method:     "GET",
+		URL:        "/foo-bar/baz",
+		StatusCode: 200,
+		UserAgent:  "python-requests/2.31",
+	}
+
+	testCases := []struct {
+		name     string
+		query    string
+		entry    *models.LogEntry
+		expected bool
+	}{
+		{"空查詢", "", entry, true},
+		{"字詞中的連字號", "path~/foo-bar", toolEntry, true},
+		{"User-Agent 中的連字號", "ua~python-requests and status=200", toolEntry, true},
+		{"不加引號的日期", "time>=2023-12-31 and time<2024-01-03", entry, true},
+		{"now 之後的減號", "time>now-1h", toolEntry, true},
+		{"NOW 不區分大小寫", "time<NOW-1h", toolEntry, false},
+		{"範例查詢", `status>=500 and path~"/api/" and not bot and time>now-1h`, entry, true},
+		{"機器人", "bot", botEntry, true},
+		{"機器人類型", `botType = "搜尋引擎"`, botEntry, true},
+		{"布林比較", "bot = false", entry, true},
+		{"時間範圍外", "time > now-10m", entry, false},
+		{"絕對時間", `time >= "2024-01-01T00:00:00Z"`, entry, true},
+		{"狀態碼類別", "statusClass = 5xx", entry, true},
+		{"IN 列表", "status in (404, 503)", entry, true},
+		{"NOT IN 列表", "method not in (GET, HEAD)", entry, false},
+		{"CIDR", "ip = 192.168.0.0/16", entry, true},
+		{"CIDR 不符", "ip != 192.168.0.0/16", entry, false},
+		{"網段", `subnet = "192.168.1.0/24"`, entry, true},
+		{"路由正規化", `route = "/api/users/:id/orders"`, entry, true},
+		{"萬用字元", `url like "/api/*/orders*"`, entry, true},
+		{"萬用字元否定", `path not like "*.php"`, entry, true},
+		{"正規表達式否定", `ua !~ "bot|spider"`, entry, true},
+		{"不區分大小寫", `METHOD = get`, entry, true},
+		{"OR 與括號", "(status = 200 or status = 503) and bytes > 4096", entry, false},
+		{"OR 優先順序", "status = 200 or status = 503 and bytes > 1000", entry, true},
+		{"雙重否定", "not not bot", botEntry, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseAt(tc.query, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q.Match(tc.entry))
+		})
+	}
+}
+
+// TestQuery_ParseError 測試語法錯誤的位置資訊
+func TestQuery_ParseError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		position int
+	}{
+		{"未知欄位", "status>=500 and foo=1", 16},
+		{"缺少比較值", "status>=", 8},
+		{"缺少右括號", "(status=500", 11},
+		{"字串未結尾", `path~"/api`, 5},
+		{"無效的數值", "status>abc", 7},
+		{"數值不接受單位", "bytes > 1k", 8},
+		{"無效的正規表達式", `path~"("`, 5},
+		{"無效的時間長度", "time>now-1x", 9},
+		{"非布林欄位單獨使用", "status and bot", 7},
+		{"字串欄位不支援大於", "path > 1", 5},
+		{"無法識別的字元", "status=500 & bot", 11},
+		{"多餘的詞", "bot bot", 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.query)
+			require.Error(t, err)
+
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tc.position, syntaxErr.Position, syntaxErr.Message)
+		})
+	}
+}
+
+// TestNormalizeRoute 測試路由正規化
+func TestNormalizeRoute(t *testing.T) {
+	assert.Equal(t, "/users/:id", NormalizeRoute("/users/42"))
+	assert.Equal(t, "/files/:id/download", NormalizeRoute("/files/550e8400-e29b-41d4-a716-446655440000/download?x=1"))
+	assert.Equal(t, "/static/app.js", NormalizeRoute("/static/app.js"))
+}
+
+// TestSubnet 測試網段計算
+func TestSubnet(t *testing.T) {
+	assert.Equal(t, "10.1.2.0/24", Subnet("10.1.2.3", subnet.Options{}))
+	assert.Equal(t, "2001:db8:1:2::/64", Subnet("2001:db8:1:2:3:4:5:6", subnet.Options{}))
+	assert.Equal(t, "invalid", Subnet("invalid", subnet.Options{}))
+	assert.Equal(t, "10.1.0.0/16", Subnet("10.1.2.3", subnet.Options{IPv4Prefix: 16}))
+	assert.Equal(t, "2001:db8::/48", Subnet("2001:db8:0:2::1", subnet.Options{IPv6Prefix: 48}))
+
+	// 查詢的 subnet 欄位使用設定的前綴長度
+	entry := &models.LogEntry{IP: "10.1.2.3"}
+	q, err := ParseWith(`subnet = "10.1.0.0/16"`, Options{Subnets: subnet.Options{IPv4Prefix: 16}})
+	require.NoError(t, err)
+	assert.True(t, q.Match(entry))
+	q, err = Parse(`subnet = "10.1.0.0/16"`)
+	require.NoError(t, err)
+	assert.False(t, q.Match(entry))
+
+	_, err = ParseWith("bot", Options{Subnets: subnet.Options{IPv4Prefix: 33}})
+	var validationErr *models.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
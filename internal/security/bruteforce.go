@@ -0,0 +1,373 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 暴力破解事件類型
+const (
+	IncidentIP          = "ip"          // 單一 IP 多次登入失敗
+	IncidentUser        = "user"        // 單一帳號多次登入失敗
+	IncidentDistributed = "distributed" // 多個 IP 嘗試同一帳號（撞庫）
+)
+
+// DefaultAuthEndpoints 預設的認證端點（正規表示式，比對不含查詢字串的路徑）
+var DefaultAuthEndpoints = []string{
+	`(?i)^/login\b`,
+	`(?i)/wp-login\.php$`,
+	`(?i)^/api/auth\b`,
+	`(?i)^/(user|users|account|admin)/(login|signin|sign_in)\b`,
+	`(?i)/xmlrpc\.php$`,
+}
+
+// BruteForceConfig 暴力破解偵測的設定
+type BruteForceConfig struct {
+	Endpoints              []string `json:"endpoints"`              // 認證端點（正規表示式）；回應 401 的路徑一律視為 Basic 認證端點
+	Window                 string   `json:"window"`                 // 滑動時間窗，例如 "5m"
+	IPThreshold            int      `json:"ipThreshold"`            // 單一 IP 在時間窗內失敗達此次數即標記
+	UserThreshold          int      `json:"userThreshold"`          // 單一帳號在時間窗內失敗達此次數即標記
+	DistributedIPThreshold int      `json:"distributedIPThreshold"` // 單一帳號在時間窗內被此數量以上的 IP 嘗試失敗即標記
+}
+
+// DefaultBruteForceConfig 返回預設設定：5 分鐘內 IP 失敗 10 次、帳號失敗 10 次、或 5 個 IP 嘗試同一帳號
+func DefaultBruteForceConfig() BruteForceConfig {
+	return BruteForceConfig{
+		Endpoints:              append([]string(nil), DefaultAuthEndpoints...),
+		Window:                 "5m",
+		IPThreshold:            10,
+		UserThreshold:          10,
+		DistributedIPThreshold: 5,
+	}
+}
+
+// BruteForceReport 暴力破解偵測結果
+type BruteForceReport struct {
+	AuthAttempts   int                  `json:"authAttempts"`   // 認證請求數
+	FailedAttempts int                  `json:"failedAttempts"` // 失敗（401，或認證端點的 403）的認證請求數
+	Incidents      []BruteForceIncident `json:"incidents"`      // 超過門檻的事件（依開始時間排序）
+}
+
+// BruteForceIncident 單一暴力破解事件（一段超過門檻的時間窗）
+type BruteForceIncident struct {
+	Kind          string    `json:"kind"`          // 事件類型：ip、user、distributed
+	Key           string    `json:"key"`           // IP 或帳號
+	Start         time.Time `json:"start"`         // 第一次失敗時間
+	End           time.Time `json:"end"`           // 最後一次失敗時間
+	Failures      int       `json:"failures"`      // 事件期間的失敗次數
+	DistinctIPs   int       `json:"distinctIPs"`   // 事件期間的不重複來源 IP 數
+	DistinctUsers int       `json:"distinctUsers"` // 事件期間嘗試的不重複帳號數
+	Endpoints     []string  `json:"endpoints"`     // 事件期間的目標路徑（排序後）
+	Succeeded     bool      `json:"succeeded"`     // 事件開始後是否曾成功認證
+	SuccessTime   time.Time `json:"successTime"`   // 第一次成功認證時間
+	SuccessIP     string    `json:"successIP"`     // 成功認證的來源 IP
+	SuccessUser   string    `json:"successUser"`   // 成功認證的帳號（日誌有記錄時）
+}
+
+// authAttempt 單筆認證請求
+type authAttempt struct {
+	ts      time.Time
+	ip      string
+	user    string
+	path    string
+	failure bool
+	success bool
+}
+
+// BruteForceDetector 依設定偵測認證端點的暴力破解與撞庫攻擊
+type BruteForceDetector struct {
+	config    BruteForceConfig
+	window    time.Duration
+	endpoints []*regexp.Regexp
+}
+
+// NewBruteForceDetector 依設定建立偵測器，設定無效時返回 ValidationError
+// 未指定的欄位（零值）使用預設值
+func NewBruteForceDetector(config BruteForceConfig) (*BruteForceDetector, error) {
+	defaults := DefaultBruteForceConfig()
+	if len(config.Endpoints) == 0 {
+		config.Endpoints = defaults.Endpoints
+	}
+	if config.Window == "" {
+		config.Window = defaults.Window
+	}
+	if config.IPThreshold == 0 {
+		config.IPThreshold = defaults.IPThreshold
+	}
+	if config.UserThreshold == 0 {
+		config.UserThreshold = defaults.UserThreshold
+	}
+	if config.DistributedIPThreshold == 0 {
+		config.DistributedIPThreshold = defaults.DistributedIPThreshold
+	}
+
+	window, err := time.ParseDuration(config.Window)
+	if err != nil || window <= 0 {
+		return nil, &models.ValidationError{Field: "Window", Value: config.Window, Message: "無效的時間窗"}
+	}
+	for _, threshold := range []struct {
+		field string
+		value int
+	}{
+		{"IPThreshold", config.IPThreshold},
+		{"UserThreshold", config.UserThreshold},
+		{"DistributedIPThreshold", config.DistributedIPThreshold},
+	} {
+		if threshold.value < 2 {
+			return nil, &models.ValidationError{Field: threshold.field, Value: fmt.Sprint(threshold.value), Message: "門檻必須至少為 2"}
+		}
+	}
+
+	detector := &BruteForceDetector{config: config, window: window}
+	for _, pattern := range config.Endpoints {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, &models.ValidationError{Field: "Endpoints", Value: pattern, Message: "無效的正規表示式"}
+		}
+		detector.endpoints = append(detector.endpoints, re)
+	}
+	return detector, nil
+}
+
+// Config 返回偵測器使用的設定（已補上預設值）
+func (d *BruteForceDetector) Config() BruteForceConfig {
+	return d.config
+}
+
+// attempt 判斷日誌記錄是否為認證請求
+// 認證端點的請求、回應 401 的請求（Basic 認證），以及記錄了使用者的請求都視為認證請求
+func (d *BruteForceDetector) attempt(entry *models.LogEntry) (authAttempt, bool) {
+	path, _, _ := strings.Cut(entry.URL, "?")
+	user := entry.User
+	if user == "-" {
+		user = ""
+	}
+
+	endpoint := d.isEndpoint(path)
+	if entry.StatusCode != 401 && user == "" && !endpoint {
+		return authAttempt{}, false
+	}
+
+	// 取得登入表單（GET/HEAD）不算成功認證；Basic 認證成功時日誌會記錄使用者
+	submitted := user != "" || !(strings.EqualFold(entry.Method, "GET") || strings.EqualFold(entry.Method, "HEAD"))
+	return authAttempt{
+		ts:      entry.Timestamp,
+		ip:      entry.IP,
+		user:    user,
+		path:    path,
+		failure: isFailure(entry.StatusCode, endpoint),
+		success: entry.StatusCode < 400 && submitted,
+	}, true
+}
+
+// isEndpoint 判斷路徑是否符合任一認證端點
+func (d *BruteForceDetector) isEndpoint(path string) bool {
+	for _, re := range d.endpoints {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFailure 判斷認證請求是否失敗
+// 403 只在設定的認證端點上視為登入失敗；其他路徑的 403 是已認證使用者的權限不足
+func isFailure(statusCode int, endpoint bool) bool {
+	return statusCode == 401 || (statusCode == 403 && endpoint)
+}
+
+// Analyze 分析日誌記錄，找出超過失敗門檻的時間窗
+func (d *BruteForceDetector) Analyze(entries []models.LogEntry) BruteForceReport {
+	report := BruteForceReport{Incidents: make([]BruteForceIncident, 0)}
+
+	var failures, successes []authAttempt
+	for i := range entries {
+		if entries[i].ParseError != "" {
+			continue
+		}
+		attempt, ok := d.attempt(&entries[i])
+		if !ok || attempt.ts.IsZero() {
+			continue
+		}
+		report.AuthAttempts++
+		switch {
+		case attempt.failure:
+			report.FailedAttempts++
+			failures = append(failures, attempt)
+		case attempt.success:
+			successes = append(successes, attempt)
+		}
+	}
+
+	sortAttempts(failures)
+	sortAttempts(successes)
+
+	byIP := groupAttempts(failures, func(a authAttempt) string { return a.ip })
+	byUser := groupAttempts(failures, func(a authAttempt) string { return a.user })
+
+	for ip, attempts := range byIP {
+		for _, incident := range d.countWindows(attempts, d.config.IPThreshold) {
+			incident.Kind, incident.Key = IncidentIP, ip
+			d.markSuccess(&incident, successes, func(a authAttempt) bool { return a.ip == ip })
+			report.Incidents = append(report.Incidents, incident)
+		}
+	}
+	for user, attempts := range byUser {
+		if user == "" {
+			continue
+		}
+		match := func(a authAttempt) bool { return a.user == user }
+		for _, incident := range d.countWindows(attempts, d.config.UserThreshold) {
+			incident.Kind, incident.Key = IncidentUser, user
+			d.markSuccess(&incident, successes, match)
+			report.Incidents = append(report.Incidents, incident)
+		}
+		for _, incident := range d.distributedWindows(attempts, d.config.DistributedIPThreshold) {
+			incident.Kind, incident.Key = IncidentDistributed, user
+			d.markSuccess(&incident, successes, match)
+			report.Incidents = append(report.Incidents, incident)
+		}
+	}
+
+	sort.Slice(report.Incidents, func(i, j int) bool {
+		a, b := report.Incidents[i], report.Incidents[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Key < b.Key
+	})
+	return report
+}
+
+// countWindows 找出失敗次數在時間窗內達到門檻的區段，重疊的時間窗合併為一個事件
+// attempts 必須依時間排序
+func (d *BruteForceDetector) countWindows(attempts []authAttempt, threshold int) []BruteForceIncident {
+	var incidents []BruteForceIncident
+	start, end := -1, -1 // 目前事件的失敗範圍
+	left := 0
+	for right := range attempts {
+		for attempts[right].ts.Sub(attempts[left].ts) > d.window {
+			left++
+		}
+		if right-left+1 < threshold {
+			continue
+		}
+		if start >= 0 && left <= end {
+			end = right // 與目前事件重疊，延長事件
+			continue
+		}
+		if start >= 0 {
+			incidents = append(incidents, newIncident(attempts[start:end+1]))
+		}
+		start, end = left, right
+	}
+	if start >= 0 {
+		incidents = append(incidents, newIncident(attempts[start:end+1]))
+	}
+	return incidents
+}
+
+// distributedWindows 找出時間窗內不重複來源 IP 數達到門檻的區段（單一帳號），重疊的時間窗合併為一個事件
+// attempts 必須依時間排序
+func (d *BruteForceDetector) distributedWindows(attempts []authAttempt, threshold int) []BruteForceIncident {
+	var incidents []BruteForceIncident
+	start, end := -1, -1
+	left := 0
+	ips := make(map[string]int) // 時間窗內各 IP 的失敗次數
+	for right := range attempts {
+		ips[attempts[right].ip]++
+		for attempts[right].ts.Sub(attempts[left].ts) > d.window {
+			ip := attempts[left].ip
+			if ips[ip]--; ips[ip] == 0 {
+				delete(ips, ip)
+			}
+			left++
+		}
+		if len(ips) < threshold {
+			continue
+		}
+		if start >= 0 && left <= end {
+			end = right
+			continue
+		}
+		if start >= 0 {
+			incidents = append(incidents, newIncident(attempts[start:end+1]))
+		}
+		start, end = left, right
+	}
+	if start >= 0 {
+		incidents = append(incidents, newIncident(attempts[start:end+1]))
+	}
+	return incidents
+}
+
+// newIncident 由一段失敗記錄建立事件
+func newIncident(attempts []authAttempt) BruteForceIncident {
+	ips := make(map[string]struct{})
+	users := make(map[string]struct{})
+	paths := make(map[string]struct{})
+	for _, attempt := range attempts {
+		ips[attempt.ip] = struct{}{}
+		if attempt.user != "" {
+			users[attempt.user] = struct{}{}
+		}
+		paths[attempt.path] = struct{}{}
+	}
+
+	endpoints := make([]string, 0, len(paths))
+	for path := range paths {
+		endpoints = append(endpoints, path)
+	}
+	sort.Strings(endpoints)
+
+	return BruteForceIncident{
+		Start:         attempts[0].ts,
+		End:           attempts[len(attempts)-1].ts,
+		Failures:      len(attempts),
+		DistinctIPs:   len(ips),
+		DistinctUsers: len(users),
+		Endpoints:     endpoints,
+	}
+}
+
+// markSuccess 標記事件開始後第一次成功的認證
+// successes 必須依時間排序
+func (d *BruteForceDetector) markSuccess(incident *BruteForceIncident, successes []authAttempt, match func(authAttempt) bool) {
+	i := sort.Search(len(successes), func(i int) bool {
+		return !successes[i].ts.Before(incident.Start)
+	})
+	for ; i < len(successes); i++ {
+		if match(successes[i]) {
+			incident.Succeeded = true
+			incident.SuccessTime = successes[i].ts
+			incident.SuccessIP = successes[i].ip
+			incident.SuccessUser = successes[i].user
+			return
+		}
+	}
+}
+
+// sortAttempts 依時間排序認證請求（時間相同時保持原順序）
+func sortAttempts(attempts []authAttempt) {
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].ts.Before(attempts[j].ts)
+	})
+}
+
+// groupAttempts 依鍵值分組認證請求，保持原順序
+func groupAttempts(attempts []authAttempt, key func(authAttempt) string) map[string][]authAttempt {
+	groups := make(map[string][]authAttempt)
+	for _, attempt := range attempts {
+		k := key(attempt)
+		groups[k] = append(groups[k], attempt)
+	}
+	return groups
+}
@@ -0,0 +1,175 @@
+package security
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bruteForceBase = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// loginEntry 建立認證請求的日誌記錄
+func loginEntry(ip, user, url string, status int, offset time.Duration) models.LogEntry {
+	return models.LogEntry{
+		IP:         ip,
+		User:       user,
+		Timestamp:  bruteForceBase.Add(offset),
+		Method:     "POST",
+		URL:        url,
+		StatusCode: status,
+	}
+}
+
+// newTestBruteForceDetector 建立門檻較低的偵測器
+func newTestBruteForceDetector(t *testing.T) *BruteForceDetector {
+	detector, err := NewBruteForceDetector(BruteForceConfig{
+		Window:                 "1m",
+		IPThreshold:            5,
+		UserThreshold:          5,
+		DistributedIPThreshold: 3,
+	})
+	require.NoError(t, err)
+	return detector
+}
+
+// TestBruteForceDetector_單一IP 測試單一 IP 在時間窗內多次登入失敗
+func TestBruteForceDetector_單一IP(t *testing.T) {
+	detector := newTestBruteForceDetector(t)
+
+	var entries []models.LogEntry
+	// 第一波：10 秒內 6 次失敗，之後成功登入
+	for i := 0; i < 6; i++ {
+		entries = append(entries, loginEntry("203.0.113.5", "", "/login", 401, time.Duration(i)*2*time.Second))
+	}
+	entries = append(entries, loginEntry("203.0.113.5", "", "/login", 302, 20*time.Second))
+	// 第二波：一小時後 5 次失敗，沒有成功
+	for i := 0; i < 5; i++ {
+		entries = append(entries, loginEntry("203.0.113.5", "", "/wp-login.php", 403, time.Hour+time.Duration(i)*time.Second))
+	}
+	// 低於門檻的 IP：失敗次數足夠但分散在時間窗外
+	for i := 0; i < 5; i++ {
+		entries = append(entries, loginEntry("198.51.100.7", "", "/login", 401, time.Duration(i)*2*time.Minute))
+	}
+	// 非認證端點的 403 不計入
+	entries = append(entries, loginEntry("192.0.2.1", "", "/private/report.pdf", 403, 0))
+
+	report := detector.Analyze(entries)
+	assert.Equal(t, 17, report.AuthAttempts)
+	assert.Equal(t, 16, report.FailedAttempts)
+	require.Len(t, report.Incidents, 2)
+
+	first := report.Incidents[0]
+	assert.Equal(t, IncidentIP, first.Kind)
+	assert.Equal(t, "203.0.113.5", first.Key)
+	assert.Equal(t, 6, first.Failures)
+	assert.Equal(t, bruteForceBase, first.Start)
+	assert.Equal(t, bruteForceBase.Add(10*time.Second), first.End)
+	assert.Equal(t, []string{"/login"}, first.Endpoints)
+	assert.True(t, first.Succeeded)
+	assert.Equal(t, bruteForceBase.Add(20*time.Second), first.SuccessTime)
+
+	second := report.Incidents[1]
+	assert.Equal(t, 5, second.Failures)
+	assert.Equal(t, []string{"/wp-login.php"}, second.Endpoints)
+	assert.False(t, second.Succeeded)
+}
+
+// TestBruteForceDetector_Basic認證與撞庫 測試帳號的失敗門檻與多個 IP 嘗試同一帳號
+func TestBruteForceDetector_Basic認證與撞庫(t *testing.T) {
+	detector := newTestBruteForceDetector(t)
+
+	var entries []models.LogEntry
+	// 4 個 IP 各嘗試 admin 兩次（Basic 認證，任何回應 401 的路徑）
+	for i := 0; i < 8; i++ {
+		ip := fmt.Sprintf("10.0.0.%d", i%4+1)
+		entries = append(entries, loginEntry(ip, "admin", "/admin/", 401, time.Duration(i)*5*time.Second))
+	}
+	// 其中一個 IP 最後成功
+	entries = append(entries, loginEntry("10.0.0.3", "admin", "/admin/", 200, 50*time.Second))
+
+	report := detector.Analyze(entries)
+	kinds := make(map[string]BruteForceIncident)
+	for _, incident := range report.Incidents {
+		kinds[incident.Kind] = incident
+	}
+	require.Len(t, kinds, 2, "每個 IP 只失敗兩次，不應產生 IP 事件")
+
+	user := kinds[IncidentUser]
+	assert.Equal(t, "admin", user.Key)
+	assert.Equal(t, 8, user.Failures)
+
+	distributed := kinds[IncidentDistributed]
+	assert.Equal(t, "admin", distributed.Key)
+	assert.Equal(t, 4, distributed.DistinctIPs)
+	assert.Equal(t, 1, distributed.DistinctUsers)
+	assert.True(t, distributed.Succeeded)
+	assert.Equal(t, "10.0.0.3", distributed.SuccessIP)
+	assert.Equal(t, "admin", distributed.SuccessUser)
+}
+
+// TestBruteForceDetector_取得登入表單不算成功 測試 GET 登入頁面不視為成功認證
+func TestBruteForceDetector_取得登入表單不算成功(t *testing.T) {
+	detector := newTestBruteForceDetector(t)
+
+	var entries []models.LogEntry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, loginEntry("203.0.113.5", "", "/api/auth/token", 401, time.Duration(i)*time.Second))
+	}
+	form := loginEntry("203.0.113.5", "", "/api/auth/token", 200, 10*time.Second)
+	form.Method = "GET"
+	entries = append(entries, form)
+
+	report := detector.Analyze(entries)
+	require.Len(t, report.Incidents, 1)
+	assert.False(t, report.Incidents[0].Succeeded)
+}
+
+// TestBruteForceDetector_權限不足與解析錯誤 測試已認證使用者在一般路徑的 403 與解析失敗的記錄不算登入失敗
+func TestBruteForceDetector_權限不足與解析錯誤(t *testing.T) {
+	detector := newTestBruteForceDetector(t)
+
+	var entries []models.LogEntry
+	for i := 0; i < 6; i++ {
+		entries = append(entries, loginEntry("10.0.0.9", "alice", "/reports/secret", 403, time.Duration(i)*time.Second))
+		broken := loginEntry("203.0.113.5", "", "/login", 401, time.Duration(i)*time.Second)
+		broken.ParseError = "無法匹配 log 格式"
+		entries = append(entries, broken)
+	}
+
+	report := detector.Analyze(entries)
+	assert.Equal(t, 6, report.AuthAttempts, "記錄了使用者的請求仍是認證請求")
+	assert.Zero(t, report.FailedAttempts)
+	assert.Empty(t, report.Incidents)
+}
+
+// TestNewBruteForceDetector_設定驗證 測試無效設定
+func TestNewBruteForceDetector_設定驗證(t *testing.T) {
+	detector, err := NewBruteForceDetector(BruteForceConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBruteForceConfig(), detector.Config(), "零值欄位使用預設值")
+
+	testCases := []struct {
+		name   string
+		config BruteForceConfig
+		field  string
+	}{
+		{"無效時間窗", BruteForceConfig{Window: "abc"}, "Window"},
+		{"負數時間窗", BruteForceConfig{Window: "-1m"}, "Window"},
+		{"門檻過低", BruteForceConfig{IPThreshold: 1}, "IPThreshold"},
+		{"無效端點", BruteForceConfig{Endpoints: []string{"("}}, "Endpoints"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewBruteForceDetector(tc.config)
+			var validationErr *models.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+}
@@ -0,0 +1,453 @@
+// Package security 偵測日誌中的攻擊行為
+// 與機器人偵測（依 User-Agent 判斷）不同，這裡直接檢查請求內容中的攻擊特徵
+package security
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 威脅嚴重程度
+const (
+	SeverityCritical = "critical" // 嚴重：可直接造成遠端程式碼執行或資料外洩
+	SeverityHigh     = "high"     // 高：常見的注入攻擊
+	SeverityMedium   = "medium"   // 中：探測敏感檔案
+)
+
+// 攻擊類別
+const (
+	CategorySQLInjection     = "SQL 注入"
+	CategoryXSS              = "跨站腳本"
+	CategoryPathTraversal    = "路徑穿越"
+	CategoryFileInclusion    = "檔案引入"
+	CategoryCommandInjection = "命令注入"
+	CategoryLog4Shell        = "Log4Shell"
+	CategorySensitiveProbe   = "敏感檔案探測"
+)
+
+// 結果數量上限
+const (
+	maxExamplesPerRule = 5  // 每條規則保留的範例數
+	maxAttackers       = 50 // 列出的攻擊來源 IP 數
+	maxDecodeRounds    = 3  // URL 解碼的最多次數（處理多重編碼）
+)
+
+// ThreatRule 攻擊特徵規則
+type ThreatRule struct {
+	ID       string `json:"id"`       // 規則代碼，例如 sqli-union
+	Name     string `json:"name"`     // 規則說明
+	Category string `json:"category"` // 攻擊類別
+	Severity string `json:"severity"` // 嚴重程度
+	PathOnly bool   `json:"pathOnly"` // 只比對路徑（不含查詢字串）
+
+	keywords []string       // 小寫關鍵字，URL 不含任何一個時跳過正規表示式（空值表示一律比對）
+	pattern  *regexp.Regexp // 攻擊特徵
+}
+
+// defaultThreatRules 內建的攻擊特徵規則
+// 比對對象為原始與解碼後的 URL；關鍵字預先篩選，避免對一般請求執行代價較高的正規表示式
+var defaultThreatRules = []ThreatRule{
+	{ID: "sqli-union", Name: "UNION SELECT 注入", Category: CategorySQLInjection, Severity: SeverityHigh,
+		keywords: []string{"union"},
+		pattern:  regexp.MustCompile(`(?i)\bunion\b[\s/*()]+(all[\s/*()]+)?select\b`)},
+	{ID: "sqli-tautology", Name: "恆真條件注入", Category: CategorySQLInjection, Severity: SeverityHigh,
+		keywords: []string{"'", `"`, ")", "or "},
+		pattern:  regexp.MustCompile(`(?i)['"\)]\s*(or|and)\s+['"]?\w+['"]?\s*(=|like)\s*['"]?\w+|\bor\s+\d+\s*=\s*\d+`)},
+	{ID: "sqli-function", Name: "SQL 函式與系統表", Category: CategorySQLInjection, Severity: SeverityHigh,
+		keywords: []string{"sleep", "benchmark", "extractvalue", "updatexml", "load_file", "waitfor", "information_schema", ";"},
+		pattern:  regexp.MustCompile(`(?i)\b(sleep|benchmark|pg_sleep|extractvalue|updatexml|load_file)\s*\(|\bwaitfor\s+delay\b|\binformation_schema\b|;\s*(drop|insert|update|delete)\s+`)},
+	{ID: "xss-script", Name: "Script 標籤或 javascript: 協定", Category: CategoryXSS, Severity: SeverityHigh,
+		keywords: []string{"script"},
+		pattern:  regexp.MustCompile(`(?i)<\s*/?\s*script\b|javascript\s*:|vbscript\s*:`)},
+	{ID: "xss-handler", Name: "HTML 事件處理器", Category: CategoryXSS, Severity: SeverityHigh,
+		keywords: []string{"<", "alert", "prompt", "confirm", "document."},
+		pattern:  regexp.MustCompile(`(?i)<[^>]*\bon(error|load|mouseover|focus|click|toggle|animationstart)\s*=|\b(alert|prompt|confirm)\s*\(|document\.(cookie|domain)`)},
+	{ID: "path-traversal", Name: "目錄穿越", Category: CategoryPathTraversal, Severity: SeverityHigh,
+		keywords: []string{".."},
+		pattern:  regexp.MustCompile(`(\.\.[/\\]){1,}|[/\\]\.\.$`)},
+	{ID: "lfi", Name: "本機檔案引入", Category: CategoryFileInclusion, Severity: SeverityCritical,
+		keywords: []string{"/etc/", "/proc/", ".ini", "://"},
+		pattern:  regexp.MustCompile(`(?i)/etc/(passwd|shadow|hosts)\b|/proc/self/(environ|cmdline|fd)|\b(boot|win)\.ini\b|\b(php|zip|phar|expect|data)://|\bfile://`)},
+	{ID: "rfi", Name: "遠端檔案引入", Category: CategoryFileInclusion, Severity: SeverityCritical,
+		keywords: []string{"://"},
+		pattern:  regexp.MustCompile(`(?i)[?&][\w\[\]]+=\s*(https?|ftp)://[^&]*?(\.(txt|php|sh|pl)\b|\?$)`)},
+	{ID: "cmd-injection", Name: "系統命令注入", Category: CategoryCommandInjection, Severity: SeverityCritical,
+		keywords: []string{";", "|", "&&", "`", "$(", "wget", "curl", "/bin/", "cmd"},
+		pattern:  regexp.MustCompile("(?i)(;|\\|\\|?|&&|`)\\s*(cat|ls|id|whoami|uname|wget|curl|nc|ncat|bash|sh|ping|nslookup|chmod|rm)\\b|\\$\\(\\s*\\w+|\\b(wget|curl)\\s+(-\\w+\\s+)*(https?|ftp)://|/bin/(ba)?sh\\b|\\bcmd(\\.exe)?\\s*/c\\b")},
+	{ID: "log4shell", Name: "Log4Shell JNDI 查詢", Category: CategoryLog4Shell, Severity: SeverityCritical,
+		keywords: []string{"${"},
+		pattern:  regexp.MustCompile(`(?i)\$\{\s*(jndi\s*:|[^}]{0,40}\$\{|(lower|upper|env|sys|date)\s*:|::-)`)},
+	{ID: "probe-env", Name: "探測 .env 設定檔", Category: CategorySensitiveProbe, Severity: SeverityMedium, PathOnly: true,
+		keywords: []string{"/.env"},
+		pattern:  regexp.MustCompile(`(?i)/\.env(\.\w+)?$`)},
+	{ID: "probe-vcs", Name: "探測版本控制目錄", Category: CategorySensitiveProbe, Severity: SeverityMedium, PathOnly: true,
+		keywords: []string{"/.git", "/.svn", "/.hg"},
+		pattern:  regexp.MustCompile(`(?i)/\.(git|svn|hg)(/|$)`)},
+	{ID: "probe-wordpress", Name: "探測 WordPress 登入與設定", Category: CategorySensitiveProbe, Severity: SeverityMedium, PathOnly: true,
+		keywords: []string{".php"},
+		pattern:  regexp.MustCompile(`(?i)/(wp-login\.php|wp-config\.php(\.\w+)?|xmlrpc\.php)$`)},
+	{ID: "probe-config", Name: "探測其他敏感檔案", Category: CategorySensitiveProbe, Severity: SeverityMedium, PathOnly: true,
+		keywords: []string{"/.", "phpinfo", "phpmyadmin"},
+		pattern:  regexp.MustCompile(`(?i)/(\.htaccess|\.htpasswd|\.aws/credentials|\.ssh/id_\w+|\.DS_Store|phpinfo\.php|phpmyadmin/?)$`)},
+}
+
+// ThreatStats 攻擊偵測的統計結果
+type ThreatStats struct {
+	AttackRequests     int              `json:"attackRequests"`     // 符合任一規則的請求數
+	SuccessfulRequests int              `json:"successfulRequests"` // 其中伺服器回應 2xx 的請求數
+	AttackerCount      int              `json:"attackerCount"`      // 不重複的攻擊來源 IP 數
+	Categories         map[string]int   `json:"categories"`         // 各攻擊類別的命中次數
+	Rules              []ThreatRuleStat `json:"rules"`              // 各規則的統計（依命中次數降序）
+	Attackers          []AttackerStat   `json:"attackers"`          // 攻擊來源 IP（依命中次數降序，最多 50 個）
+}
+
+// ThreatRuleStat 單一規則的命中統計
+type ThreatRuleStat struct {
+	ThreatRule
+	Hits        int             `json:"hits"`        // 命中的請求數
+	Successful  int             `json:"successful"`  // 伺服器回應 2xx 的請求數
+	DistinctIPs int             `json:"distinctIPs"` // 不重複的來源 IP 數
+	Examples    []ThreatExample `json:"examples"`    // 範例請求（最多 5 筆，優先保留 2xx 的請求）
+}
+
+// ThreatExample 命中規則的範例請求
+type ThreatExample struct {
+	LineNumber int       `json:"lineNumber"` // 原始檔案中的行號
+	IP         string    `json:"ip"`         // 來源 IP
+	Timestamp  time.Time `json:"timestamp"`  // 請求時間
+	Method     string    `json:"method"`     // HTTP 方法
+	URL        string    `json:"url"`        // 原始 URL
+	StatusCode int       `json:"statusCode"` // 回應狀態碼
+	Successful bool      `json:"successful"` // 伺服器是否回應 2xx
+	RawLine    string    `json:"rawLine"`    // 原始日誌行
+}
+
+// AttackerStat 單一攻擊來源 IP 的統計
+type AttackerStat struct {
+	IP         string    `json:"ip"`         // 來源 IP
+	Hits       int       `json:"hits"`       // 攻擊請求數
+	Successful int       `json:"successful"` // 伺服器回應 2xx 的攻擊請求數
+	Categories []string  `json:"categories"` // 使用過的攻擊類別（排序後）
+	FirstSeen  time.Time `json:"firstSeen"`  // 第一次攻擊時間
+	LastSeen   time.Time `json:"lastSeen"`   // 最後一次攻擊時間
+}
+
+// ruleAccumulator 累積單一規則的命中資訊
+type ruleAccumulator struct {
+	hits       int
+	successful int
+	ips        map[string]struct{}
+	examples   []ThreatExample
+}
+
+// attackerAccumulator 累積單一 IP 的攻擊資訊
+type attackerAccumulator struct {
+	hits       int
+	successful int
+	categories map[string]struct{}
+	firstSeen  time.Time
+	lastSeen   time.Time
+}
+
+// ThreatDetector 檢查請求 URL（路徑與查詢字串，含多重 URL 編碼）中的攻擊特徵
+type ThreatDetector struct {
+	mu         sync.Mutex
+	rules      []ThreatRule
+	stats      ThreatStats
+	ruleStats  []ruleAccumulator // 與 rules 同順序
+	attackers  map[string]*attackerAccumulator
+	categories map[string]int
+}
+
+// NewThreatDetector 建立使用內建規則的攻擊偵測器
+func NewThreatDetector() *ThreatDetector {
+	d := &ThreatDetector{rules: defaultThreatRules}
+	d.reset()
+	return d
+}
+
+// Rules 返回偵測器使用的規則
+func (d *ThreatDetector) Rules() []ThreatRule {
+	rules := make([]ThreatRule, len(d.rules))
+	copy(rules, d.rules)
+	return rules
+}
+
+// Match 返回 URL 符合的規則（不記錄統計）
+func (d *ThreatDetector) Match(rawURL string) []ThreatRule {
+	var matched []ThreatRule
+	for _, i := range d.match(rawURL) {
+		matched = append(matched, d.rules[i])
+	}
+	return matched
+}
+
+// match 返回 URL 符合的規則索引
+func (d *ThreatDetector) match(rawURL string) []int {
+	if rawURL == "" || rawURL == "-" {
+		return nil
+	}
+
+	variants := decodeVariants(rawURL)
+	paths := make([]string, len(variants))
+	lowered := make([]string, len(variants))
+	for i, variant := range variants {
+		paths[i], _, _ = strings.Cut(variant, "?")
+		lowered[i] = strings.ToLower(variant)
+	}
+
+	var matched []int
+	for i := range d.rules {
+		rule := &d.rules[i]
+		targets := variants
+		if rule.PathOnly {
+			targets = paths
+		}
+		for j, target := range targets {
+			if rule.hasKeyword(lowered[j]) && rule.pattern.MatchString(target) {
+				matched = append(matched, i)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// hasKeyword 判斷小寫化的 URL 是否含有規則的任一關鍵字
+func (r *ThreatRule) hasKeyword(lowerURL string) bool {
+	if len(r.keywords) == 0 {
+		return true
+	}
+	for _, keyword := range r.keywords {
+		if strings.Contains(lowerURL, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeVariants 返回原始 URL 與逐次 URL 解碼後的結果（直到不再變化）
+// 多重編碼（例如 %252e%252e%252f）會在第二次解碼後現形
+func decodeVariants(rawURL string) []string {
+	variants := []string{rawURL}
+	current := rawURL
+	for i := 0; i < maxDecodeRounds && strings.ContainsAny(current, "%+"); i++ {
+		decoded, err := url.QueryUnescape(current)
+		if err != nil {
+			// 含有不完整的 % 序列時改用寬鬆解碼
+			decoded = lenientUnescape(current)
+		}
+		if decoded == current {
+			break
+		}
+		variants = append(variants, decoded)
+		current = decoded
+	}
+	return variants
+}
+
+// lenientUnescape 解碼有效的 %XX 序列，保留無效的序列
+func lenientUnescape(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+			i += 2
+		case s[i] == '+':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// Observe 檢查日誌記錄並累積統計，返回命中的攻擊類別（未命中時為 nil）
+func (d *ThreatDetector) Observe(entry *models.LogEntry) []string {
+	matched := d.match(entry.URL)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	successful := entry.StatusCode >= 200 && entry.StatusCode < 300
+	example := ThreatExample{
+		LineNumber: entry.LineNumber,
+		IP:         entry.IP,
+		Timestamp:  entry.Timestamp,
+		Method:     entry.Method,
+		URL:        entry.URL,
+		StatusCode: entry.StatusCode,
+		Successful: successful,
+		RawLine:    entry.RawLine,
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stats.AttackRequests++
+	if successful {
+		d.stats.SuccessfulRequests++
+	}
+
+	attacker, exists := d.attackers[entry.IP]
+	if !exists {
+		attacker = &attackerAccumulator{categories: make(map[string]struct{})}
+		d.attackers[entry.IP] = attacker
+	}
+	attacker.hits++
+	if successful {
+		attacker.successful++
+	}
+	if !entry.Timestamp.IsZero() {
+		if attacker.firstSeen.IsZero() || entry.Timestamp.Before(attacker.firstSeen) {
+			attacker.firstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(attacker.lastSeen) {
+			attacker.lastSeen = entry.Timestamp
+		}
+	}
+
+	var categories []string
+	seen := make(map[string]bool, len(matched))
+	for _, i := range matched {
+		rule := d.rules[i]
+		acc := &d.ruleStats[i]
+		acc.hits++
+		if successful {
+			acc.successful++
+		}
+		acc.ips[entry.IP] = struct{}{}
+		acc.addExample(example)
+
+		if !seen[rule.Category] {
+			seen[rule.Category] = true
+			categories = append(categories, rule.Category)
+			d.categories[rule.Category]++
+			attacker.categories[rule.Category] = struct{}{}
+		}
+	}
+	return categories
+}
+
+// addExample 保留範例請求，已滿時以 2xx 的請求取代非 2xx 的請求
+func (a *ruleAccumulator) addExample(example ThreatExample) {
+	if len(a.examples) < maxExamplesPerRule {
+		a.examples = append(a.examples, example)
+		return
+	}
+	if !example.Successful {
+		return
+	}
+	for i := range a.examples {
+		if !a.examples[i].Successful {
+			a.examples[i] = example
+			return
+		}
+	}
+}
+
+// GetStats 取得目前的攻擊統計
+func (d *ThreatDetector) GetStats() ThreatStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := ThreatStats{
+		AttackRequests:     d.stats.AttackRequests,
+		SuccessfulRequests: d.stats.SuccessfulRequests,
+		AttackerCount:      len(d.attackers),
+		Categories:         make(map[string]int, len(d.categories)),
+		Rules:              make([]ThreatRuleStat, 0),
+		Attackers:          make([]AttackerStat, 0),
+	}
+	for category, count := range d.categories {
+		result.Categories[category] = count
+	}
+
+	// 各規則的統計（只列出有命中的規則）
+	for i, acc := range d.ruleStats {
+		if acc.hits == 0 {
+			continue
+		}
+		examples := make([]ThreatExample, len(acc.examples))
+		copy(examples, acc.examples)
+		result.Rules = append(result.Rules, ThreatRuleStat{
+			ThreatRule:  d.rules[i],
+			Hits:        acc.hits,
+			Successful:  acc.successful,
+			DistinctIPs: len(acc.ips),
+			Examples:    examples,
+		})
+	}
+	sort.SliceStable(result.Rules, func(i, j int) bool {
+		return result.Rules[i].Hits > result.Rules[j].Hits
+	})
+
+	// 攻擊來源 IP（依命中次數降序，次數相同依 IP 排序）
+	for ip, acc := range d.attackers {
+		categories := make([]string, 0, len(acc.categories))
+		for category := range acc.categories {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		result.Attackers = append(result.Attackers, AttackerStat{
+			IP:         ip,
+			Hits:       acc.hits,
+			Successful: acc.successful,
+			Categories: categories,
+			FirstSeen:  acc.firstSeen,
+			LastSeen:   acc.lastSeen,
+		})
+	}
+	sort.Slice(result.Attackers, func(i, j int) bool {
+		if result.Attackers[i].Hits != result.Attackers[j].Hits {
+			return result.Attackers[i].Hits > result.Attackers[j].Hits
+		}
+		return result.Attackers[i].IP < result.Attackers[j].IP
+	})
+	if len(result.Attackers) > maxAttackers {
+		result.Attackers = result.Attackers[:maxAttackers]
+	}
+
+	return result
+}
+
+// ResetStats 重置統計資訊
+func (d *ThreatDetector) ResetStats() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reset()
+}
+
+// reset 重置統計資訊（呼叫者需持有鎖或尚未共用偵測器）
+func (d *ThreatDetector) reset() {
+	d.stats = ThreatStats{}
+	d.ruleStats = make([]ruleAccumulator, len(d.rules))
+	for i := range d.ruleStats {
+		d.ruleStats[i].ips = make(map[string]struct{})
+	}
+	d.attackers = make(map[string]*attackerAccumulator)
+	d.categories = make(map[string]int)
+}
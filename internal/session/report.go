@@ -0,0 +1,315 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// otherNode Sankey 圖中合併較少見頁面的節點名稱
+const otherNode = "(其他)"
+
+// Report 訪客工作階段分析結果
+type Report struct {
+	Options              Options           `json:"options"`              // 實際使用的參數（已補上預設值）
+	Sessions             int               `json:"sessions"`             // 工作階段數
+	Visitors             int               `json:"visitors"`             // 不重複訪客數
+	PageViews            int               `json:"pageViews"`            // 頁面瀏覽數
+	ExcludedBotRequests  int               `json:"excludedBotRequests"`  // 被排除的機器人請求數
+	BounceRate           float64           `json:"bounceRate"`           // 跳出率（只瀏覽一頁的工作階段，百分比）
+	AvgDuration          float64           `json:"avgDuration"`          // 平均持續時間（秒）
+	MedianDuration       float64           `json:"medianDuration"`       // 持續時間中位數（秒）
+	AvgDepth             float64           `json:"avgDepth"`             // 平均瀏覽頁數
+	DurationDistribution []DistributionBin `json:"durationDistribution"` // 持續時間分布
+	DepthDistribution    []DistributionBin `json:"depthDistribution"`    // 瀏覽頁數分布
+	EntryPages           []PageStat        `json:"entryPages"`           // 最常見的入口頁
+	ExitPages            []PageStat        `json:"exitPages"`            // 最常見的離開頁
+	Transitions          []Transition      `json:"transitions"`          // 最常見的頁面轉換
+	Sankey               SankeyData        `json:"sankey"`               // 依步數展開的訪客路徑
+}
+
+// DistributionBin 分布的單一區間
+type DistributionBin struct {
+	Label      string  `json:"label"`      // 區間名稱
+	Count      int     `json:"count"`      // 工作階段數
+	Percentage float64 `json:"percentage"` // 佔比（百分比）
+}
+
+// PageStat 入口頁或離開頁的統計
+type PageStat struct {
+	Path       string  `json:"path"`       // 路徑
+	Count      int     `json:"count"`      // 工作階段數
+	Percentage float64 `json:"percentage"` // 佔全部工作階段的比例（百分比）
+}
+
+// Transition 頁面到頁面的轉換
+type Transition struct {
+	From  string `json:"from"`  // 來源頁面
+	To    string `json:"to"`    // 目標頁面
+	Count int    `json:"count"` // 轉換次數
+}
+
+// SankeyData Sankey 圖資料
+// 節點以「步數. 路徑」命名，同一路徑在不同步數為不同節點，確保圖中沒有循環
+type SankeyData struct {
+	Nodes []SankeyNode `json:"nodes"` // 節點
+	Links []SankeyLink `json:"links"` // 連線（Source、Target 為 Nodes 的索引）
+}
+
+// SankeyNode Sankey 圖節點
+type SankeyNode struct {
+	Name string `json:"name"` // 顯示名稱，例如 "2. /products"
+	Step int    `json:"step"` // 步數（從 1 開始）
+	Path string `json:"path"` // 路徑，較少見的頁面合併為 "(其他)"
+}
+
+// SankeyLink Sankey 圖連線
+type SankeyLink struct {
+	Source int `json:"source"` // 來源節點索引
+	Target int `json:"target"` // 目標節點索引
+	Value  int `json:"value"`  // 工作階段數
+}
+
+// durationBins 持續時間分布的區間（上限不含）
+var durationBins = []struct {
+	label string
+	upper time.Duration
+}{
+	{"0 秒（單頁）", time.Nanosecond},
+	{"1-10 秒", 10 * time.Second},
+	{"10-30 秒", 30 * time.Second},
+	{"30 秒-1 分", time.Minute},
+	{"1-3 分", 3 * time.Minute},
+	{"3-10 分", 10 * time.Minute},
+	{"10-30 分", 30 * time.Minute},
+	{"30 分以上", 0},
+}
+
+// depthBins 瀏覽頁數分布的區間（上限含）
+var depthBins = []struct {
+	label string
+	upper int
+}{
+	{"1 頁", 1},
+	{"2 頁", 2},
+	{"3 頁", 3},
+	{"4 頁", 4},
+	{"5-9 頁", 9},
+	{"10 頁以上", 0},
+}
+
+// Analyze 重建工作階段並計算訪客路徑統計
+func (s *Sessionizer) Analyze(entries []models.LogEntry, opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	sessions, excludedBots, err := s.Build(entries, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Options:              opts,
+		Sessions:             len(sessions),
+		ExcludedBotRequests:  excludedBots,
+		DurationDistribution: make([]DistributionBin, len(durationBins)),
+		DepthDistribution:    make([]DistributionBin, len(depthBins)),
+		EntryPages:           make([]PageStat, 0),
+		ExitPages:            make([]PageStat, 0),
+		Transitions:          make([]Transition, 0),
+		Sankey:               SankeyData{Nodes: make([]SankeyNode, 0), Links: make([]SankeyLink, 0)},
+	}
+	for i, bin := range durationBins {
+		report.DurationDistribution[i].Label = bin.label
+	}
+	for i, bin := range depthBins {
+		report.DepthDistribution[i].Label = bin.label
+	}
+	if len(sessions) == 0 {
+		return report, nil
+	}
+
+	visitors := make(map[string]struct{})
+	entryPages := make(map[string]int)
+	exitPages := make(map[string]int)
+	transitions := make(map[[2]string]int)
+	durations := make([]float64, 0, len(sessions))
+	bounces := 0
+	totalDuration := 0.0
+
+	for i := range sessions {
+		session := &sessions[i]
+		visitors[session.Key] = struct{}{}
+		depth := len(session.Pages)
+		report.PageViews += depth
+		if depth == 1 {
+			bounces++
+		}
+
+		duration := session.Duration()
+		durations = append(durations, duration.Seconds())
+		totalDuration += duration.Seconds()
+		report.DurationDistribution[durationBin(duration)].Count++
+		report.DepthDistribution[depthBin(depth)].Count++
+
+		entryPages[session.Pages[0].Path]++
+		exitPages[session.Pages[depth-1].Path]++
+		for p := 1; p < depth; p++ {
+			from, to := session.Pages[p-1].Path, session.Pages[p].Path
+			if from != to { // 重新整理同一頁不算轉換
+				transitions[[2]string{from, to}]++
+			}
+		}
+	}
+
+	total := float64(len(sessions))
+	report.Visitors = len(visitors)
+	report.BounceRate = float64(bounces) / total * 100
+	report.AvgDuration = totalDuration / total
+	report.AvgDepth = float64(report.PageViews) / total
+	sort.Float64s(durations)
+	if n := len(durations); n%2 == 1 {
+		report.MedianDuration = durations[n/2]
+	} else {
+		report.MedianDuration = (durations[n/2-1] + durations[n/2]) / 2
+	}
+	for i := range report.DurationDistribution {
+		report.DurationDistribution[i].Percentage = float64(report.DurationDistribution[i].Count) / total * 100
+	}
+	for i := range report.DepthDistribution {
+		report.DepthDistribution[i].Percentage = float64(report.DepthDistribution[i].Count) / total * 100
+	}
+
+	report.EntryPages = topPages(entryPages, opts.TopN, total)
+	report.ExitPages = topPages(exitPages, opts.TopN, total)
+	report.Transitions = topTransitions(transitions, opts.TopN)
+	report.Sankey = buildSankey(sessions, opts.SankeySteps, opts.TopN)
+
+	s.log.Info().
+		Int("sessions", report.Sessions).
+		Int("visitors", report.Visitors).
+		Float64("bounceRate", report.BounceRate).
+		Msg("工作階段分析完成")
+	return report, nil
+}
+
+// durationBin 返回持續時間所屬的分布區間索引
+func durationBin(d time.Duration) int {
+	for i, bin := range durationBins {
+		if bin.upper == 0 || d < bin.upper {
+			return i
+		}
+	}
+	return len(durationBins) - 1
+}
+
+// depthBin 返回瀏覽頁數所屬的分布區間索引
+func depthBin(depth int) int {
+	for i, bin := range depthBins {
+		if bin.upper == 0 || depth <= bin.upper {
+			return i
+		}
+	}
+	return len(depthBins) - 1
+}
+
+// topPages 依工作階段數降序取出前 N 個頁面
+func topPages(counts map[string]int, n int, total float64) []PageStat {
+	pages := make([]PageStat, 0, len(counts))
+	for path, count := range counts {
+		pages = append(pages, PageStat{Path: path, Count: count, Percentage: float64(count) / total * 100})
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		if pages[i].Count != pages[j].Count {
+			return pages[i].Count > pages[j].Count
+		}
+		return pages[i].Path < pages[j].Path
+	})
+	if len(pages) > n {
+		pages = pages[:n]
+	}
+	return pages
+}
+
+// topTransitions 依次數降序取出前 N 個頁面轉換
+func topTransitions(counts map[[2]string]int, n int) []Transition {
+	transitions := make([]Transition, 0, len(counts))
+	for pair, count := range counts {
+		transitions = append(transitions, Transition{From: pair[0], To: pair[1], Count: count})
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		a, b := transitions[i], transitions[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	if len(transitions) > n {
+		transitions = transitions[:n]
+	}
+	return transitions
+}
+
+// buildSankey 依步數展開工作階段的前幾個頁面
+// 每一步只保留最常見的 N 個頁面，其餘合併為 "(其他)"，避免圖形過於擁擠
+func buildSankey(sessions []Session, steps, topN int) SankeyData {
+	// 統計每一步各頁面的出現次數
+	stepCounts := make([]map[string]int, steps)
+	for i := range stepCounts {
+		stepCounts[i] = make(map[string]int)
+	}
+	for i := range sessions {
+		for step, page := range sessions[i].Pages {
+			if step >= steps {
+				break
+			}
+			stepCounts[step][page.Path]++
+		}
+	}
+
+	// 每一步保留的頁面
+	kept := make([]map[string]bool, steps)
+	for step, counts := range stepCounts {
+		kept[step] = make(map[string]bool)
+		for _, page := range topPages(counts, topN, 1) {
+			kept[step][page.Path] = true
+		}
+	}
+
+	data := SankeyData{Nodes: make([]SankeyNode, 0), Links: make([]SankeyLink, 0)}
+	nodeIndex := make(map[string]int)
+	node := func(step int, path string) int {
+		if !kept[step][path] {
+			path = otherNode
+		}
+		name := fmt.Sprintf("%d. %s", step+1, path)
+		if idx, exists := nodeIndex[name]; exists {
+			return idx
+		}
+		data.Nodes = append(data.Nodes, SankeyNode{Name: name, Step: step + 1, Path: path})
+		nodeIndex[name] = len(data.Nodes) - 1
+		return len(data.Nodes) - 1
+	}
+
+	linkIndex := make(map[[2]int]int)
+	for i := range sessions {
+		pages := sessions[i].Pages
+		for step := 1; step < len(pages) && step < steps; step++ {
+			key := [2]int{node(step-1, pages[step-1].Path), node(step, pages[step].Path)}
+			if idx, exists := linkIndex[key]; exists {
+				data.Links[idx].Value++
+				continue
+			}
+			data.Links = append(data.Links, SankeyLink{Source: key[0], Target: key[1], Value: 1})
+			linkIndex[key] = len(data.Links) - 1
+		}
+	}
+
+	sort.SliceStable(data.Links, func(i, j int) bool {
+		return data.Links[i].Value > data.Links[j].Value
+	})
+	return data
+}
@@ -0,0 +1,176 @@
+// Package session 將日誌記錄重建為訪客工作階段
+// 以認證使用者或 IP 與 User-Agent 組合識別訪客，超過閒置時間即視為新的工作階段
+package session
+
+import (
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/stats"
+	"access-log-analyzer/pkg/logger"
+)
+
+// Options 工作階段重建的參數，零值欄位使用預設值
+type Options struct {
+	Timeout       string `json:"timeout"`       // 閒置逾時，超過即開始新的工作階段（預設 30m）
+	IncludeBots   bool   `json:"includeBots"`   // 是否包含 User-Agent 被識別為機器人的請求
+	IncludeStatic bool   `json:"includeStatic"` // 是否將靜態資源（CSS、圖片等）視為頁面瀏覽
+	TopN          int    `json:"topN"`          // 入口頁、離開頁與頁面轉換列出的數量（預設 20）
+	SankeySteps   int    `json:"sankeySteps"`   // Sankey 圖的步數（預設 5）
+}
+
+// DefaultOptions 返回預設的工作階段參數
+func DefaultOptions() Options {
+	return Options{
+		Timeout:     "30m",
+		TopN:        20,
+		SankeySteps: 5,
+	}
+}
+
+// withDefaults 以預設值補上零值欄位
+func (o Options) withDefaults() Options {
+	d := DefaultOptions()
+	aggregate.Default(&o.Timeout, d.Timeout)
+	aggregate.Default(&o.TopN, d.TopN)
+	aggregate.Default(&o.SankeySteps, d.SankeySteps)
+	return o
+}
+
+// validate 驗證參數並返回閒置逾時
+func (o Options) validate() (time.Duration, error) {
+	timeout, err := aggregate.ParseWindow("Timeout", o.Timeout, "無效的閒置逾時")
+	if err != nil {
+		return 0, err
+	}
+	err = aggregate.FirstError(
+		aggregate.NonNegative("TopN", o.TopN, "TopN 不可為負數"),
+		aggregate.AtLeast("SankeySteps", o.SankeySteps, 2, "Sankey 圖至少需要 2 步"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return timeout, nil
+}
+
+// Session 單一訪客工作階段
+type Session struct {
+	Key       string     `json:"key"`       // 訪客識別（user:帳號 或 IP 與 User-Agent）
+	IP        string     `json:"ip"`        // 第一個請求的來源 IP
+	UserAgent string     `json:"userAgent"` // User-Agent
+	User      string     `json:"user"`      // 認證使用者（如果有）
+	Start     time.Time  `json:"start"`     // 第一個頁面瀏覽時間
+	End       time.Time  `json:"end"`       // 最後一個頁面瀏覽時間
+	Pages     []PageView `json:"pages"`     // 依時間排序的頁面瀏覽
+}
+
+// PageView 工作階段中的單一頁面瀏覽
+type PageView struct {
+	Path       string    `json:"path"`       // 不含查詢字串的路徑
+	Timestamp  time.Time `json:"timestamp"`  // 請求時間
+	StatusCode int       `json:"statusCode"` // HTTP 狀態碼
+}
+
+// Duration 返回工作階段的持續時間（第一個到最後一個頁面瀏覽）
+func (s *Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// VisitorKey 返回日誌記錄的訪客識別
+// 有認證使用者時以帳號識別（跨 IP 仍為同一訪客），否則使用 IP 與 User-Agent 組合
+func VisitorKey(entry *models.LogEntry) string {
+	if entry.User != "" && entry.User != "-" {
+		return "user:" + entry.User
+	}
+	return entry.IP + "\x00" + entry.UserAgent
+}
+
+// Sessionizer 工作階段重建器
+type Sessionizer struct {
+	detector *stats.BotDetector
+	log      *logger.Logger
+}
+
+// NewSessionizer 建立工作階段重建器，rules 為 nil 時使用預設的機器人規則
+func NewSessionizer(rules *stats.BotRuleSet) *Sessionizer {
+	return &Sessionizer{
+		detector: stats.NewBotDetectorWithRules(rules),
+		log:      logger.Get().WithModule("session"),
+	}
+}
+
+// Build 將日誌記錄重建為工作階段（依開始時間排序）
+// 第二個返回值為被排除的機器人請求數
+func (s *Sessionizer) Build(entries []models.LogEntry, opts Options) ([]Session, int, error) {
+	return s.BuildWhere(entries, opts, nil)
+}
+
+// BuildWhere 只以符合 match 的日誌記錄重建工作階段，match 為 nil 時使用全部記錄
+// 以條件篩選而不複製 LogEntry，例如只取特定時間範圍
+func (s *Sessionizer) BuildWhere(entries []models.LogEntry, opts Options, match func(*models.LogEntry) bool) ([]Session, int, error) {
+	opts = opts.withDefaults()
+	timeout, err := opts.validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 篩選頁面瀏覽並依時間排序
+	botAgents := make(map[string]bool)
+	excludedBots := 0
+	indexes := aggregate.Chronological(entries, func(entry *models.LogEntry) bool {
+		if entry.ParseError != "" {
+			return false
+		}
+		if match != nil && !match(entry) {
+			return false
+		}
+		if !opts.IncludeStatic && stats.IsStaticAsset(entry.URL) {
+			return false
+		}
+		if !opts.IncludeBots {
+			isBot, cached := botAgents[entry.UserAgent]
+			if !cached {
+				_, isBot = s.detector.Identify(entry.UserAgent)
+				botAgents[entry.UserAgent] = isBot
+			}
+			if isBot {
+				excludedBots++
+				return false
+			}
+		}
+		return true
+	})
+
+	sessions := make([]Session, 0)
+	open := make(map[string]int) // 訪客識別 -> 進行中工作階段的索引
+	for _, i := range indexes {
+		entry := &entries[i]
+		key := VisitorKey(entry)
+		page := PageView{Path: aggregate.StripQuery(entry.URL), Timestamp: entry.Timestamp, StatusCode: entry.StatusCode}
+
+		if idx, exists := open[key]; exists && entry.Timestamp.Sub(sessions[idx].End) <= timeout {
+			sessions[idx].Pages = append(sessions[idx].Pages, page)
+			sessions[idx].End = entry.Timestamp
+			continue
+		}
+
+		sessions = append(sessions, Session{
+			Key:       key,
+			IP:        entry.IP,
+			UserAgent: entry.UserAgent,
+			User:      entry.User,
+			Start:     entry.Timestamp,
+			End:       entry.Timestamp,
+			Pages:     []PageView{page},
+		})
+		open[key] = len(sessions) - 1
+	}
+
+	s.log.Debug().
+		Int("pageViews", len(indexes)).
+		Int("sessions", len(sessions)).
+		Int("excludedBots", excludedBots).
+		Msg("工作階段重建完成")
+	return sessions, excludedBots, nil
+}
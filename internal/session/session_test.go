@@ -0,0 +1,201 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chrome    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	firefox   = "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	googlebot = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+)
+
+var testBase = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// visit 建立單一請求
+func visit(ip, ua string, offset time.Duration, url string) models.LogEntry {
+	return models.LogEntry{IP: ip, UserAgent: ua, Timestamp: testBase.Add(offset), Method: "GET", URL: url, StatusCode: 200}
+}
+
+// newTestEntries 建立工作階段測試用的日誌記錄
+func newTestEntries() []models.LogEntry {
+	return []models.LogEntry{
+		// 訪客 A：首頁 -> 商品 -> 購物車，45 分鐘後再回到首頁（新的工作階段）
+		visit("10.0.0.1", chrome, 0, "/"),
+		visit("10.0.0.1", chrome, 5*time.Second, "/static/app.css"),
+		visit("10.0.0.1", chrome, 20*time.Second, "/products?page=2"),
+		visit("10.0.0.1", chrome, 2*time.Minute, "/cart"),
+		visit("10.0.0.1", chrome, 47*time.Minute, "/"),
+		// 同一 IP 不同瀏覽器為不同訪客：首頁 -> 商品
+		visit("10.0.0.1", firefox, time.Minute, "/"),
+		visit("10.0.0.1", firefox, 90*time.Second, "/products"),
+		// 訪客 C：直接進入商品頁後離開
+		visit("10.0.0.3", chrome, 3*time.Minute, "/products"),
+		// 機器人
+		visit("66.249.66.1", googlebot, 0, "/"),
+		visit("66.249.66.1", googlebot, time.Second, "/products"),
+		// 解析失敗
+		{LineNumber: 99, ParseError: "無法匹配 log 格式"},
+	}
+}
+
+// TestBuild_工作階段重建 測試閒置逾時、訪客識別、靜態資源與機器人排除
+func TestBuild_工作階段重建(t *testing.T) {
+	sessions, excludedBots, err := NewSessionizer(nil).Build(newTestEntries(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, excludedBots)
+	require.Len(t, sessions, 4)
+
+	first := sessions[0]
+	assert.Equal(t, "10.0.0.1", first.IP)
+	assert.Equal(t, chrome, first.UserAgent)
+	require.Len(t, first.Pages, 3, "靜態資源不算頁面瀏覽")
+	assert.Equal(t, "/products", first.Pages[1].Path, "路徑不含查詢字串")
+	assert.Equal(t, 2*time.Minute, first.Duration())
+
+	assert.Equal(t, firefox, sessions[1].UserAgent)
+	assert.Equal(t, "10.0.0.3", sessions[2].IP)
+	assert.Equal(t, testBase.Add(47*time.Minute), sessions[3].Start, "閒置超過 30 分鐘為新的工作階段")
+	assert.Equal(t, first.Key, sessions[3].Key)
+
+	// 包含機器人與靜態資源
+	sessions, excludedBots, err = NewSessionizer(nil).Build(newTestEntries(), Options{IncludeBots: true, IncludeStatic: true})
+	require.NoError(t, err)
+	assert.Zero(t, excludedBots)
+	assert.Len(t, sessions, 5)
+	assert.Len(t, sessions[0].Pages, 4)
+}
+
+// TestBuildWhere 測試只以符合條件的記錄重建工作階段
+func TestBuildWhere(t *testing.T) {
+	firstHour := func(entry *models.LogEntry) bool {
+		return entry.Timestamp.Before(testBase.Add(time.Hour)) && entry.IP != "66.249.66.1"
+	}
+	sessions, excludedBots, err := NewSessionizer(nil).BuildWhere(newTestEntries(), Options{}, firstHour)
+	require.NoError(t, err)
+	assert.Zero(t, excludedBots, "不符合條件的機器人請求不列入排除數")
+	assert.Len(t, sessions, 4)
+
+	early := func(entry *models.LogEntry) bool { return entry.Timestamp.Before(testBase.Add(2 * time.Minute)) }
+	sessions, _, err = NewSessionizer(nil).BuildWhere(newTestEntries(), Options{}, early)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Len(t, sessions[0].Pages, 2)
+}
+
+// TestBuild_認證使用者 測試以帳號識別跨 IP 的訪客
+func TestBuild_認證使用者(t *testing.T) {
+	entries := []models.LogEntry{
+		visit("10.0.0.1", chrome, 0, "/account"),
+		visit("10.0.0.2", chrome, time.Minute, "/orders"),
+		visit("10.0.0.3", chrome, 2*time.Minute, "/"),
+	}
+	entries[0].User = "alice"
+	entries[1].User = "alice"
+	entries[2].User = "-"
+
+	sessions, _, err := NewSessionizer(nil).Build(entries, Options{})
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "user:alice", sessions[0].Key)
+	assert.Len(t, sessions[0].Pages, 2)
+	assert.Equal(t, "10.0.0.3\x00"+chrome, sessions[1].Key, "\"-\" 表示未認證")
+}
+
+// TestBuild_無效參數 測試參數驗證
+func TestBuild_無效參數(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  Options
+		field string
+	}{
+		{"無效逾時", Options{Timeout: "abc"}, "Timeout"},
+		{"負數逾時", Options{Timeout: "-5m"}, "Timeout"},
+		{"負數 TopN", Options{TopN: -1}, "TopN"},
+		{"Sankey 步數過少", Options{SankeySteps: 1}, "SankeySteps"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := NewSessionizer(nil).Build(newTestEntries(), tc.opts)
+			var validationErr *models.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+}
+
+// TestAnalyze 測試工作階段統計、入口與離開頁、頁面轉換與 Sankey 資料
+func TestAnalyze(t *testing.T) {
+	report, err := NewSessionizer(nil).Analyze(newTestEntries(), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultOptions(), report.Options)
+	assert.Equal(t, 4, report.Sessions)
+	assert.Equal(t, 3, report.Visitors)
+	assert.Equal(t, 7, report.PageViews)
+	assert.Equal(t, 2, report.ExcludedBotRequests)
+	assert.InDelta(t, 50.0, report.BounceRate, 0.001)
+	assert.InDelta(t, (120.0+30.0)/4, report.AvgDuration, 0.001)
+	assert.InDelta(t, 15.0, report.MedianDuration, 0.001)
+	assert.InDelta(t, 7.0/4, report.AvgDepth, 0.001)
+
+	assert.Equal(t, 2, report.DurationDistribution[0].Count, "單頁工作階段")
+	assert.Equal(t, 1, report.DurationDistribution[3].Count, "30 秒-1 分")
+	assert.Equal(t, 1, report.DurationDistribution[4].Count, "1-3 分")
+	assert.Equal(t, 2, report.DepthDistribution[0].Count)
+	assert.Equal(t, 1, report.DepthDistribution[1].Count)
+	assert.Equal(t, 1, report.DepthDistribution[2].Count)
+
+	require.NotEmpty(t, report.EntryPages)
+	assert.Equal(t, PageStat{Path: "/", Count: 3, Percentage: 75}, report.EntryPages[0])
+	require.NotEmpty(t, report.ExitPages)
+	assert.Equal(t, PageStat{Path: "/products", Count: 2, Percentage: 50}, report.ExitPages[0])
+	assert.Equal(t, PageStat{Path: "/", Count: 1, Percentage: 25}, report.ExitPages[1], "同數量依路徑排序")
+
+	assert.Equal(t, []Transition{
+		{From: "/", To: "/products", Count: 2},
+		{From: "/products", To: "/cart", Count: 1},
+	}, report.Transitions)
+
+	// Sankey：1. / -> 2. /products (2)，2. /products -> 3. /cart (1)
+	nodes := make([]string, 0, len(report.Sankey.Nodes))
+	for _, node := range report.Sankey.Nodes {
+		nodes = append(nodes, node.Name)
+	}
+	assert.Equal(t, []string{"1. /", "2. /products", "3. /cart"}, nodes)
+	assert.Equal(t, []SankeyLink{{Source: 0, Target: 1, Value: 2}, {Source: 1, Target: 2, Value: 1}}, report.Sankey.Links)
+}
+
+// TestBuildSankey_合併較少見頁面 測試每一步只保留前 N 個頁面
+func TestBuildSankey_合併較少見頁面(t *testing.T) {
+	page := func(path string) PageView { return PageView{Path: path} }
+	sessions := []Session{
+		{Pages: []PageView{page("/"), page("/a")}},
+		{Pages: []PageView{page("/"), page("/a")}},
+		{Pages: []PageView{page("/"), page("/b")}},
+		{Pages: []PageView{page("/"), page("/c")}},
+	}
+
+	data := buildSankey(sessions, 3, 1)
+	require.Len(t, data.Nodes, 3)
+	assert.Equal(t, "2. (其他)", data.Nodes[2].Name)
+	assert.Equal(t, otherNode, data.Nodes[2].Path)
+	assert.Equal(t, []SankeyLink{{Source: 0, Target: 1, Value: 2}, {Source: 0, Target: 2, Value: 2}}, data.Links)
+}
+
+// TestAnalyze_無資料 測試沒有頁面瀏覽時的結果
+func TestAnalyze_無資料(t *testing.T) {
+	report, err := NewSessionizer(nil).Analyze(nil, Options{})
+	require.NoError(t, err)
+	assert.Zero(t, report.Sessions)
+	assert.Len(t, report.DurationDistribution, len(durationBins))
+	assert.Empty(t, report.Transitions)
+	assert.NotNil(t, report.Sankey.Nodes)
+}
@@ -0,0 +1,325 @@
+package stats
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 行為訊號名稱
+const (
+	SignalRate       = "rate"       // 頁面請求速率
+	SignalBurst      = "burst"      // 短時間內的突發請求
+	SignalHead       = "head"       // HEAD 請求比例
+	SignalNoStatic   = "no-static"  // 從不取得靜態資源
+	SignalNoReferer  = "no-referer" // 幾乎沒有 Referer
+	SignalRobots     = "robots"     // 讀取 robots.txt
+	SignalSequential = "sequential" // 依序列舉 URL 中的數字
+	SignalErrors     = "errors"     // 錯誤回應比例
+	SignalRegularity = "regularity" // 請求間隔過於規律
+)
+
+// 行為評分的門檻值
+const (
+	minScoredRequests  = 5                // 比例類訊號所需的最少請求數
+	minNoStaticCount   = 10               // 判斷「從不取得靜態資源」所需的最少請求數
+	burstWindow        = 10 * time.Second // 突發請求的時間窗
+	burstThreshold     = 20               // 時間窗內達此請求數視為突發
+	minRegularGaps     = 9                // 判斷間隔規律所需的最少間隔數
+	regularityMaxCV    = 0.3              // 間隔變異係數低於此值視為規律
+	minSequentialSteps = 5                // 判斷依序列舉所需的最少連續步數
+	suspectScore       = 50               // 列為疑似機器人的最低分數
+	maxSuspectClients  = 100              // 最多列出的疑似機器人數
+)
+
+// staticExtensions 視為靜態資源的副檔名
+var staticExtensions = map[string]struct{}{
+	".css": {}, ".js": {}, ".mjs": {}, ".map": {},
+	".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {}, ".svg": {}, ".webp": {}, ".avif": {}, ".ico": {},
+	".woff": {}, ".woff2": {}, ".ttf": {}, ".otf": {}, ".eot": {},
+}
+
+// IsStaticAsset 判斷 URL 是否為靜態資源（CSS、JavaScript、圖片、字型等）
+func IsStaticAsset(url string) bool {
+	urlPath, _, _ := strings.Cut(url, "?")
+	_, ok := staticExtensions[strings.ToLower(path.Ext(urlPath))]
+	return ok
+}
+
+// BotScore 單一用戶端（IP 與 User-Agent 組合）的行為評分
+// 不依賴 User-Agent 關鍵字，可找出偽裝成瀏覽器的爬蟲（例如 Headless Chrome）
+type BotScore struct {
+	IP        string           `json:"ip"`        // 來源 IP
+	UserAgent string           `json:"userAgent"` // User-Agent
+	Score     int              `json:"score"`     // 機器人可能性（0-100）
+	Level     string           `json:"level"`     // 可能性等級：極高、高、中、低
+	Requests  int              `json:"requests"`  // 請求次數
+	Reasons   []BotScoreReason `json:"reasons"`   // 造成分數的訊號（依分數降序）
+}
+
+// BotScoreReason 造成行為評分的單一訊號
+type BotScoreReason struct {
+	Signal string `json:"signal"` // 訊號名稱，例如 rate、robots
+	Points int    `json:"points"` // 此訊號貢獻的分數
+	Detail string `json:"detail"` // 說明，例如「每分鐘 42.0 個頁面請求」
+}
+
+// behaviorAccumulator 累積單一用戶端的行為訊號
+type behaviorAccumulator struct {
+	requests  int
+	pages     int // 非靜態資源的請求
+	head      int
+	static    int
+	noReferer int
+	robots    int
+	errors    int
+
+	firstSeen time.Time
+	lastSeen  time.Time
+
+	// 請求間隔（Welford 演算法計算平均值與變異數）
+	prev    time.Time
+	gaps    int
+	gapMean float64
+	gapM2   float64
+
+	// 突發請求
+	bucket      int64
+	bucketCount int
+	maxBurst    int
+
+	// 依序列舉：上一個 URL 去除數字後的樣式與數字
+	lastPattern string
+	lastNumber  int64
+	hasNumber   bool
+	sequential  int
+}
+
+// BehaviorScorer 依請求行為為每個用戶端（IP 與 User-Agent 組合）計算機器人可能性
+// 非並行安全，由 BotDetector 負責同步
+type BehaviorScorer struct {
+	clients map[string]*behaviorAccumulator // IP + "\x00" + User-Agent -> 行為
+}
+
+// NewBehaviorScorer 建立行為評分器
+func NewBehaviorScorer() *BehaviorScorer {
+	return &BehaviorScorer{clients: make(map[string]*behaviorAccumulator)}
+}
+
+// Observe 記錄一筆請求的行為訊號
+func (s *BehaviorScorer) Observe(entry *models.LogEntry) {
+	key := entry.IP + "\x00" + entry.UserAgent
+	acc, exists := s.clients[key]
+	if !exists {
+		acc = &behaviorAccumulator{}
+		s.clients[key] = acc
+	}
+	acc.observe(entry)
+}
+
+// Scores 返回所有用戶端的評分（依分數降序，分數相同依請求次數降序）
+func (s *BehaviorScorer) Scores() []BotScore {
+	scores := make([]BotScore, 0, len(s.clients))
+	for key, acc := range s.clients {
+		ip, userAgent, _ := strings.Cut(key, "\x00")
+		scores = append(scores, acc.score(ip, userAgent))
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		if scores[i].Requests != scores[j].Requests {
+			return scores[i].Requests > scores[j].Requests
+		}
+		if scores[i].IP != scores[j].IP {
+			return scores[i].IP < scores[j].IP
+		}
+		return scores[i].UserAgent < scores[j].UserAgent
+	})
+	return scores
+}
+
+// Score 返回指定用戶端的評分，沒有記錄時返回 false
+func (s *BehaviorScorer) Score(ip, userAgent string) (BotScore, bool) {
+	acc, exists := s.clients[ip+"\x00"+userAgent]
+	if !exists {
+		return BotScore{}, false
+	}
+	return acc.score(ip, userAgent), true
+}
+
+// observe 累積單筆請求
+func (a *behaviorAccumulator) observe(entry *models.LogEntry) {
+	a.requests++
+
+	urlPath, _, _ := strings.Cut(entry.URL, "?")
+	if IsStaticAsset(urlPath) {
+		a.static++
+	} else {
+		a.pages++
+	}
+	if strings.EqualFold(entry.Method, "HEAD") {
+		a.head++
+	}
+	if entry.Referer == "" || entry.Referer == "-" {
+		a.noReferer++
+	}
+	if strings.EqualFold(urlPath, "/robots.txt") {
+		a.robots++
+	}
+	if entry.StatusCode >= 400 {
+		a.errors++
+	}
+
+	a.observeTime(entry.Timestamp)
+	a.observeSequence(entry.URL)
+}
+
+// observeTime 累積請求間隔與突發請求
+func (a *behaviorAccumulator) observeTime(ts time.Time) {
+	if ts.IsZero() {
+		return
+	}
+	seen(&a.firstSeen, &a.lastSeen, ts)
+
+	// 日誌大致依時間排序，倒序的記錄不計入間隔
+	if !a.prev.IsZero() && !ts.Before(a.prev) {
+		gap := ts.Sub(a.prev).Seconds()
+		a.gaps++
+		delta := gap - a.gapMean
+		a.gapMean += delta / float64(a.gaps)
+		a.gapM2 += delta * (gap - a.gapMean)
+	}
+	if a.prev.IsZero() || ts.After(a.prev) {
+		a.prev = ts
+	}
+
+	bucket := ts.UnixNano() / int64(burstWindow)
+	if bucket != a.bucket || a.bucketCount == 0 {
+		a.bucket = bucket
+		a.bucketCount = 0
+	}
+	a.bucketCount++
+	if a.bucketCount > a.maxBurst {
+		a.maxBurst = a.bucketCount
+	}
+}
+
+// observeSequence 判斷 URL 是否只是將上一個 URL 中的數字加減一（例如 /item/41 -> /item/42）
+func (a *behaviorAccumulator) observeSequence(url string) {
+	pattern, number, ok := splitLastNumber(url)
+	if !ok {
+		a.hasNumber = false
+		return
+	}
+	if a.hasNumber && pattern == a.lastPattern && (number == a.lastNumber+1 || number == a.lastNumber-1) {
+		a.sequential++
+	}
+	a.lastPattern, a.lastNumber, a.hasNumber = pattern, number, true
+}
+
+// splitLastNumber 取出 URL 中最後一段數字，返回去除該數字後的樣式
+func splitLastNumber(url string) (string, int64, bool) {
+	end := strings.LastIndexAny(url, "0123456789") + 1
+	if end == 0 {
+		return "", 0, false
+	}
+	start := end - 1
+	for start > 0 && url[start-1] >= '0' && url[start-1] <= '9' {
+		start--
+	}
+	number, err := strconv.ParseInt(url[start:end], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return url[:start] + "#" + url[end:], number, true
+}
+
+// score 依累積的訊號計算評分，各訊號分數合計最高 100
+func (a *behaviorAccumulator) score(ip, userAgent string) BotScore {
+	result := BotScore{IP: ip, UserAgent: userAgent, Requests: a.requests, Reasons: []BotScoreReason{}}
+	add := func(signal string, points int, detail string) {
+		result.Score += points
+		result.Reasons = append(result.Reasons, BotScoreReason{Signal: signal, Points: points, Detail: detail})
+	}
+	ratio := func(n int) float64 {
+		return float64(n) / float64(a.requests)
+	}
+
+	// 頁面請求速率（靜態資源不計入，瀏覽器載入頁面時會一次取得大量資源）
+	// 時間跨度不足一分鐘時以一分鐘計算
+	if minutes := a.lastSeen.Sub(a.firstSeen).Minutes(); a.pages >= minScoredRequests && !a.firstSeen.IsZero() {
+		rate := float64(a.pages) / math.Max(minutes, 1)
+		switch {
+		case rate >= 30:
+			add(SignalRate, 15, "每分鐘 "+strconv.FormatFloat(rate, 'f', 1, 64)+" 個頁面請求")
+		case rate >= 10:
+			add(SignalRate, 8, "每分鐘 "+strconv.FormatFloat(rate, 'f', 1, 64)+" 個頁面請求")
+		}
+	}
+
+	if a.maxBurst >= burstThreshold {
+		add(SignalBurst, 10, strconv.Itoa(a.maxBurst)+" 個請求集中在 10 秒內")
+	}
+
+	if a.requests >= minScoredRequests {
+		if r := ratio(a.head); r >= 0.3 {
+			add(SignalHead, 10, "HEAD 請求佔 "+formatPercent(r))
+		}
+		if r := ratio(a.noReferer); r >= 0.9 {
+			add(SignalNoReferer, 10, formatPercent(r)+" 的請求沒有 Referer")
+		}
+		if r := ratio(a.errors); r >= 0.3 {
+			add(SignalErrors, 10, "錯誤回應佔 "+formatPercent(r))
+		}
+	}
+
+	if a.requests >= minNoStaticCount && a.static == 0 {
+		add(SignalNoStatic, 15, strconv.Itoa(a.requests)+" 個請求中沒有任何靜態資源")
+	}
+
+	if a.robots > 0 {
+		add(SignalRobots, 10, "讀取 robots.txt")
+	}
+
+	if a.sequential >= minSequentialSteps && float64(a.sequential) >= 0.3*float64(a.requests-1) {
+		add(SignalSequential, 10, strconv.Itoa(a.sequential)+" 次依序列舉 URL 中的數字")
+	}
+
+	if a.gaps >= minRegularGaps && a.gapMean > 0 {
+		cv := math.Sqrt(a.gapM2/float64(a.gaps)) / a.gapMean
+		if cv < regularityMaxCV {
+			add(SignalRegularity, 10, "請求間隔約 "+strconv.FormatFloat(a.gapMean, 'f', 1, 64)+" 秒且非常規律")
+		}
+	}
+
+	sort.SliceStable(result.Reasons, func(i, j int) bool {
+		return result.Reasons[i].Points > result.Reasons[j].Points
+	})
+	result.Level = BotScoreLevel(result.Score)
+	return result
+}
+
+// formatPercent 格式化比例為百分比字串
+func formatPercent(ratio float64) string {
+	return strconv.FormatFloat(ratio*100, 'f', 0, 64) + "%"
+}
+
+// BotScoreLevel 將行為評分轉換為可能性等級
+func BotScoreLevel(score int) string {
+	switch {
+	case score >= 75:
+		return "極高"
+	case score >= 50:
+		return "高"
+	case score >= 25:
+		return "中"
+	default:
+		return "低"
+	}
+}
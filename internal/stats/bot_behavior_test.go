@@ -0,0 +1,182 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const headlessChrome = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// scraperEntries 產生偽裝成瀏覽器、依序列舉商品頁的爬蟲請求
+func scraperEntries(ip string, base time.Time) []*models.LogEntry {
+	entries := []*models.LogEntry{
+		{IP: ip, UserAgent: headlessChrome, Method: "GET", URL: "/robots.txt", StatusCode: 200, Timestamp: base},
+	}
+	for i := 1; i <= 60; i++ {
+		entries = append(entries, &models.LogEntry{
+			IP:         ip,
+			UserAgent:  headlessChrome,
+			Method:     "GET",
+			URL:        fmt.Sprintf("/product/%d?ref=list", i),
+			StatusCode: 200,
+			Referer:    "-",
+			Timestamp:  base.Add(time.Duration(i) * 2 * time.Second),
+		})
+	}
+	return entries
+}
+
+// humanEntries 產生一般瀏覽器使用者的請求（頁面加上靜態資源，間隔不規則）
+func humanEntries(ip string, base time.Time) []*models.LogEntry {
+	var entries []*models.LogEntry
+	offsets := []int{0, 35, 47, 160, 171, 400}
+	for i, offset := range offsets {
+		ts := base.Add(time.Duration(offset) * time.Second)
+		page := fmt.Sprintf("/article/%d", i*7)
+		entries = append(entries,
+			&models.LogEntry{IP: ip, UserAgent: headlessChrome, Method: "GET", URL: page, StatusCode: 200, Referer: "https://example.com/", Timestamp: ts},
+			&models.LogEntry{IP: ip, UserAgent: headlessChrome, Method: "GET", URL: "/static/app.css", StatusCode: 200, Referer: "https://example.com" + page, Timestamp: ts},
+			&models.LogEntry{IP: ip, UserAgent: headlessChrome, Method: "GET", URL: "/static/app.js?v=3", StatusCode: 200, Referer: "https://example.com" + page, Timestamp: ts},
+		)
+	}
+	return entries
+}
+
+// TestBehaviorScorer_爬蟲與人類 測試偽裝成瀏覽器的爬蟲與一般使用者的評分差異
+func TestBehaviorScorer_爬蟲與人類(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scorer := NewBehaviorScorer()
+	for _, entry := range scraperEntries("203.0.113.10", base) {
+		scorer.Observe(entry)
+	}
+	for _, entry := range humanEntries("198.51.100.20", base) {
+		scorer.Observe(entry)
+	}
+
+	scraper, ok := scorer.Score("203.0.113.10", headlessChrome)
+	require.True(t, ok)
+	assert.Equal(t, 61, scraper.Requests)
+	assert.GreaterOrEqual(t, scraper.Score, 50)
+	assert.Contains(t, []string{"高", "極高"}, scraper.Level)
+
+	signals := make(map[string]int)
+	for _, reason := range scraper.Reasons {
+		signals[reason.Signal] = reason.Points
+		assert.NotEmpty(t, reason.Detail)
+	}
+	assert.Equal(t, 15, signals[SignalNoStatic])
+	assert.Equal(t, 10, signals[SignalNoReferer])
+	assert.Equal(t, 10, signals[SignalRobots])
+	assert.Equal(t, 10, signals[SignalSequential])
+	assert.Equal(t, 10, signals[SignalRegularity])
+	assert.Contains(t, signals, SignalRate)
+	assert.NotContains(t, signals, SignalHead)
+	assert.NotContains(t, signals, SignalErrors)
+
+	human, ok := scorer.Score("198.51.100.20", headlessChrome)
+	require.True(t, ok)
+	assert.Less(t, human.Score, 25, "一般使用者不應被判定為機器人: %+v", human.Reasons)
+	assert.Equal(t, "低", human.Level)
+
+	scores := scorer.Scores()
+	require.Len(t, scores, 2)
+	assert.Equal(t, "203.0.113.10", scores[0].IP, "依分數降序排序")
+
+	_, ok = scorer.Score("192.0.2.1", headlessChrome)
+	assert.False(t, ok)
+}
+
+// TestBehaviorScorer_HEAD與錯誤 測試 HEAD 請求、錯誤比例與突發請求
+func TestBehaviorScorer_HEAD與錯誤(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scorer := NewBehaviorScorer()
+	paths := []string{"/admin", "/wp-login.php", "/.env", "/backup.zip", "/config.php"}
+	for i := 0; i < 25; i++ {
+		scorer.Observe(&models.LogEntry{
+			IP:         "192.0.2.5",
+			UserAgent:  headlessChrome,
+			Method:     "HEAD",
+			URL:        paths[i%len(paths)],
+			StatusCode: 404,
+			Timestamp:  base.Add(time.Duration(i) * 300 * time.Millisecond),
+		})
+	}
+
+	score, ok := scorer.Score("192.0.2.5", headlessChrome)
+	require.True(t, ok)
+
+	signals := make(map[string]int)
+	for _, reason := range score.Reasons {
+		signals[reason.Signal] = reason.Points
+	}
+	assert.Equal(t, 10, signals[SignalHead])
+	assert.Equal(t, 10, signals[SignalErrors])
+	assert.Equal(t, 10, signals[SignalBurst])
+	assert.Equal(t, 8, signals[SignalRate], "時間跨度不足一分鐘時以一分鐘計算")
+	assert.NotContains(t, signals, SignalSequential)
+
+	// 原因依分數降序排列
+	for i := 1; i < len(score.Reasons); i++ {
+		assert.GreaterOrEqual(t, score.Reasons[i-1].Points, score.Reasons[i].Points)
+	}
+}
+
+// TestSplitLastNumber 測試取出 URL 中最後一段數字
+func TestSplitLastNumber(t *testing.T) {
+	testCases := []struct {
+		url      string
+		pattern  string
+		number   int64
+		expected bool
+	}{
+		{"/product/42", "/product/#", 42, true},
+		{"/page?id=7&sort=asc", "/page?id=#&sort=asc", 7, true},
+		{"/v2/items/0015.html", "/v2/items/#.html", 15, true},
+		{"/about", "", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			pattern, number, ok := splitLastNumber(tc.url)
+			assert.Equal(t, tc.expected, ok)
+			assert.Equal(t, tc.pattern, pattern)
+			assert.Equal(t, tc.number, number)
+		})
+	}
+}
+
+// TestBotDetector_疑似機器人 測試 User-Agent 未識別但行為像機器人的用戶端
+func TestBotDetector_疑似機器人(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	detector := NewBotDetector()
+	for _, entry := range scraperEntries("203.0.113.10", base) {
+		detector.Observe(entry)
+	}
+	for _, entry := range humanEntries("198.51.100.20", base) {
+		detector.Observe(entry)
+	}
+	// 以機器人 User-Agent 進行相同行為的爬蟲已由關鍵字識別，不列入疑似機器人
+	for _, entry := range scraperEntries("192.0.2.7", base) {
+		entry.UserAgent = "python-requests/2.31.0"
+		detector.Observe(entry)
+	}
+
+	stats := detector.GetStats()
+	assert.Equal(t, 61, stats.BotRequests, "行為評分不影響以 User-Agent 判定的機器人請求數")
+	require.Len(t, stats.SuspectedBots, 1)
+	assert.Equal(t, "203.0.113.10", stats.SuspectedBots[0].IP)
+	assert.Equal(t, headlessChrome, stats.SuspectedBots[0].UserAgent)
+
+	require.Len(t, stats.BotIPs, 1)
+	assert.GreaterOrEqual(t, stats.BotIPs[0].Score, 50)
+	assert.Equal(t, "中", stats.BotIPs[0].Confidence, "爬蟲類型 1 + 行為 2")
+
+	detector.ResetStats()
+	assert.Empty(t, detector.GetStats().SuspectedBots)
+}
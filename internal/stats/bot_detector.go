@@ -1,16 +1,37 @@
 package stats
 
 import (
-	"strings"
+	"sort"
 	"sync"
+	"time"
+
+	"access-log-analyzer/internal/models"
 )
 
 // BotDetector 提供機器人 User-Agent 的偵測功能
-// 使用關鍵字匹配來識別常見的機器人、爬蟲和自動化工具
+// 依規則集合（預設規則、規則檔與自訂規則）識別常見的機器人、爬蟲和自動化工具
 type BotDetector struct {
-	patterns map[string]string // 關鍵字 -> 機器人類型的映射
-	mu       sync.RWMutex      // 保護統計數據的互斥鎖
-	stats    BotStats          // 統計資訊
+	rules    *BotRuleSet                     // 偵測規則
+	mu       sync.RWMutex                    // 保護統計數據的互斥鎖
+	stats    BotStats                        // 統計資訊
+	ipStats  map[string]*botIPAccumulator    // 各 IP 的機器人活動
+	agents   map[string]*botAgentAccumulator // 各代理程式的活動（依名稱）
+	spoofed  map[string]*SpoofedCrawlerStat  // 冒充的爬蟲（名稱|IP）
+	behavior *BehaviorScorer                 // 各用戶端的行為評分
+
+	verifier  *CrawlerVerifier         // 爬蟲驗證器（nil 表示不驗證）
+	claims    map[string]*crawlerClaim // 尚未驗證的爬蟲來源（名稱|IP）
+	resolveMu sync.Mutex               // 確保同時只有一個 resolveClaims 在驗證
+}
+
+// crawlerClaim 自稱為某爬蟲的來源 IP 的請求
+// 記錄時不驗證，取得統計時再並行驗證所有不重複的 (名稱, IP)，避免 DNS 查詢拖慢單次掃描
+type crawlerClaim struct {
+	identity  BotIdentity
+	ip        string
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
 }
 
 // BotStats 儲存機器人偵測的統計資訊
@@ -20,7 +41,30 @@ type BotStats struct {
 	HumanRequests int            `json:"humanRequests"` // 人類請求數
 	BotPercentage float64        `json:"botPercentage"` // 機器人請求百分比
 	BotTypes      map[string]int `json:"botTypes"`      // 各類型機器人的數量
-	TopBots       []BotStat      `json:"topBots"`       // Top 10 機器人統計
+	TopBots       []BotStat      `json:"topBots"`       // Top 10 機器人類型統計
+	BotIPs        []BotIPStat    `json:"botIPs"`        // 各機器人 IP 的分類結果（依請求次數降序）
+	Agents        []BotAgentStat `json:"agents"`        // 各機器人代理程式的統計（依請求次數降序）
+
+	Verification    BotVerificationStats `json:"verification"`    // 爬蟲驗證結果統計（設定驗證器時）
+	SpoofedCrawlers []SpoofedCrawlerStat `json:"spoofedCrawlers"` // 冒充主要爬蟲的來源（依請求次數降序）
+	SuspectedBots   []BotScore           `json:"suspectedBots"`   // User-Agent 未識別但行為像機器人的用戶端（依分數降序）
+}
+
+// BotVerificationStats 各驗證結果的機器人請求數
+type BotVerificationStats struct {
+	Verified     int `json:"verified"`     // 確認為官方爬蟲
+	Spoofed      int `json:"spoofed"`      // 冒充官方爬蟲
+	Unverifiable int `json:"unverifiable"` // 無法驗證
+}
+
+// SpoofedCrawlerStat 冒充主要爬蟲的來源 IP
+type SpoofedCrawlerStat struct {
+	IP          string    `json:"ip"`          // 來源 IP
+	ClaimedName string    `json:"claimedName"` // 自稱的爬蟲名稱
+	Category    string    `json:"category"`    // 自稱的機器人類型
+	Count       int       `json:"count"`       // 請求次數
+	FirstSeen   time.Time `json:"firstSeen"`   // 第一次出現時間
+	LastSeen    time.Time `json:"lastSeen"`    // 最後一次出現時間
 }
 
 // BotStat 單個機器人的統計資訊
@@ -30,173 +74,240 @@ type BotStat struct {
 	Percentage float64 `json:"percentage"` // 佔總請求的百分比
 }
 
-// NewBotDetector 建立新的機器人偵測器
-func NewBotDetector() *BotDetector {
-	detector := &BotDetector{
-		patterns: make(map[string]string),
-		stats: BotStats{
-			BotTypes: make(map[string]int),
-		},
-	}
-
-	// 初始化機器人模式
-	detector.initPatterns()
+// BotIPStat 單個機器人 IP 的分類結果
+type BotIPStat struct {
+	IP         string `json:"ip"`         // IP 位址
+	BotType    string `json:"botType"`    // 機器人類型（同一 IP 出現多種類型時取最具體者）
+	Confidence string `json:"confidence"` // 信心等級：極高、高、中、低
+	Score      int    `json:"score"`      // 此 IP 各用戶端中最高的行為評分（0-100）
+	Count      int    `json:"count"`      // 機器人請求次數
+}
 
-	return detector
+// BotAgentStat 單個機器人代理程式（例如 Googlebot、curl）的統計資訊
+type BotAgentStat struct {
+	Name        string    `json:"name"`        // 代理程式名稱
+	Vendor      string    `json:"vendor"`      // 提供者
+	Category    string    `json:"category"`    // 機器人類型
+	Versions    []string  `json:"versions"`    // 出現過的版本（排序後）
+	Count       int       `json:"count"`       // 請求次數
+	Percentage  float64   `json:"percentage"`  // 佔總請求的百分比
+	Bytes       int64     `json:"bytes"`       // 總傳輸量（位元組）
+	DistinctIPs int       `json:"distinctIPs"` // 不重複的來源 IP 數
+	FirstSeen   time.Time `json:"firstSeen"`   // 第一次出現時間
+	LastSeen    time.Time `json:"lastSeen"`    // 最後一次出現時間
+
+	BotVerificationStats // 各驗證結果的請求數
 }
 
-// initPatterns 初始化機器人偵測模式
-func (d *BotDetector) initPatterns() {
-	// 搜尋引擎機器人
-	searchEngines := []string{
-		"googlebot", "bingbot", "slurp", "duckduckbot",
-		"baiduspider", "yandexbot", "sogou", "exabot",
-		"facebot", "ia_archiver",
-	}
-	for _, pattern := range searchEngines {
-		d.patterns[pattern] = "搜尋引擎"
-	}
+// botAgentAccumulator 累積單個代理程式的活動
+type botAgentAccumulator struct {
+	identity  BotIdentity
+	versions  map[string]struct{}
+	ips       map[string]struct{}
+	count     int
+	bytes     int64
+	firstSeen time.Time
+	lastSeen  time.Time
+	verified  BotVerificationStats
+}
 
-	// 爬蟲和抓取工具
-	crawlers := []string{
-		"bot", "crawler", "spider", "scraper", "scraping",
-		"python-requests", "curl", "wget", "httpclient",
-		"scrapy", "beautifulsoup", "mechanize", "pycurl",
-		"libwww", "okhttp", "go-http-client",
-	}
-	for _, pattern := range crawlers {
-		d.patterns[pattern] = "爬蟲"
-	}
+// botIPAccumulator 累積單個 IP 的機器人活動
+type botIPAccumulator struct {
+	botType string
+	count   int
+}
 
-	// 社交媒體機器人
-	socialMedia := []string{
-		"facebookexternalhit", "twitterbot", "linkedinbot",
-		"pinterest", "slackbot", "telegrambot", "whatsapp",
-		"discordbot",
-	}
-	for _, pattern := range socialMedia {
-		d.patterns[pattern] = "社交媒體"
-	}
+// NewBotDetector 建立使用預設規則的機器人偵測器
+func NewBotDetector() *BotDetector {
+	return NewBotDetectorWithRules(NewBotRuleSet())
+}
 
-	// 監控和正常運行時間檢查工具
-	monitoring := []string{
-		"pingdom", "uptimerobot", "statuscake", "monitor",
-		"site24x7", "newrelic", "datadog", "nagios",
+// NewBotDetectorWithRules 建立使用指定規則集合的機器人偵測器
+// 規則集合可在多個偵測器間共用，規則變更會立即生效
+func NewBotDetectorWithRules(rules *BotRuleSet) *BotDetector {
+	if rules == nil {
+		rules = NewBotRuleSet()
 	}
-	for _, pattern := range monitoring {
-		d.patterns[pattern] = "監控工具"
+	return &BotDetector{
+		rules: rules,
+		stats: BotStats{
+			BotTypes: make(map[string]int),
+		},
+		ipStats:  make(map[string]*botIPAccumulator),
+		agents:   make(map[string]*botAgentAccumulator),
+		spoofed:  make(map[string]*SpoofedCrawlerStat),
+		behavior: NewBehaviorScorer(),
+		claims:   make(map[string]*crawlerClaim),
 	}
+}
 
-	// SEO 和分析工具
-	seo := []string{
-		"semrush", "ahrefs", "mj12bot", "majestic",
-		"screaming frog", "seokicks", "seoscan",
-	}
-	for _, pattern := range seo {
-		d.patterns[pattern] = "SEO 工具"
-	}
+// SetVerifier 設定爬蟲驗證器，之後每個機器人請求都會分類為 verified、spoofed 或 unverifiable
+func (d *BotDetector) SetVerifier(verifier *CrawlerVerifier) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.verifier = verifier
+}
 
-	// 安全和掃描工具
-	security := []string{
-		"nessus", "nikto", "nmap", "masscan", "acunetix",
-		"qualys", "securityscanner", "vulnscanner",
-	}
-	for _, pattern := range security {
-		d.patterns[pattern] = "安全掃描"
-	}
+// Rules 返回偵測器使用的規則集合
+func (d *BotDetector) Rules() *BotRuleSet {
+	return d.rules
 }
 
 // IsBot 判斷給定的 User-Agent 是否為機器人
 // 返回值: (是否為機器人, 機器人類型)
 func (d *BotDetector) IsBot(userAgent string) (bool, string) {
+	identity, isBot := d.Identify(userAgent)
+	d.recordRequest(isBot, identity.Category)
+	return isBot, identity.Category
+}
+
+// Identify 識別 User-Agent 的機器人代理程式（名稱、提供者、版本與類型），不記錄統計
+func (d *BotDetector) Identify(userAgent string) (BotIdentity, bool) {
 	// 空字串或無效值不是機器人
 	if userAgent == "" || userAgent == "-" {
-		d.recordRequest(false, "")
-		return false, ""
+		return BotIdentity{}, false
 	}
 
-	// 轉換為小寫進行不區分大小寫的匹配
-	lowerUA := strings.ToLower(userAgent)
+	// 規則依優先順序比對：特定機器人優先，通用關鍵字（bot、crawler 等）最後
+	return d.rules.Identify(userAgent)
+}
 
-	// 優先順序匹配：先檢查特定關鍵字，後檢查通用關鍵字
-	// 1. 先檢查搜尋引擎的特定名稱
-	searchEnginePatterns := []string{
-		"googlebot", "bingbot", "slurp", "duckduckbot",
-		"baiduspider", "yandexbot", "yandex", "sogou", "exabot",
+// Observe 判斷日誌記錄是否來自機器人，並同時記錄請求統計、各 IP 與各代理程式的機器人活動
+// 所有請求（包括 User-Agent 看似瀏覽器者）都會計入行為評分
+// 返回值: (是否為機器人, 機器人類型)
+func (d *BotDetector) Observe(entry *models.LogEntry) (bool, string) {
+	identity, isBot := d.Identify(entry.UserAgent)
+	d.recordRequest(isBot, identity.Category)
+	d.mu.Lock()
+	d.behavior.Observe(entry)
+	d.mu.Unlock()
+	if isBot {
+		d.recordIP(entry.IP, identity.Category)
+		d.recordAgent(entry, identity)
 	}
-	for _, pattern := range searchEnginePatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "搜尋引擎")
-			return true, "搜尋引擎"
-		}
+	return isBot, identity.Category
+}
+
+// resolveClaims 並行驗證尚未驗證的爬蟲來源，並累加到各代理程式與冒充來源的統計
+func (d *BotDetector) resolveClaims() {
+	d.resolveMu.Lock()
+	defer d.resolveMu.Unlock()
+
+	d.mu.Lock()
+	pending, verifier := d.claims, d.verifier
+	d.claims = make(map[string]*crawlerClaim)
+	d.mu.Unlock()
+	if len(pending) == 0 || verifier == nil {
+		return
 	}
 
-	// 2. 檢查社交媒體機器人
-	socialMediaPatterns := []string{
-		"facebookexternalhit", "twitterbot", "linkedinbot",
-		"pinterest", "slackbot", "telegrambot", "whatsapp",
-		"discordbot",
+	claims := make([]*crawlerClaim, 0, len(pending))
+	requests := make([]CrawlerClaim, 0, len(pending))
+	for _, claim := range pending {
+		claims = append(claims, claim)
+		requests = append(requests, CrawlerClaim{Name: claim.identity.Name, IP: claim.ip})
 	}
-	for _, pattern := range socialMediaPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "社交媒體")
-			return true, "社交媒體"
+	statuses := verifier.VerifyAll(requests)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, claim := range claims {
+		status := statuses[i]
+		if acc, ok := d.agents[claim.identity.Name]; ok {
+			acc.verified.add(status, claim.count)
+		}
+		d.stats.Verification.add(status, claim.count)
+		if status != VerificationSpoofed {
+			continue
+		}
+		key := claim.identity.Name + "|" + claim.ip
+		spoofed, exists := d.spoofed[key]
+		if !exists {
+			spoofed = &SpoofedCrawlerStat{IP: claim.ip, ClaimedName: claim.identity.Name, Category: claim.identity.Category}
+			d.spoofed[key] = spoofed
 		}
+		spoofed.Count += claim.count
+		seen(&spoofed.FirstSeen, &spoofed.LastSeen, claim.firstSeen)
+		seen(&spoofed.FirstSeen, &spoofed.LastSeen, claim.lastSeen)
 	}
+}
 
-	// 3. 檢查監控工具
-	monitoringPatterns := []string{
-		"pingdom", "uptimerobot", "statuscake", "monitor",
-		"site24x7", "newrelic", "datadog", "nagios",
-	}
-	for _, pattern := range monitoringPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "監控工具")
-			return true, "監控工具"
-		}
+// add 依驗證結果累加請求數
+func (s *BotVerificationStats) add(status string, count int) {
+	switch status {
+	case VerificationVerified:
+		s.Verified += count
+	case VerificationSpoofed:
+		s.Spoofed += count
+	case VerificationUnverifiable:
+		s.Unverifiable += count
 	}
+}
 
-	// 4. 檢查 SEO 工具
-	seoPatterns := []string{
-		"semrush", "ahrefs", "mj12bot", "majestic",
-		"screaming frog", "seokicks", "seoscan",
+// seen 更新第一次與最後一次出現時間
+func seen(first, last *time.Time, ts time.Time) {
+	if ts.IsZero() {
+		return
 	}
-	for _, pattern := range seoPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "SEO 工具")
-			return true, "SEO 工具"
-		}
+	if first.IsZero() || ts.Before(*first) {
+		*first = ts
 	}
-
-	// 5. 檢查安全掃描工具
-	securityPatterns := []string{
-		"nessus", "nikto", "nmap", "masscan", "acunetix",
-		"qualys", "securityscanner", "vulnscanner",
+	if ts.After(*last) {
+		*last = ts
 	}
-	for _, pattern := range securityPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "安全掃描")
-			return true, "安全掃描"
+}
+
+// recordAgent 記錄代理程式的請求，設定驗證器時另外記錄待驗證的來源
+func (d *BotDetector) recordAgent(entry *models.LogEntry, identity BotIdentity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	acc, exists := d.agents[identity.Name]
+	if !exists {
+		acc = &botAgentAccumulator{
+			identity: identity,
+			versions: make(map[string]struct{}),
+			ips:      make(map[string]struct{}),
 		}
+		d.agents[identity.Name] = acc
 	}
 
-	// 6. 最後檢查通用爬蟲關鍵字（這些關鍵字較通用，放在最後）
-	crawlerPatterns := []string{
-		"bot", "crawler", "spider", "scraper", "scraping",
-		"python-requests", "curl", "wget", "httpclient",
-		"scrapy", "beautifulsoup", "mechanize", "pycurl",
-		"libwww", "okhttp", "go-http-client",
+	acc.count++
+	acc.bytes += entry.ResponseBytes
+	acc.ips[entry.IP] = struct{}{}
+	if identity.Version != "" {
+		acc.versions[identity.Version] = struct{}{}
 	}
-	for _, pattern := range crawlerPatterns {
-		if strings.Contains(lowerUA, pattern) {
-			d.recordRequest(true, "爬蟲")
-			return true, "爬蟲"
-		}
+	seen(&acc.firstSeen, &acc.lastSeen, entry.Timestamp)
+
+	if d.verifier == nil {
+		return
 	}
+	key := identity.Name + "|" + entry.IP
+	claim, exists := d.claims[key]
+	if !exists {
+		claim = &crawlerClaim{identity: identity, ip: entry.IP}
+		d.claims[key] = claim
+	}
+	claim.count++
+	seen(&claim.firstSeen, &claim.lastSeen, entry.Timestamp)
+}
 
-	// 沒有匹配到任何模式，判定為人類
-	d.recordRequest(false, "")
-	return false, ""
+// recordIP 記錄 IP 的機器人請求
+// 同一 IP 出現多種類型時保留特異性較高的類型
+func (d *BotDetector) recordIP(ip, botType string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	acc, exists := d.ipStats[ip]
+	if !exists {
+		d.ipStats[ip] = &botIPAccumulator{botType: botType, count: 1}
+		return
+	}
+	acc.count++
+	if BotTypeSpecificity(botType) > BotTypeSpecificity(acc.botType) {
+		acc.botType = botType
+	}
 }
 
 // recordRequest 記錄請求統計
@@ -220,7 +331,10 @@ func (d *BotDetector) recordRequest(isBot bool, botType string) {
 }
 
 // GetStats 獲取當前的統計資訊
+// 會先驗證尚未驗證的爬蟲來源（啟用 DNS 時可能需要等待查詢）
 func (d *BotDetector) GetStats() BotStats {
+	d.resolveClaims()
+
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
@@ -230,6 +344,7 @@ func (d *BotDetector) GetStats() BotStats {
 		BotRequests:   d.stats.BotRequests,
 		HumanRequests: d.stats.HumanRequests,
 		BotPercentage: d.stats.BotPercentage,
+		Verification:  d.stats.Verification,
 		BotTypes:      make(map[string]int),
 		TopBots:       make([]BotStat, 0),
 	}
@@ -279,6 +394,90 @@ func (d *BotDetector) GetStats() BotStats {
 		})
 	}
 
+	// 行為評分：每個 IP 取最高分，User-Agent 未識別者列為疑似機器人
+	ipScores := make(map[string]int)
+	statsCopy.SuspectedBots = make([]BotScore, 0)
+	for _, score := range d.behavior.Scores() {
+		if score.Score > ipScores[score.IP] {
+			ipScores[score.IP] = score.Score
+		}
+		if score.Score < suspectScore || len(statsCopy.SuspectedBots) >= maxSuspectClients {
+			continue
+		}
+		if _, isBot := d.Identify(score.UserAgent); !isBot {
+			statsCopy.SuspectedBots = append(statsCopy.SuspectedBots, score)
+		}
+	}
+
+	// 各機器人 IP 的分類結果（依請求次數降序，次數相同依 IP 排序）
+	statsCopy.BotIPs = make([]BotIPStat, 0, len(d.ipStats))
+	for ip, acc := range d.ipStats {
+		statsCopy.BotIPs = append(statsCopy.BotIPs, BotIPStat{
+			IP:         ip,
+			BotType:    acc.botType,
+			Confidence: BotConfidence(acc.botType, ipScores[ip]),
+			Score:      ipScores[ip],
+			Count:      acc.count,
+		})
+	}
+	sort.Slice(statsCopy.BotIPs, func(i, j int) bool {
+		if statsCopy.BotIPs[i].Count != statsCopy.BotIPs[j].Count {
+			return statsCopy.BotIPs[i].Count > statsCopy.BotIPs[j].Count
+		}
+		return statsCopy.BotIPs[i].IP < statsCopy.BotIPs[j].IP
+	})
+
+	// 各代理程式的統計（依請求次數降序，次數相同依名稱排序）
+	statsCopy.Agents = make([]BotAgentStat, 0, len(d.agents))
+	for _, acc := range d.agents {
+		versions := make([]string, 0, len(acc.versions))
+		for version := range acc.versions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		percentage := 0.0
+		if d.stats.Total > 0 {
+			percentage = float64(acc.count) / float64(d.stats.Total) * 100
+		}
+		statsCopy.Agents = append(statsCopy.Agents, BotAgentStat{
+			Name:        acc.identity.Name,
+			Vendor:      acc.identity.Vendor,
+			Category:    acc.identity.Category,
+			Versions:    versions,
+			Count:       acc.count,
+			Percentage:  percentage,
+			Bytes:       acc.bytes,
+			DistinctIPs: len(acc.ips),
+			FirstSeen:   acc.firstSeen,
+			LastSeen:    acc.lastSeen,
+
+			BotVerificationStats: acc.verified,
+		})
+	}
+	sort.Slice(statsCopy.Agents, func(i, j int) bool {
+		if statsCopy.Agents[i].Count != statsCopy.Agents[j].Count {
+			return statsCopy.Agents[i].Count > statsCopy.Agents[j].Count
+		}
+		return statsCopy.Agents[i].Name < statsCopy.Agents[j].Name
+	})
+
+	// 冒充的爬蟲（依請求次數降序，次數相同依 IP 排序）
+	statsCopy.SpoofedCrawlers = make([]SpoofedCrawlerStat, 0, len(d.spoofed))
+	for _, spoofed := range d.spoofed {
+		statsCopy.SpoofedCrawlers = append(statsCopy.SpoofedCrawlers, *spoofed)
+	}
+	sort.Slice(statsCopy.SpoofedCrawlers, func(i, j int) bool {
+		a, b := statsCopy.SpoofedCrawlers[i], statsCopy.SpoofedCrawlers[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.IP != b.IP {
+			return a.IP < b.IP
+		}
+		return a.ClaimedName < b.ClaimedName
+	})
+
 	return statsCopy
 }
 
@@ -290,22 +489,73 @@ func (d *BotDetector) ResetStats() {
 	d.stats = BotStats{
 		BotTypes: make(map[string]int),
 	}
+	d.ipStats = make(map[string]*botIPAccumulator)
+	d.agents = make(map[string]*botAgentAccumulator)
+	d.spoofed = make(map[string]*SpoofedCrawlerStat)
+	d.claims = make(map[string]*crawlerClaim)
+	d.behavior = NewBehaviorScorer()
+}
+
+// BotTypeSpecificity 取得機器人類型的特異性評分
+// 特異性越高的類型越具體，優先度越高；未知（自訂）類型為 0
+func BotTypeSpecificity(botType string) int {
+	switch botType {
+	case "搜尋引擎":
+		return 5
+	case "社交媒體", "SEO 工具", "AI 爬蟲":
+		return 4
+	case "監控工具", "安全掃描":
+		return 3
+	case "爬蟲":
+		return 1 // 最通用的類別
+	}
+	return 0
+}
+
+// BotConfidence 依機器人類型與行為評分計算信心等級
+// 類型越具體、行為越像機器人（見 BehaviorScorer），越可能確實是機器人
+func BotConfidence(botType string, behaviorScore int) string {
+	// 基於機器人類型的基礎信心分數
+	baseScore := BotTypeSpecificity(botType)
+
+	// 基於行為評分調整信心分數
+	var behaviorPoints int
+	switch {
+	case behaviorScore >= 75:
+		behaviorPoints = 3
+	case behaviorScore >= 50:
+		behaviorPoints = 2
+	case behaviorScore >= 25:
+		behaviorPoints = 1
+	}
+
+	// 轉換為信心等級
+	switch totalScore := baseScore + behaviorPoints; {
+	case totalScore >= 7:
+		return "極高"
+	case totalScore >= 5:
+		return "高"
+	case totalScore >= 3:
+		return "中"
+	default:
+		return "低"
+	}
 }
 
 // AddPattern 添加自訂的機器人偵測模式
 // pattern: 要匹配的關鍵字（不區分大小寫）
 // botType: 機器人類型
-func (d *BotDetector) AddPattern(pattern, botType string) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	d.patterns[strings.ToLower(pattern)] = botType
+// 自訂模式的優先順序高於所有預設規則
+func (d *BotDetector) AddPattern(pattern, botType string) error {
+	return d.rules.Add(BotRule{
+		Name:      pattern,
+		Category:  botType,
+		Substring: pattern,
+		Priority:  customRulePriority,
+	})
 }
 
-// RemovePattern 移除機器人偵測模式
+// RemovePattern 移除以 AddPattern 添加的機器人偵測模式
 func (d *BotDetector) RemovePattern(pattern string) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	delete(d.patterns, strings.ToLower(pattern))
+	d.rules.Remove(pattern)
 }
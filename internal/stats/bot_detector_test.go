@@ -2,8 +2,12 @@ package stats
 
 import (
 	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestBotDetector_常見機器人 測試常見機器人的偵測
@@ -177,6 +181,100 @@ func TestBotDetector_重置統計(t *testing.T) {
 	assert.Empty(t, stats.BotTypes)
 }
 
+// TestBotDetector_IP分類 測試各 IP 的機器人類型與信心等級
+func TestBotDetector_IP分類(t *testing.T) {
+	detector := NewBotDetector()
+
+	for i := 0; i < 12; i++ {
+		detector.Observe(&models.LogEntry{IP: "10.0.0.1", UserAgent: "curl/7.68.0"})
+	}
+	detector.Observe(&models.LogEntry{IP: "10.0.0.1", UserAgent: "Googlebot/2.1"})
+	detector.Observe(&models.LogEntry{IP: "10.0.0.2", UserAgent: "Twitterbot/1.0"})
+	detector.Observe(&models.LogEntry{IP: "10.0.0.3", UserAgent: "Mozilla/5.0 Chrome/120.0.0.0"})
+
+	stats := detector.GetStats()
+	assert.Equal(t, []BotIPStat{
+		{IP: "10.0.0.1", BotType: "搜尋引擎", Confidence: "高", Score: 25, Count: 13},
+		{IP: "10.0.0.2", BotType: "社交媒體", Confidence: "中", Count: 1},
+	}, stats.BotIPs)
+
+	detector.ResetStats()
+	assert.Empty(t, detector.GetStats().BotIPs)
+}
+
+// TestBotConfidence 測試信心等級的計算（類型特異性加上行為評分）
+func TestBotConfidence(t *testing.T) {
+	assert.Equal(t, "高", BotConfidence("搜尋引擎", 0))
+	assert.Equal(t, "極高", BotConfidence("搜尋引擎", 50))
+	assert.Equal(t, "低", BotConfidence("爬蟲", 24))
+	assert.Equal(t, "中", BotConfidence("爬蟲", 50))
+	assert.Equal(t, "極高", BotConfidence("SEO 工具", 75))
+	assert.Equal(t, "中", BotConfidence("自訂", 100))
+}
+
+// TestBotDetector_識別代理程式 測試代理程式名稱、提供者與版本的識別
+func TestBotDetector_識別代理程式(t *testing.T) {
+	detector := NewBotDetector()
+
+	testCases := []struct {
+		userAgent string
+		expected  BotIdentity
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", BotIdentity{"Googlebot", "Google", "2.1", "搜尋引擎"}},
+		{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", BotIdentity{"Bingbot", "Microsoft", "2.0", "搜尋引擎"}},
+		{"Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)", BotIdentity{"AhrefsBot", "Ahrefs", "7.0", "SEO 工具"}},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)", BotIdentity{"GPTBot", "OpenAI", "1.2", "AI 爬蟲"}},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ClaudeBot/1.0; +claudebot@anthropic.com)", BotIdentity{"ClaudeBot", "Anthropic", "1.0", "AI 爬蟲"}},
+		{"CCBot/2.0 (https://commoncrawl.org/faq/)", BotIdentity{"CCBot", "Common Crawl", "2.0", "AI 爬蟲"}},
+		{"python-requests/2.31.0", BotIdentity{"python-requests", "", "2.31.0", "爬蟲"}},
+		{"curl/7.68.0", BotIdentity{"curl", "", "7.68.0", "爬蟲"}},
+		{"Mozilla/5.0 (compatible; FooBot/3.4.1; +http://foo.example/bot)", BotIdentity{"FooBot", "", "3.4.1", "爬蟲"}},
+		{"Apache-HttpClient/4.5.13 (Java/11.0.2)", BotIdentity{"Apache-HttpClient", "", "4.5.13", "爬蟲"}},
+		{"Baiduspider+(+http://www.baidu.com/search/spider.htm)", BotIdentity{"Baiduspider", "Baidu", "", "搜尋引擎"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected.Name, func(t *testing.T) {
+			identity, ok := detector.Identify(tc.userAgent)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, identity)
+		})
+	}
+
+	_, ok := detector.Identify("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0")
+	assert.False(t, ok)
+}
+
+// TestBotDetector_代理程式統計 測試各代理程式的請求數、傳輸量、時間範圍與不重複 IP
+func TestBotDetector_代理程式統計(t *testing.T) {
+	detector := NewBotDetector()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	detector.Observe(&models.LogEntry{IP: "66.249.66.1", UserAgent: "Googlebot/2.1", ResponseBytes: 100, Timestamp: base.Add(time.Hour)})
+	detector.Observe(&models.LogEntry{IP: "66.249.66.2", UserAgent: "Googlebot/2.1", ResponseBytes: 200, Timestamp: base})
+	detector.Observe(&models.LogEntry{IP: "66.249.66.1", UserAgent: "Googlebot/2.2", ResponseBytes: 300, Timestamp: base.Add(2 * time.Hour)})
+	detector.Observe(&models.LogEntry{IP: "10.0.0.1", UserAgent: "curl/8.0", ResponseBytes: 50, Timestamp: base})
+	detector.Observe(&models.LogEntry{IP: "10.0.0.2", UserAgent: "Mozilla/5.0 Chrome/120.0.0.0", ResponseBytes: 50, Timestamp: base})
+
+	agents := detector.GetStats().Agents
+	require.Len(t, agents, 2)
+
+	assert.Equal(t, BotAgentStat{
+		Name:        "Googlebot",
+		Vendor:      "Google",
+		Category:    "搜尋引擎",
+		Versions:    []string{"2.1", "2.2"},
+		Count:       3,
+		Percentage:  60,
+		Bytes:       600,
+		DistinctIPs: 2,
+		FirstSeen:   base,
+		LastSeen:    base.Add(2 * time.Hour),
+	}, agents[0])
+	assert.Equal(t, "curl", agents[1].Name)
+	assert.Equal(t, 1, agents[1].Count)
+}
+
 // BenchmarkBotDetector_偵測 測試機器人偵測的性能
 func BenchmarkBotDetector_偵測(b *testing.B) {
 	detector := NewBotDetector()
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BotIdentity 識別出的機器人代理程式
+type BotIdentity struct {
+	Name     string `json:"name"`     // 代理程式名稱，例如 Googlebot、curl
+	Vendor   string `json:"vendor"`   // 提供者，例如 Google（未知時為空字串）
+	Version  string `json:"version"`  // 版本號，例如 2.1（無法擷取時為空字串）
+	Category string `json:"category"` // 機器人類型，例如 搜尋引擎
+}
+
+// versionPattern 產品字串中 "/" 之後的版本號
+var versionPattern = regexp.MustCompile(`^v?(\d[0-9A-Za-z._-]*)`)
+
+// identify 依符合的規則與位置建立代理程式身分
+// 版本號取自符合位置所在的產品字串（Name/Version）；通用規則的名稱取自產品字串的名稱部分
+func identify(rule BotRule, userAgent string, pos int) BotIdentity {
+	identity := BotIdentity{
+		Name:     rule.Name,
+		Vendor:   rule.Vendor,
+		Category: rule.Category,
+	}
+
+	name, version := productAt(userAgent, pos)
+	identity.Version = version
+	if rule.Generic && name != "" {
+		identity.Name = name
+	}
+	return identity
+}
+
+// productAt 取得 User-Agent 中包含指定位置的產品字串，拆分為名稱與版本
+// 例如 "Mozilla/5.0 (compatible; Googlebot/2.1; +http://...)" 在 Googlebot 的位置返回 ("Googlebot", "2.1")
+// 產品字串為網址時名稱返回空字串
+func productAt(userAgent string, pos int) (string, string) {
+	if pos < 0 || pos >= len(userAgent) {
+		return "", ""
+	}
+
+	start := strings.LastIndexAny(userAgent[:pos], productSeparators) + 1
+	end := len(userAgent)
+	if i := strings.IndexAny(userAgent[pos:], productSeparators); i >= 0 {
+		end = pos + i
+	}
+	token := strings.Trim(userAgent[start:end], "+-_.")
+
+	name, rest, hasSlash := strings.Cut(token, "/")
+	if strings.Contains(token, "://") || strings.HasPrefix(strings.ToLower(token), "www.") {
+		return "", ""
+	}
+
+	var version string
+	if hasSlash {
+		if m := versionPattern.FindStringSubmatch(rest); m != nil {
+			version = strings.TrimRight(m[1], "._-")
+		}
+	}
+	return name, version
+}
+
+// productSeparators 分隔 User-Agent 產品字串的字元
+const productSeparators = " ;(),"
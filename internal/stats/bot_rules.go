@@ -0,0 +1,354 @@
+package stats
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultBotRulesData 內建的預設機器人偵測規則
+//
+//go:embed bot_rules.yaml
+var defaultBotRulesData []byte
+
+// 規則檔的套用模式
+const (
+	RuleModeExtend  = "extend"  // 擴充預設規則（同名規則覆寫預設規則）
+	RuleModeReplace = "replace" // 完全取代預設規則
+)
+
+// customRulePriority 以 AddPattern 新增的規則優先順序（高於所有預設規則）
+const customRulePriority = 1000
+
+// BotRule 單條機器人偵測規則
+// Substring 與 Regex 擇一，皆不區分大小寫
+type BotRule struct {
+	Name      string `json:"name" yaml:"name"`                               // 代理程式名稱（規則識別用）
+	Vendor    string `json:"vendor,omitempty" yaml:"vendor,omitempty"`       // 提供者
+	Category  string `json:"category" yaml:"category"`                       // 機器人類型
+	Substring string `json:"substring,omitempty" yaml:"substring,omitempty"` // User-Agent 子字串
+	Regex     string `json:"regex,omitempty" yaml:"regex,omitempty"`         // User-Agent 正規表達式
+	Priority  int    `json:"priority" yaml:"priority"`                       // 優先順序（越大越先比對）
+	Generic   bool   `json:"generic,omitempty" yaml:"generic,omitempty"`     // 通用規則：名稱取自 User-Agent 的產品名稱
+
+	substring string         // 小寫的子字串
+	re        *regexp.Regexp // 編譯後的正規表達式
+}
+
+// botRuleFile 規則檔格式（YAML 或 JSON）
+type botRuleFile struct {
+	Mode  string    `yaml:"mode"`  // 套用模式：extend（預設）或 replace
+	Rules []BotRule `yaml:"rules"` // 規則列表
+}
+
+// compile 驗證並編譯規則
+func (r *BotRule) compile() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &models.ValidationError{Field: "name", Value: r.Name, Message: "規則名稱不可為空"}
+	}
+	if strings.TrimSpace(r.Category) == "" {
+		return &models.ValidationError{Field: "category", Value: r.Category, Message: fmt.Sprintf("規則 %s 的類型不可為空", r.Name)}
+	}
+	if (r.Substring == "") == (r.Regex == "") {
+		return &models.ValidationError{Field: "substring", Value: r.Name, Message: "substring 與 regex 必須擇一設定"}
+	}
+
+	if r.Regex != "" {
+		re, err := regexp.Compile("(?i)" + r.Regex)
+		if err != nil {
+			return &models.ValidationError{Field: "regex", Value: r.Regex, Message: fmt.Sprintf("規則 %s 的正規表達式無效: %v", r.Name, err)}
+		}
+		r.re = re
+		return nil
+	}
+	r.substring = strings.ToLower(r.Substring)
+	return nil
+}
+
+// find 在小寫的 User-Agent 中尋找符合規則的位置，不符合時返回 -1
+func (r *BotRule) find(lowerUA string) int {
+	if r.re != nil {
+		if loc := r.re.FindStringIndex(lowerUA); loc != nil {
+			return loc[0]
+		}
+		return -1
+	}
+	return strings.Index(lowerUA, r.substring)
+}
+
+// ParseBotRules 解析規則檔內容（YAML 或 JSON）
+// 返回套用模式與已編譯的規則
+func ParseBotRules(data []byte) (string, []BotRule, error) {
+	var file botRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", nil, fmt.Errorf("解析機器人規則失敗: %w", err)
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(file.Mode))
+	if mode == "" {
+		mode = RuleModeExtend
+	}
+	if mode != RuleModeExtend && mode != RuleModeReplace {
+		return "", nil, &models.ValidationError{Field: "mode", Value: file.Mode, Message: "模式必須為 extend 或 replace"}
+	}
+
+	for i := range file.Rules {
+		if err := file.Rules[i].compile(); err != nil {
+			return "", nil, fmt.Errorf("第 %d 條規則無效: %w", i+1, err)
+		}
+	}
+	return mode, file.Rules, nil
+}
+
+var (
+	defaultRulesOnce sync.Once
+	defaultRules     []BotRule
+)
+
+// DefaultBotRules 返回內建的預設規則
+func DefaultBotRules() []BotRule {
+	defaultRulesOnce.Do(func() {
+		_, rules, err := ParseBotRules(defaultBotRulesData)
+		if err != nil {
+			// 內建規則隨程式編譯，解析失敗屬於程式錯誤
+			panic(fmt.Sprintf("內建機器人規則無效: %v", err))
+		}
+		defaultRules = rules
+	})
+	return append([]BotRule(nil), defaultRules...)
+}
+
+// BotRuleSet 機器人偵測規則集合
+// 由預設規則、規則檔與執行時新增的自訂規則組成，可安全地在多個 goroutine 間共用
+type BotRuleSet struct {
+	mu        sync.RWMutex
+	defaults  []BotRule  // 內建預設規則
+	fileMode  string     // 規則檔的套用模式
+	fileRules []BotRule  // 規則檔中的規則
+	custom    []BotRule  // 執行時新增的規則
+	effective []*BotRule // 依優先順序排序的有效規則
+	log       *logger.Logger
+}
+
+// NewBotRuleSet 建立只包含預設規則的規則集合
+func NewBotRuleSet() *BotRuleSet {
+	s := &BotRuleSet{
+		defaults: DefaultBotRules(),
+		fileMode: RuleModeExtend,
+		log:      logger.Get().WithModule("bot-rules"),
+	}
+	s.rebuild()
+	return s
+}
+
+// rebuild 重新計算有效規則（呼叫者須持有寫鎖）
+// 規則檔中與預設規則同名的規則會取代預設規則，其餘依優先順序排序（相同優先順序維持定義順序）
+func (s *BotRuleSet) rebuild() {
+	var base []BotRule
+	if s.fileMode == RuleModeReplace {
+		base = s.fileRules
+	} else {
+		overrides := make(map[string]bool, len(s.fileRules))
+		for _, rule := range s.fileRules {
+			overrides[strings.ToLower(rule.Name)] = true
+		}
+		for _, rule := range s.defaults {
+			if !overrides[strings.ToLower(rule.Name)] {
+				base = append(base, rule)
+			}
+		}
+		base = append(base, s.fileRules...)
+	}
+
+	effective := make([]*BotRule, 0, len(base)+len(s.custom))
+	for i := range s.custom {
+		effective = append(effective, &s.custom[i])
+	}
+	for i := range base {
+		effective = append(effective, &base[i])
+	}
+	sort.SliceStable(effective, func(i, j int) bool {
+		return effective[i].Priority > effective[j].Priority
+	})
+
+	s.effective = effective
+}
+
+// Match 以 User-Agent 比對規則，返回第一條符合的規則
+func (s *BotRuleSet) Match(userAgent string) (BotRule, bool) {
+	rule, _, ok := s.match(strings.ToLower(userAgent))
+	return rule, ok
+}
+
+// Identify 以 User-Agent 識別機器人的名稱、提供者與版本
+func (s *BotRuleSet) Identify(userAgent string) (BotIdentity, bool) {
+	lowerUA := strings.ToLower(userAgent)
+	rule, pos, ok := s.match(lowerUA)
+	if !ok {
+		return BotIdentity{}, false
+	}
+
+	// 轉小寫可能改變非 ASCII 字元的位元組長度，此時改從小寫字串擷取名稱與版本
+	source := userAgent
+	if len(lowerUA) != len(userAgent) {
+		source = lowerUA
+	}
+	return identify(rule, source, pos), true
+}
+
+// match 在小寫的 User-Agent 中比對規則，返回第一條符合的規則與符合位置
+func (s *BotRuleSet) match(lowerUA string) (BotRule, int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rule := range s.effective {
+		if pos := rule.find(lowerUA); pos >= 0 {
+			return *rule, pos, true
+		}
+	}
+	return BotRule{}, -1, false
+}
+
+// Rules 返回依優先順序排序的有效規則
+func (s *BotRuleSet) Rules() []BotRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := make([]BotRule, len(s.effective))
+	for i, rule := range s.effective {
+		rules[i] = *rule
+	}
+	return rules
+}
+
+// Add 新增自訂規則（同名規則會被取代）
+func (s *BotRuleSet) Add(rule BotRule) error {
+	if err := rule.compile(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.custom = removeRules(s.custom, func(r BotRule) bool {
+		return strings.EqualFold(r.Name, rule.Name)
+	})
+	s.custom = append(s.custom, rule)
+	s.rebuild()
+	return nil
+}
+
+// Remove 移除子字串相同的自訂規則，返回是否有規則被移除
+func (s *BotRuleSet) Remove(substring string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := len(s.custom)
+	s.custom = removeRules(s.custom, func(r BotRule) bool {
+		return r.Substring != "" && strings.EqualFold(r.Substring, substring)
+	})
+	if len(s.custom) == before {
+		return false
+	}
+	s.rebuild()
+	return true
+}
+
+// removeRules 移除符合條件的規則
+func removeRules(rules []BotRule, match func(BotRule) bool) []BotRule {
+	kept := rules[:0:0]
+	for _, rule := range rules {
+		if !match(rule) {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// LoadFile 載入規則檔（YAML 或 JSON），取代先前載入的規則檔
+// 解析失敗時保留原有規則
+func (s *BotRuleSet) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("讀取機器人規則檔失敗: %w", err)
+	}
+	mode, rules, err := ParseBotRules(data)
+	if err != nil {
+		return fmt.Errorf("載入機器人規則檔 %s 失敗: %w", path, err)
+	}
+
+	s.mu.Lock()
+	s.fileMode = mode
+	s.fileRules = rules
+	s.rebuild()
+	s.mu.Unlock()
+
+	s.log.Info().
+		Str("path", path).
+		Str("mode", mode).
+		Int("rules", len(rules)).
+		Msg("已載入機器人規則檔")
+	return nil
+}
+
+// ClearFile 移除已載入的規則檔，恢復為預設規則
+func (s *BotRuleSet) ClearFile() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileMode = RuleModeExtend
+	s.fileRules = nil
+	s.rebuild()
+}
+
+// Watch 監看規則檔，啟動時立即載入（若存在），之後定期檢查修改時間並在變更時重新載入
+// 檔案被刪除時恢復預設規則；規則檔無效時記錄錯誤並保留原有規則。ctx 取消時停止
+func (s *BotRuleSet) Watch(ctx context.Context, path string, interval time.Duration) {
+	var lastMod time.Time
+	s.reloadIfChanged(path, &lastMod)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reloadIfChanged(path, &lastMod)
+		}
+	}
+}
+
+// reloadIfChanged 規則檔的修改時間與 lastMod 不同時重新載入
+func (s *BotRuleSet) reloadIfChanged(path string, lastMod *time.Time) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !lastMod.IsZero() {
+			*lastMod = time.Time{}
+			s.ClearFile()
+			s.log.Info().Str("path", path).Msg("機器人規則檔已移除，恢復預設規則")
+		}
+		return
+	}
+	if info.ModTime().Equal(*lastMod) {
+		return
+	}
+	*lastMod = info.ModTime()
+
+	if err := s.LoadFile(path); err != nil {
+		s.log.Error().Err(err).Str("path", path).Msg("重新載入機器人規則檔失敗，保留原有規則")
+	}
+}
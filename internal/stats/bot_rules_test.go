@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultBotRules_內建規則有效 測試內建規則可以正確解析且依優先順序排序
+func TestDefaultBotRules_內建規則有效(t *testing.T) {
+	rules := NewBotRuleSet().Rules()
+	require.NotEmpty(t, rules)
+
+	for i := 1; i < len(rules); i++ {
+		assert.GreaterOrEqual(t, rules[i-1].Priority, rules[i].Priority, "規則應依優先順序排序")
+	}
+
+	// 通用關鍵字應排在最後
+	assert.Equal(t, "爬蟲", rules[len(rules)-1].Category)
+}
+
+// TestParseBotRules_驗證 測試規則檔的驗證
+func TestParseBotRules_驗證(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"YAML 子字串規則", "rules:\n  - {name: A, category: 爬蟲, substring: abc}\n", false},
+		{"JSON 正規表達式規則", `{"mode": "replace", "rules": [{"name": "A", "category": "爬蟲", "regex": "ab+c"}]}`, false},
+		{"缺少名稱", "rules:\n  - {category: 爬蟲, substring: abc}\n", true},
+		{"缺少類型", "rules:\n  - {name: A, substring: abc}\n", true},
+		{"同時設定子字串與正規表達式", "rules:\n  - {name: A, category: 爬蟲, substring: abc, regex: abc}\n", true},
+		{"未設定比對方式", "rules:\n  - {name: A, category: 爬蟲}\n", true},
+		{"無效的正規表達式", "rules:\n  - {name: A, category: 爬蟲, regex: '(abc'}\n", true},
+		{"無效的模式", "mode: merge\nrules: []\n", true},
+		{"無效的格式", "rules: [", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParseBotRules([]byte(tc.data))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestBotRuleSet_正規表達式規則 測試正規表達式規則不區分大小寫
+func TestBotRuleSet_正規表達式規則(t *testing.T) {
+	detector := NewBotDetector()
+
+	isBot, botType := detector.IsBot("My-SCRAPING-Tool/1.0")
+	assert.True(t, isBot)
+	assert.Equal(t, "爬蟲", botType)
+}
+
+// TestBotDetector_自訂模式 測試 AddPattern/RemovePattern 會影響偵測結果
+func TestBotDetector_自訂模式(t *testing.T) {
+	detector := NewBotDetector()
+	ua := "InternalHealthProbe/1.0"
+
+	isBot, _ := detector.IsBot(ua)
+	assert.False(t, isBot)
+
+	require.NoError(t, detector.AddPattern("HealthProbe", "監控工具"))
+	isBot, botType := detector.IsBot(ua)
+	assert.True(t, isBot)
+	assert.Equal(t, "監控工具", botType)
+
+	// 自訂模式優先於預設規則
+	require.NoError(t, detector.AddPattern("googlebot", "自訂"))
+	_, botType = detector.IsBot("Googlebot/2.1")
+	assert.Equal(t, "自訂", botType)
+
+	detector.RemovePattern("healthprobe")
+	isBot, _ = detector.IsBot(ua)
+	assert.False(t, isBot)
+
+	// 統計使用相同的規則結果
+	stats := detector.GetStats()
+	assert.Equal(t, 1, stats.BotTypes["監控工具"])
+	assert.Equal(t, 1, stats.BotTypes["自訂"])
+
+	assert.Error(t, detector.AddPattern("", "爬蟲"))
+}
+
+// TestBotRuleSet_載入規則檔 測試規則檔的擴充與取代模式
+func TestBotRuleSet_載入規則檔(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("擴充模式覆寫同名規則", func(t *testing.T) {
+		path := filepath.Join(dir, "extend.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - {name: Googlebot, category: 友善爬蟲, substring: googlebot, priority: 100}
+  - {name: Internal, category: 內部工具, regex: '^acme-(agent|probe)', priority: 50}
+`), 0644))
+
+		rules := NewBotRuleSet()
+		require.NoError(t, rules.LoadFile(path))
+
+		rule, ok := rules.Match("Mozilla/5.0 (compatible; Googlebot/2.1)")
+		require.True(t, ok)
+		assert.Equal(t, "友善爬蟲", rule.Category)
+
+		rule, ok = rules.Match("ACME-Probe/3")
+		require.True(t, ok)
+		assert.Equal(t, "Internal", rule.Name)
+
+		// 預設規則仍然有效
+		rule, ok = rules.Match("bingbot/2.0")
+		require.True(t, ok)
+		assert.Equal(t, "搜尋引擎", rule.Category)
+	})
+
+	t.Run("取代模式", func(t *testing.T) {
+		path := filepath.Join(dir, "replace.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"mode": "replace", "rules": [{"name": "Only", "category": "爬蟲", "substring": "only"}]}`), 0644))
+
+		rules := NewBotRuleSet()
+		require.NoError(t, rules.LoadFile(path))
+		assert.Len(t, rules.Rules(), 1)
+
+		_, ok := rules.Match("Googlebot/2.1")
+		assert.False(t, ok)
+
+		rules.ClearFile()
+		_, ok = rules.Match("Googlebot/2.1")
+		assert.True(t, ok)
+	})
+
+	t.Run("無效規則檔保留原有規則", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("rules:\n  - {name: A}\n"), 0644))
+
+		rules := NewBotRuleSet()
+		count := len(rules.Rules())
+		assert.Error(t, rules.LoadFile(path))
+		assert.Len(t, rules.Rules(), count)
+	})
+}
+
+// TestBotRuleSet_熱重載 測試規則檔變更時自動重新載入
+func TestBotRuleSet_熱重載(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot-rules.yaml")
+	rules := NewBotRuleSet()
+	detector := NewBotDetectorWithRules(rules)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rules.Watch(ctx, path, 10*time.Millisecond)
+
+	ua := "ReloadTester/1.0"
+	isBot, _ := detector.IsBot(ua)
+	assert.False(t, isBot)
+
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  - {name: Reload, category: 測試, substring: reloadtester}\n"), 0644))
+	assert.Eventually(t, func() bool {
+		_, botType := detector.IsBot(ua)
+		return botType == "測試"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// 刪除規則檔後恢復預設規則
+	require.NoError(t, os.Remove(path))
+	assert.Eventually(t, func() bool {
+		isBot, _ := detector.IsBot(ua)
+		return !isBot
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,327 @@
+package stats
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCrawlerRangesData 內建的爬蟲驗證資料（網域與 IP 範圍）
+//
+//go:embed crawler_ranges.yaml
+var defaultCrawlerRangesData []byte
+
+// 爬蟲驗證結果
+const (
+	VerificationVerified     = "verified"     // 確認為官方爬蟲（IP 位於公布範圍，或反向/正向 DNS 相符）
+	VerificationSpoofed      = "spoofed"      // 冒充官方爬蟲（IP 與 DNS 皆不相符）
+	VerificationUnverifiable = "unverifiable" // 無法驗證（沒有驗證資料或 DNS 查詢失敗）
+)
+
+// defaultDNSTimeout 單次 DNS 查詢的預設逾時
+const defaultDNSTimeout = 2 * time.Second
+
+// maxCacheEntries 驗證快取的上限，超過時清空重建
+const maxCacheEntries = 100000
+
+// maxVerifyWorkers VerifyAll 同時進行的驗證數（DNS 查詢大多在等待網路）
+const maxVerifyWorkers = 32
+
+// Resolver DNS 查詢介面
+// *net.Resolver 實作此介面；測試時可以用本地樁替換
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error) // 反向查詢 IP 的主機名稱
+	LookupHost(ctx context.Context, host string) ([]string, error) // 正向查詢主機名稱的 IP
+}
+
+// CrawlerProfile 單一爬蟲的驗證資料
+type CrawlerProfile struct {
+	Name    string   `json:"name" yaml:"name"`                 // 代理程式名稱（對應 BotIdentity.Name）
+	Domains []string `json:"domains,omitempty" yaml:"domains"` // 反向 DNS 主機名稱必須結尾的網域
+	CIDRs   []string `json:"cidrs,omitempty" yaml:"cidrs"`     // 官方公布的 IP 範圍
+
+	networks []*net.IPNet // 解析後的 IP 範圍
+}
+
+// crawlerRangesFile 爬蟲驗證資料檔格式（YAML 或 JSON）
+type crawlerRangesFile struct {
+	Mode     string           `yaml:"mode"`     // 套用模式：extend（預設）或 replace
+	Crawlers []CrawlerProfile `yaml:"crawlers"` // 爬蟲列表
+}
+
+// ParseCrawlerProfiles 解析爬蟲驗證資料（YAML 或 JSON）
+// 返回套用模式與已解析的爬蟲資料
+func ParseCrawlerProfiles(data []byte) (string, []CrawlerProfile, error) {
+	var file crawlerRangesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", nil, fmt.Errorf("解析爬蟲驗證資料失敗: %w", err)
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(file.Mode))
+	if mode == "" {
+		mode = RuleModeExtend
+	}
+	if mode != RuleModeExtend && mode != RuleModeReplace {
+		return "", nil, &models.ValidationError{Field: "mode", Value: file.Mode, Message: "模式必須為 extend 或 replace"}
+	}
+
+	for i := range file.Crawlers {
+		profile := &file.Crawlers[i]
+		if strings.TrimSpace(profile.Name) == "" {
+			return "", nil, &models.ValidationError{Field: "name", Value: profile.Name, Message: fmt.Sprintf("第 %d 個爬蟲的名稱不可為空", i+1)}
+		}
+		for _, cidr := range profile.CIDRs {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return "", nil, &models.ValidationError{Field: "cidrs", Value: cidr, Message: fmt.Sprintf("爬蟲 %s 的 IP 範圍無效", profile.Name)}
+			}
+			profile.networks = append(profile.networks, network)
+		}
+		for j, domain := range profile.Domains {
+			profile.Domains[j] = strings.ToLower(strings.Trim(domain, "."))
+		}
+	}
+	return mode, file.Crawlers, nil
+}
+
+// CrawlerVerifier 驗證自稱為主要爬蟲的請求是否真的來自該爬蟲
+// 先比對官方公布的 IP 範圍，再（選擇性地）以反向 DNS 加正向 DNS 確認。
+// 結果依 (爬蟲名稱, IP) 快取（上限 maxCacheEntries），可安全地在多個 goroutine 間共用
+type CrawlerVerifier struct {
+	mu       sync.RWMutex
+	profiles map[string]*CrawlerProfile // 小寫名稱 -> 驗證資料
+	resolver Resolver                   // DNS 查詢（nil 表示不使用 DNS）
+	timeout  time.Duration              // 單次 DNS 查詢逾時
+	cache    map[string]string          // 名稱|IP -> 驗證結果
+	log      *logger.Logger
+}
+
+// NewCrawlerVerifier 建立使用內建驗證資料、不使用 DNS 的驗證器
+func NewCrawlerVerifier() *CrawlerVerifier {
+	_, profiles, err := ParseCrawlerProfiles(defaultCrawlerRangesData)
+	if err != nil {
+		// 內建資料隨程式編譯，解析失敗屬於程式錯誤
+		panic(fmt.Sprintf("內建爬蟲驗證資料無效: %v", err))
+	}
+
+	v := &CrawlerVerifier{
+		profiles: make(map[string]*CrawlerProfile, len(profiles)),
+		timeout:  defaultDNSTimeout,
+		cache:    make(map[string]string),
+		log:      logger.Get().WithModule("crawler-verify"),
+	}
+	for i := range profiles {
+		v.profiles[strings.ToLower(profiles[i].Name)] = &profiles[i]
+	}
+	return v
+}
+
+// SetResolver 設定 DNS 查詢（nil 表示只比對 IP 範圍，範圍外的 IP 無法判定為冒充）
+// 傳入 net.DefaultResolver 即可使用系統 DNS
+func (v *CrawlerVerifier) SetResolver(resolver Resolver, timeout time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.resolver = resolver
+	if timeout > 0 {
+		v.timeout = timeout
+	}
+	v.cache = make(map[string]string)
+}
+
+// LoadFile 載入爬蟲驗證資料檔，更新官方 IP 範圍與網域
+// extend 模式依名稱覆寫或新增，replace 模式完全取代內建資料
+func (v *CrawlerVerifier) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("讀取爬蟲驗證資料檔失敗: %w", err)
+	}
+	mode, profiles, err := ParseCrawlerProfiles(data)
+	if err != nil {
+		return fmt.Errorf("載入爬蟲驗證資料檔 %s 失敗: %w", path, err)
+	}
+
+	v.mu.Lock()
+	if mode == RuleModeReplace {
+		v.profiles = make(map[string]*CrawlerProfile, len(profiles))
+	}
+	for i := range profiles {
+		v.profiles[strings.ToLower(profiles[i].Name)] = &profiles[i]
+	}
+	v.cache = make(map[string]string)
+	v.mu.Unlock()
+
+	v.log.Info().
+		Str("path", path).
+		Str("mode", mode).
+		Int("crawlers", len(profiles)).
+		Msg("已載入爬蟲驗證資料檔")
+	return nil
+}
+
+// Profiles 返回目前的爬蟲驗證資料
+func (v *CrawlerVerifier) Profiles() []CrawlerProfile {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	profiles := make([]CrawlerProfile, 0, len(v.profiles))
+	for _, profile := range v.profiles {
+		profiles = append(profiles, *profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// Verify 驗證自稱為 name 的請求是否來自該爬蟲
+// 沒有驗證資料的代理程式（例如 curl）一律返回 unverifiable；
+// 只有啟用 DNS 查詢時才會判定為 spoofed，否則公布範圍外的 IP 返回 unverifiable
+func (v *CrawlerVerifier) Verify(name, ip string) string {
+	key := strings.ToLower(name) + "|" + ip
+
+	v.mu.RLock()
+	status, cached := v.cache[key]
+	profile := v.profiles[strings.ToLower(name)]
+	resolver, timeout := v.resolver, v.timeout
+	v.mu.RUnlock()
+	if cached {
+		return status
+	}
+
+	// DNS 查詢可能耗時，不持有鎖
+	status = v.verify(profile, ip, resolver, timeout)
+
+	v.mu.Lock()
+	if len(v.cache) >= maxCacheEntries {
+		v.cache = make(map[string]string)
+	}
+	v.cache[key] = status
+	v.mu.Unlock()
+	return status
+}
+
+// CrawlerClaim 自稱為某爬蟲的來源
+type CrawlerClaim struct {
+	Name string // 代理程式名稱
+	IP   string // 來源 IP
+}
+
+// VerifyAll 並行驗證多個來源，返回與 claims 順序相同的驗證結果
+// 啟用 DNS 時每個來源最多需等待兩次查詢逾時，並行可避免總耗時隨來源數線性增加
+func (v *CrawlerVerifier) VerifyAll(claims []CrawlerClaim) []string {
+	statuses := make([]string, len(claims))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(maxVerifyWorkers, len(claims)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				statuses[i] = v.Verify(claims[i].Name, claims[i].IP)
+			}
+		}()
+	}
+	for i := range claims {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return statuses
+}
+
+// verify 執行驗證（不使用快取）
+func (v *CrawlerVerifier) verify(profile *CrawlerProfile, ip string, resolver Resolver, timeout time.Duration) string {
+	if profile == nil {
+		return VerificationUnverifiable
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return VerificationUnverifiable
+	}
+
+	for _, network := range profile.networks {
+		if network.Contains(addr) {
+			return VerificationVerified
+		}
+	}
+
+	if resolver != nil && len(profile.Domains) > 0 {
+		return v.verifyDNS(profile, ip, resolver, timeout)
+	}
+
+	// 不使用 DNS 時無法判定為冒充：內建的 IP 範圍只是快照，可能不完整或已過期
+	return VerificationUnverifiable
+}
+
+// verifyDNS 以反向 DNS 查詢主機名稱，確認網域相符後再正向查詢確認 IP 相同
+func (v *CrawlerVerifier) verifyDNS(profile *CrawlerProfile, ip string, resolver Resolver, timeout time.Duration) string {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	hosts, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		if isNotFound(err) {
+			// IP 沒有反向記錄：官方爬蟲一定有，判定為冒充
+			return VerificationSpoofed
+		}
+		v.log.Debug().Err(err).Str("ip", ip).Msg("反向 DNS 查詢失敗")
+		return VerificationUnverifiable
+	}
+
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSuffix(host, "."))
+		if !hasDomainSuffix(host, profile.Domains) {
+			continue
+		}
+
+		addrs, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			v.log.Debug().Err(err).Str("host", host).Msg("正向 DNS 查詢失敗")
+			return VerificationUnverifiable
+		}
+		for _, addr := range addrs {
+			if sameIP(addr, ip) {
+				return VerificationVerified
+			}
+		}
+	}
+	return VerificationSpoofed
+}
+
+// hasDomainSuffix 判斷主機名稱是否屬於任一網域
+func hasDomainSuffix(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameIP 比較兩個 IP 字串（IPv6 可能有不同表示法）
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	return ipA != nil && ipB != nil && ipA.Equal(ipB)
+}
+
+// isNotFound 判斷 DNS 錯誤是否為查無記錄
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,193 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubResolver 本地 DNS 樁，以對照表回應查詢並記錄查詢次數
+type stubResolver struct {
+	ptr     map[string][]string // IP -> 主機名稱
+	hosts   map[string][]string // 主機名稱 -> IP
+	fail    map[string]bool     // 查詢會逾時的 IP
+	delay   time.Duration       // 每次反向查詢的延遲
+	lookups atomic.Int32
+	active  atomic.Int32 // 進行中的反向查詢數
+	peak    atomic.Int32 // 同時進行的反向查詢數最大值
+}
+
+func (r *stubResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	r.lookups.Add(1)
+	if active := r.active.Add(1); active > r.peak.Load() {
+		r.peak.Store(active)
+	}
+	defer r.active.Add(-1)
+	time.Sleep(r.delay)
+	if r.fail[addr] {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: addr, IsTimeout: true}
+	}
+	if hosts, ok := r.ptr[addr]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func (r *stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// TestCrawlerVerifier_IP範圍 測試以官方公布的 IP 範圍驗證
+func TestCrawlerVerifier_IP範圍(t *testing.T) {
+	verifier := NewCrawlerVerifier()
+
+	testCases := []struct {
+		name     string
+		ip       string
+		expected string
+	}{
+		{"Googlebot", "66.249.66.1", VerificationVerified},
+		{"Googlebot", "2001:4860:4801:10::1", VerificationVerified},
+		{"Googlebot", "203.0.113.9", VerificationUnverifiable}, // 內建範圍只是快照，未啟用 DNS 時不判定為冒充
+		{"Bingbot", "157.55.39.10", VerificationVerified},
+		{"GPTBot", "20.15.240.70", VerificationVerified},
+		{"GPTBot", "198.51.100.1", VerificationUnverifiable},
+		{"YandexBot", "203.0.113.9", VerificationUnverifiable}, // 只有網域資料，未啟用 DNS
+		{"curl", "203.0.113.9", VerificationUnverifiable},      // 沒有驗證資料
+		{"Googlebot", "not-an-ip", VerificationUnverifiable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name+"_"+tc.ip, func(t *testing.T) {
+			assert.Equal(t, tc.expected, verifier.Verify(tc.name, tc.ip))
+		})
+	}
+}
+
+// TestCrawlerVerifier_DNS驗證 測試反向加正向 DNS 確認
+func TestCrawlerVerifier_DNS驗證(t *testing.T) {
+	resolver := &stubResolver{
+		ptr: map[string][]string{
+			"203.0.113.1": {"crawl-203-0-113-1.googlebot.com."},
+			"203.0.113.2": {"evil.example.com."},
+			"203.0.113.3": {"crawl-fake.googlebot.com."}, // 正向查詢不是同一個 IP
+			"5.255.253.1": {"5-255-253-1.spider.yandex.com."},
+			"203.0.113.6": {"6.113.0.203.bc.googleusercontent.com."}, // Google Cloud 客戶的虛擬機器
+		},
+		hosts: map[string][]string{
+			"crawl-203-0-113-1.googlebot.com":      {"203.0.113.1"},
+			"crawl-fake.googlebot.com":             {"198.51.100.7"},
+			"5-255-253-1.spider.yandex.com":        {"5.255.253.1"},
+			"6.113.0.203.bc.googleusercontent.com": {"203.0.113.6"},
+		},
+		fail: map[string]bool{"203.0.113.5": true},
+	}
+
+	verifier := NewCrawlerVerifier()
+	verifier.SetResolver(resolver, time.Second)
+
+	assert.Equal(t, VerificationVerified, verifier.Verify("Googlebot", "203.0.113.1"))
+	assert.Equal(t, VerificationSpoofed, verifier.Verify("Googlebot", "203.0.113.2"), "網域不符")
+	assert.Equal(t, VerificationSpoofed, verifier.Verify("Googlebot", "203.0.113.3"), "正向查詢不符")
+	assert.Equal(t, VerificationSpoofed, verifier.Verify("Googlebot", "203.0.113.4"), "沒有反向記錄")
+	assert.Equal(t, VerificationUnverifiable, verifier.Verify("Googlebot", "203.0.113.5"), "DNS 逾時")
+	assert.Equal(t, VerificationSpoofed, verifier.Verify("Googlebot", "203.0.113.6"), "googleusercontent.com 不是 Googlebot 的網域")
+	assert.Equal(t, VerificationVerified, verifier.Verify("YandexBot", "5.255.253.1"))
+
+	// IP 位於公布範圍時不需查詢 DNS；相同查詢使用快取
+	lookups := resolver.lookups.Load()
+	assert.Equal(t, VerificationVerified, verifier.Verify("Googlebot", "66.249.66.1"))
+	assert.Equal(t, VerificationVerified, verifier.Verify("googlebot", "203.0.113.1"))
+	assert.Equal(t, lookups, resolver.lookups.Load())
+}
+
+// TestCrawlerVerifier_VerifyAll 測試並行驗證多個來源並保持結果順序
+func TestCrawlerVerifier_VerifyAll(t *testing.T) {
+	resolver := &stubResolver{delay: 20 * time.Millisecond}
+	verifier := NewCrawlerVerifier()
+	verifier.SetResolver(resolver, time.Second)
+
+	claims := []CrawlerClaim{{Name: "Googlebot", IP: "66.249.66.1"}, {Name: "curl", IP: "10.0.0.1"}}
+	for i := 0; i < 10; i++ {
+		claims = append(claims, CrawlerClaim{Name: "Googlebot", IP: fmt.Sprintf("203.0.113.%d", i)})
+	}
+
+	statuses := verifier.VerifyAll(claims)
+	require.Len(t, statuses, len(claims))
+	assert.Equal(t, VerificationVerified, statuses[0])
+	assert.Equal(t, VerificationUnverifiable, statuses[1])
+	for _, status := range statuses[2:] {
+		assert.Equal(t, VerificationSpoofed, status)
+	}
+	assert.Equal(t, int32(10), resolver.lookups.Load())
+	assert.Greater(t, resolver.peak.Load(), int32(1), "DNS 查詢應並行進行")
+	assert.Empty(t, verifier.VerifyAll(nil))
+}
+
+// TestCrawlerVerifier_載入資料檔 測試以資料檔更新 IP 範圍
+func TestCrawlerVerifier_載入資料檔(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crawler-ranges.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+crawlers:
+  - name: Googlebot
+    cidrs: [203.0.113.0/24]
+  - name: ClaudeBot
+    cidrs: [198.51.100.0/24]
+`), 0644))
+
+	verifier := NewCrawlerVerifier()
+	assert.Equal(t, VerificationUnverifiable, verifier.Verify("Googlebot", "203.0.113.9"))
+
+	require.NoError(t, verifier.LoadFile(path))
+	assert.Equal(t, VerificationVerified, verifier.Verify("Googlebot", "203.0.113.9"), "載入後應清除快取")
+	assert.Equal(t, VerificationUnverifiable, verifier.Verify("Googlebot", "66.249.66.1"), "同名資料取代內建資料")
+	assert.Equal(t, VerificationVerified, verifier.Verify("ClaudeBot", "198.51.100.20"))
+	assert.Equal(t, VerificationVerified, verifier.Verify("Bingbot", "157.55.39.10"), "其他內建資料保留")
+
+	_, _, err := ParseCrawlerProfiles([]byte("crawlers:\n  - {name: X, cidrs: [not-a-cidr]}\n"))
+	assert.Error(t, err)
+}
+
+// TestBotDetector_冒充爬蟲 測試統計中分開回報冒充的爬蟲
+func TestBotDetector_冒充爬蟲(t *testing.T) {
+	// 冒充只能以 DNS 判定：203.0.113.9 沒有反向記錄
+	verifier := NewCrawlerVerifier()
+	verifier.SetResolver(&stubResolver{}, time.Second)
+	detector := NewBotDetector()
+	detector.SetVerifier(verifier)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+	detector.Observe(&models.LogEntry{IP: "66.249.66.1", UserAgent: googlebot, Timestamp: base})
+	detector.Observe(&models.LogEntry{IP: "203.0.113.9", UserAgent: googlebot, Timestamp: base})
+	detector.Observe(&models.LogEntry{IP: "203.0.113.9", UserAgent: googlebot, Timestamp: base.Add(time.Minute)})
+	detector.Observe(&models.LogEntry{IP: "10.0.0.1", UserAgent: "curl/8.0", Timestamp: base})
+
+	stats := detector.GetStats()
+	assert.Equal(t, BotVerificationStats{Verified: 1, Spoofed: 2, Unverifiable: 1}, stats.Verification)
+	assert.Equal(t, []SpoofedCrawlerStat{{
+		IP:          "203.0.113.9",
+		ClaimedName: "Googlebot",
+		Category:    "搜尋引擎",
+		Count:       2,
+		FirstSeen:   base,
+		LastSeen:    base.Add(time.Minute),
+	}}, stats.SpoofedCrawlers)
+
+	require.NotEmpty(t, stats.Agents)
+	assert.Equal(t, "Googlebot", stats.Agents[0].Name)
+	assert.Equal(t, BotVerificationStats{Verified: 1, Spoofed: 2}, stats.Agents[0].BotVerificationStats)
+}
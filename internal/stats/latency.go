@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// LatencyStatistics 請求處理時間統計（微秒，只計入日誌有記錄處理時間的請求）
+type LatencyStatistics struct {
+	Samples int   `json:"samples"` // 有處理時間的請求數
+	Average int64 `json:"average"` // 平均處理時間
+	P50     int64 `json:"p50"`     // 中位數
+	P95     int64 `json:"p95"`     // 第 95 百分位數
+	P99     int64 `json:"p99"`     // 第 99 百分位數
+	Max     int64 `json:"max"`     // 最大處理時間
+}
+
+// buildLatency 從處理時間樣本計算統計（會就地排序樣本）
+func buildLatency(samples []int64) LatencyStatistics {
+	if len(samples) == 0 {
+		return LatencyStatistics{}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	var sum int64
+	for _, v := range samples {
+		sum += v
+	}
+	return LatencyStatistics{
+		Samples: len(samples),
+		Average: sum / int64(len(samples)),
+		P50:     latencyPercentile(samples, 50),
+		P95:     latencyPercentile(samples, 95),
+		P99:     latencyPercentile(samples, 99),
+		Max:     samples[len(samples)-1],
+	}
+}
+
+// latencyPercentile 以線性內插計算已排序樣本的百分位數
+func latencyPercentile(sorted []int64, p float64) int64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	weight := rank - float64(lower)
+	return int64(math.Round(float64(sorted[lower])*(1-weight) + float64(sorted[upper])*weight))
+}
@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"testing"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculator_Latency 測試處理時間分布與各路徑的平均處理時間
+func TestCalculator_Latency(t *testing.T) {
+	var entries []models.LogEntry
+	// /api：1..100 毫秒
+	for i := 1; i <= 100; i++ {
+		entries = append(entries, models.LogEntry{IP: "10.0.0.1", URL: "/api", StatusCode: 200, RequestTime: int64(i) * 1000})
+	}
+	// 沒有記錄處理時間的請求不列入
+	entries = append(entries, models.LogEntry{IP: "10.0.0.2", URL: "/static.css", StatusCode: 200})
+
+	statistics := NewCalculator().Calculate(entries)
+
+	latency := statistics.Latency
+	assert.Equal(t, 100, latency.Samples)
+	assert.Equal(t, int64(50500), latency.Average)
+	assert.Equal(t, int64(50500), latency.P50)
+	assert.Equal(t, int64(95050), latency.P95)
+	assert.Equal(t, int64(99010), latency.P99)
+	assert.Equal(t, int64(100000), latency.Max)
+
+	require.Len(t, statistics.TopPaths, 2)
+	assert.Equal(t, "/api", statistics.TopPaths[0].Path)
+	assert.Equal(t, int64(50500), statistics.TopPaths[0].AverageRequestTime)
+	assert.Zero(t, statistics.TopPaths[1].AverageRequestTime)
+}
+
+// TestBuildLatency_空資料 測試沒有處理時間時回傳零值
+func TestBuildLatency_空資料(t *testing.T) {
+	assert.Equal(t, LatencyStatistics{}, buildLatency(nil))
+	assert.Equal(t, LatencyStatistics{Samples: 1, Average: 7, P50: 7, P95: 7, P99: 7, Max: 7}, buildLatency([]int64{7}))
+}
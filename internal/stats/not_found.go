@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"access-log-analyzer/internal/models"
+)
+
+// maxNotFoundPaths 404 報表保留的路徑上限（依次數降序），避免掃描器造成的大量路徑撐大統計結果
+const maxNotFoundPaths = 1000
+
+// maxNotFoundSources 每個路徑保留的 Referer 與轉址來源上限
+const maxNotFoundSources = 5
+
+// redirectFollowWindow 轉址後同一用戶端在此時間內請求的 404 路徑視為轉址的目的地
+const redirectFollowWindow = 10 * time.Second
+
+// NotFoundReport 失效連結（404/410）報表
+type NotFoundReport struct {
+	TotalRequests int            `json:"totalRequests"` // 404/410 請求總數
+	BotRequests   int            `json:"botRequests"`   // 其中由機器人發出的請求數
+	UniquePaths   int            `json:"uniquePaths"`   // 不重複的 404/410 路徑數
+	Paths         []NotFoundPath `json:"paths"`         // 各路徑明細（依次數降序，最多 1000 筆）
+}
+
+// NotFoundPath 單一 404/410 路徑的明細
+type NotFoundPath struct {
+	Path             string             `json:"path"`             // 請求路徑
+	Count            int                `json:"count"`            // 404/410 次數
+	GoneCount        int                `json:"goneCount"`        // 其中 410 的次數
+	FirstSeen        time.Time          `json:"firstSeen"`        // 第一次出現時間
+	LastSeen         time.Time          `json:"lastSeen"`         // 最後一次出現時間
+	BotHits          int                `json:"botHits"`          // 機器人請求次數
+	HumanHits        int                `json:"humanHits"`        // 一般用戶請求次數
+	InternalReferers []DistributionItem `json:"internalReferers"` // 站內連結來源（需設定本站主機，Top 5）
+	ExternalReferers []DistributionItem `json:"externalReferers"` // 外部連結來源（Top 5）
+	RedirectCount    int                `json:"redirectCount"`    // 同一路徑回應 301/302/307/308 的次數
+	LastRedirect     time.Time          `json:"lastRedirect"`     // 同一路徑最後一次轉址的時間（沒有轉址時為零值）
+	RedirectedFrom   []DistributionItem `json:"redirectedFrom"`   // 轉址後緊接著請求此路徑的來源路徑（Top 5）
+}
+
+// notFoundAccumulator 累積單一路徑的 404/410 請求
+type notFoundAccumulator struct {
+	count          int
+	gone           int
+	bots           int
+	firstSeen      time.Time
+	lastSeen       time.Time
+	referers       map[string]int // Referer -> 次數（計算結束後才分類站內與外部）
+	redirectedFrom map[string]int // 轉址來源路徑 -> 次數
+}
+
+// redirectHop 用戶端最後一次收到的轉址
+type redirectHop struct {
+	path string
+	at   time.Time
+}
+
+// isBrokenLinkStatus 判斷狀態碼是否為失效連結
+func isBrokenLinkStatus(statusCode int) bool {
+	return statusCode == 404 || statusCode == 410
+}
+
+// isRedirect 判斷狀態碼是否為轉址
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case 301, 302, 307, 308:
+		return true
+	}
+	return false
+}
+
+// observeNotFound 記錄路徑的轉址與 404/410 請求
+// hops 保存各用戶端（IP 與 User-Agent）最後一次轉址，用於推測轉址鏈
+func (acc *pathStatAccumulator) observeNotFound(entry *models.LogEntry, isBot bool, hops map[string]redirectHop) {
+	switch {
+	case isRedirect(entry.StatusCode):
+		acc.redirects++
+		if entry.Timestamp.After(acc.lastRedirect) {
+			acc.lastRedirect = entry.Timestamp
+		}
+		hops[entry.IP+"|"+entry.UserAgent] = redirectHop{path: entry.URL, at: entry.Timestamp}
+
+	case isBrokenLinkStatus(entry.StatusCode):
+		nf := acc.notFound
+		if nf == nil {
+			nf = &notFoundAccumulator{
+				firstSeen:      entry.Timestamp,
+				lastSeen:       entry.Timestamp,
+				referers:       make(map[string]int),
+				redirectedFrom: make(map[string]int),
+			}
+			acc.notFound = nf
+		}
+		nf.count++
+		if entry.StatusCode == 410 {
+			nf.gone++
+		}
+		if isBot {
+			nf.bots++
+		}
+		if entry.Timestamp.Before(nf.firstSeen) {
+			nf.firstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(nf.lastSeen) {
+			nf.lastSeen = entry.Timestamp
+		}
+		if entry.Referer != "" && entry.Referer != "-" {
+			nf.referers[entry.Referer]++
+		}
+
+		client := entry.IP + "|" + entry.UserAgent
+		if hop, ok := hops[client]; ok && hop.path != entry.URL {
+			if elapsed := entry.Timestamp.Sub(hop.at); elapsed >= 0 && elapsed <= redirectFollowWindow {
+				nf.redirectedFrom[hop.path]++
+				delete(hops, client)
+			}
+		}
+	}
+}
+
+// buildNotFoundReport 從路徑統計建立 404/410 報表
+func buildNotFoundReport(pathStats map[string]*pathStatAccumulator, siteHosts []string) NotFoundReport {
+	var report NotFoundReport
+	classified := make(map[string]string) // Referer -> 管道，不同路徑共用分類結果
+
+	for urlPath, acc := range pathStats {
+		nf := acc.notFound
+		if nf == nil {
+			continue
+		}
+		report.TotalRequests += nf.count
+		report.BotRequests += nf.bots
+		report.UniquePaths++
+
+		internal := make(map[string]int)
+		external := make(map[string]int)
+		for referer, count := range nf.referers {
+			channel, ok := classified[referer]
+			if !ok {
+				channel = classifyReferer(referer, siteHosts).channel
+				classified[referer] = channel
+			}
+			if channel == ChannelInternal {
+				internal[referer] += count
+			} else {
+				external[referer] += count
+			}
+		}
+
+		report.Paths = append(report.Paths, NotFoundPath{
+			Path:             urlPath,
+			Count:            nf.count,
+			GoneCount:        nf.gone,
+			FirstSeen:        nf.firstSeen,
+			LastSeen:         nf.lastSeen,
+			BotHits:          nf.bots,
+			HumanHits:        nf.count - nf.bots,
+			InternalReferers: distribution(internal, nf.count, maxNotFoundSources),
+			ExternalReferers: distribution(external, nf.count, maxNotFoundSources),
+			RedirectCount:    acc.redirects,
+			LastRedirect:     acc.lastRedirect,
+			RedirectedFrom:   distribution(nf.redirectedFrom, nf.count, maxNotFoundSources),
+		})
+	}
+
+	sort.Slice(report.Paths, func(i, j int) bool {
+		if report.Paths[i].Count != report.Paths[j].Count {
+			return report.Paths[i].Count > report.Paths[j].Count
+		}
+		return report.Paths[i].Path < report.Paths[j].Path
+	})
+	if len(report.Paths) > maxNotFoundPaths {
+		report.Paths = report.Paths[:maxNotFoundPaths]
+	}
+	return report
+}
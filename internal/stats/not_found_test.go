@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculator_NotFound 測試失效連結報表的來源、機器人比例與轉址提示
+func TestCalculator_NotFound(t *testing.T) {
+	const (
+		browser   = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		googlebot = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	)
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []models.LogEntry{
+		// /old-page 曾經轉址，之後改為 404
+		{IP: "10.0.0.1", Timestamp: base, URL: "/old-page", StatusCode: 301, UserAgent: browser},
+		{IP: "10.0.0.1", Timestamp: base.Add(time.Second), URL: "/new-page", StatusCode: 404, UserAgent: browser, Referer: "https://www.example.com/blog"},
+		{IP: "10.0.0.2", Timestamp: base.Add(time.Hour), URL: "/old-page", StatusCode: 404, UserAgent: browser, Referer: "https://www.example.com/blog"},
+		{IP: "10.0.0.3", Timestamp: base.Add(2 * time.Hour), URL: "/old-page", StatusCode: 404, UserAgent: browser, Referer: "https://partner.org/links"},
+		{IP: "66.249.66.1", Timestamp: base.Add(30 * time.Minute), URL: "/old-page", StatusCode: 410, UserAgent: googlebot},
+		// 轉址超過時間窗後的 404 不算轉址鏈
+		{IP: "10.0.0.4", Timestamp: base, URL: "/moved", StatusCode: 302, UserAgent: browser},
+		{IP: "10.0.0.4", Timestamp: base.Add(time.Minute), URL: "/new-page", StatusCode: 404, UserAgent: browser},
+		{IP: "10.0.0.5", Timestamp: base, URL: "/", StatusCode: 200, UserAgent: browser},
+	}
+
+	calc := NewCalculator()
+	require.NoError(t, calc.SetSiteHosts([]string{"example.com"}))
+	report := calc.Calculate(entries).NotFound
+
+	assert.Equal(t, 5, report.TotalRequests)
+	assert.Equal(t, 1, report.BotRequests)
+	assert.Equal(t, 2, report.UniquePaths)
+	require.Len(t, report.Paths, 2)
+
+	oldPage := report.Paths[0]
+	assert.Equal(t, "/old-page", oldPage.Path)
+	assert.Equal(t, 3, oldPage.Count)
+	assert.Equal(t, 1, oldPage.GoneCount)
+	assert.Equal(t, 1, oldPage.BotHits)
+	assert.Equal(t, 2, oldPage.HumanHits)
+	assert.Equal(t, base.Add(30*time.Minute), oldPage.FirstSeen, "不依記錄順序計算首次出現時間")
+	assert.Equal(t, base.Add(2*time.Hour), oldPage.LastSeen)
+	require.Len(t, oldPage.InternalReferers, 1)
+	assert.Equal(t, "https://www.example.com/blog", oldPage.InternalReferers[0].Name)
+	require.Len(t, oldPage.ExternalReferers, 1)
+	assert.Equal(t, "https://partner.org/links", oldPage.ExternalReferers[0].Name)
+	assert.Equal(t, 1, oldPage.RedirectCount)
+	assert.Equal(t, base, oldPage.LastRedirect)
+	assert.Empty(t, oldPage.RedirectedFrom)
+
+	newPage := report.Paths[1]
+	assert.Equal(t, "/new-page", newPage.Path)
+	assert.Equal(t, 2, newPage.Count)
+	assert.Equal(t, 0, newPage.RedirectCount)
+	assert.True(t, newPage.LastRedirect.IsZero())
+	assert.Equal(t, []DistributionItem{{Name: "/old-page", Count: 1, Percentage: 50}}, newPage.RedirectedFrom)
+}
@@ -0,0 +1,507 @@
+package stats
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 流量來源管道
+const (
+	ChannelDirect   = "direct"   // 沒有 Referer（直接輸入網址、書籤或 App）
+	ChannelInternal = "internal" // 來自本站的頁面
+	ChannelSearch   = "search"   // 搜尋引擎
+	ChannelSocial   = "social"   // 社群網站
+	ChannelEmail    = "email"    // 網頁郵件或 utm_medium=email 的連結
+	ChannelReferral = "referral" // 其他外部網站
+)
+
+// 本站主機的來源
+const (
+	SiteHostsConfigured = "configured" // 使用者設定
+	SiteHostsVirtual    = "vhost"      // 由日誌的虛擬主機推斷
+	SiteHostsReferer    = "referer"    // 由最常見的來源網域推斷
+	SiteHostsNone       = "none"       // 無法判斷，不區分站內外也不偵測盜連
+)
+
+// unknownReferer 無法解析網域的 Referer
+const unknownReferer = "未知"
+
+// notSet 未帶入的 utm 參數
+const notSet = "未設定"
+
+// RefererStatistics 流量來源分析
+type RefererStatistics struct {
+	Direct          int                  `json:"direct"`          // 沒有 Referer 的請求數
+	Internal        int                  `json:"internal"`        // 來自本站的請求數
+	External        int                  `json:"external"`        // 來自外部網站的請求數
+	Channels        []DistributionItem   `json:"channels"`        // 依管道的分布：direct、internal、search、social、email、referral
+	Domains         []RefererDomain      `json:"domains"`         // 外部來源網域（Top-N）
+	SearchEngines   []DistributionItem   `json:"searchEngines"`   // 搜尋引擎分布
+	Campaigns       []CampaignStatistics `json:"campaigns"`       // 依 utm 參數彙總的行銷活動（Top-N）
+	Hotlinks        []HotlinkStatistics  `json:"hotlinks"`        // 外部網站盜連的圖片與媒體（Top-N）
+	HotlinkRequests int                  `json:"hotlinkRequests"` // 盜連請求總數
+	HotlinkBytes    int64                `json:"hotlinkBytes"`    // 盜連消耗的傳輸量（位元組）
+	SiteHosts       []string             `json:"siteHosts"`       // 判斷站內流量使用的本站主機
+	SiteHostsSource string               `json:"siteHostsSource"` // 本站主機來源：configured、vhost、referer 或 none
+}
+
+// RefererDomain 外部來源網域
+type RefererDomain struct {
+	Domain     string  `json:"domain"`     // 來源網域（去除 www.），無法解析時為 "未知"
+	Channel    string  `json:"channel"`    // 管道：search、social、email 或 referral
+	Count      int     `json:"count"`      // 請求次數
+	Percentage float64 `json:"percentage"` // 佔外部來源請求的百分比
+}
+
+// CampaignStatistics 依 utm_source、utm_medium、utm_campaign 彙總的行銷活動
+type CampaignStatistics struct {
+	Source    string `json:"source"`    // utm_source（小寫），未帶入為 "未設定"
+	Medium    string `json:"medium"`    // utm_medium（小寫），未帶入為 "未設定"
+	Campaign  string `json:"campaign"`  // utm_campaign，未帶入為 "未設定"
+	Count     int    `json:"count"`     // 請求次數
+	UniqueIPs int    `json:"uniqueIPs"` // 不重複 IP 數
+}
+
+// HotlinkStatistics 外部網站直接引用的圖片或媒體
+type HotlinkStatistics struct {
+	Domain string `json:"domain"` // 引用的外部網域
+	Path   string `json:"path"`   // 被引用的資源路徑（不含查詢字串）
+	Count  int    `json:"count"`  // 請求次數
+	Bytes  int64  `json:"bytes"`  // 傳輸量（位元組）
+}
+
+// domainRule 依網域判斷管道的規則
+// pattern 以 "." 結尾時比對網域開頭（例如 google. 涵蓋各國的 Google），否則比對網域本身或其子網域
+type domainRule struct {
+	pattern string
+	name    string
+}
+
+// match 判斷網域是否符合規則
+func (r domainRule) match(domain string) bool {
+	if strings.HasSuffix(r.pattern, ".") {
+		return strings.HasPrefix(domain, r.pattern)
+	}
+	return domain == r.pattern || strings.HasSuffix(domain, "."+r.pattern)
+}
+
+// emailDomains 網頁郵件服務，需在搜尋引擎之前比對（mail.yahoo.com 與 search.yahoo.com）
+var emailDomains = []domainRule{
+	{"mail.google.com", "Gmail"},
+	{"outlook.live.com", "Outlook"},
+	{"outlook.office.com", "Outlook"},
+	{"outlook.office365.com", "Outlook"},
+	{"mail.yahoo.com", "Yahoo Mail"},
+	{"mail.proton.me", "Proton Mail"},
+	{"mail.", "Webmail"},
+	{"webmail.", "Webmail"},
+}
+
+// searchDomains 搜尋引擎
+var searchDomains = []domainRule{
+	{"google.", "Google"},
+	{"bing.com", "Bing"},
+	{"search.yahoo.com", "Yahoo"},
+	{"search.yahoo.co.jp", "Yahoo"},
+	{"duckduckgo.com", "DuckDuckGo"},
+	{"baidu.com", "Baidu"},
+	{"yandex.", "Yandex"},
+	{"search.naver.com", "Naver"},
+	{"ecosia.org", "Ecosia"},
+	{"search.brave.com", "Brave"},
+	{"sogou.com", "Sogou"},
+}
+
+// socialDomains 社群網站
+var socialDomains = []domainRule{
+	{"facebook.com", "Facebook"},
+	{"instagram.com", "Instagram"},
+	{"twitter.com", "X"},
+	{"x.com", "X"},
+	{"t.co", "X"},
+	{"linkedin.com", "LinkedIn"},
+	{"lnkd.in", "LinkedIn"},
+	{"reddit.com", "Reddit"},
+	{"youtube.com", "YouTube"},
+	{"pinterest.com", "Pinterest"},
+	{"threads.net", "Threads"},
+	{"tiktok.com", "TikTok"},
+	{"line.me", "LINE"},
+	{"ptt.cc", "PTT"},
+	{"dcard.tw", "Dcard"},
+	{"weibo.com", "Weibo"},
+}
+
+// mediaExtensions 盜連偵測的圖片、影音與文件副檔名
+var mediaExtensions = map[string]struct{}{
+	".jpg": {}, ".jpeg": {}, ".png": {}, ".gif": {}, ".webp": {}, ".avif": {}, ".svg": {}, ".bmp": {}, ".ico": {},
+	".mp4": {}, ".webm": {}, ".mov": {}, ".m4v": {},
+	".mp3": {}, ".ogg": {}, ".wav": {}, ".m4a": {}, ".flac": {},
+	".pdf": {},
+}
+
+// isMediaPath 判斷路徑是否為圖片、影音或文件
+func isMediaPath(urlPath string) bool {
+	_, ok := mediaExtensions[strings.ToLower(path.Ext(urlPath))]
+	return ok
+}
+
+// NormalizeSiteHosts 正規化本站主機名稱：轉小寫、去除 scheme、路徑、連接埠與開頭的 www.
+// 空白項目會略過，重複項目只保留一個；格式無效時返回 ValidationError
+func NormalizeSiteHosts(hosts []string) ([]string, error) {
+	normalized := make([]string, 0, len(hosts))
+	seen := make(map[string]bool)
+	for _, raw := range hosts {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+		host := normalizeDomain(hostOf(trimmed))
+		if host == "" || strings.ContainsAny(trimmed, " @") {
+			return nil, &models.ValidationError{Field: "siteHosts", Value: raw, Message: "無效的主機名稱"}
+		}
+		if !seen[host] {
+			seen[host] = true
+			normalized = append(normalized, host)
+		}
+	}
+	return normalized, nil
+}
+
+// SetSiteHosts 設定本站的主機名稱，來自這些主機（含子網域）的 Referer 視為站內流量
+// 未設定時由日誌的虛擬主機或最常見的來源網域推斷（見 inferSiteHosts）
+func (c *Calculator) SetSiteHosts(hosts []string) error {
+	normalized, err := NormalizeSiteHosts(hosts)
+	if err != nil {
+		return err
+	}
+	c.siteHosts = normalized
+	return nil
+}
+
+// hostOf 取出 URL 或主機字串中的主機名稱（不含連接埠）
+func hostOf(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// normalizeDomain 轉小寫並去除結尾的 "." 與開頭的 www.
+func normalizeDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return strings.TrimPrefix(host, "www.")
+}
+
+// refererInfo 單一 Referer 的分類結果
+type refererInfo struct {
+	domain  string // 來源網域
+	channel string // 管道
+	engine  string // 搜尋引擎名稱（僅 search 管道）
+}
+
+// classifyReferer 依本站主機與內建網域清單分類 Referer
+func classifyReferer(referer string, siteHosts []string) refererInfo {
+	if referer == "" || referer == "-" {
+		return refererInfo{channel: ChannelDirect}
+	}
+
+	domain := normalizeDomain(hostOf(referer))
+	if domain == "" {
+		return refererInfo{domain: unknownReferer, channel: ChannelReferral}
+	}
+	for _, host := range siteHosts {
+		if domain == host || strings.HasSuffix(domain, "."+host) {
+			return refererInfo{domain: domain, channel: ChannelInternal}
+		}
+	}
+	for _, rule := range emailDomains {
+		if rule.match(domain) {
+			return refererInfo{domain: domain, channel: ChannelEmail}
+		}
+	}
+	for _, rule := range searchDomains {
+		if rule.match(domain) {
+			return refererInfo{domain: domain, channel: ChannelSearch, engine: rule.name}
+		}
+	}
+	for _, rule := range socialDomains {
+		if rule.match(domain) {
+			return refererInfo{domain: domain, channel: ChannelSocial}
+		}
+	}
+	return refererInfo{domain: domain, channel: ChannelReferral}
+}
+
+// campaignKey 行銷活動的彙總鍵
+type campaignKey struct {
+	source, medium, campaign string
+}
+
+// hotlinkKey 盜連候選的彙總鍵
+// 計算時 source 為原始 Referer，分類後改以來源網域合併
+type hotlinkKey struct {
+	source, path string
+}
+
+// hotlinkAccumulator 累積單一盜連候選的請求
+type hotlinkAccumulator struct {
+	count int
+	bytes int64
+}
+
+// refererAccumulator 累積 Referer、utm 參數與媒體請求
+// 計算時只記錄字串，結束後每個不重複的 Referer 只分類一次
+type refererAccumulator struct {
+	counts       map[string]int                      // Referer -> 請求次數
+	emailTagged  map[string]int                      // Referer -> 帶 utm_medium=email 的請求次數
+	campaigns    map[campaignKey]map[string]struct{} // 行銷活動 -> 不重複 IP
+	campaignHits map[campaignKey]int                 // 行銷活動 -> 請求次數
+	media        map[hotlinkKey]*hotlinkAccumulator  // 帶 Referer 的媒體請求
+	vhosts       map[string]int                      // 虛擬主機 -> 請求次數
+}
+
+// newRefererAccumulator 建立 Referer 累積器
+func newRefererAccumulator() *refererAccumulator {
+	return &refererAccumulator{
+		counts:       make(map[string]int),
+		emailTagged:  make(map[string]int),
+		campaigns:    make(map[campaignKey]map[string]struct{}),
+		campaignHits: make(map[campaignKey]int),
+		media:        make(map[hotlinkKey]*hotlinkAccumulator),
+		vhosts:       make(map[string]int),
+	}
+}
+
+// observe 記錄一次請求
+func (a *refererAccumulator) observe(entry *models.LogEntry) {
+	a.counts[entry.Referer]++
+	if entry.VirtualHost != "" {
+		a.vhosts[entry.VirtualHost]++
+	}
+
+	urlPath, query, _ := strings.Cut(entry.URL, "?")
+	if strings.Contains(query, "utm_") {
+		if key, ok := parseCampaign(query); ok {
+			if a.campaigns[key] == nil {
+				a.campaigns[key] = make(map[string]struct{})
+			}
+			a.campaigns[key][entry.IP] = struct{}{}
+			a.campaignHits[key]++
+			if isEmailMedium(key.medium) {
+				a.emailTagged[entry.Referer]++
+			}
+		}
+	}
+
+	if entry.Referer != "" && entry.Referer != "-" && isMediaPath(urlPath) {
+		key := hotlinkKey{source: entry.Referer, path: urlPath}
+		acc := a.media[key]
+		if acc == nil {
+			acc = &hotlinkAccumulator{}
+			a.media[key] = acc
+		}
+		acc.count++
+		acc.bytes += entry.ResponseBytes
+	}
+}
+
+// parseCampaign 從查詢字串取出 utm_source、utm_medium 與 utm_campaign
+func parseCampaign(query string) (campaignKey, bool) {
+	values, err := url.ParseQuery(query)
+	if err != nil && len(values) == 0 {
+		return campaignKey{}, false
+	}
+	source := strings.ToLower(strings.TrimSpace(values.Get("utm_source")))
+	medium := strings.ToLower(strings.TrimSpace(values.Get("utm_medium")))
+	campaign := strings.TrimSpace(values.Get("utm_campaign"))
+	if source == "" && medium == "" && campaign == "" {
+		return campaignKey{}, false
+	}
+	key := campaignKey{source: source, medium: medium, campaign: campaign}
+	for _, field := range []*string{&key.source, &key.medium, &key.campaign} {
+		if *field == "" {
+			*field = notSet
+		}
+	}
+	return key, true
+}
+
+// isEmailMedium 判斷 utm_medium 是否代表電子郵件
+func isEmailMedium(medium string) bool {
+	switch medium {
+	case "email", "e-mail", "newsletter", "edm":
+		return true
+	}
+	return false
+}
+
+// inferSiteHosts 未設定本站主機時推斷本站主機
+// 日誌帶有虛擬主機時全部視為本站；否則取最常見的非搜尋、社群、郵件來源網域（通常是站內頁面）
+func (a *refererAccumulator) inferSiteHosts() ([]string, string) {
+	if len(a.vhosts) > 0 {
+		seen := make(map[string]bool)
+		hosts := make([]string, 0, len(a.vhosts))
+		for vhost := range a.vhosts {
+			host := normalizeDomain(hostOf(vhost))
+			if host != "" && !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+		if len(hosts) > 0 {
+			sort.Strings(hosts)
+			return hosts, SiteHostsVirtual
+		}
+	}
+
+	domains := make(map[string]int)
+	for referer, count := range a.counts {
+		info := classifyReferer(referer, nil)
+		if info.channel == ChannelReferral && info.domain != unknownReferer {
+			domains[info.domain] += count
+		}
+	}
+	if top := distribution(domains, 0, 1); len(top) > 0 {
+		return []string{top[0].Name}, SiteHostsReferer
+	}
+	return nil, SiteHostsNone
+}
+
+// result 分類各 Referer 並彙總來源分析
+// siteHosts 為空時以 inferSiteHosts 推斷；仍無法判斷時不區分站內外，也不偵測盜連
+func (a *refererAccumulator) result(siteHosts []string, total, topN int) RefererStatistics {
+	var result RefererStatistics
+	result.SiteHosts, result.SiteHostsSource = siteHosts, SiteHostsConfigured
+	if len(siteHosts) == 0 {
+		result.SiteHosts, result.SiteHostsSource = a.inferSiteHosts()
+	}
+	siteHosts = result.SiteHosts
+	infos := make(map[string]refererInfo, len(a.counts))
+	channels := make(map[string]int)
+	domains := make(map[string]int)
+	domainChannels := make(map[string]string)
+	engines := make(map[string]int)
+
+	for referer, count := range a.counts {
+		info := classifyReferer(referer, siteHosts)
+		infos[referer] = info
+
+		// 帶 utm_medium=email 的請求不論 Referer 都計為 email 管道
+		tagged := a.emailTagged[referer]
+		channels[info.channel] += count - tagged
+		channels[ChannelEmail] += tagged
+
+		switch info.channel {
+		case ChannelDirect:
+			result.Direct += count
+		case ChannelInternal:
+			result.Internal += count
+		default:
+			result.External += count
+			domains[info.domain] += count
+			domainChannels[info.domain] = info.channel
+			if info.engine != "" {
+				engines[info.engine] += count
+			}
+		}
+	}
+	for channel, count := range channels {
+		if count == 0 {
+			delete(channels, channel)
+		}
+	}
+
+	result.Channels = distribution(channels, total, 0)
+	result.SearchEngines = distribution(engines, total, 0)
+
+	for _, item := range distribution(domains, result.External, topN) {
+		result.Domains = append(result.Domains, RefererDomain{
+			Domain:     item.Name,
+			Channel:    domainChannels[item.Name],
+			Count:      item.Count,
+			Percentage: item.Percentage,
+		})
+	}
+
+	result.Campaigns = make([]CampaignStatistics, 0, len(a.campaignHits))
+	for key, count := range a.campaignHits {
+		result.Campaigns = append(result.Campaigns, CampaignStatistics{
+			Source:    key.source,
+			Medium:    key.medium,
+			Campaign:  key.campaign,
+			Count:     count,
+			UniqueIPs: len(a.campaigns[key]),
+		})
+	}
+	sort.Slice(result.Campaigns, func(i, j int) bool {
+		ci, cj := result.Campaigns[i], result.Campaigns[j]
+		if ci.Count != cj.Count {
+			return ci.Count > cj.Count
+		}
+		if ci.Source != cj.Source {
+			return ci.Source < cj.Source
+		}
+		if ci.Medium != cj.Medium {
+			return ci.Medium < cj.Medium
+		}
+		return ci.Campaign < cj.Campaign
+	})
+	if topN > 0 && len(result.Campaigns) > topN {
+		result.Campaigns = result.Campaigns[:topN]
+	}
+
+	// 盜連：外部網站的頁面直接引用本站的圖片或媒體（同一網域、同一路徑合併）
+	// 不知道本站主機時無法分辨站內引用，不偵測盜連
+	media := a.media
+	if len(siteHosts) == 0 {
+		media = nil
+	}
+	hotlinks := make(map[hotlinkKey]*hotlinkAccumulator)
+	for key, acc := range media {
+		info := infos[key.source]
+		if info.channel == ChannelDirect || info.channel == ChannelInternal {
+			continue
+		}
+		grouped := hotlinkKey{source: info.domain, path: key.path}
+		if hotlinks[grouped] == nil {
+			hotlinks[grouped] = &hotlinkAccumulator{}
+		}
+		hotlinks[grouped].count += acc.count
+		hotlinks[grouped].bytes += acc.bytes
+		result.HotlinkRequests += acc.count
+		result.HotlinkBytes += acc.bytes
+	}
+	result.Hotlinks = make([]HotlinkStatistics, 0, len(hotlinks))
+	for key, acc := range hotlinks {
+		result.Hotlinks = append(result.Hotlinks, HotlinkStatistics{
+			Domain: key.source,
+			Path:   key.path,
+			Count:  acc.count,
+			Bytes:  acc.bytes,
+		})
+	}
+	sort.Slice(result.Hotlinks, func(i, j int) bool {
+		hi, hj := result.Hotlinks[i], result.Hotlinks[j]
+		if hi.Count != hj.Count {
+			return hi.Count > hj.Count
+		}
+		if hi.Domain != hj.Domain {
+			return hi.Domain < hj.Domain
+		}
+		return hi.Path < hj.Path
+	})
+	if topN > 0 && len(result.Hotlinks) > topN {
+		result.Hotlinks = result.Hotlinks[:topN]
+	}
+
+	return result
+}
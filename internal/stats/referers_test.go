@@ -0,0 +1,172 @@
+package stats
+
+import (
+	"testing"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassifyReferer 測試 Referer 的管道分類
+func TestClassifyReferer(t *testing.T) {
+	siteHosts := []string{"example.com"}
+
+	testCases := []struct {
+		name     string
+		referer  string
+		expected refererInfo
+	}{
+		{"沒有 Referer", "-", refererInfo{channel: ChannelDirect}},
+		{"空字串", "", refererInfo{channel: ChannelDirect}},
+		{"本站", "https://www.example.com/blog/", refererInfo{domain: "example.com", channel: ChannelInternal}},
+		{"本站子網域", "https://shop.example.com/cart", refererInfo{domain: "shop.example.com", channel: ChannelInternal}},
+		{"相似網域不是本站", "https://notexample.com/", refererInfo{domain: "notexample.com", channel: ChannelReferral}},
+		{"Google 各國網域", "https://www.google.com.tw/", refererInfo{domain: "google.com.tw", channel: ChannelSearch, engine: "Google"}},
+		{"Bing", "https://cn.bing.com/search?q=x", refererInfo{domain: "cn.bing.com", channel: ChannelSearch, engine: "Bing"}},
+		{"Gmail 優先於 Google 搜尋", "https://mail.google.com/mail/u/0/", refererInfo{domain: "mail.google.com", channel: ChannelEmail}},
+		{"Yahoo 信箱", "https://mail.yahoo.com/", refererInfo{domain: "mail.yahoo.com", channel: ChannelEmail}},
+		{"Facebook 行動版", "https://m.facebook.com/", refererInfo{domain: "m.facebook.com", channel: ChannelSocial}},
+		{"X 短網址", "https://t.co/abc", refererInfo{domain: "t.co", channel: ChannelSocial}},
+		{"其他網站含連接埠", "http://Blog.Partner.org:8080/post", refererInfo{domain: "blog.partner.org", channel: ChannelReferral}},
+		{"無法解析", "http://%zz", refererInfo{domain: unknownReferer, channel: ChannelReferral}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, classifyReferer(tc.referer, siteHosts))
+		})
+	}
+}
+
+// TestNormalizeSiteHosts 測試本站主機名稱的正規化
+func TestNormalizeSiteHosts(t *testing.T) {
+	hosts, err := NormalizeSiteHosts([]string{"https://WWW.Example.com:8443/path", "example.com", " ", "cdn.example.net"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "cdn.example.net"}, hosts)
+
+	_, err = NormalizeSiteHosts([]string{"user@example.com"})
+	var validationErr *models.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	calc := NewCalculator()
+	assert.Error(t, calc.SetSiteHosts([]string{"http://"}))
+}
+
+// TestCalculator_Referers 測試流量來源、行銷活動與盜連分析
+func TestCalculator_Referers(t *testing.T) {
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", URL: "/", Referer: "-"},
+		{IP: "10.0.0.1", URL: "/about", Referer: "https://www.example.com/"},
+		{IP: "10.0.0.2", URL: "/", Referer: "https://www.google.com/"},
+		{IP: "10.0.0.3", URL: "/", Referer: "https://www.google.co.jp/"},
+		{IP: "10.0.0.4", URL: "/", Referer: "https://duckduckgo.com/"},
+		{IP: "10.0.0.5", URL: "/", Referer: "https://l.facebook.com/l.php"},
+		{IP: "10.0.0.6", URL: "/sale?utm_source=Newsletter&utm_medium=Email&utm_campaign=spring", Referer: "-"},
+		{IP: "10.0.0.7", URL: "/sale?utm_source=newsletter&utm_medium=email&utm_campaign=spring", Referer: "-"},
+		{IP: "10.0.0.7", URL: "/sale?utm_source=newsletter&utm_medium=email&utm_campaign=spring", Referer: "-"},
+		{IP: "10.0.0.8", URL: "/?utm_source=facebook", Referer: "https://l.facebook.com/l.php"},
+		{IP: "10.0.0.9", URL: "/images/logo.png?v=2", Referer: "https://forum.other.net/thread/1", ResponseBytes: 1000},
+		{IP: "10.0.0.10", URL: "/images/logo.png", Referer: "https://forum.other.net/thread/2", ResponseBytes: 1000},
+		{IP: "10.0.0.11", URL: "/images/logo.png", Referer: "https://www.example.com/", ResponseBytes: 1000},
+		{IP: "10.0.0.12", URL: "/images/logo.png", Referer: "-", ResponseBytes: 1000},
+	}
+
+	calc := NewCalculator()
+	require.NoError(t, calc.SetSiteHosts([]string{"example.com"}))
+	referers := calc.Calculate(entries).Referers
+
+	assert.Equal(t, []string{"example.com"}, referers.SiteHosts)
+	assert.Equal(t, SiteHostsConfigured, referers.SiteHostsSource)
+	assert.Equal(t, 5, referers.Direct)
+	assert.Equal(t, 2, referers.Internal)
+	assert.Equal(t, 7, referers.External)
+
+	channels := make(map[string]int)
+	for _, item := range referers.Channels {
+		channels[item.Name] = item.Count
+	}
+	assert.Equal(t, map[string]int{
+		ChannelDirect:   2,
+		ChannelInternal: 2,
+		ChannelSearch:   3,
+		ChannelSocial:   2,
+		ChannelEmail:    3,
+		ChannelReferral: 2,
+	}, channels, "utm_medium=email 的請求計為 email 管道")
+
+	require.Len(t, referers.SearchEngines, 2)
+	assert.Equal(t, "Google", referers.SearchEngines[0].Name)
+	assert.Equal(t, 2, referers.SearchEngines[0].Count)
+	assert.InDelta(t, 14.29, referers.SearchEngines[0].Percentage, 0.01)
+	assert.Equal(t, "DuckDuckGo", referers.SearchEngines[1].Name)
+
+	require.NotEmpty(t, referers.Domains)
+	top := referers.Domains[0]
+	assert.Equal(t, "forum.other.net", top.Domain)
+	assert.Equal(t, ChannelReferral, top.Channel)
+	assert.Equal(t, 2, top.Count)
+	assert.InDelta(t, 28.57, top.Percentage, 0.01, "百分比以外部來源請求為分母")
+	assert.Len(t, referers.Domains, 5)
+
+	assert.Equal(t, []CampaignStatistics{
+		{Source: "newsletter", Medium: "email", Campaign: "spring", Count: 3, UniqueIPs: 2},
+		{Source: "facebook", Medium: notSet, Campaign: notSet, Count: 1, UniqueIPs: 1},
+	}, referers.Campaigns)
+
+	assert.Equal(t, []HotlinkStatistics{
+		{Domain: "forum.other.net", Path: "/images/logo.png", Count: 2, Bytes: 2000},
+	}, referers.Hotlinks, "站內與沒有 Referer 的媒體請求不算盜連")
+	assert.Equal(t, 2, referers.HotlinkRequests)
+	assert.Equal(t, int64(2000), referers.HotlinkBytes)
+}
+
+// TestCalculator_Referers未設定本站 測試未設定本站主機時以最常見的來源網域推斷本站
+func TestCalculator_Referers未設定本站(t *testing.T) {
+	referers := NewCalculator().Calculate([]models.LogEntry{
+		{IP: "10.0.0.1", URL: "/", Referer: "https://www.example.com/"},
+		{IP: "10.0.0.1", URL: "/about", Referer: "https://example.com/"},
+		{IP: "10.0.0.2", URL: "/", Referer: "-"},
+		{IP: "10.0.0.3", URL: "/", Referer: "https://www.google.com/"},
+		{IP: "10.0.0.4", URL: "/images/logo.png", Referer: "https://www.google.com/"},
+		{IP: "10.0.0.5", URL: "/images/logo.png", Referer: "https://forum.other.net/"},
+	}).Referers
+
+	assert.Equal(t, []string{"example.com"}, referers.SiteHosts, "搜尋引擎不會被推斷為本站")
+	assert.Equal(t, SiteHostsReferer, referers.SiteHostsSource)
+	assert.Equal(t, 2, referers.Internal)
+	assert.Equal(t, 3, referers.External)
+	assert.Equal(t, 1, referers.Direct)
+	assert.Empty(t, referers.Campaigns)
+	assert.Len(t, referers.Hotlinks, 2)
+}
+
+// TestCalculator_Referers虛擬主機 測試以日誌的虛擬主機推斷本站
+func TestCalculator_Referers虛擬主機(t *testing.T) {
+	referers := NewCalculator().Calculate([]models.LogEntry{
+		{IP: "10.0.0.1", URL: "/", VirtualHost: "www.example.com", Referer: "https://forum.other.net/"},
+		{IP: "10.0.0.1", URL: "/", VirtualHost: "www.example.com", Referer: "https://forum.other.net/"},
+		{IP: "10.0.0.2", URL: "/logo.png", VirtualHost: "shop.example.org:443", Referer: "https://www.example.com/"},
+	}).Referers
+
+	assert.Equal(t, []string{"example.com", "shop.example.org"}, referers.SiteHosts)
+	assert.Equal(t, SiteHostsVirtual, referers.SiteHostsSource)
+	assert.Equal(t, 1, referers.Internal)
+	assert.Equal(t, 2, referers.External, "最常見的來源網域不會覆蓋虛擬主機")
+	assert.Empty(t, referers.Hotlinks)
+}
+
+// TestCalculator_Referers無法判斷本站 測試無法判斷本站主機時不偵測盜連
+func TestCalculator_Referers無法判斷本站(t *testing.T) {
+	referers := NewCalculator().Calculate([]models.LogEntry{
+		{IP: "10.0.0.1", URL: "/images/logo.png", Referer: "https://www.google.com/"},
+		{IP: "10.0.0.2", URL: "/", Referer: "-"},
+	}).Referers
+
+	assert.Empty(t, referers.SiteHosts)
+	assert.Equal(t, SiteHostsNone, referers.SiteHostsSource)
+	assert.Equal(t, 1, referers.External)
+	assert.Empty(t, referers.Hotlinks)
+	assert.Zero(t, referers.HotlinkRequests)
+}
@@ -1,36 +1,91 @@
 package stats
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/geoip"
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/security"
+	"access-log-analyzer/internal/subnet"
+	"access-log-analyzer/internal/useragent"
 	"access-log-analyzer/pkg/logger"
 )
 
 // Calculator 提供日誌統計計算功能
-// 整合 Top-N 演算法和機器人偵測功能
+// 整合 Top-N 演算法、機器人偵測和攻擊偵測功能
 type Calculator struct {
-	topN        int          // Top-N 的 N 值
-	botDetector *BotDetector // 機器人偵測器
-	log         *logger.Logger
+	topN           int                      // Top-N 的 N 值
+	botDetector    *BotDetector             // 機器人偵測器
+	threatDetector *security.ThreatDetector // 攻擊偵測器
+	geo            *geoip.Enricher          // GeoIP 與 ASN 查詢（nil 表示不補充）
+	subnets        subnet.Options           // 網段彙總的前綴長度
+	cidrGroups     *subnet.GroupSet         // 具名 CIDR 群組（nil 表示不標記）
+	uaParser       *useragent.Parser        // User-Agent 解析器（含快取）
+	siteHosts      []string                 // 本站主機名稱（判斷站內 Referer）
+	log            *logger.Logger
 }
 
 // Statistics 包含完整的統計資訊
 type Statistics struct {
-	TotalRequests          int                  `json:"totalRequests"`          // 總請求數
-	UniqueIPs              int                  `json:"uniqueIPs"`              // 唯一 IP 數量
-	UniquePaths            int                  `json:"uniquePaths"`            // 唯一路徑數量
-	TotalBytes             int64                `json:"totalBytes"`             // 總傳輸量（位元組）
-	AverageResponseSize    int64                `json:"averageResponseSize"`    // 平均回應大小
-	TopIPs                 []IPStatistics       `json:"topIPs"`                 // Top IP 統計
-	TopPaths               []PathStatistics     `json:"topPaths"`               // Top 路徑統計
-	StatusCodeDistribution StatusCodeStatistics `json:"statusCodeDistribution"` // 狀態碼分布
-	BotStats               BotStats             `json:"botStats"`               // 機器人統計
+	TotalRequests          int                     `json:"totalRequests"`          // 總請求數
+	UniqueIPs              int                     `json:"uniqueIPs"`              // 唯一 IP 數量
+	UniquePaths            int                     `json:"uniquePaths"`            // 唯一路徑數量
+	TotalBytes             int64                   `json:"totalBytes"`             // 總傳輸量（位元組）
+	AverageResponseSize    int64                   `json:"averageResponseSize"`    // 平均回應大小
+	TopIPs                 []IPStatistics          `json:"topIPs"`                 // Top IP 統計
+	TopPaths               []PathStatistics        `json:"topPaths"`               // Top 路徑統計
+	StatusCodeDistribution StatusCodeStatistics    `json:"statusCodeDistribution"` // 狀態碼分布
+	BotStats               BotStats                `json:"botStats"`               // 機器人統計
+	Threats                security.ThreatStats    `json:"threats"`                // 攻擊偵測統計
+	Countries              []GeoStatistics         `json:"countries"`              // 依國家排名（需載入 GeoIP 資料庫）
+	ASNs                   []GeoStatistics         `json:"asns"`                   // 依 ASN 排名（需載入 ASN 資料庫）
+	TopSubnets             []SubnetStatistics      `json:"topSubnets"`             // 依網段排名（IPv4 預設 /24、IPv6 預設 /64）
+	CIDRGroups             []GeoStatistics         `json:"cidrGroups"`             // 依具名 CIDR 群組排名（需設定群組）
+	UserAgents             UserAgentStatistics     `json:"userAgents"`             // 瀏覽器、作業系統、裝置與排版引擎分布
+	Referers               RefererStatistics       `json:"referers"`               // 流量來源、行銷活動與盜連分析
+	NotFound               NotFoundReport          `json:"notFound"`               // 失效連結（404/410）報表
+	Latency                LatencyStatistics       `json:"latency"`                // 請求處理時間統計（日誌需記錄處理時間）
+	VirtualHosts           []VirtualHostStatistics `json:"virtualHosts"`           // 各虛擬主機摘要（日誌需記錄虛擬主機，依請求數降序）
+	Servers                []VirtualHostStatistics `json:"servers"`                // 各來源伺服器摘要（載入時標記伺服器，依請求數降序）
+	Users                  UserStatistics          `json:"users"`                  // 已認證使用者（%u）的活動統計
 }
 
 // IPStatistics IP 統計資訊
 type IPStatistics struct {
-	IP           string `json:"ip"`           // IP 位址
-	RequestCount int    `json:"requestCount"` // 請求次數
-	TotalBytes   int64  `json:"totalBytes"`   // 總傳輸量（位元組）
+	IP           string `json:"ip"`                     // IP 位址
+	RequestCount int    `json:"requestCount"`           // 請求次數
+	TotalBytes   int64  `json:"totalBytes"`             // 總傳輸量（位元組）
+	Country      string `json:"country,omitempty"`      // 國家代碼（需載入 GeoIP 資料庫）
+	City         string `json:"city,omitempty"`         // 城市
+	ASN          uint   `json:"asn,omitempty"`          // 自治系統編號
+	Organization string `json:"organization,omitempty"` // ASN 所屬組織
+	Group        string `json:"group,omitempty"`        // 所屬的具名 CIDR 群組
+}
+
+// GeoStatistics 依國家、ASN 或具名 CIDR 群組彙總的統計資訊
+type GeoStatistics struct {
+	Key          string  `json:"key"`          // 國家代碼、ASN（例如 "AS3462"）或群組名稱；查無資料為 "未知"
+	Name         string  `json:"name"`         // 國家名稱、ASN 所屬組織或群組的網段
+	RequestCount int     `json:"requestCount"` // 請求次數
+	TotalBytes   int64   `json:"totalBytes"`   // 總傳輸量（位元組）
+	UniqueIPs    int     `json:"uniqueIPs"`    // 不重複 IP 數
+	ErrorRate    float64 `json:"errorRate"`    // 錯誤率（4xx/5xx，百分比）
+	BotShare     float64 `json:"botShare"`     // 機器人請求佔比（百分比）
+}
+
+// SubnetStatistics 網段統計資訊
+// 輪替同一網段內位址的爬蟲不會出現在 TopIPs，但會集中在同一個網段
+type SubnetStatistics struct {
+	Subnet       string  `json:"subnet"`          // 網段，例如 203.0.113.0/24、2001:db8::/64
+	Group        string  `json:"group,omitempty"` // 具名 CIDR 群組（網段內所有位址屬於同一群組時）
+	RequestCount int     `json:"requestCount"`    // 請求次數
+	TotalBytes   int64   `json:"totalBytes"`      // 總傳輸量（位元組）
+	UniqueIPs    int     `json:"uniqueIPs"`       // 不重複 IP 數
+	ErrorRate    float64 `json:"errorRate"`       // 錯誤率（4xx/5xx，百分比）
+	BotShare     float64 `json:"botShare"`        // 機器人請求佔比（百分比）
 }
 
 // PathStatistics 路徑統計資訊
@@ -39,6 +94,8 @@ type PathStatistics struct {
 	RequestCount int     `json:"requestCount"` // 請求次數
 	AverageSize  int64   `json:"averageSize"`  // 平均大小
 	ErrorRate    float64 `json:"errorRate"`    // 錯誤率（百分比）
+
+	AverageRequestTime int64 `json:"averageRequestTime,omitempty"` // 平均處理時間（微秒，日誌需記錄處理時間）
 }
 
 // StatusCodeStatistics 狀態碼統計資訊
@@ -52,10 +109,58 @@ type StatusCodeStatistics struct {
 
 // NewCalculator 建立新的統計計算器
 func NewCalculator() *Calculator {
+	detector := NewBotDetector()
+	detector.SetVerifier(NewCrawlerVerifier()) // 預設只比對官方公布的 IP 範圍
+
 	return &Calculator{
-		topN:        10, // 預設保留 Top 10
-		botDetector: NewBotDetector(),
-		log:         logger.Get().WithModule("stats"),
+		topN:           10, // 預設保留 Top 10
+		botDetector:    detector,
+		threatDetector: security.NewThreatDetector(),
+		subnets:        subnet.DefaultOptions(),
+		uaParser:       useragent.NewParser(),
+		log:            logger.Get().WithModule("stats"),
+	}
+}
+
+// SetBotRules 設定機器人偵測使用的規則集合
+// 讓統計計算與應用程式共用同一份（可熱重載的）規則
+func (c *Calculator) SetBotRules(rules *BotRuleSet) {
+	detector := NewBotDetectorWithRules(rules)
+	detector.SetVerifier(c.botDetector.verifier)
+	c.botDetector = detector
+}
+
+// SetCrawlerVerifier 設定爬蟲驗證器（nil 表示不驗證）
+// 讓統計計算與應用程式共用同一份驗證資料與 DNS 設定
+func (c *Calculator) SetCrawlerVerifier(verifier *CrawlerVerifier) {
+	c.botDetector.SetVerifier(verifier)
+}
+
+// SetGeoIP 設定 GeoIP 與 ASN 查詢（nil 或未載入資料庫時不補充）
+// 啟用後 TopIPs 會附上國家、城市與 ASN，並產生依國家與 ASN 的排名
+func (c *Calculator) SetGeoIP(enricher *geoip.Enricher) {
+	c.geo = enricher
+}
+
+// SetSubnetOptions 設定網段彙總的前綴長度（未設定的欄位使用預設值）
+func (c *Calculator) SetSubnetOptions(opts subnet.Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	c.subnets = opts.WithDefaults()
+	return nil
+}
+
+// SetCIDRGroups 設定具名 CIDR 群組，TopIPs 與網段會標上所屬群組並產生依群組的排名
+func (c *Calculator) SetCIDRGroups(groups *subnet.GroupSet) {
+	c.cidrGroups = groups
+}
+
+// SetUserAgentParser 設定 User-Agent 解析器
+// 讓統計計算與應用程式共用同一份（可更新的）規則與解析快取
+func (c *Calculator) SetUserAgentParser(parser *useragent.Parser) {
+	if parser != nil {
+		c.uaParser = parser
 	}
 }
 
@@ -67,18 +172,33 @@ func (c *Calculator) SetTopN(n int) {
 // Calculate 計算日誌條目的統計資訊
 // 使用單次遍歷和 Top-N 堆積實現高效計算
 func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
-	c.log.Info().Int("count", len(entries)).Msg("開始計算統計資訊")
+	return c.CalculateWhere(entries, nil)
+}
+
+// CalculateWhere 只計算符合 match 的日誌條目，match 為 nil 時計算全部
+// 以條件篩選而不複製 LogEntry，例如只取特定時間範圍或虛擬主機
+func (c *Calculator) CalculateWhere(entries []models.LogEntry, match func(*models.LogEntry) bool) Statistics {
+	total := len(entries)
+	if match != nil {
+		total = 0
+		for i := range entries {
+			if match(&entries[i]) {
+				total++
+			}
+		}
+	}
+	c.log.Info().Int("count", total).Msg("開始計算統計資訊")
 
 	// 初始化統計結構
 	stats := Statistics{
-		TotalRequests: len(entries),
+		TotalRequests: total,
 		StatusCodeDistribution: StatusCodeStatistics{
 			Details: make(map[int]int),
 		},
 	}
 
 	// 特殊情況：空資料集
-	if len(entries) == 0 {
+	if total == 0 {
 		return stats
 	}
 
@@ -93,14 +213,37 @@ func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
 	// 用於計算路徑詳細統計
 	pathStats := make(map[string]*pathStatAccumulator)
 
+	// 用於推測轉址鏈：各用戶端最後一次收到的轉址
+	redirectHops := make(map[string]redirectHop)
+
 	// 用於計算 IP 詳細統計
 	ipStats := make(map[string]*ipStatAccumulator)
 
-	// 重置機器人偵測器統計
+	// 用於計算瀏覽器、作業系統與裝置分布
+	userAgents := newUserAgentAccumulator()
+
+	// 用於分析流量來源、utm 參數與盜連
+	referers := newRefererAccumulator()
+
+	// 用於彙總各虛擬主機與來源伺服器
+	virtualHosts := make(virtualHostsAccumulator)
+	servers := make(virtualHostsAccumulator)
+
+	// 用於彙總已認證使用者的活動
+	users := make(usersAccumulator)
+
+	// 用於計算處理時間分布（只收集有記錄處理時間的請求）
+	var latencies []int64
+
+	// 重置機器人與攻擊偵測器統計
 	c.botDetector.ResetStats()
+	c.threatDetector.ResetStats()
 
 	// 單次遍歷所有記錄
 	for _, entry := range entries {
+		if match != nil && !match(&entry) {
+			continue
+		}
 		// 統計唯一 IP 和路徑
 		uniqueIPs[entry.IP] = true
 		uniquePaths[entry.URL] = true
@@ -112,8 +255,12 @@ func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
 		if _, exists := ipStats[entry.IP]; !exists {
 			ipStats[entry.IP] = &ipStatAccumulator{}
 		}
-		ipStats[entry.IP].requestCount++
-		ipStats[entry.IP].totalBytes += entry.ResponseBytes
+		ipAcc := ipStats[entry.IP]
+		ipAcc.requestCount++
+		ipAcc.totalBytes += entry.ResponseBytes
+		if entry.StatusCode >= 400 {
+			ipAcc.errorCount++
+		}
 
 		// 統計路徑
 		if _, exists := pathStats[entry.URL]; !exists {
@@ -125,12 +272,31 @@ func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
 		if entry.StatusCode >= 400 {
 			acc.errorCount++
 		}
+		if entry.RequestTime > 0 {
+			acc.timedCount++
+			acc.totalTime += entry.RequestTime
+			latencies = append(latencies, entry.RequestTime)
+		}
 
 		// 統計狀態碼
 		c.updateStatusCodeStats(&stats.StatusCodeDistribution, entry.StatusCode)
 
-		// 機器人偵測
-		c.botDetector.IsBot(entry.UserAgent)
+		// 機器人偵測（同時記錄各 IP 的機器人活動）
+		isBot, _ := c.botDetector.Observe(&entry)
+		if isBot {
+			ipAcc.botCount++
+		}
+		userAgents.observe(entry.UserAgent, isBot)
+		referers.observe(&entry)
+		virtualHosts.observe(entry.VirtualHost, &entry)
+		servers.observe(entry.Server, &entry)
+		users.observe(&entry)
+
+		// 失效連結與轉址（只處理 3xx 與 404/410）
+		acc.observeNotFound(&entry, isBot, redirectHops)
+
+		// 攻擊偵測（檢查 URL 中的攻擊特徵）
+		c.threatDetector.Observe(&entry)
 	}
 
 	// 設定唯一計數
@@ -156,8 +322,48 @@ func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
 			RequestCount: item.Count,
 			TotalBytes:   acc.totalBytes,
 		}
+		if location, found := c.geo.Lookup(item.Key); found {
+			stats.TopIPs[i].Country = location.Country
+			stats.TopIPs[i].City = location.City
+			stats.TopIPs[i].ASN = location.ASN
+			stats.TopIPs[i].Organization = location.Organization
+		}
+	}
+
+	// 依國家與 ASN 排名（每個 IP 只查詢一次）
+	if c.geo.Enabled() {
+		stats.Countries, stats.ASNs = c.rankGeo(ipStats)
+	}
+
+	// 依網段與 CIDR 群組排名，並標記 TopIPs 所屬的群組
+	stats.TopSubnets, stats.CIDRGroups = c.rankSubnets(ipStats)
+	if c.cidrGroups.Len() > 0 {
+		for i := range stats.TopIPs {
+			if addr, err := subnet.Parse(stats.TopIPs[i].IP); err == nil {
+				stats.TopIPs[i].Group = c.cidrGroups.Match(addr)
+			}
+		}
 	}
 
+	// 瀏覽器、作業系統、裝置與排版引擎分布（每個不重複的 User-Agent 只解析一次）
+	stats.UserAgents = userAgents.result(c.uaParser, stats.TotalRequests, c.topN)
+
+	// 流量來源分析（每個不重複的 Referer 只分類一次）
+	stats.Referers = referers.result(c.siteHosts, stats.TotalRequests, c.topN)
+
+	// 失效連結報表（沿用路徑統計中的 404/410 明細）
+	stats.NotFound = buildNotFoundReport(pathStats, c.siteHosts)
+
+	// 虛擬主機與來源伺服器摘要
+	stats.VirtualHosts = virtualHosts.result(stats.TotalRequests)
+	stats.Servers = servers.result(stats.TotalRequests)
+
+	// 已認證使用者的活動（只有 Top-N 使用者查詢 IP 所在國家）
+	stats.Users = c.userStatistics(users)
+
+	// 處理時間分布
+	stats.Latency = buildLatency(latencies)
+
 	// 建立 Top 路徑統計
 	for path, acc := range pathStats {
 		pathHeap.Push(path, acc.requestCount)
@@ -182,16 +388,23 @@ func (c *Calculator) Calculate(entries []models.LogEntry) Statistics {
 			AverageSize:  averageSize,
 			ErrorRate:    errorRate,
 		}
+		if acc.timedCount > 0 {
+			stats.TopPaths[i].AverageRequestTime = acc.totalTime / int64(acc.timedCount)
+		}
 	}
 
 	// 獲取機器人統計
 	stats.BotStats = c.botDetector.GetStats()
 
+	// 獲取攻擊偵測統計
+	stats.Threats = c.threatDetector.GetStats()
+
 	c.log.Info().
 		Int("totalRequests", stats.TotalRequests).
 		Int("uniqueIPs", stats.UniqueIPs).
 		Int("uniquePaths", stats.UniquePaths).
 		Int("botRequests", stats.BotStats.BotRequests).
+		Int("attackRequests", stats.Threats.AttackRequests).
 		Msg("統計計算完成")
 
 	return stats
@@ -220,10 +433,166 @@ type pathStatAccumulator struct {
 	requestCount int
 	totalBytes   int64
 	errorCount   int
+	timedCount   int                  // 有處理時間的請求數
+	totalTime    int64                // 處理時間總和（微秒）
+	redirects    int                  // 301/302/307/308 回應次數
+	lastRedirect time.Time            // 最後一次轉址的時間
+	notFound     *notFoundAccumulator // 404/410 明細（沒有失效請求時為 nil）
 }
 
 // ipStatAccumulator 累積 IP 統計資訊
 type ipStatAccumulator struct {
 	requestCount int
 	totalBytes   int64
+	errorCount   int
+	botCount     int
+}
+
+// geoAccumulator 累積國家、ASN、網段或 CIDR 群組的統計資訊
+type geoAccumulator struct {
+	name     string
+	ips      int
+	requests int
+	bytes    int64
+	errors   int
+	bots     int
+}
+
+// geoGroups 依鍵彙總的 IP 統計
+type geoGroups map[string]*geoAccumulator
+
+// add 將單一 IP 的統計加入 key 對應的彙總
+func (m geoGroups) add(key, name string, acc *ipStatAccumulator) *geoAccumulator {
+	g, exists := m[key]
+	if !exists {
+		g = &geoAccumulator{name: name}
+		m[key] = g
+	}
+	g.ips++
+	g.requests += acc.requestCount
+	g.bytes += acc.totalBytes
+	g.errors += acc.errorCount
+	g.bots += acc.botCount
+	return g
+}
+
+// rates 返回錯誤率與機器人請求佔比（百分比）
+func (g *geoAccumulator) rates() (float64, float64) {
+	if g.requests == 0 {
+		return 0, 0
+	}
+	return float64(g.errors) / float64(g.requests) * 100, float64(g.bots) / float64(g.requests) * 100
+}
+
+// unknownGeoKey 查無國家或 ASN 時使用的鍵
+const unknownGeoKey = "未知"
+
+// rankGeo 依國家與 ASN 彙總各 IP 的統計並排名（依請求次數降序，保留 Top-N）
+func (c *Calculator) rankGeo(ipStats map[string]*ipStatAccumulator) ([]GeoStatistics, []GeoStatistics) {
+	countries := make(geoGroups)
+	asns := make(geoGroups)
+
+	for ip, acc := range ipStats {
+		location, _ := c.geo.Lookup(ip)
+		if location.Country != "" {
+			countries.add(location.Country, location.CountryName, acc)
+		} else {
+			countries.add(unknownGeoKey, "", acc)
+		}
+		if location.ASN != 0 {
+			asns.add(fmt.Sprintf("AS%d", location.ASN), location.Organization, acc)
+		} else {
+			asns.add(unknownGeoKey, "", acc)
+		}
+	}
+
+	return geoRanking(countries, c.topN), geoRanking(asns, c.topN)
+}
+
+// rankSubnets 依網段與具名 CIDR 群組彙總各 IP 的統計並排名
+// 網段保留 Top-N；群組全部列出。無法解析的位址（例如主機名稱）不列入網段
+func (c *Calculator) rankSubnets(ipStats map[string]*ipStatAccumulator) ([]SubnetStatistics, []GeoStatistics) {
+	withGroups := c.cidrGroups.Len() > 0
+	subnets := make(geoGroups)
+	groups := make(geoGroups)
+	mixedGroups := make(map[string]bool) // 網段內的位址屬於不同群組
+
+	for ip, acc := range ipStats {
+		addr, err := subnet.Parse(ip)
+		if err != nil {
+			continue
+		}
+		group := ""
+		if withGroups {
+			if group = c.cidrGroups.Match(addr); group != "" {
+				groups.add(group, "", acc)
+			}
+		}
+
+		key := c.subnets.Of(addr).String()
+		if g := subnets.add(key, group, acc); g.name != group {
+			mixedGroups[key] = true
+		}
+	}
+
+	ranking := make([]SubnetStatistics, 0, len(subnets))
+	for key, g := range subnets {
+		stat := SubnetStatistics{
+			Subnet:       key,
+			Group:        g.name,
+			RequestCount: g.requests,
+			TotalBytes:   g.bytes,
+			UniqueIPs:    g.ips,
+		}
+		if mixedGroups[key] {
+			stat.Group = ""
+		}
+		stat.ErrorRate, stat.BotShare = g.rates()
+		ranking = append(ranking, stat)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].RequestCount != ranking[j].RequestCount {
+			return ranking[i].RequestCount > ranking[j].RequestCount
+		}
+		return ranking[i].Subnet < ranking[j].Subnet
+	})
+	if c.topN > 0 && len(ranking) > c.topN {
+		ranking = ranking[:c.topN]
+	}
+
+	if !withGroups {
+		return ranking, nil
+	}
+	for _, group := range c.cidrGroups.Groups() {
+		if g, exists := groups[group.Name]; exists {
+			g.name = strings.Join(group.CIDRs, ", ")
+		}
+	}
+	return ranking, geoRanking(groups, 0)
+}
+
+// geoRanking 將彙總結果依請求次數降序排名，limit 大於 0 時只保留前 limit 名
+func geoRanking(m geoGroups, limit int) []GeoStatistics {
+	ranking := make([]GeoStatistics, 0, len(m))
+	for key, g := range m {
+		stat := GeoStatistics{
+			Key:          key,
+			Name:         g.name,
+			RequestCount: g.requests,
+			TotalBytes:   g.bytes,
+			UniqueIPs:    g.ips,
+		}
+		stat.ErrorRate, stat.BotShare = g.rates()
+		ranking = append(ranking, stat)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].RequestCount != ranking[j].RequestCount {
+			return ranking[i].RequestCount > ranking[j].RequestCount
+		}
+		return ranking[i].Key < ranking[j].Key
+	})
+	if limit > 0 && len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+	return ranking
 }
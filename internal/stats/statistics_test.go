@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"access-log-analyzer/internal/geoip"
 	"access-log-analyzer/internal/models"
+	"access-log-analyzer/internal/subnet"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +56,23 @@ func TestCalculator_基本統計(t *testing.T) {
 	assert.Equal(t, 2, stats.UniquePaths, "唯一路徑數應該是 2")
 }
 
+// TestCalculator_CalculateWhere 測試只計算符合條件的記錄
+func TestCalculator_CalculateWhere(t *testing.T) {
+	entries := []models.LogEntry{
+		{IP: "192.168.1.1", URL: "/a", StatusCode: 200, ResponseBytes: 100},
+		{IP: "192.168.1.2", URL: "/b", StatusCode: 404, ResponseBytes: 10},
+		{IP: "192.168.1.1", URL: "/a", StatusCode: 200, ResponseBytes: 100},
+	}
+
+	stats := NewCalculator().CalculateWhere(entries, func(e *models.LogEntry) bool { return e.URL == "/a" })
+	assert.Equal(t, 2, stats.TotalRequests)
+	assert.Equal(t, int64(200), stats.TotalBytes)
+	assert.Equal(t, 1, stats.UniquePaths)
+
+	stats = NewCalculator().CalculateWhere(entries, func(*models.LogEntry) bool { return false })
+	assert.Zero(t, stats.TotalRequests)
+}
+
 // TestCalculator_TopIPs 測試 Top IP 統計
 func TestCalculator_TopIPs(t *testing.T) {
 	calc := NewCalculator()
@@ -145,6 +164,134 @@ func TestCalculator_BotDetection(t *testing.T) {
 	assert.InDelta(t, 50.0, stats.BotStats.BotPercentage, 0.01, "機器人百分比應該是 50%")
 }
 
+// TestCalculator_攻擊偵測 測試統計結果包含攻擊偵測
+func TestCalculator_攻擊偵測(t *testing.T) {
+	calc := NewCalculator()
+
+	entries := []models.LogEntry{
+		{IP: "203.0.113.5", URL: "/.env", StatusCode: 404},
+		{IP: "203.0.113.5", URL: "/download?file=..%2F..%2Fetc%2Fpasswd", StatusCode: 200},
+		{IP: "192.168.1.1", URL: "/index.html", StatusCode: 200},
+	}
+
+	stats := calc.Calculate(entries)
+	assert.Equal(t, 2, stats.Threats.AttackRequests)
+	assert.Equal(t, 1, stats.Threats.SuccessfulRequests)
+	assert.Equal(t, 1, stats.Threats.AttackerCount)
+
+	// 重新計算時不累積前一次的結果
+	stats = calc.Calculate(entries[2:])
+	assert.Equal(t, 0, stats.Threats.AttackRequests)
+}
+
+// TestCalculator_GeoIP 測試依國家與 ASN 的排名與 TopIPs 的地理資訊
+func TestCalculator_GeoIP(t *testing.T) {
+	calc := NewCalculator()
+
+	entries := []models.LogEntry{
+		{IP: "203.0.113.5", URL: "/", StatusCode: 200, ResponseBytes: 100, UserAgent: "Mozilla/5.0 Chrome/120.0.0.0"},
+		{IP: "203.0.113.5", URL: "/missing", StatusCode: 404, ResponseBytes: 50, UserAgent: "Mozilla/5.0 Chrome/120.0.0.0"},
+		{IP: "203.0.113.9", URL: "/", StatusCode: 200, ResponseBytes: 100, UserAgent: "Googlebot/2.1"},
+		{IP: "198.51.100.1", URL: "/", StatusCode: 500, ResponseBytes: 10, UserAgent: "Mozilla/5.0 Firefox/121.0"},
+		{IP: "10.0.0.1", URL: "/", StatusCode: 200, ResponseBytes: 10, UserAgent: "Mozilla/5.0 Firefox/121.0"},
+	}
+
+	// 未載入資料庫時不產生排名
+	stats := calc.Calculate(entries)
+	assert.Empty(t, stats.Countries)
+	assert.Empty(t, stats.ASNs)
+	assert.Empty(t, stats.TopIPs[0].Country)
+
+	enricher := geoip.NewEnricher()
+	_, err := enricher.Load("../geoip/testdata")
+	require.NoError(t, err)
+	calc.SetGeoIP(enricher)
+	stats = calc.Calculate(entries)
+
+	require.Len(t, stats.Countries, 3)
+	tw := stats.Countries[0]
+	assert.Equal(t, "TW", tw.Key)
+	assert.Equal(t, "Taiwan", tw.Name)
+	assert.Equal(t, 3, tw.RequestCount)
+	assert.Equal(t, int64(250), tw.TotalBytes)
+	assert.Equal(t, 2, tw.UniqueIPs)
+	assert.InDelta(t, 33.33, tw.ErrorRate, 0.01)
+	assert.InDelta(t, 33.33, tw.BotShare, 0.01)
+	assert.Equal(t, "US", stats.Countries[1].Key)
+	assert.InDelta(t, 100.0, stats.Countries[1].ErrorRate, 0.01)
+	assert.Equal(t, "未知", stats.Countries[2].Key, "查無資料的 IP 歸類為未知")
+
+	require.Len(t, stats.ASNs, 3)
+	assert.Equal(t, "AS3462", stats.ASNs[0].Key)
+	assert.Equal(t, "Data Communication Business Group", stats.ASNs[0].Name)
+	assert.Equal(t, "AS14618", stats.ASNs[1].Key)
+
+	assert.Equal(t, "203.0.113.5", stats.TopIPs[0].IP)
+	assert.Equal(t, "TW", stats.TopIPs[0].Country)
+	assert.Equal(t, "Taipei", stats.TopIPs[0].City)
+	assert.Equal(t, uint(3462), stats.TopIPs[0].ASN)
+}
+
+// TestCalculator_Subnets 測試網段排名與具名 CIDR 群組
+func TestCalculator_Subnets(t *testing.T) {
+	calc := NewCalculator()
+
+	// 爬蟲輪替同一個 /24 與 /64 中的位址，單一 IP 的請求數都不高
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", URL: "/", StatusCode: 200, ResponseBytes: 100},
+		{IP: "10.0.0.1", URL: "/", StatusCode: 200, ResponseBytes: 100},
+		{IP: "10.0.0.1", URL: "/", StatusCode: 200, ResponseBytes: 100},
+		{IP: "203.0.113.1", URL: "/a", StatusCode: 200, ResponseBytes: 10},
+		{IP: "203.0.113.2", URL: "/b", StatusCode: 404, ResponseBytes: 10},
+		{IP: "203.0.113.3", URL: "/c", StatusCode: 200, ResponseBytes: 10},
+		{IP: "203.0.113.4", URL: "/d", StatusCode: 200, ResponseBytes: 10},
+		{IP: "2001:db8:1:2::a", URL: "/", StatusCode: 200, ResponseBytes: 1},
+		{IP: "[2001:db8:1:2::b]", URL: "/", StatusCode: 200, ResponseBytes: 1},
+		{IP: "unknown-host", URL: "/", StatusCode: 200, ResponseBytes: 1},
+	}
+
+	stats := calc.Calculate(entries)
+	require.Len(t, stats.TopSubnets, 3, "無法解析的位址不列入網段")
+	assert.Equal(t, SubnetStatistics{Subnet: "203.0.113.0/24", RequestCount: 4, TotalBytes: 40, UniqueIPs: 4, ErrorRate: 25}, stats.TopSubnets[0])
+	assert.Equal(t, "10.0.0.0/24", stats.TopSubnets[1].Subnet)
+	assert.Equal(t, "2001:db8:1:2::/64", stats.TopSubnets[2].Subnet)
+	assert.Equal(t, 2, stats.TopSubnets[2].UniqueIPs)
+	assert.Empty(t, stats.CIDRGroups)
+
+	// 自訂前綴長度
+	require.NoError(t, calc.SetSubnetOptions(subnet.Options{IPv4Prefix: 8, IPv6Prefix: 32}))
+	stats = calc.Calculate(entries)
+	assert.Equal(t, "203.0.0.0/8", stats.TopSubnets[0].Subnet)
+	assert.Equal(t, "2001:db8::/32", stats.TopSubnets[2].Subnet)
+	assert.Error(t, calc.SetSubnetOptions(subnet.Options{IPv4Prefix: 40}))
+
+	// 具名 CIDR 群組
+	require.NoError(t, calc.SetSubnetOptions(subnet.Options{}))
+	groups := subnet.NewGroupSet()
+	require.NoError(t, groups.Set([]subnet.Group{
+		{Name: "office", CIDRs: []string{"10.0.0.0/8"}},
+		{Name: "monitoring", CIDRs: []string{"203.0.113.1", "2001:db8::/32"}},
+	}))
+	calc.SetCIDRGroups(groups)
+	stats = calc.Calculate(entries)
+
+	assert.Equal(t, "10.0.0.1", stats.TopIPs[0].IP)
+	assert.Equal(t, "office", stats.TopIPs[0].Group)
+	assert.Equal(t, "", stats.TopSubnets[0].Group, "網段內的位址屬於不同群組時不標記")
+	assert.Equal(t, "office", stats.TopSubnets[1].Group)
+	assert.Equal(t, "monitoring", stats.TopSubnets[2].Group)
+
+	// 請求數相同時依名稱排序
+	require.Len(t, stats.CIDRGroups, 2)
+	assert.Equal(t, "monitoring", stats.CIDRGroups[0].Key)
+	assert.Equal(t, "203.0.113.1/32, 2001:db8::/32", stats.CIDRGroups[0].Name)
+	assert.Equal(t, 3, stats.CIDRGroups[0].RequestCount)
+	assert.Equal(t, 3, stats.CIDRGroups[0].UniqueIPs)
+	assert.Equal(t, "office", stats.CIDRGroups[1].Key)
+	assert.Equal(t, "10.0.0.0/8", stats.CIDRGroups[1].Name)
+	assert.Equal(t, 3, stats.CIDRGroups[1].RequestCount)
+}
+
 // TestCalculator_空資料 測試空資料集
 func TestCalculator_空資料(t *testing.T) {
 	calc := NewCalculator()
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"sort"
+
+	"access-log-analyzer/internal/useragent"
+)
+
+// UserAgentStatistics 依 User-Agent 解析結果的分布
+type UserAgentStatistics struct {
+	Browsers         []DistributionItem `json:"browsers"`         // 瀏覽器家族（Top-N）
+	BrowserVersions  []DistributionItem `json:"browserVersions"`  // 瀏覽器家族與主版本，例如 Chrome 120（Top-N）
+	OperatingSystems []DistributionItem `json:"operatingSystems"` // 作業系統
+	Devices          []DistributionItem `json:"devices"`          // 裝置類型：desktop、mobile、tablet、bot、tv、other
+	Engines          []DistributionItem `json:"engines"`          // 排版引擎
+}
+
+// DistributionItem 分布中的單一項目
+type DistributionItem struct {
+	Name       string  `json:"name"`       // 名稱
+	Count      int     `json:"count"`      // 請求次數
+	Percentage float64 `json:"percentage"` // 佔總請求的百分比
+}
+
+// userAgentAccumulator 累積各 User-Agent 的請求次數
+// 計算時只記錄字串，結束後每個不重複的 User-Agent 只解析一次
+type userAgentAccumulator struct {
+	counts    map[string]int // User-Agent -> 請求次數
+	botCounts map[string]int // User-Agent -> 被判定為機器人的請求次數
+}
+
+// newUserAgentAccumulator 建立 User-Agent 累積器
+func newUserAgentAccumulator() *userAgentAccumulator {
+	return &userAgentAccumulator{
+		counts:    make(map[string]int),
+		botCounts: make(map[string]int),
+	}
+}
+
+// observe 記錄一次請求
+func (a *userAgentAccumulator) observe(userAgent string, isBot bool) {
+	a.counts[userAgent]++
+	if isBot {
+		a.botCounts[userAgent]++
+	}
+}
+
+// result 解析各 User-Agent 並彙總分布
+// 機器人偵測判定為機器人的請求一律計為 bot 裝置，與機器人統計一致
+func (a *userAgentAccumulator) result(parser *useragent.Parser, total, topN int) UserAgentStatistics {
+	browsers := make(map[string]int)
+	versions := make(map[string]int)
+	systems := make(map[string]int)
+	devices := make(map[string]int)
+	engines := make(map[string]int)
+
+	for userAgent, count := range a.counts {
+		ua := parser.Parse(userAgent)
+		browsers[ua.Browser] += count
+		versions[ua.BrowserMajor()] += count
+		systems[ua.OS] += count
+		engines[ua.Engine] += count
+
+		bots := a.botCounts[userAgent]
+		if bots > 0 {
+			devices[useragent.DeviceBot] += bots
+		}
+		if count > bots {
+			devices[ua.Device] += count - bots
+		}
+	}
+
+	return UserAgentStatistics{
+		Browsers:         distribution(browsers, total, topN),
+		BrowserVersions:  distribution(versions, total, topN),
+		OperatingSystems: distribution(systems, total, 0),
+		Devices:          distribution(devices, total, 0),
+		Engines:          distribution(engines, total, 0),
+	}
+}
+
+// distribution 將計數轉為依次數降序的分布，limit 大於 0 時只保留前 limit 項
+func distribution(counts map[string]int, total, limit int) []DistributionItem {
+	items := make([]DistributionItem, 0, len(counts))
+	for name, count := range counts {
+		item := DistributionItem{Name: name, Count: count}
+		if total > 0 {
+			item.Percentage = float64(count) / float64(total) * 100
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
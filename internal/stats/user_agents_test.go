@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculator_UserAgents 測試瀏覽器、作業系統、裝置與排版引擎分布
+func TestCalculator_UserAgents(t *testing.T) {
+	const (
+		chrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		iphone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1"
+		firefox = "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	)
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", UserAgent: chrome},
+		{IP: "10.0.0.1", UserAgent: chrome},
+		{IP: "10.0.0.2", UserAgent: iphone},
+		{IP: "10.0.0.3", UserAgent: firefox},
+		{IP: "66.249.66.1", UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"},
+	}
+
+	stats := NewCalculator().Calculate(entries)
+	ua := stats.UserAgents
+
+	require.NotEmpty(t, ua.Browsers)
+	assert.Equal(t, DistributionItem{Name: "Chrome", Count: 2, Percentage: 40}, ua.Browsers[0])
+	assert.Len(t, ua.Browsers, 4)
+	assert.Equal(t, "Chrome 120", ua.BrowserVersions[0].Name)
+
+	assert.Equal(t, []DistributionItem{
+		{Name: "Windows", Count: 2, Percentage: 40},
+		{Name: "Linux", Count: 1, Percentage: 20},
+		{Name: "Other", Count: 1, Percentage: 20},
+		{Name: "iOS", Count: 1, Percentage: 20},
+	}, ua.OperatingSystems)
+
+	assert.Equal(t, []DistributionItem{
+		{Name: "desktop", Count: 3, Percentage: 60},
+		{Name: "bot", Count: 1, Percentage: 20},
+		{Name: "mobile", Count: 1, Percentage: 20},
+	}, ua.Devices)
+
+	assert.Equal(t, "Blink", ua.Engines[0].Name)
+	assert.Equal(t, 2, ua.Engines[0].Count)
+}
+
+// TestCalculator_UserAgents機器人裝置 測試機器人偵測判定的請求計為 bot 裝置
+func TestCalculator_UserAgents機器人裝置(t *testing.T) {
+	// 自訂規則把瀏覽器 User-Agent 判定為機器人
+	rules := NewBotRuleSet()
+	require.NoError(t, rules.Add(BotRule{Name: "InternalMonitor", Category: "監控工具", Substring: "windows nt", Priority: 1000}))
+	calc := NewCalculator()
+	calc.SetBotRules(rules)
+
+	stats := calc.Calculate([]models.LogEntry{
+		{IP: "10.0.0.1", UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0"},
+	})
+	assert.Equal(t, []DistributionItem{{Name: "bot", Count: 1, Percentage: 100}}, stats.UserAgents.Devices)
+	assert.Equal(t, "Chrome", stats.UserAgents.Browsers[0].Name)
+}
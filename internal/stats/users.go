@@ -0,0 +1,238 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"access-log-analyzer/internal/aggregate"
+	"access-log-analyzer/internal/models"
+)
+
+// manyIPsThreshold 同一使用者的不重複 IP 達此數量時標記為多 IP
+const manyIPsThreshold = 5
+
+// maxUserIPs 每個使用者列出的 IP 數
+const maxUserIPs = 5
+
+// maxUserTimelineBuckets 使用者時間軸的區間數量上限
+const maxUserTimelineBuckets = 10000
+
+// UserStatistics 已認證使用者（%u）的統計資訊
+type UserStatistics struct {
+	AuthenticatedRequests int            `json:"authenticatedRequests"` // 帶有使用者名稱的請求數
+	UniqueUsers           int            `json:"uniqueUsers"`           // 不重複的使用者數
+	TopUsers              []UserActivity `json:"topUsers"`              // 依請求數排名的使用者（Top-N）
+}
+
+// UserActivity 單一使用者的活動摘要
+type UserActivity struct {
+	User          string             `json:"user"`          // 使用者名稱
+	RequestCount  int                `json:"requestCount"`  // 請求次數
+	UniquePaths   int                `json:"uniquePaths"`   // 不重複的路徑數（不含查詢字串）
+	ErrorCount    int                `json:"errorCount"`    // 4xx/5xx 請求數
+	ErrorRate     float64            `json:"errorRate"`     // 錯誤率（百分比）
+	ActiveHours   []int              `json:"activeHours"`   // 一天中各小時（0-23，依記錄時區）的請求數
+	FirstSeen     time.Time          `json:"firstSeen"`     // 第一次出現時間
+	LastSeen      time.Time          `json:"lastSeen"`      // 最後一次出現時間
+	UniqueIPs     int                `json:"uniqueIPs"`     // 使用過的不重複 IP 數
+	IPs           []DistributionItem `json:"ips"`           // 最常使用的 IP（Top 5）
+	Countries     []string           `json:"countries"`     // 使用過的 IP 所在國家（需載入 GeoIP 資料庫，排序後）
+	ManyIPs       bool               `json:"manyIPs"`       // 使用 5 個以上的 IP
+	ManyCountries bool               `json:"manyCountries"` // 從 2 個以上的國家連線
+}
+
+// userAccumulator 累積單一使用者的統計資訊
+type userAccumulator struct {
+	requests  int
+	errors    int
+	hours     [24]int
+	firstSeen time.Time
+	lastSeen  time.Time
+	paths     map[string]bool
+	ips       map[string]int
+}
+
+// usersAccumulator 依使用者分組累積（沒有使用者名稱的記錄不列入）
+type usersAccumulator map[string]*userAccumulator
+
+// authenticatedUser 返回記錄的使用者名稱，未認證（空白或 "-"）時返回空字串
+func authenticatedUser(entry *models.LogEntry) string {
+	if entry.User == "-" {
+		return ""
+	}
+	return entry.User
+}
+
+// observe 記錄一筆請求
+func (users usersAccumulator) observe(entry *models.LogEntry) {
+	user := authenticatedUser(entry)
+	if user == "" {
+		return
+	}
+	acc, ok := users[user]
+	if !ok {
+		acc = &userAccumulator{
+			firstSeen: entry.Timestamp,
+			lastSeen:  entry.Timestamp,
+			paths:     make(map[string]bool),
+			ips:       make(map[string]int),
+		}
+		users[user] = acc
+	}
+	acc.requests++
+	if entry.StatusCode >= 400 {
+		acc.errors++
+	}
+	acc.hours[entry.Timestamp.Hour()]++
+	if entry.Timestamp.Before(acc.firstSeen) {
+		acc.firstSeen = entry.Timestamp
+	}
+	if entry.Timestamp.After(acc.lastSeen) {
+		acc.lastSeen = entry.Timestamp
+	}
+	acc.paths[aggregate.StripQuery(entry.URL)] = true
+	acc.ips[entry.IP]++
+}
+
+// userStatistics 建立使用者統計，只有 Top-N 使用者會查詢 IP 所在國家
+func (c *Calculator) userStatistics(users usersAccumulator) UserStatistics {
+	result := UserStatistics{UniqueUsers: len(users)}
+	if len(users) == 0 {
+		return result
+	}
+
+	heap := NewTopNHeap(c.topN)
+	for user, acc := range users {
+		result.AuthenticatedRequests += acc.requests
+		heap.Push(user, acc.requests)
+	}
+
+	for _, item := range heap.GetResults() {
+		acc := users[item.Key]
+		activity := UserActivity{
+			User:         item.Key,
+			RequestCount: acc.requests,
+			UniquePaths:  len(acc.paths),
+			ErrorCount:   acc.errors,
+			ErrorRate:    float64(acc.errors) / float64(acc.requests) * 100,
+			ActiveHours:  append([]int(nil), acc.hours[:]...),
+			FirstSeen:    acc.firstSeen,
+			LastSeen:     acc.lastSeen,
+			UniqueIPs:    len(acc.ips),
+			IPs:          distribution(acc.ips, acc.requests, maxUserIPs),
+		}
+		activity.ManyIPs = activity.UniqueIPs >= manyIPsThreshold
+
+		if c.geo.Enabled() {
+			countries := make(map[string]bool)
+			for ip := range acc.ips {
+				if location, found := c.geo.Lookup(ip); found && location.Country != "" {
+					countries[location.Country] = true
+				}
+			}
+			for country := range countries {
+				activity.Countries = append(activity.Countries, country)
+			}
+			sort.Strings(activity.Countries)
+			activity.ManyCountries = len(activity.Countries) >= 2
+		}
+
+		result.TopUsers = append(result.TopUsers, activity)
+	}
+	return result
+}
+
+// UserTimeline 單一使用者的活動時間軸
+type UserTimeline struct {
+	User      string              `json:"user"`      // 使用者名稱
+	Bucket    string              `json:"bucket"`    // 區間長度，例如 "1h"
+	FirstSeen time.Time           `json:"firstSeen"` // 第一次出現時間
+	LastSeen  time.Time           `json:"lastSeen"`  // 最後一次出現時間
+	Points    []UserTimelinePoint `json:"points"`    // 各區間的活動（涵蓋第一次到最後一次出現，沒有活動的區間為 0）
+}
+
+// UserTimelinePoint 時間軸上的單一區間
+type UserTimelinePoint struct {
+	Start       time.Time `json:"start"`       // 區間開始時間
+	Requests    int       `json:"requests"`    // 請求數
+	Errors      int       `json:"errors"`      // 4xx/5xx 請求數
+	UniquePaths int       `json:"uniquePaths"` // 不重複的路徑數（不含查詢字串）
+	UniqueIPs   int       `json:"uniqueIPs"`   // 不重複的 IP 數
+}
+
+// BuildUserTimeline 依區間長度（例如 "1h"、"1d"）建立單一使用者的活動時間軸
+func BuildUserTimeline(entries []models.LogEntry, user, bucket string) (*UserTimeline, error) {
+	user = strings.TrimSpace(user)
+	if user == "" || user == "-" {
+		return nil, &models.ValidationError{Field: "User", Value: user, Message: "必須指定使用者名稱"}
+	}
+	if bucket == "" {
+		bucket = "1h"
+	}
+	size, err := aggregate.ParseBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucketAccumulator struct {
+		point UserTimelinePoint
+		paths map[string]bool
+		ips   map[string]bool
+	}
+	// 以 Unix 秒為鍵：time.Time 的比較包含時區指標，同一時刻可能有不同的 *Location
+	buckets := make(map[int64]*bucketAccumulator)
+	timeline := &UserTimeline{User: user, Bucket: bucket}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.User != user {
+			continue
+		}
+		if timeline.FirstSeen.IsZero() || entry.Timestamp.Before(timeline.FirstSeen) {
+			timeline.FirstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(timeline.LastSeen) {
+			timeline.LastSeen = entry.Timestamp
+		}
+
+		start := aggregate.TruncateTime(entry.Timestamp, size)
+		acc, ok := buckets[start.Unix()]
+		if !ok {
+			acc = &bucketAccumulator{
+				point: UserTimelinePoint{Start: start},
+				paths: make(map[string]bool),
+				ips:   make(map[string]bool),
+			}
+			buckets[start.Unix()] = acc
+		}
+		acc.point.Requests++
+		if entry.StatusCode >= 400 {
+			acc.point.Errors++
+		}
+		acc.paths[aggregate.StripQuery(entry.URL)] = true
+		acc.ips[entry.IP] = true
+	}
+
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("找不到使用者 %s 的記錄", user)
+	}
+
+	first := aggregate.TruncateTime(timeline.FirstSeen, size)
+	last := aggregate.TruncateTime(timeline.LastSeen, size)
+	if count := int(last.Sub(first)/size) + 1; count > maxUserTimelineBuckets {
+		return nil, fmt.Errorf("時間區間過多（%d 個，上限 %d），請使用較長的區間", count, maxUserTimelineBuckets)
+	}
+
+	for start := first; !start.After(last); start = start.Add(size) {
+		point := UserTimelinePoint{Start: start}
+		if acc, ok := buckets[start.Unix()]; ok {
+			point = acc.point
+			point.UniquePaths = len(acc.paths)
+			point.UniqueIPs = len(acc.ips)
+		}
+		timeline.Points = append(timeline.Points, point)
+	}
+	return timeline, nil
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"access-log-analyzer/internal/geoip"
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculator_Users 測試已認證使用者的活動摘要與多 IP、多國家標記
+func TestCalculator_Users(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := []models.LogEntry{
+		{IP: "203.0.113.5", User: "alice", Timestamp: base, URL: "/", StatusCode: 200},
+		{IP: "203.0.113.5", User: "alice", Timestamp: base.Add(5 * time.Hour), URL: "/report", StatusCode: 403},
+		{IP: "198.51.100.1", User: "alice", Timestamp: base.Add(-time.Hour), URL: "/?page=2", StatusCode: 200},
+		{IP: "10.0.0.1", User: "-", Timestamp: base, URL: "/", StatusCode: 200},
+		{IP: "10.0.0.1", Timestamp: base, URL: "/", StatusCode: 200},
+	}
+	// bob 從 5 個 IP 連線
+	for i := 1; i <= 5; i++ {
+		entries = append(entries, models.LogEntry{IP: fmt.Sprintf("10.0.1.%d", i), User: "bob", Timestamp: base, URL: "/", StatusCode: 200})
+	}
+
+	calc := NewCalculator()
+	enricher := geoip.NewEnricher()
+	_, err := enricher.Load("../geoip/testdata")
+	require.NoError(t, err)
+	calc.SetGeoIP(enricher)
+
+	users := calc.Calculate(entries).Users
+	assert.Equal(t, 8, users.AuthenticatedRequests, "未認證（空白或 -）的請求不列入")
+	assert.Equal(t, 2, users.UniqueUsers)
+	require.Len(t, users.TopUsers, 2)
+
+	bob := users.TopUsers[0]
+	assert.Equal(t, "bob", bob.User)
+	assert.Equal(t, 5, bob.UniqueIPs)
+	assert.True(t, bob.ManyIPs)
+	assert.False(t, bob.ManyCountries)
+
+	alice := users.TopUsers[1]
+	assert.Equal(t, "alice", alice.User)
+	assert.Equal(t, 3, alice.RequestCount)
+	assert.Equal(t, 2, alice.UniquePaths, "路徑不含查詢字串")
+	assert.Equal(t, 1, alice.ErrorCount)
+	assert.InDelta(t, 33.33, alice.ErrorRate, 0.01)
+	require.Len(t, alice.ActiveHours, 24)
+	assert.Equal(t, 1, alice.ActiveHours[8])
+	assert.Equal(t, 1, alice.ActiveHours[9])
+	assert.Equal(t, 1, alice.ActiveHours[14])
+	assert.Equal(t, base.Add(-time.Hour), alice.FirstSeen)
+	assert.Equal(t, base.Add(5*time.Hour), alice.LastSeen)
+	assert.Equal(t, 2, alice.UniqueIPs)
+	assert.Equal(t, "203.0.113.5", alice.IPs[0].Name)
+	assert.False(t, alice.ManyIPs)
+	assert.Equal(t, []string{"TW", "US"}, alice.Countries)
+	assert.True(t, alice.ManyCountries)
+}
+
+// TestBuildUserTimeline 測試單一使用者的活動時間軸與參數驗證
+func TestBuildUserTimeline(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 15, 0, 0, time.UTC)
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", User: "alice", Timestamp: base.Add(2 * time.Hour), URL: "/b", StatusCode: 500},
+		{IP: "10.0.0.1", User: "alice", Timestamp: base, URL: "/a", StatusCode: 200},
+		{IP: "10.0.0.2", User: "alice", Timestamp: base.Add(10 * time.Minute), URL: "/a?ref=mail", StatusCode: 200},
+		{IP: "10.0.0.3", User: "bob", Timestamp: base.Add(time.Hour), URL: "/", StatusCode: 200},
+	}
+
+	timeline, err := BuildUserTimeline(entries, "alice", "")
+	require.NoError(t, err)
+	assert.Equal(t, "1h", timeline.Bucket)
+	assert.Equal(t, base, timeline.FirstSeen)
+	assert.Equal(t, base.Add(2*time.Hour), timeline.LastSeen)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []UserTimelinePoint{
+		{Start: start, Requests: 2, UniquePaths: 1, UniqueIPs: 2},
+		{Start: start.Add(time.Hour)},
+		{Start: start.Add(2 * time.Hour), Requests: 1, Errors: 1, UniquePaths: 1, UniqueIPs: 1},
+	}, timeline.Points)
+
+	_, err = BuildUserTimeline(entries, "-", "1h")
+	var validationErr *models.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = BuildUserTimeline(entries, "alice", "abc")
+	assert.Error(t, err)
+	_, err = BuildUserTimeline(entries, "carol", "1h")
+	assert.Error(t, err, "找不到使用者")
+	_, err = BuildUserTimeline(append(entries, models.LogEntry{User: "alice", Timestamp: base.AddDate(2, 0, 0)}), "alice", "1m")
+	assert.Error(t, err, "時間區間過多")
+}
+
+// TestBuildUserTimeline_非整點時區 測試時區偏移不是整點（+0530）的記錄落在同一個區間
+// time.Parse 會為每個時間戳記建立不同的 *Location，區間不可依 time.Time 比對
+func TestBuildUserTimeline_非整點時區(t *testing.T) {
+	parse := func(value string) time.Time {
+		ts, err := time.Parse("02/Jan/2006:15:04:05 -0700", value)
+		require.NoError(t, err)
+		return ts
+	}
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", User: "alice", Timestamp: parse("01/Jan/2024:10:05:00 +0530"), URL: "/a", StatusCode: 200},
+		{IP: "10.0.0.2", User: "alice", Timestamp: parse("01/Jan/2024:10:45:00 +0530"), URL: "/b", StatusCode: 404},
+		{IP: "10.0.0.1", User: "alice", Timestamp: parse("01/Jan/2024:12:10:00 +0530"), URL: "/a", StatusCode: 200},
+	}
+
+	timeline, err := BuildUserTimeline(entries, "alice", "1h")
+	require.NoError(t, err)
+	require.Len(t, timeline.Points, 3)
+
+	first := timeline.Points[0]
+	assert.True(t, first.Start.Equal(parse("01/Jan/2024:10:00:00 +0530")), "區間依記錄的時區對齊整點")
+	assert.Equal(t, 2, first.Requests)
+	assert.Equal(t, 1, first.Errors)
+	assert.Equal(t, 2, first.UniquePaths)
+	assert.Equal(t, 2, first.UniqueIPs)
+	assert.Zero(t, timeline.Points[1].Requests)
+	assert.Equal(t, 1, timeline.Points[2].Requests)
+}
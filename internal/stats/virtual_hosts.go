@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"sort"
+
+	"access-log-analyzer/internal/models"
+)
+
+// maxVirtualHosts 虛擬主機摘要保留的主機上限（依請求數降序）
+const maxVirtualHosts = 1000
+
+// maxVirtualHostPaths 每個虛擬主機列出的熱門路徑數
+const maxVirtualHostPaths = 5
+
+// VirtualHostStatistics 單一虛擬主機（或來源伺服器）的摘要
+type VirtualHostStatistics struct {
+	Host         string             `json:"host"`         // 虛擬主機名稱（來源伺服器摘要時為伺服器名稱）
+	Ports        []int              `json:"ports"`        // 出現過的伺服器連接埠（排序後，格式未記錄時為空）
+	RequestCount int                `json:"requestCount"` // 請求次數
+	Percentage   float64            `json:"percentage"`   // 佔總請求的百分比
+	TotalBytes   int64              `json:"totalBytes"`   // 總傳輸量（位元組）
+	ErrorCount   int                `json:"errorCount"`   // 4xx/5xx 請求數
+	ErrorRate    float64            `json:"errorRate"`    // 錯誤率（百分比）
+	UniqueIPs    int                `json:"uniqueIPs"`    // 不重複 IP 數
+	TopPaths     []DistributionItem `json:"topPaths"`     // 熱門路徑（Top 5，百分比相對於此主機）
+}
+
+// virtualHostAccumulator 累積單一虛擬主機的統計資訊
+type virtualHostAccumulator struct {
+	requests int
+	bytes    int64
+	errors   int
+	ports    map[int]bool
+	ips      map[string]bool
+	paths    map[string]int
+}
+
+// virtualHostsAccumulator 依虛擬主機或來源伺服器分組累積（分組鍵為空的記錄不列入）
+type virtualHostsAccumulator map[string]*virtualHostAccumulator
+
+// observe 將一筆請求記錄到 host 分組
+func (hosts virtualHostsAccumulator) observe(host string, entry *models.LogEntry) {
+	if host == "" {
+		return
+	}
+	acc, ok := hosts[host]
+	if !ok {
+		acc = &virtualHostAccumulator{
+			ports: make(map[int]bool),
+			ips:   make(map[string]bool),
+			paths: make(map[string]int),
+		}
+		hosts[host] = acc
+	}
+	acc.requests++
+	acc.bytes += entry.ResponseBytes
+	if entry.StatusCode >= 400 {
+		acc.errors++
+	}
+	if entry.ServerPort > 0 {
+		acc.ports[entry.ServerPort] = true
+	}
+	acc.ips[entry.IP] = true
+	acc.paths[entry.URL]++
+}
+
+// result 建立虛擬主機摘要（依請求數降序）
+func (hosts virtualHostsAccumulator) result(total int) []VirtualHostStatistics {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	result := make([]VirtualHostStatistics, 0, len(hosts))
+	for host, acc := range hosts {
+		ports := make([]int, 0, len(acc.ports))
+		for port := range acc.ports {
+			ports = append(ports, port)
+		}
+		sort.Ints(ports)
+
+		result = append(result, VirtualHostStatistics{
+			Host:         host,
+			Ports:        ports,
+			RequestCount: acc.requests,
+			Percentage:   float64(acc.requests) / float64(total) * 100,
+			TotalBytes:   acc.bytes,
+			ErrorCount:   acc.errors,
+			ErrorRate:    float64(acc.errors) / float64(acc.requests) * 100,
+			UniqueIPs:    len(acc.ips),
+			TopPaths:     distribution(acc.paths, acc.requests, maxVirtualHostPaths),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RequestCount != result[j].RequestCount {
+			return result[i].RequestCount > result[j].RequestCount
+		}
+		return result[i].Host < result[j].Host
+	})
+	if len(result) > maxVirtualHosts {
+		result = result[:maxVirtualHosts]
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+
+	"access-log-analyzer/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculator_VirtualHosts 測試各虛擬主機的請求數、流量、錯誤與熱門路徑
+func TestCalculator_VirtualHosts(t *testing.T) {
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", VirtualHost: "www.example.com", ServerPort: 443, URL: "/", StatusCode: 200, ResponseBytes: 100},
+		{IP: "10.0.0.2", VirtualHost: "www.example.com", ServerPort: 80, URL: "/", StatusCode: 301, ResponseBytes: 10},
+		{IP: "10.0.0.1", VirtualHost: "www.example.com", ServerPort: 443, URL: "/about", StatusCode: 404, ResponseBytes: 50},
+		{IP: "10.0.0.3", VirtualHost: "api.example.com", URL: "/v1", StatusCode: 500, ResponseBytes: 20},
+		// 沒有虛擬主機資訊的記錄不列入摘要
+		{IP: "10.0.0.4", URL: "/", StatusCode: 200},
+	}
+
+	hosts := NewCalculator().Calculate(entries).VirtualHosts
+	require.Len(t, hosts, 2)
+
+	www := hosts[0]
+	assert.Equal(t, "www.example.com", www.Host)
+	assert.Equal(t, []int{80, 443}, www.Ports)
+	assert.Equal(t, 3, www.RequestCount)
+	assert.InDelta(t, 60, www.Percentage, 0.001)
+	assert.Equal(t, int64(160), www.TotalBytes)
+	assert.Equal(t, 1, www.ErrorCount)
+	assert.InDelta(t, 33.33, www.ErrorRate, 0.01)
+	assert.Equal(t, 2, www.UniqueIPs)
+	require.Len(t, www.TopPaths, 2)
+	assert.Equal(t, "/", www.TopPaths[0].Name)
+	assert.Equal(t, 2, www.TopPaths[0].Count)
+
+	api := hosts[1]
+	assert.Equal(t, "api.example.com", api.Host)
+	assert.Empty(t, api.Ports)
+	assert.InDelta(t, 100, api.ErrorRate, 0.001)
+
+	assert.Nil(t, NewCalculator().Calculate(entries[4:]).VirtualHosts, "沒有虛擬主機資訊時不產生摘要")
+}
+
+// TestCalculator_Servers 測試依來源伺服器分組的摘要
+func TestCalculator_Servers(t *testing.T) {
+	entries := []models.LogEntry{
+		{IP: "10.0.0.1", Server: "web-01", URL: "/", StatusCode: 200, ResponseBytes: 100},
+		{IP: "10.0.0.2", Server: "web-01", URL: "/about", StatusCode: 500, ResponseBytes: 10},
+		{IP: "10.0.0.1", Server: "web-02", URL: "/", StatusCode: 200, ResponseBytes: 50},
+	}
+
+	servers := NewCalculator().Calculate(entries).Servers
+	require.Len(t, servers, 2)
+	assert.Equal(t, "web-01", servers[0].Host)
+	assert.Equal(t, 2, servers[0].RequestCount)
+	assert.Equal(t, int64(110), servers[0].TotalBytes)
+	assert.InDelta(t, 50, servers[0].ErrorRate, 0.001)
+	assert.Equal(t, "web-02", servers[1].Host)
+	assert.InDelta(t, 33.33, servers[1].Percentage, 0.01)
+
+	assert.Nil(t, NewCalculator().Calculate(entries).VirtualHosts, "伺服器不列入虛擬主機摘要")
+}
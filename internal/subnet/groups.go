@@ -0,0 +1,189 @@
+package subnet
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Group 具名的 CIDR 群組，例如辦公室、監控服務或合作夥伴
+type Group struct {
+	Name  string   `json:"name" yaml:"name"`   // 群組名稱（顯示為標籤）
+	CIDRs []string `json:"cidrs" yaml:"cidrs"` // 網段列表；單一位址視為 /32 或 /128
+
+	prefixes []netip.Prefix // 解析後的網段
+}
+
+// groupsFile CIDR 群組檔格式（YAML 或 JSON）
+type groupsFile struct {
+	Groups []Group `yaml:"groups"` // 群組列表
+}
+
+// ParseGroups 解析 CIDR 群組檔（YAML 或 JSON）
+//
+//	groups:
+//	  - name: office
+//	    cidrs: [203.0.113.0/24, "2001:db8:10::/48"]
+func ParseGroups(data []byte) ([]Group, error) {
+	var file groupsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析 CIDR 群組失敗: %w", err)
+	}
+	if err := compileGroups(file.Groups); err != nil {
+		return nil, err
+	}
+	return file.Groups, nil
+}
+
+// compileGroups 驗證群組並解析網段
+func compileGroups(groups []Group) error {
+	names := make(map[string]bool, len(groups))
+	for i := range groups {
+		group := &groups[i]
+		group.Name = strings.TrimSpace(group.Name)
+		if group.Name == "" {
+			return &models.ValidationError{Field: "name", Value: group.Name, Message: fmt.Sprintf("第 %d 個群組的名稱不可為空", i+1)}
+		}
+		if names[group.Name] {
+			return &models.ValidationError{Field: "name", Value: group.Name, Message: "群組名稱重複"}
+		}
+		names[group.Name] = true
+		if len(group.CIDRs) == 0 {
+			return &models.ValidationError{Field: "cidrs", Value: group.Name, Message: fmt.Sprintf("群組 %s 沒有任何網段", group.Name)}
+		}
+
+		group.prefixes = make([]netip.Prefix, 0, len(group.CIDRs))
+		for j, cidr := range group.CIDRs {
+			prefix, err := parsePrefix(cidr)
+			if err != nil {
+				return &models.ValidationError{Field: "cidrs", Value: cidr, Message: fmt.Sprintf("群組 %s 的網段無效", group.Name)}
+			}
+			group.CIDRs[j] = prefix.String()
+			group.prefixes = append(group.prefixes, prefix)
+		}
+	}
+	return nil
+}
+
+// parsePrefix 解析網段或單一位址，返回標準化（主機位元清零）的網段
+func parsePrefix(cidr string) (netip.Prefix, error) {
+	cidr = strings.TrimSpace(cidr)
+	if !strings.Contains(cidr, "/") {
+		addr, err := Parse(cidr)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.WithZone("")
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// GroupSet 目前生效的 CIDR 群組，可安全地在多個 goroutine 間共用
+type GroupSet struct {
+	mu     sync.RWMutex
+	groups []Group
+	log    *logger.Logger
+}
+
+// NewGroupSet 建立沒有任何群組的 GroupSet
+func NewGroupSet() *GroupSet {
+	return &GroupSet{
+		log: logger.Get().WithModule("subnet"),
+	}
+}
+
+// Set 驗證並取代目前的群組
+func (s *GroupSet) Set(groups []Group) error {
+	groups = append([]Group(nil), groups...)
+	for i := range groups {
+		groups[i].CIDRs = append([]string(nil), groups[i].CIDRs...)
+	}
+	if err := compileGroups(groups); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.groups = groups
+	s.mu.Unlock()
+	return nil
+}
+
+// LoadFile 載入 CIDR 群組檔，取代目前的群組
+func (s *GroupSet) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("讀取 CIDR 群組檔失敗: %w", err)
+	}
+	groups, err := ParseGroups(data)
+	if err != nil {
+		return fmt.Errorf("載入 CIDR 群組檔 %s 失敗: %w", path, err)
+	}
+
+	s.mu.Lock()
+	s.groups = groups
+	s.mu.Unlock()
+
+	s.log.Info().
+		Str("path", path).
+		Int("groups", len(groups)).
+		Msg("已載入 CIDR 群組檔")
+	return nil
+}
+
+// Groups 返回目前的群組
+func (s *GroupSet) Groups() []Group {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Group(nil), s.groups...)
+}
+
+// Len 返回群組數量
+func (s *GroupSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.groups)
+}
+
+// Match 返回位址所屬的群組名稱，不屬於任何群組時返回空字串
+// 多個群組包含同一位址時，以最精確（前綴最長）的網段為準；長度相同時取檔案中較前面的群組
+func (s *GroupSet) Match(addr netip.Addr) string {
+	if s == nil {
+		return ""
+	}
+	addr = addr.WithZone("")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, bits := "", -1
+	for _, group := range s.groups {
+		for _, prefix := range group.prefixes {
+			if prefix.Bits() > bits && prefix.Contains(addr) {
+				name, bits = group.Name, prefix.Bits()
+			}
+		}
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+// Package subnet 解析與正規化 IP 位址，並將位址彙總為網段或具名的 CIDR 群組
+package subnet
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"access-log-analyzer/internal/models"
+)
+
+// 預設的網段前綴長度：爬蟲通常輪替同一個 IPv4 /24 或 IPv6 /64 中的位址
+const (
+	DefaultIPv4Prefix = 24
+	DefaultIPv6Prefix = 64
+)
+
+// Options 網段彙總的前綴長度設定
+type Options struct {
+	IPv4Prefix int `json:"ipv4Prefix"` // IPv4 前綴長度（1-32，0 表示預設 24）
+	IPv6Prefix int `json:"ipv6Prefix"` // IPv6 前綴長度（1-128，0 表示預設 64）
+}
+
+// DefaultOptions 返回預設的網段設定
+func DefaultOptions() Options {
+	return Options{IPv4Prefix: DefaultIPv4Prefix, IPv6Prefix: DefaultIPv6Prefix}
+}
+
+// WithDefaults 以預設值補上未設定的欄位
+func (o Options) WithDefaults() Options {
+	if o.IPv4Prefix == 0 {
+		o.IPv4Prefix = DefaultIPv4Prefix
+	}
+	if o.IPv6Prefix == 0 {
+		o.IPv6Prefix = DefaultIPv6Prefix
+	}
+	return o
+}
+
+// Validate 驗證前綴長度（未設定的欄位視為預設值）
+func (o Options) Validate() error {
+	o = o.WithDefaults()
+	if o.IPv4Prefix < 1 || o.IPv4Prefix > 32 {
+		return &models.ValidationError{Field: "ipv4Prefix", Value: strconv.Itoa(o.IPv4Prefix), Message: "IPv4 前綴長度必須介於 1 到 32"}
+	}
+	if o.IPv6Prefix < 1 || o.IPv6Prefix > 128 {
+		return &models.ValidationError{Field: "ipv6Prefix", Value: strconv.Itoa(o.IPv6Prefix), Message: "IPv6 前綴長度必須介於 1 到 128"}
+	}
+	return nil
+}
+
+// Of 返回位址所屬的網段（IPv6 的 zone 不屬於網段）
+func (o Options) Of(addr netip.Addr) netip.Prefix {
+	o = o.WithDefaults()
+	bits := o.IPv6Prefix
+	if addr.Is4() {
+		bits = o.IPv4Prefix
+	}
+	prefix, err := addr.WithZone("").Prefix(bits)
+	if err != nil {
+		return netip.Prefix{}
+	}
+	return prefix
+}
+
+// Parse 驗證並解析 log 中的用戶端位址
+// 接受 IPv4、IPv6、帶 zone 的 IPv6（fe80::1%eth0）與中括號形式（[2001:db8::1]、[2001:db8::1]:443、
+// RFC 6874 的 [fe80::1%25eth0]）；IPv4 對應的 IPv6 位址（::ffff:192.0.2.1）轉為 IPv4
+func Parse(raw string) (netip.Addr, error) {
+	s := strings.TrimSpace(raw)
+	if strings.HasPrefix(s, "[") {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return netip.Addr{}, invalidAddress(raw)
+		}
+		if rest := s[end+1:]; rest != "" {
+			if rest[0] != ':' {
+				return netip.Addr{}, invalidAddress(raw)
+			}
+			if _, err := strconv.ParseUint(rest[1:], 10, 16); err != nil {
+				return netip.Addr{}, invalidAddress(raw)
+			}
+		}
+		s = strings.Replace(s[1:end], "%25", "%", 1)
+		if !strings.Contains(s, ":") {
+			// 中括號只用於 IPv6
+			return netip.Addr{}, invalidAddress(raw)
+		}
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, invalidAddress(raw)
+	}
+	if addr.Is4In6() {
+		addr = addr.Unmap()
+	}
+	return addr, nil
+}
+
+// Canonical 返回位址的標準形式（IPv6 為小寫的 RFC 5952 壓縮格式，保留 zone）
+// 無法解析的值（例如啟用 HostnameLookups 時的主機名稱）原樣返回
+func Canonical(raw string) string {
+	addr, err := Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return addr.String()
+}
+
+// invalidAddress 建立無效位址的錯誤
+func invalidAddress(raw string) error {
+	return &models.ValidationError{Field: "ip", Value: raw, Message: "無效的 IP 位址"}
+}
@@ -0,0 +1,116 @@
+package subnet
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse 測試位址的驗證與標準化
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{"IPv4", "203.0.113.7", "203.0.113.7"},
+		{"IPv6 壓縮", "2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{"IPv6 前導零", "2001:0db8:0000::0001", "2001:db8::1"},
+		{"中括號", "[2001:db8::1]", "2001:db8::1"},
+		{"中括號與連接埠", "[2001:db8::1]:8443", "2001:db8::1"},
+		{"zone", "fe80::1%eth0", "fe80::1%eth0"},
+		{"RFC 6874 zone", "[fe80::1%25eth0]", "fe80::1%eth0"},
+		{"IPv4 對應的 IPv6", "::ffff:203.0.113.7", "203.0.113.7"},
+		{"前後空白", " 10.0.0.1 ", "10.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, err := Parse(tc.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, addr.String())
+			assert.Equal(t, tc.expected, Canonical(tc.raw))
+		})
+	}
+
+	for _, raw := range []string{"", "example.com", "256.1.1.1", "[203.0.113.7]", "[2001:db8::1", "[2001:db8::1]x", "[2001:db8::1]:port", "10.0.0.1%eth0"} {
+		_, err := Parse(raw)
+		assert.Error(t, err, raw)
+	}
+	assert.Equal(t, "example.com", Canonical("example.com"), "無法解析的值原樣返回")
+}
+
+// TestOptions 測試前綴長度的預設值、驗證與網段計算
+func TestOptions(t *testing.T) {
+	assert.Equal(t, DefaultOptions(), Options{}.WithDefaults())
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{IPv4Prefix: 32, IPv6Prefix: 128}.Validate())
+	assert.Error(t, Options{IPv4Prefix: 33}.Validate())
+	assert.Error(t, Options{IPv6Prefix: -1}.Validate())
+	assert.Error(t, Options{IPv6Prefix: 129}.Validate())
+
+	opts := Options{}
+	assert.Equal(t, "203.0.113.0/24", opts.Of(netip.MustParseAddr("203.0.113.7")).String())
+	assert.Equal(t, "2001:db8:1:2::/64", opts.Of(netip.MustParseAddr("2001:db8:1:2:3::1")).String())
+	assert.Equal(t, "fe80::/64", opts.Of(netip.MustParseAddr("fe80::1%eth0")).String(), "zone 不屬於網段")
+
+	opts = Options{IPv4Prefix: 16, IPv6Prefix: 48}
+	assert.Equal(t, "203.0.0.0/16", opts.Of(netip.MustParseAddr("203.0.113.7")).String())
+	assert.Equal(t, "2001:db8:1::/48", opts.Of(netip.MustParseAddr("2001:db8:1:2:3::1")).String())
+}
+
+// TestGroupSet 測試具名 CIDR 群組的解析與比對
+func TestGroupSet(t *testing.T) {
+	groups, err := ParseGroups([]byte(`
+groups:
+  - name: office
+    cidrs: [203.0.113.0/24, "2001:db8:10::/48"]
+  - name: monitoring
+    cidrs: [203.0.113.128/25, 198.51.100.7]
+  - name: partners
+    cidrs: ["::ffff:192.0.2.0/120", 203.0.113.5/24]
+`))
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"198.51.100.7/32"}, groups[1].CIDRs[1:], "單一位址視為 /32")
+	assert.Equal(t, []string{"192.0.2.0/24", "203.0.113.0/24"}, groups[2].CIDRs, "網段標準化")
+
+	set := NewGroupSet()
+	assert.Equal(t, "", set.Match(netip.MustParseAddr("203.0.113.7")))
+	require.NoError(t, set.Set(groups))
+	assert.Equal(t, 3, set.Len())
+
+	testCases := []struct {
+		ip       string
+		expected string
+	}{
+		{"203.0.113.7", "office"},
+		{"203.0.113.200", "monitoring"}, // /25 比 /24 精確
+		{"198.51.100.7", "monitoring"},
+		{"198.51.100.8", ""},
+		{"192.0.2.1", "partners"},
+		{"2001:db8:10:ffff::1", "office"},
+		{"2001:db8:11::1", ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, set.Match(netip.MustParseAddr(tc.ip)), tc.ip)
+	}
+
+	// 無效的群組
+	for _, data := range []string{
+		"groups: [{name: '', cidrs: [10.0.0.0/8]}]",
+		"groups: [{name: a, cidrs: []}]",
+		"groups: [{name: a, cidrs: [10.0.0.0/33]}]",
+		"groups: [{name: a, cidrs: [10.0.0.0/8]}, {name: a, cidrs: [10.0.0.0/8]}]",
+		"groups: {",
+	} {
+		_, err := ParseGroups([]byte(data))
+		assert.Error(t, err, data)
+	}
+
+	var nilSet *GroupSet
+	assert.Equal(t, "", nilSet.Match(netip.MustParseAddr("203.0.113.7")))
+	assert.Zero(t, nilSet.Len())
+}
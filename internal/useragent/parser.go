@@ -0,0 +1,315 @@
+// Package useragent 以 uap-core 風格的規則解析 User-Agent 的瀏覽器、作業系統、裝置與排版引擎
+package useragent
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"access-log-analyzer/internal/models"
+	"access-log-analyzer/pkg/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRegexesData 內建的解析規則
+//
+//go:embed regexes.yaml
+var defaultRegexesData []byte
+
+// maxCacheEntries 解析快取的上限，超過時清空重建
+const maxCacheEntries = 100000
+
+// 裝置類型
+const (
+	DeviceDesktop = "desktop" // 桌上型電腦與筆電
+	DeviceMobile  = "mobile"  // 手機
+	DeviceTablet  = "tablet"  // 平板
+	DeviceBot     = "bot"     // 機器人、爬蟲與命令列工具
+	DeviceTV      = "tv"      // 智慧電視、串流裝置與遊戲主機
+	DeviceOther   = "other"   // 無法判斷
+)
+
+// Other 無法辨識的瀏覽器、作業系統或排版引擎
+const Other = "Other"
+
+// UserAgent 解析後的 User-Agent
+type UserAgent struct {
+	Browser        string `json:"browser"`        // 瀏覽器家族，例如 Chrome、Mobile Safari
+	BrowserVersion string `json:"browserVersion"` // 瀏覽器版本，例如 120.0（無法擷取時為空字串）
+	OS             string `json:"os"`             // 作業系統，例如 Windows、iOS
+	OSVersion      string `json:"osVersion"`      // 作業系統版本，例如 10、17.1
+	Device         string `json:"device"`         // 裝置類型：desktop、mobile、tablet、bot、tv 或 other
+	Engine         string `json:"engine"`         // 排版引擎，例如 Blink、Gecko、WebKit
+}
+
+// BrowserMajor 返回瀏覽器家族與主版本，例如 "Chrome 120"
+func (ua UserAgent) BrowserMajor() string {
+	major, _, _ := strings.Cut(ua.BrowserVersion, ".")
+	if major == "" {
+		return ua.Browser
+	}
+	return ua.Browser + " " + major
+}
+
+// rule 單條解析規則（uap-core 格式，依區段使用不同的 replacement 欄位）
+type rule struct {
+	Regex             string `yaml:"regex"`
+	RegexFlag         string `yaml:"regex_flag"`
+	FamilyReplacement string `yaml:"family_replacement"`
+	V1Replacement     string `yaml:"v1_replacement"`
+	V2Replacement     string `yaml:"v2_replacement"`
+	V3Replacement     string `yaml:"v3_replacement"`
+	OSReplacement     string `yaml:"os_replacement"`
+	OSV1Replacement   string `yaml:"os_v1_replacement"`
+	OSV2Replacement   string `yaml:"os_v2_replacement"`
+	OSV3Replacement   string `yaml:"os_v3_replacement"`
+	DeviceType        string `yaml:"device_type"`
+	EngineReplacement string `yaml:"engine_replacement"`
+
+	re *regexp.Regexp
+}
+
+// regexesFile 解析規則檔格式
+type regexesFile struct {
+	UserAgentParsers []rule `yaml:"user_agent_parsers"`
+	OSParsers        []rule `yaml:"os_parsers"`
+	DeviceParsers    []rule `yaml:"device_parsers"`
+	EngineParsers    []rule `yaml:"engine_parsers"`
+}
+
+// validDeviceTypes 規則檔中允許的裝置類型
+var validDeviceTypes = map[string]bool{
+	DeviceDesktop: true,
+	DeviceMobile:  true,
+	DeviceTablet:  true,
+	DeviceBot:     true,
+	DeviceTV:      true,
+}
+
+// parseRegexes 解析並編譯規則檔（YAML 或 JSON）
+func parseRegexes(data []byte) (*regexesFile, error) {
+	var file regexesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析 User-Agent 規則失敗: %w", err)
+	}
+	if len(file.UserAgentParsers) == 0 && len(file.OSParsers) == 0 && len(file.DeviceParsers) == 0 && len(file.EngineParsers) == 0 {
+		return nil, &models.ValidationError{Field: "user_agent_parsers", Value: "", Message: "規則檔沒有任何規則"}
+	}
+
+	sections := []struct {
+		name  string
+		rules []rule
+	}{
+		{"user_agent_parsers", file.UserAgentParsers},
+		{"os_parsers", file.OSParsers},
+		{"device_parsers", file.DeviceParsers},
+		{"engine_parsers", file.EngineParsers},
+	}
+	for _, section := range sections {
+		for i := range section.rules {
+			r := &section.rules[i]
+			pattern := r.Regex
+			switch r.RegexFlag {
+			case "":
+			case "i":
+				pattern = "(?i)" + pattern
+			default:
+				return nil, &models.ValidationError{Field: section.name, Value: r.RegexFlag, Message: fmt.Sprintf("第 %d 條規則的 regex_flag 只支援 i", i+1)}
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil || r.Regex == "" {
+				return nil, &models.ValidationError{Field: section.name, Value: r.Regex, Message: fmt.Sprintf("第 %d 條規則的正規表達式無效", i+1)}
+			}
+			r.re = re
+			if section.name == "device_parsers" && !validDeviceTypes[r.DeviceType] {
+				return nil, &models.ValidationError{Field: section.name, Value: r.DeviceType, Message: fmt.Sprintf("第 %d 條規則的裝置類型無效", i+1)}
+			}
+		}
+	}
+	return &file, nil
+}
+
+// Parser 以 uap-core 風格的規則解析 User-Agent
+// 解析結果依 User-Agent 字串快取，相同的 User-Agent 只解析一次；可在多個 goroutine 間共用
+type Parser struct {
+	mu      sync.RWMutex
+	rules   *regexesFile
+	source  string // 規則來源：內建或檔案路徑
+	cacheMu sync.Mutex
+	cache   map[string]UserAgent
+	log     *logger.Logger
+}
+
+// NewParser 建立使用內建規則的解析器
+func NewParser() *Parser {
+	rules, err := parseRegexes(defaultRegexesData)
+	if err != nil {
+		// 內建規則隨程式編譯，解析失敗屬於程式錯誤
+		panic(fmt.Sprintf("內建 User-Agent 規則無效: %v", err))
+	}
+	return &Parser{
+		rules:  rules,
+		source: "內建",
+		cache:  make(map[string]UserAgent),
+		log:    logger.Get().WithModule("useragent"),
+	}
+}
+
+// LoadFile 載入規則檔，取代目前的規則並清空快取
+func (p *Parser) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("讀取 User-Agent 規則檔失敗: %w", err)
+	}
+	rules, err := parseRegexes(data)
+	if err != nil {
+		return fmt.Errorf("載入 User-Agent 規則檔 %s 失敗: %w", path, err)
+	}
+
+	p.mu.Lock()
+	p.rules, p.source = rules, path
+	p.mu.Unlock()
+	p.clearCache()
+
+	p.log.Info().
+		Str("path", path).
+		Int("browsers", len(rules.UserAgentParsers)).
+		Int("os", len(rules.OSParsers)).
+		Int("devices", len(rules.DeviceParsers)).
+		Int("engines", len(rules.EngineParsers)).
+		Msg("已載入 User-Agent 規則檔")
+	return nil
+}
+
+// Source 返回目前規則的來源（"內建" 或規則檔路徑）
+func (p *Parser) Source() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.source
+}
+
+// Parse 解析 User-Agent，結果會快取
+func (p *Parser) Parse(userAgent string) UserAgent {
+	p.cacheMu.Lock()
+	ua, cached := p.cache[userAgent]
+	p.cacheMu.Unlock()
+	if cached {
+		return ua
+	}
+
+	ua = p.parse(userAgent)
+
+	p.cacheMu.Lock()
+	if len(p.cache) >= maxCacheEntries {
+		p.cache = make(map[string]UserAgent)
+	}
+	p.cache[userAgent] = ua
+	p.cacheMu.Unlock()
+	return ua
+}
+
+// parse 依序比對各區段的規則
+func (p *Parser) parse(userAgent string) UserAgent {
+	ua := UserAgent{Browser: Other, OS: Other, Device: DeviceOther, Engine: Other}
+	if userAgent == "" || userAgent == "-" {
+		return ua
+	}
+
+	p.mu.RLock()
+	rules := p.rules
+	p.mu.RUnlock()
+
+	for _, r := range rules.UserAgentParsers {
+		if m := r.re.FindStringSubmatch(userAgent); m != nil {
+			ua.Browser = replace(r.FamilyReplacement, m, 1)
+			ua.BrowserVersion = joinVersion(
+				replace(r.V1Replacement, m, 2),
+				replace(r.V2Replacement, m, 3),
+				replace(r.V3Replacement, m, 4),
+			)
+			break
+		}
+	}
+	for _, r := range rules.OSParsers {
+		if m := r.re.FindStringSubmatch(userAgent); m != nil {
+			ua.OS = replace(r.OSReplacement, m, 1)
+			ua.OSVersion = joinVersion(
+				replace(r.OSV1Replacement, m, 2),
+				replace(r.OSV2Replacement, m, 3),
+				replace(r.OSV3Replacement, m, 4),
+			)
+			break
+		}
+	}
+	for _, r := range rules.DeviceParsers {
+		if r.re.MatchString(userAgent) {
+			ua.Device = r.DeviceType
+			break
+		}
+	}
+	for _, r := range rules.EngineParsers {
+		if m := r.re.FindStringSubmatch(userAgent); m != nil {
+			ua.Engine = replace(r.EngineReplacement, m, 1)
+			break
+		}
+	}
+
+	if ua.Browser == "" {
+		ua.Browser = Other
+	}
+	if ua.OS == "" {
+		ua.OS = Other
+	}
+	if ua.Engine == "" {
+		ua.Engine = Other
+	}
+	return ua
+}
+
+// clearCache 清空解析快取
+func (p *Parser) clearCache() {
+	p.cacheMu.Lock()
+	p.cache = make(map[string]UserAgent)
+	p.cacheMu.Unlock()
+}
+
+// replacementRef replacement 中的群組引用
+var replacementRef = regexp.MustCompile(`\$(\d)`)
+
+// replace 套用 replacement；沒有 replacement 時使用第 group 個群組
+func replace(replacement string, match []string, group int) string {
+	if replacement == "" {
+		if group < len(match) {
+			return match[group]
+		}
+		return ""
+	}
+	if !strings.Contains(replacement, "$") {
+		return replacement
+	}
+	result := replacementRef.ReplaceAllStringFunc(replacement, func(ref string) string {
+		index, _ := strconv.Atoi(ref[1:])
+		if index < len(match) {
+			return match[index]
+		}
+		return ""
+	})
+	return strings.TrimSpace(result)
+}
+
+// joinVersion 以 "." 串接非空的版本片段（遇到空片段即停止）
+func joinVersion(parts ...string) string {
+	var version []string
+	for _, part := range parts {
+		if part == "" {
+			break
+		}
+		version = append(version, part)
+	}
+	return strings.Join(version, ".")
+}
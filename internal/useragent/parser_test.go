@@ -0,0 +1,144 @@
+package useragent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParser_Parse 測試常見 User-Agent 的解析
+func TestParser_Parse(t *testing.T) {
+	parser := NewParser()
+
+	testCases := []struct {
+		name     string
+		ua       string
+		expected UserAgent
+	}{
+		{
+			"Windows Chrome",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.130 Safari/537.36",
+			UserAgent{Browser: "Chrome", BrowserVersion: "120.0", OS: "Windows", OSVersion: "10", Device: DeviceDesktop, Engine: "Blink"},
+		},
+		{
+			"Windows Edge",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			UserAgent{Browser: "Edge", BrowserVersion: "120.0.2210", OS: "Windows", OSVersion: "10", Device: DeviceDesktop, Engine: "Blink"},
+		},
+		{
+			"macOS Safari",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			UserAgent{Browser: "Safari", BrowserVersion: "17.2", OS: "macOS", OSVersion: "10.15.7", Device: DeviceDesktop, Engine: "WebKit"},
+		},
+		{
+			"Linux Firefox",
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			UserAgent{Browser: "Firefox", BrowserVersion: "121.0", OS: "Ubuntu", Device: DeviceDesktop, Engine: "Gecko"},
+		},
+		{
+			"iPhone Safari",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			UserAgent{Browser: "Mobile Safari", BrowserVersion: "17.1.2", OS: "iOS", OSVersion: "17.1.2", Device: DeviceMobile, Engine: "WebKit"},
+		},
+		{
+			"iPhone Chrome 使用 WebKit",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			UserAgent{Browser: "Chrome Mobile iOS", BrowserVersion: "120.0", OS: "iOS", OSVersion: "17.1", Device: DeviceMobile, Engine: "WebKit"},
+		},
+		{
+			"iPad",
+			"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			UserAgent{Browser: "Mobile Safari", BrowserVersion: "16.6", OS: "iOS", OSVersion: "16.6", Device: DeviceTablet, Engine: "WebKit"},
+		},
+		{
+			"Android 手機 Chrome",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			UserAgent{Browser: "Chrome Mobile", BrowserVersion: "120.0", OS: "Android", OSVersion: "14", Device: DeviceMobile, Engine: "Blink"},
+		},
+		{
+			"Android 平板 Samsung Internet",
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36",
+			UserAgent{Browser: "Samsung Internet", BrowserVersion: "23.0", OS: "Android", OSVersion: "13", Device: DeviceTablet, Engine: "Blink"},
+		},
+		{
+			"智慧電視",
+			"Mozilla/5.0 (SMART-TV; Linux; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/4.0 Chrome/76.0.3809.146 TV Safari/537.36",
+			UserAgent{Browser: "Samsung Internet", BrowserVersion: "4.0", OS: "Tizen", OSVersion: "6.0", Device: DeviceTV, Engine: "Blink"},
+		},
+		{
+			"Googlebot",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			UserAgent{Browser: "Googlebot", BrowserVersion: "2.1", OS: Other, Device: DeviceBot, Engine: Other},
+		},
+		{
+			"curl",
+			"curl/8.4.0",
+			UserAgent{Browser: "curl", BrowserVersion: "8.4.0", OS: Other, Device: DeviceBot, Engine: Other},
+		},
+		{
+			"IE 11",
+			"Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko",
+			UserAgent{Browser: "IE", BrowserVersion: "11.0", OS: "Windows", OSVersion: "7", Device: DeviceDesktop, Engine: "Trident"},
+		},
+		{
+			"空白",
+			"-",
+			UserAgent{Browser: Other, OS: Other, Device: DeviceOther, Engine: Other},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parser.Parse(tc.ua))
+			// 第二次使用快取
+			assert.Equal(t, tc.expected, parser.Parse(tc.ua))
+		})
+	}
+
+	assert.Equal(t, "Chrome 120", parser.Parse(testCases[0].ua).BrowserMajor())
+	assert.Equal(t, Other, parser.Parse("-").BrowserMajor())
+}
+
+// TestParser_LoadFile 測試載入自訂規則檔與 replacement 的群組引用
+func TestParser_LoadFile(t *testing.T) {
+	parser := NewParser()
+	assert.Equal(t, "內建", parser.Source())
+	assert.Equal(t, "Chrome", parser.Parse("Mozilla/5.0 Chrome/120.0").Browser)
+
+	path := filepath.Join(t.TempDir(), "ua-regexes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+user_agent_parsers:
+  - regex: '(Internal)App/(\d+)\.(\d+)'
+    family_replacement: '$1 App'
+os_parsers:
+  - regex: 'Kiosk OS (\d+)'
+    os_replacement: 'Kiosk'
+    os_v1_replacement: '$1'
+device_parsers:
+  - regex: 'kiosk'
+    regex_flag: 'i'
+    device_type: 'tv'
+`), 0644))
+	require.NoError(t, parser.LoadFile(path))
+	assert.Equal(t, path, parser.Source())
+
+	assert.Equal(t, UserAgent{Browser: "Internal App", BrowserVersion: "3.2", OS: "Kiosk", OSVersion: "7", Device: DeviceTV, Engine: Other},
+		parser.Parse("InternalApp/3.2 (Kiosk OS 7)"))
+	assert.Equal(t, Other, parser.Parse("Mozilla/5.0 Chrome/120.0").Browser, "規則檔取代內建規則並清空快取")
+
+	// 無效的規則檔保留原本的規則
+	for _, content := range []string{
+		"user_agent_parsers: [{regex: '(unclosed'}]",
+		"device_parsers: [{regex: 'x', device_type: 'watch'}]",
+		"os_parsers: [{regex: 'x', regex_flag: 'g'}]",
+		"{}",
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		assert.Error(t, parser.LoadFile(path), content)
+	}
+	assert.Error(t, parser.LoadFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	assert.Equal(t, "Internal App", parser.Parse("InternalApp/3.2").Browser)
+}